@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/utils/generator"
+)
+
+// writes a synthetic batch for POST /api/v1/orders/process to stdout or -out,
+// with -anonymize an anonymized copy of a real batch instead
+func main() {
+	config := generator.Config{}
+	flag.IntVar(&config.Rows, "rows", 100, "number of rows")
+	flag.Float64Var(&config.DirtyPrefixRate, "dirty-rate", 0.2, "share of rows with a garbage platform prefix")
+	flag.Float64Var(&config.BundleRate, "bundle-rate", 0.2, "share of rows that are bundles")
+	flag.Float64Var(&config.ErrorRate, "error-rate", 0, "share of rows the parser rejects")
+	flag.Int64Var(&config.Seed, "seed", time.Now().UnixNano(), "random seed, the same seed generates the same batch")
+	out := flag.String("out", "", "output file, stdout when empty")
+	anonymize := flag.String("anonymize", "", "batch file to anonymize instead of generating one")
+	anonymizeConfig := generator.AnonymizeConfig{}
+	flag.StringVar(&anonymizeConfig.Salt, "salt", "", "secret the external refs are hashed with, random when empty")
+	flag.Float64Var(&anonymizeConfig.PriceJitter, "jitter", 0.1, "share prices move by at most either way")
+	flag.Parse()
+
+	log.Init("prod")
+
+	var orders []*entity.InputOrder
+	var err error
+	if *anonymize != "" {
+		orders, err = anonymizeFile(*anonymize, anonymizeConfig, config.Seed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "anonymizing %s: %v\n", *anonymize, err)
+			os.Exit(2)
+		}
+	} else {
+		orders, err = generator.Generate(config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid generator flags")
+			os.Exit(2)
+		}
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(orders); err != nil {
+		fmt.Fprintf(os.Stderr, "encoding batch: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(body.Bytes())
+		return
+	}
+	if err := os.WriteFile(*out, body.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "writing %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+}
+
+// a random salt hashes refs differently on every run, so two exports cannot be
+// joined on them
+func anonymizeFile(path string, config generator.AnonymizeConfig, seed int64) ([]*entity.InputOrder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*entity.InputOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+
+	if config.Salt == "" {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		config.Salt = hex.EncodeToString(salt)
+	}
+	config.Seed = seed
+
+	return generator.Anonymize(orders, config)
+}
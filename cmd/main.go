@@ -6,15 +6,28 @@ import (
 	"net/http"
 	"order-placement-system/env"
 	"order-placement-system/internal/adapter/handler"
+	"order-placement-system/internal/adapter/handler/model"
 	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/service"
+	"order-placement-system/internal/infrastructure/events"
+	"order-placement-system/internal/infrastructure/knowledgebase"
+	"order-placement-system/internal/infrastructure/metrics"
 	"order-placement-system/internal/infrastructure/middleware"
+	"order-placement-system/internal/infrastructure/pricelist"
 	"order-placement-system/internal/infrastructure/router"
+	"order-placement-system/internal/infrastructure/runtimerules"
+	"order-placement-system/internal/infrastructure/stock"
+	"order-placement-system/internal/infrastructure/weightcatalog"
 	"order-placement-system/internal/usecases/implementation"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/cache"
 	"order-placement-system/pkg/log"
 	"order-placement-system/pkg/utils/parser"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -33,26 +46,227 @@ func main() {
 		log.S("serviceName", env.ServiceName),
 		log.S("version", env.AppVersion))
 
+	config, err := env.LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration", log.E(err))
+	}
+	settings, err := loadAdapterSettings(config)
+	if err != nil {
+		log.Fatalf("Invalid configuration", log.E(err))
+	}
+	processOptions := config.ProcessOptions
+
+	log.Infof("Environment profile",
+		log.S("profile", env.Profile),
+		log.S("gin_mode", env.GinMode),
+		log.S("log_level", env.LogLevel),
+		log.S("process_mode", env.ProcessMode),
+		log.AtoS("overridden", env.ProfileOverrides()))
+
+	// a build whose models break clients of the same major version never serves
+	if err := model.CheckAPIContract(); err != nil {
+		log.Fatalf("Incompatible api models", log.E(err))
+	}
+
 	gin.SetMode(env.GinMode)
 	engine := gin.New()
 
 	middleware.Setup(engine)
+
+	// before any admin route is registered
+	if len(settings.adminKeys) > 0 {
+		engine.Use(middleware.AdminAuthorization(settings.adminKeys))
+	} else {
+		log.Warnf("No admin API keys, admin and metrics endpoints are not served")
+	}
+	if len(settings.deprecations) > 0 {
+		engine.Use(middleware.DeprecationNotices(settings.deprecations))
+	}
+
 	router.SetupHealthCheck(engine)
 
-	productParser := parser.NewProductParser()
+	// configuration is read once at startup, only the runtime rules can be
+	// changed later through the admin API
+	runtimeRules := runtimerules.NewRuntimeRules(processOptions.TexturePriorities)
+
+	// one parser is shared by every request
+	parserFactory := parser.NewParserFactory()
+	parserFactory.Register(parser.DefaultProfile, func() service.ProductParser {
+		return parser.NewProductParserWithOptions(parser.ProductParserOptions{
+			TextureAliases:       config.TextureAliases,
+			RuntimeAliases:       runtimeRules,
+			UnderscoreSeparators: env.ParserUnderscoreSeparators,
+		})
+	})
+	productParser := parserFactory.Get(parser.DefaultProfile)
 
 	complementaryCalculator := implementation.NewComplementaryCalculator()
 
-	orderProcessor := implementation.NewOrderProcessor(
-		productParser,
-		complementaryCalculator,
-	)
+	var priceList usecase.PriceList
+	if env.PriceListFile != "" {
+		staticPriceList, err := pricelist.LoadStaticPriceList(env.PriceListFile)
+		if err != nil {
+			log.Fatalf("Invalid price list", log.S("path", env.PriceListFile), log.E(err))
+		}
+		priceList = staticPriceList
+	}
+
+	if env.WeightCatalogFile != "" {
+		if processOptions.WeightCatalog, err = weightcatalog.LoadWeightCatalog(env.WeightCatalogFile); err != nil {
+			log.Fatalf("Invalid weight catalog", log.S("path", env.WeightCatalogFile), log.E(err))
+		}
+	}
+
+	priceSplitMetrics := metrics.NewPriceSplitMetrics(env.PriceSplitRecentBatches, env.PriceSplitOffendersPerBatch)
+	batchWarningMetrics := metrics.NewBatchWarningMetrics()
+	businessMetrics := metrics.NewBusinessMetrics(priceList)
+
+	dashboardMetrics := metrics.NewDashboardMetrics(time.Now(), time.Now)
+	clientMetrics := metrics.NewClientMetrics(time.Now)
+	batchUsageMetrics := metrics.NewBatchUsageMetrics()
+	garbageTokenMetrics := metrics.NewGarbageTokenMetrics(env.ParserLearningMode, env.ParserProposeAfter)
+	catalogGapMetrics := metrics.NewCatalogGapMetrics()
+
+	errorArticles := knowledgebase.NewErrorArticles(time.Now, entity.DefaultErrorArticles())
+
+	reportHandler := handler.NewReportHandler(presenter.NewReportPresenter(), handler.ReportHandlerDeps{
+		PriceSplits:   priceSplitMetrics,
+		BatchWarnings: batchWarningMetrics,
+		Business:      businessMetrics,
+		ErrorArticles: errorArticles,
+		PricePolicy:   processOptions.PricePolicy,
+		RuntimeRules:  runtimeRules,
+	})
+	router.SetupDocs(engine, reportHandler)
+	router.ErrorArticlesV1Routes(engine, reportHandler)
+
+	eventBus := events.NewBus()
+	eventBus.Subscribe(entity.EventOrderBatchProcessed, dashboardMetrics.RecordEvent)
+	eventBus.Subscribe(entity.EventRowDropped, dashboardMetrics.RecordEvent)
+	var eventWebhook *events.Webhook
+	// stays nil without a webhook, so its admin endpoints answer 404
+	var deliveryLog usecase.DeliveryLog
+	if env.EventWebhookURL != "" {
+		eventWebhook = events.NewWebhook(env.EventWebhookURL, env.EventWebhookTimeout, env.EventWebhookQueueSize)
+		for _, name := range []entity.EventName{
+			entity.EventOrderBatchProcessed,
+			entity.EventComplementaryItemsGenerated,
+			entity.EventRowDropped,
+		} {
+			eventBus.Subscribe(name, eventWebhook.Deliver)
+		}
+		dashboardMetrics.WatchQueue("eventWebhook", eventWebhook.QueueDepth)
+		deliveryLog = eventWebhook
+	}
+
+	pipeline := implementation.NewDefaultPipeline(productParser, complementaryCalculator, priceList)
+	if len(processOptions.CleanerSubstitutions) > 0 {
+		pipeline = pipeline.Replace(implementation.NewComplementStage(
+			complementaryCalculator,
+			stock.NewStaticStockChecker(config.OutOfStockSkus),
+		))
+	}
+
+	// shared by the serving processor and the config verifier, so a
+	// verification runs the stages requests run
+	processorOptions := implementation.OrderProcessorOptions{
+		Pipeline:       pipeline,
+		ProcessOptions: processOptions,
+		RuntimeRules:   runtimeRules,
+	}
+
+	servingOptions := processorOptions
+	servingOptions.PriceSplitRecorder = priceSplitMetrics
+	servingOptions.BusinessRecorder = businessMetrics
+	servingOptions.EventPublisher = eventBus
+	orderProcessor := implementation.NewOrderProcessor(nil, nil, servingOptions)
 
 	orderPresenter := presenter.NewOrderPresenter()
 
-	orderHandler := handler.NewOrderHandler(orderProcessor, orderPresenter)
+	var resultCache *cache.TTLCache
+	switch {
+	case env.ResultCacheTTL <= 0:
+	case len(processOptions.CleanerSubstitutions) > 0:
+		// substitutes follow the stock, which is not part of the cache key
+		log.Warnf("CLEANER_SUBSTITUTIONS is set, results are not cached")
+	default:
+		resultCache = cache.NewTTLCache(env.ResultCacheTTL, env.ResultCacheMaxEntries)
+	}
+
+	batchInspector := implementation.NewBatchInspector(productParser, implementation.BatchInspectorOptions{
+		PriceList:     priceList,
+		Thresholds:    config.BatchWarningThresholds,
+		Recorder:      batchWarningMetrics,
+		GarbageTokens: garbageTokenMetrics,
+		CatalogGaps:   catalogGapMetrics,
+		Defaults:      processOptions,
+	})
+
+	outputTemplates, err := presenter.NewOutputTemplates(settings.outputTemplates, presenter.OutputTemplateOptions{
+		Timeout:     env.OutputTemplateTimeout,
+		MaxBytes:    env.OutputTemplateMaxBytes,
+		MaxRenders:  env.OutputTemplateMaxRenders,
+		PricePolicy: processOptions.PricePolicy,
+		PickBins:    config.PickListBins,
+	})
+	if err != nil {
+		log.Fatalf("Invalid output templates", log.E(err))
+	}
+
+	orderHandler := handler.NewOrderHandler(orderProcessor, orderPresenter, handler.OrderHandlerDeps{
+		ResultCache:      resultCache,
+		ConfigGeneration: runtimeRules,
+		BatchInspector:   batchInspector,
+		Templates:        outputTemplates,
+		UsageRecorder:    batchUsageMetrics,
+		AmountFormat:     config.AmountFormat,
+	})
+
+	// tracking comes first so rejected requests show on the dashboard too
+	orderMiddlewares := []gin.HandlerFunc{middleware.TrackBatches(dashboardMetrics), middleware.TrackClients(clientMetrics)}
+	if len(settings.orderKeys) > 0 {
+		orderMiddlewares = append(orderMiddlewares, middleware.OrderAuthentication(settings.orderKeys))
+	} else {
+		log.Warnf("ORDER_API_KEYS is not set, order requests are priced from the \"*\" prices only")
+	}
+	if env.RequestSigningSecret != "" {
+		orderMiddlewares = append(orderMiddlewares, middleware.SignedRequestsWithCanonicalBody(
+			[]byte(env.RequestSigningSecret),
+			env.RequestSigningClockSkew,
+			env.RequestSigningNonceCacheSize,
+			env.RequestSigningCanonical,
+		))
+	}
 
-	router.OrderPlacementV1Routes(engine, orderHandler)
+	router.OrderPlacementV1Routes(engine, orderHandler, orderMiddlewares...)
+
+	// verification runs are kept out of the metrics and events
+	configVerifier, err := implementation.NewConfigVerifier(implementation.NewOrderProcessor(nil, nil, processorOptions))
+	if err != nil {
+		log.Fatalf("Invalid canonical cases", log.E(err))
+	}
+	// an admin API or metrics without keys would be open to anyone, they show
+	// every tenant's data
+	if len(settings.adminKeys) > 0 {
+		router.SetupMetrics(engine, reportHandler)
+		router.AdminV1Routes(engine, handler.NewAdminHandler(configVerifier, orderPresenter, presenter.NewReportPresenter(), handler.AdminHandlerDeps{
+			Dashboard:     dashboardMetrics,
+			Clients:       clientMetrics,
+			BatchUsage:    batchUsageMetrics,
+			GarbageTokens: garbageTokenMetrics,
+			CatalogGaps:   catalogGapMetrics,
+			Webhook:       deliveryLog,
+			ErrorArticles: errorArticles,
+			RuntimeRules:  runtimeRules,
+			Rules: &entity.RuleSet{
+				PlatformPrefixes:      parser.PlatformPrefixes(),
+				TextureAliases:        config.TextureAliases,
+				ModelSuffixAliases:    processOptions.ModelSuffixAliases,
+				AccessoryPattern:      env.AccessoryPattern,
+				ClothAccessoryPattern: env.ClothAccessoryPattern,
+			},
+		}))
+	}
 
 	router.LogRoutes(engine)
 	server := &http.Server{
@@ -80,6 +294,10 @@ func main() {
 		log.Fatalf("Server forced to shutdown", log.E(err))
 	}
 
+	if eventWebhook != nil {
+		eventWebhook.Close()
+	}
+
 	log.Info("Server exited gracefully")
 
 }
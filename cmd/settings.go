@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"order-placement-system/env"
+	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/infrastructure/middleware"
+)
+
+// adapterSettings are the settings env only decodes, so that it does not
+// depend on the middleware and presenter they are built into
+type adapterSettings struct {
+	adminKeys       middleware.AdminKeys
+	orderKeys       middleware.OrderKeys
+	deprecations    middleware.Deprecations
+	outputTemplates presenter.OutputTemplateSpecs
+}
+
+// the error names the first invalid setting, as LoadConfig's does. Output
+// templates are checked when they are compiled
+func loadAdapterSettings(config *env.Config) (*adapterSettings, error) {
+	settings := &adapterSettings{
+		adminKeys:       make(middleware.AdminKeys, len(config.AdminKeys)),
+		orderKeys:       middleware.OrderKeys(config.OrderKeys),
+		deprecations:    make(middleware.Deprecations, len(config.Deprecations)),
+		outputTemplates: make(presenter.OutputTemplateSpecs, len(config.OutputTemplates)),
+	}
+
+	for key, spec := range config.AdminKeys {
+		// a key sent as null stays nil and is refused below
+		var adminKey *middleware.AdminKey
+		if spec != nil {
+			adminKey = &middleware.AdminKey{Role: middleware.AdminRole(spec.Role), Tenant: spec.Tenant}
+		}
+		settings.adminKeys[key] = adminKey
+	}
+	for i, spec := range config.Deprecations {
+		settings.deprecations[i] = (*middleware.Deprecation)(spec)
+	}
+	for tenantId, named := range config.OutputTemplates {
+		settings.outputTemplates[tenantId] = make(map[string]*presenter.OutputTemplateSpec, len(named))
+		for name, spec := range named {
+			settings.outputTemplates[tenantId][name] = (*presenter.OutputTemplateSpec)(spec)
+		}
+	}
+
+	for _, setting := range []struct {
+		envName string
+		value   interface{ Validate() error }
+	}{
+		{"ADMIN_API_KEYS", settings.adminKeys},
+		{"ORDER_API_KEYS", settings.orderKeys},
+		{"API_DEPRECATIONS", settings.deprecations},
+	} {
+		if err := setting.value.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", setting.envName, err)
+		}
+	}
+	return settings, nil
+}
@@ -0,0 +1,240 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+)
+
+// Config is the environment read by LoadEnv, parsed and validated. Files it
+// names, e.g. PRICE_LIST_FILE, are loaded by whoever needs them
+type Config struct {
+	// empty when ADMIN_API_KEYS is not set. The admin keys, order keys,
+	// deprecations and output templates are only decoded here, whoever builds
+	// the middleware and presenter from them checks them
+	AdminKeys map[string]*AdminKeySpec
+	// API key to tenant, empty when ORDER_API_KEYS is not set
+	OrderKeys    map[string]string
+	Deprecations []*DeprecationSpec
+
+	TextureAliases value_object.TextureAliases
+
+	// what every batch runs with unless a request overrides it, the weight
+	// catalog is left to the caller
+	ProcessOptions *entity.ProcessOptions
+
+	OutOfStockSkus []string
+
+	// the JSON type order amounts are accepted as
+	AmountFormat value_object.AmountFormat
+
+	BatchWarningThresholds entity.BatchWarningThresholds
+
+	// tenant to template name to template
+	OutputTemplates map[string]map[string]*OutputTemplateSpec
+	PickListBins    entity.PickBins
+}
+
+// AdminKeySpec is one key of ADMIN_API_KEYS, e.g.
+// {"role": "tenant-admin", "tenant": "acme"}
+type AdminKeySpec struct {
+	Role   string `json:"role"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// DeprecationSpec is one entry of API_DEPRECATIONS
+type DeprecationSpec struct {
+	Route       string `json:"route"`
+	Field       string `json:"field,omitempty"`
+	Since       string `json:"since,omitempty"`
+	Sunset      string `json:"sunset,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// OutputTemplateSpec is one template of OUTPUT_TEMPLATES
+type OutputTemplateSpec struct {
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+}
+
+type validator interface {
+	Validate() error
+}
+
+// LoadConfig parses what LoadEnv read, the error names the first setting
+// that is invalid
+func LoadConfig() (*Config, error) {
+	if len(parseErrors) > 0 {
+		return nil, parseErrors[0]
+	}
+	if !IsValidProfile(Profile) {
+		return nil, fmt.Errorf("invalid APP_PROFILE %q", Profile)
+	}
+
+	config := &Config{}
+	for _, setting := range []struct {
+		envName string
+		raw     string
+		value   any
+	}{
+		{"ADMIN_API_KEYS", AdminApiKeys, &config.AdminKeys},
+		{"ORDER_API_KEYS", OrderApiKeys, &config.OrderKeys},
+		{"API_DEPRECATIONS", ApiDeprecations, &config.Deprecations},
+		{"TEXTURE_ALIASES", TextureAliases, &config.TextureAliases},
+		{"OUTPUT_TEMPLATES", OutputTemplates, &config.OutputTemplates},
+		{"PICK_LIST_BINS", PickListBins, &config.PickListBins},
+	} {
+		if err := decodeSetting(setting.envName, setting.raw, setting.value); err != nil {
+			return nil, err
+		}
+	}
+
+	processOptions, err := loadProcessOptions()
+	if err != nil {
+		return nil, err
+	}
+	config.ProcessOptions = processOptions
+
+	config.OutOfStockSkus = splitList(OutOfStockSkus)
+
+	config.AmountFormat = value_object.AmountFormat(AmountFormat)
+	if !config.AmountFormat.IsValid() {
+		return nil, fmt.Errorf("invalid AMOUNT_FORMAT %q", AmountFormat)
+	}
+
+	config.BatchWarningThresholds = entity.BatchWarningThresholds{
+		MinRows:            WarnMinBatchRows,
+		UnitPriceDeviation: WarnUnitPriceDeviation,
+		PrefixedRowRate:    WarnPrefixedRowRate,
+		BundleRatio:        WarnBundleRatio,
+		UnitPriceMinRatio:  WarnUnitPriceMinRatio,
+		UnitPriceMaxRatio:  WarnUnitPriceMaxRatio,
+	}
+
+	if PriceSplitRecentBatches < 0 || PriceSplitOffendersPerBatch < 0 {
+		return nil, fmt.Errorf("invalid PRICE_SPLIT_RECENT_BATCHES %d or PRICE_SPLIT_OFFENDERS_PER_BATCH %d",
+			PriceSplitRecentBatches, PriceSplitOffendersPerBatch)
+	}
+	if EventWebhookURL != "" && (EventWebhookTimeout <= 0 || EventWebhookQueueSize <= 0) {
+		return nil, fmt.Errorf("invalid EVENT_WEBHOOK_TIMEOUT %s or EVENT_WEBHOOK_QUEUE_SIZE %d",
+			EventWebhookTimeout, EventWebhookQueueSize)
+	}
+	if RequestSigningSecret != "" && (RequestSigningClockSkew <= 0 || RequestSigningNonceCacheSize <= 0) {
+		return nil, fmt.Errorf("invalid REQUEST_SIGNING_CLOCK_SKEW %s or REQUEST_SIGNING_NONCE_CACHE_SIZE %d",
+			RequestSigningClockSkew, RequestSigningNonceCacheSize)
+	}
+
+	return config, nil
+}
+
+func loadProcessOptions() (*entity.ProcessOptions, error) {
+	options := &entity.ProcessOptions{
+		RowTimeout:              RowProcessingTimeout,
+		BatchDeadline:           BatchProcessingTimeout,
+		ComplementaryUnit:       entity.ComplementaryUnit(ComplementaryUnit),
+		ComplementaryPlacement:  entity.ComplementaryPlacement(ComplementaryPlacement),
+		ComplementaryDuplicates: entity.ComplementaryDuplicates(ComplementaryDuplicates),
+		MaxBundleComponents:     MaxBundleComponents,
+		MaxBundleUnits:          MaxBundleUnits,
+		MaxLineQty:              MaxLineQty,
+		Mode:                    entity.ProcessMode(ProcessMode),
+		FilmTextureStrictness:   entity.FilmTextureStrictness(FilmTextureStrictness),
+		KitTenants:              splitList(ComplementaryKitTenants),
+		AdditiveQuantityTenants: splitList(AdditiveQuantityTenants),
+	}
+
+	for _, setting := range []struct {
+		envName string
+		value   string
+		valid   bool
+	}{
+		{"COMPLEMENTARY_UNIT", ComplementaryUnit, options.ComplementaryUnit.IsValid()},
+		{"COMPLEMENTARY_PLACEMENT", ComplementaryPlacement, options.ComplementaryPlacement.IsValid()},
+		{"COMPLEMENTARY_DUPLICATES", ComplementaryDuplicates, options.ComplementaryDuplicates.IsValid()},
+		{"PROCESS_MODE", ProcessMode, options.Mode.IsValid()},
+		{"FILM_TEXTURE_STRICTNESS", FilmTextureStrictness, options.FilmTextureStrictness.IsValid()},
+	} {
+		if !setting.valid {
+			return nil, fmt.Errorf("invalid %s %q", setting.envName, setting.value)
+		}
+	}
+
+	var err error
+	if options.AccessoryPattern, err = compilePattern("ACCESSORY_PATTERN", AccessoryPattern); err != nil {
+		return nil, err
+	}
+	if options.ClothAccessoryPattern, err = compilePattern("ACCESSORY_CLOTH_PATTERN", ClothAccessoryPattern); err != nil {
+		return nil, err
+	}
+
+	if options.PricePolicy, err = value_object.NewPricePolicy(PriceCurrency, PriceEpsilon); err != nil {
+		return nil, fmt.Errorf("invalid price policy of PRICE_CURRENCY %q: %w", PriceCurrency, err)
+	}
+
+	for _, setting := range []struct {
+		envName string
+		raw     string
+		value   any
+	}{
+		{"MODEL_SUFFIX_ALIASES", ModelSuffixAliases, &options.ModelSuffixAliases},
+		{"VARIANT_TOKENS", VariantTokens, &options.VariantTokens},
+		{"COMPLEMENTARY_CAPS", ComplementaryCaps, &options.ComplementaryCaps},
+		{"COMPLEMENTARY_CUSTOMS", ComplementaryCustoms, &options.CustomsClassification},
+		{"COMPLEMENTARY_CHANNELS", ComplementaryChannels, &options.ChannelPolicies},
+		{"COMPLEMENTARY_CAMPAIGNS", ComplementaryCampaigns, &options.PromoCampaigns},
+		{"CLEANER_SUBSTITUTIONS", CleanerSubstitutions, &options.CleanerSubstitutions},
+		{"SKU_AFFIXES", SkuAffixes, &options.SkuAffixes},
+		{"TEXTURE_PRIORITIES", TexturePriorities, &options.TexturePriorities},
+		{"FILM_TEXTURE_MATRIX", FilmTextureMatrix, &options.FilmTextureMatrix},
+	} {
+		if err := decodeSetting(setting.envName, setting.raw, setting.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return options, nil
+}
+
+// an empty setting leaves value as it is, value is validated when it can be
+func decodeSetting(envName, raw string, value any) error {
+	if raw == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(raw), value); err != nil {
+		return fmt.Errorf("invalid %s: %w", envName, err)
+	}
+	if validated, ok := value.(validator); ok {
+		if err := validated.Validate(); err != nil {
+			return fmt.Errorf("invalid %s: %w", envName, err)
+		}
+	}
+	return nil
+}
+
+// nil when pattern is empty
+func compilePattern(envName, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", envName, pattern, err)
+	}
+	return compiled, nil
+}
+
+// comma separated, blanks dropped
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
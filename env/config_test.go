@@ -0,0 +1,98 @@
+package env_test
+
+import (
+	"testing"
+
+	"order-placement-system/env"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_Template(t *testing.T) {
+	setTemplateEnv(t)
+	env.LoadEnv()
+
+	config, err := env.LoadConfig()
+	require.NoError(t, err)
+
+	assert.Empty(t, config.AdminKeys)
+	assert.Nil(t, config.ProcessOptions.TexturePriorities)
+	assert.Equal(t, entity.ProcessModeStrict, config.ProcessOptions.Mode)
+	assert.Equal(t, value_object.DefaultPricePolicy(), config.ProcessOptions.PricePolicy)
+	assert.Equal(t, value_object.AmountFormatAny, config.AmountFormat)
+	assert.True(t, config.ProcessOptions.AccessoryPattern.MatchString("ACC-CABLE"))
+	assert.Nil(t, config.ProcessOptions.ClothAccessoryPattern)
+	assert.Equal(t, 10, config.BatchWarningThresholds.MinRows)
+}
+
+func TestLoadConfig_Settings(t *testing.T) {
+	setTemplateEnv(t)
+	t.Setenv("ADMIN_API_KEYS", `{"k-view": {"role": "viewer"}}`)
+	t.Setenv("ORDER_API_KEYS", `{"k-acme": "acme"}`)
+	t.Setenv("COMPLEMENTARY_KIT_TENANTS", " acme, ,globex")
+	t.Setenv("OUT_OF_STOCK_SKUS", "WIPING-CLOTH")
+	t.Setenv("MODEL_SUFFIX_ALIASES", `{"*": {"-BLK": "-B"}}`)
+	env.LoadEnv()
+
+	config, err := env.LoadConfig()
+	require.NoError(t, err)
+
+	assert.Contains(t, config.AdminKeys, "k-view")
+	assert.Equal(t, "acme", config.OrderKeys["k-acme"])
+	assert.Equal(t, entity.KitTenants{"acme", "globex"}, config.ProcessOptions.KitTenants)
+	assert.Equal(t, []string{"WIPING-CLOTH"}, config.OutOfStockSkus)
+	assert.Equal(t, "-B", config.ProcessOptions.ModelSuffixAliases["*"]["-BLK"])
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		envName string
+		value   string
+	}{
+		{"APP_PROFILE", "qa"},
+		{"ADMIN_API_KEYS", `{"k": {"role": 1}}`},
+		{"ORDER_API_KEYS", `{"k": 1}`},
+		{"TEXTURE_ALIASES", `{"PRIV": "SHINY"}`},
+		{"TEXTURE_ALIASES", `{"PRIV": "PRIVACY", "priv": "MATTE"}`},
+		{"TEXTURE_PRIORITIES", `{"CLEAR": 1, "MATTE": 1, "PRIVACY": 2}`},
+		{"COMPLEMENTARY_CAPS", `not json`},
+		{"PROCESS_MODE", "careless"},
+		{"ACCESSORY_PATTERN", "^ACC-("},
+		{"PRICE_CURRENCY", "XXX"},
+		{"PRICE_SPLIT_RECENT_BATCHES", "-1"},
+		{"ROW_PROCESSING_TIMEOUT", "1sec"},
+		{"MAX_BUNDLE_UNITS", "1k"},
+		{"MAX_BUNDLE_UNITS", "-1"},
+		{"MAX_BUNDLE_COMPONENTS", "-3"},
+		{"PRICE_EPSILON", "0,01"},
+		{"AMOUNT_FORMAT", "float"},
+		{"PARSER_LEARNING_MODE", "yes"},
+		{"SHUTDOWN_TIMEOUT", "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.envName, func(t *testing.T) {
+			setTemplateEnv(t)
+			t.Setenv(tt.envName, tt.value)
+			env.LoadEnv()
+
+			_, err := env.LoadConfig()
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.envName)
+			}
+		})
+	}
+
+	t.Run("Webhook without a queue", func(t *testing.T) {
+		setTemplateEnv(t)
+		t.Setenv("EVENT_WEBHOOK_URL", "http://hooks.example.com")
+		t.Setenv("EVENT_WEBHOOK_QUEUE_SIZE", "0")
+		env.LoadEnv()
+
+		_, err := env.LoadConfig()
+		assert.Error(t, err)
+	})
+}
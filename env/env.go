@@ -1,25 +1,253 @@
 package env
 
 import (
+	"fmt"
 	"order-placement-system/pkg/load_env"
+	"strconv"
 	"time"
 )
 
 var (
+	Profile string
+
 	GinMode         string
 	ServiceName     string
 	AppVersion      string
 	LogLevel        string
 	Port            string
 	ShutdownTimeout time.Duration
+
+	RowProcessingTimeout   time.Duration
+	BatchProcessingTimeout time.Duration
+
+	AccessoryPattern      string
+	ClothAccessoryPattern string
+
+	ComplementaryUnit       string
+	ComplementaryPlacement  string
+	ComplementaryDuplicates string
+
+	ComplementaryKitTenants string
+	ComplementaryCaps       string
+
+	AdditiveQuantityTenants string
+
+	ComplementaryCustoms   string
+	ComplementaryCampaigns string
+	ComplementaryChannels  string
+
+	CleanerSubstitutions string
+	OutOfStockSkus       string
+
+	SkuAffixes string
+
+	ProcessMode string
+
+	PriceCurrency string
+	PriceEpsilon  float64
+	AmountFormat  string
+
+	PriceListFile     string
+	WeightCatalogFile string
+
+	ModelSuffixAliases string
+	VariantTokens      string
+
+	TextureAliases    string
+	TexturePriorities string
+
+	FilmTextureMatrix     string
+	FilmTextureStrictness string
+
+	ParserUnderscoreSeparators bool
+
+	MaxBundleComponents int
+	MaxBundleUnits      int
+
+	MaxLineQty int
+
+	WarnMinBatchRows       int
+	WarnUnitPriceDeviation float64
+	WarnPrefixedRowRate    float64
+	WarnBundleRatio        float64
+	WarnUnitPriceMinRatio  float64
+	WarnUnitPriceMaxRatio  float64
+
+	RequestSigningSecret         string
+	RequestSigningClockSkew      time.Duration
+	RequestSigningNonceCacheSize int
+	RequestSigningCanonical      bool
+
+	AdminApiKeys string
+	OrderApiKeys string
+
+	ApiDeprecations string
+
+	EventWebhookURL       string
+	EventWebhookTimeout   time.Duration
+	EventWebhookQueueSize int
+
+	ParserLearningMode bool
+	ParserProposeAfter int
+
+	ResultCacheTTL        time.Duration
+	ResultCacheMaxEntries int
+
+	OutputTemplates          string
+	OutputTemplateTimeout    time.Duration
+	OutputTemplateMaxBytes   int
+	OutputTemplateMaxRenders int
+	PickListBins             string
+
+	PriceSplitRecentBatches     int
+	PriceSplitOffendersPerBatch int
 )
 
+// what LoadEnv could not parse, in the order it read them
+var parseErrors []error
+
+// settings that do not parse are left at their zero value and reported by
+// LoadConfig
 func LoadEnv() {
+	parseErrors = nil
+
+	Profile = load_env.DefaultIfEmpty("APP_PROFILE", "")
+
 	// GinMode = load_env.Require("GIN_MODE")
-	GinMode = load_env.Default("GIN_MODE", "release")
-	ServiceName = load_env.Default("SERVICE_NAME", "order-placement-system")
-	AppVersion = load_env.Default("APP_VERSION", "v1.0.4")
-	LogLevel = load_env.Default("LOG_LEVEL", "dev")
-	Port = load_env.Default("PORT", "8080")
-	ShutdownTimeout, _ = time.ParseDuration(load_env.Default("SHUTDOWN_TIMEOUT", "5s"))
+	GinMode = load_env.DefaultIfEmpty("GIN_MODE", profileDefault("GIN_MODE", "release"))
+	ServiceName = load_env.DefaultIfEmpty("SERVICE_NAME", "order-placement-system")
+	AppVersion = load_env.DefaultIfEmpty("APP_VERSION", "v1.0.4")
+	LogLevel = load_env.DefaultIfEmpty("LOG_LEVEL", profileDefault("LOG_LEVEL", "dev"))
+	Port = load_env.DefaultIfEmpty("PORT", "8080")
+	ShutdownTimeout = parseDurationSetting("SHUTDOWN_TIMEOUT", "5s")
+
+	RowProcessingTimeout = parseDurationSetting("ROW_PROCESSING_TIMEOUT", "0")
+	BatchProcessingTimeout = parseDurationSetting("BATCH_PROCESSING_TIMEOUT", "0")
+
+	AccessoryPattern = load_env.DefaultIfEmpty("ACCESSORY_PATTERN", "^ACC-")
+	ClothAccessoryPattern = load_env.DefaultIfEmpty("ACCESSORY_CLOTH_PATTERN", "")
+
+	ComplementaryUnit = load_env.DefaultIfEmpty("COMPLEMENTARY_UNIT", "batch")
+	ComplementaryPlacement = load_env.DefaultIfEmpty("COMPLEMENTARY_PLACEMENT", "end")
+	ComplementaryDuplicates = load_env.DefaultIfEmpty("COMPLEMENTARY_DUPLICATES", "merge")
+
+	ComplementaryKitTenants = load_env.DefaultIfEmpty("COMPLEMENTARY_KIT_TENANTS", "")
+	ComplementaryCaps = load_env.DefaultIfEmpty("COMPLEMENTARY_CAPS", "")
+
+	AdditiveQuantityTenants = load_env.DefaultIfEmpty("ADDITIVE_QUANTITY_TENANTS", "")
+
+	ComplementaryCustoms = load_env.DefaultIfEmpty("COMPLEMENTARY_CUSTOMS", "")
+	ComplementaryCampaigns = load_env.DefaultIfEmpty("COMPLEMENTARY_CAMPAIGNS", "")
+	ComplementaryChannels = load_env.DefaultIfEmpty("COMPLEMENTARY_CHANNELS", "")
+
+	CleanerSubstitutions = load_env.DefaultIfEmpty("CLEANER_SUBSTITUTIONS", "")
+	OutOfStockSkus = load_env.DefaultIfEmpty("OUT_OF_STOCK_SKUS", "")
+
+	SkuAffixes = load_env.DefaultIfEmpty("SKU_AFFIXES", "")
+
+	ProcessMode = load_env.DefaultIfEmpty("PROCESS_MODE", profileDefault("PROCESS_MODE", "strict"))
+
+	PriceCurrency = load_env.DefaultIfEmpty("PRICE_CURRENCY", "THB")
+	PriceEpsilon = parseFloatSetting("PRICE_EPSILON", "0")
+	AmountFormat = load_env.DefaultIfEmpty("AMOUNT_FORMAT", "any")
+
+	PriceListFile = load_env.DefaultIfEmpty("PRICE_LIST_FILE", "")
+	WeightCatalogFile = load_env.DefaultIfEmpty("WEIGHT_CATALOG_FILE", "")
+
+	ModelSuffixAliases = load_env.DefaultIfEmpty("MODEL_SUFFIX_ALIASES", "")
+	VariantTokens = load_env.DefaultIfEmpty("VARIANT_TOKENS", "")
+
+	TextureAliases = load_env.DefaultIfEmpty("TEXTURE_ALIASES", "")
+	TexturePriorities = load_env.DefaultIfEmpty("TEXTURE_PRIORITIES", "")
+	FilmTextureMatrix = load_env.DefaultIfEmpty("FILM_TEXTURE_MATRIX", "")
+	FilmTextureStrictness = load_env.DefaultIfEmpty("FILM_TEXTURE_STRICTNESS", "warn")
+
+	ParserUnderscoreSeparators = parseBoolSetting("PARSER_UNDERSCORE_SEPARATORS", "false")
+
+	MaxBundleComponents = parseLimitSetting("MAX_BUNDLE_COMPONENTS", "0")
+	MaxBundleUnits = parseLimitSetting("MAX_BUNDLE_UNITS", "0")
+
+	MaxLineQty = parseIntSetting("MAX_LINE_QTY", "0")
+
+	WarnMinBatchRows = parseIntSetting("WARN_MIN_BATCH_ROWS", "10")
+	WarnUnitPriceDeviation = parseFloatSetting("WARN_UNIT_PRICE_DEVIATION", "0.5")
+	WarnPrefixedRowRate = parseFloatSetting("WARN_PREFIXED_ROW_RATE", "0.5")
+	WarnBundleRatio = parseFloatSetting("WARN_BUNDLE_RATIO", "0.5")
+	WarnUnitPriceMinRatio = parseFloatSetting("WARN_UNIT_PRICE_MIN_RATIO", "0")
+	WarnUnitPriceMaxRatio = parseFloatSetting("WARN_UNIT_PRICE_MAX_RATIO", "0")
+
+	RequestSigningSecret = load_env.DefaultIfEmpty("REQUEST_SIGNING_SECRET", "")
+	RequestSigningClockSkew = parseDurationSetting("REQUEST_SIGNING_CLOCK_SKEW", "5m")
+	RequestSigningNonceCacheSize = parseIntSetting("REQUEST_SIGNING_NONCE_CACHE_SIZE", "100000")
+	RequestSigningCanonical = parseBoolSetting("REQUEST_SIGNING_CANONICAL", "false")
+
+	AdminApiKeys = load_env.DefaultIfEmpty("ADMIN_API_KEYS", "")
+	OrderApiKeys = load_env.DefaultIfEmpty("ORDER_API_KEYS", "")
+
+	ApiDeprecations = load_env.DefaultIfEmpty("API_DEPRECATIONS", "")
+
+	EventWebhookURL = load_env.DefaultIfEmpty("EVENT_WEBHOOK_URL", "")
+	EventWebhookTimeout = parseDurationSetting("EVENT_WEBHOOK_TIMEOUT", "5s")
+	EventWebhookQueueSize = parseIntSetting("EVENT_WEBHOOK_QUEUE_SIZE", "1000")
+
+	ParserLearningMode = parseBoolSetting("PARSER_LEARNING_MODE", "false")
+	ParserProposeAfter = parseIntSetting("PARSER_PROPOSE_AFTER", "20")
+
+	ResultCacheTTL = parseDurationSetting("RESULT_CACHE_TTL", "30s")
+	ResultCacheMaxEntries = parseIntSetting("RESULT_CACHE_MAX_ENTRIES", "1000")
+
+	OutputTemplates = load_env.DefaultIfEmpty("OUTPUT_TEMPLATES", "")
+	OutputTemplateTimeout = parseDurationSetting("OUTPUT_TEMPLATE_TIMEOUT", "1s")
+	OutputTemplateMaxBytes = parseIntSetting("OUTPUT_TEMPLATE_MAX_BYTES", "10485760")
+	OutputTemplateMaxRenders = parseIntSetting("OUTPUT_TEMPLATE_MAX_RENDERS", "16")
+	PickListBins = load_env.DefaultIfEmpty("PICK_LIST_BINS", "")
+
+	PriceSplitRecentBatches = parseIntSetting("PRICE_SPLIT_RECENT_BATCHES", "20")
+	PriceSplitOffendersPerBatch = parseIntSetting("PRICE_SPLIT_OFFENDERS_PER_BATCH", "10")
+}
+
+func parseDurationSetting(envName, defaultValue string) time.Duration {
+	raw := load_env.DefaultIfEmpty(envName, defaultValue)
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		parseErrors = append(parseErrors, fmt.Errorf("invalid %s %q: %w", envName, raw, err))
+	}
+	return value
+}
+
+func parseIntSetting(envName, defaultValue string) int {
+	raw := load_env.DefaultIfEmpty(envName, defaultValue)
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		parseErrors = append(parseErrors, fmt.Errorf("invalid %s %q: %w", envName, raw, err))
+	}
+	return value
+}
+
+// a limit where 0 is off, a negative one would be read as off too
+func parseLimitSetting(envName, defaultValue string) int {
+	value := parseIntSetting(envName, defaultValue)
+	if value < 0 {
+		parseErrors = append(parseErrors, fmt.Errorf("invalid %s %d: must not be negative", envName, value))
+		return 0
+	}
+	return value
+}
+
+func parseFloatSetting(envName, defaultValue string) float64 {
+	raw := load_env.DefaultIfEmpty(envName, defaultValue)
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		parseErrors = append(parseErrors, fmt.Errorf("invalid %s %q: %w", envName, raw, err))
+	}
+	return value
+}
+
+func parseBoolSetting(envName, defaultValue string) bool {
+	raw := load_env.DefaultIfEmpty(envName, defaultValue)
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		parseErrors = append(parseErrors, fmt.Errorf("invalid %s %q: %w", envName, raw, err))
+	}
+	return value
 }
@@ -0,0 +1,84 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	"order-placement-system/env"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/log"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+// loads the .env.dev template as is, every key set but empty
+func setTemplateEnv(t *testing.T) {
+	t.Helper()
+	template, err := godotenv.Read("../.env.dev")
+	require.NoError(t, err)
+	require.NotEmpty(t, template)
+	for key, value := range template {
+		require.Empty(t, value, key)
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoadEnv_Template(t *testing.T) {
+	setTemplateEnv(t)
+
+	env.LoadEnv()
+
+	assert.Equal(t, "", env.Profile)
+	assert.Equal(t, "^ACC-", env.AccessoryPattern)
+	assert.Empty(t, env.ProfileOverrides())
+
+	assert.True(t, entity.ComplementaryUnit(env.ComplementaryUnit).IsValid(), env.ComplementaryUnit)
+	assert.True(t, entity.ComplementaryPlacement(env.ComplementaryPlacement).IsValid(), env.ComplementaryPlacement)
+	assert.True(t, entity.ComplementaryDuplicates(env.ComplementaryDuplicates).IsValid(), env.ComplementaryDuplicates)
+	assert.True(t, entity.FilmTextureStrictness(env.FilmTextureStrictness).IsValid(), env.FilmTextureStrictness)
+
+	assert.Equal(t, 20, env.PriceSplitRecentBatches)
+	assert.Equal(t, 10, env.PriceSplitOffendersPerBatch)
+	assert.Equal(t, 5*time.Minute, env.RequestSigningClockSkew)
+	assert.Equal(t, 1000, env.EventWebhookQueueSize)
+}
+
+func TestLoadEnv_TemplateProcessMode(t *testing.T) {
+	tests := []struct {
+		profile     string
+		processMode string
+	}{
+		{"", "strict"},
+		{env.ProfileStaging, "strict"},
+		{env.ProfileProd, "lenient"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			setTemplateEnv(t)
+			t.Setenv("APP_PROFILE", tt.profile)
+
+			env.LoadEnv()
+
+			assert.Equal(t, tt.processMode, env.ProcessMode)
+			assert.True(t, entity.ProcessMode(env.ProcessMode).IsValid())
+		})
+	}
+}
+
+func TestLoadEnv_TemplatePricePolicy(t *testing.T) {
+	setTemplateEnv(t)
+
+	env.LoadEnv()
+
+	policy, err := value_object.NewPricePolicy(env.PriceCurrency, env.PriceEpsilon)
+	require.NoError(t, err)
+	assert.Equal(t, value_object.DefaultPricePolicy(), policy)
+}
@@ -0,0 +1,58 @@
+package env
+
+import (
+	"sort"
+	"syscall"
+)
+
+const (
+	ProfileDev     = "dev"
+	ProfileStaging = "staging"
+	ProfileProd    = "prod"
+)
+
+// the defaults APP_PROFILE sets, a variable set in the environment still wins.
+// Settings not listed keep their usual default in every profile
+var profileDefaults = map[string]map[string]string{
+	ProfileDev: {
+		"GIN_MODE":     "debug",
+		"LOG_LEVEL":    "dev",
+		"PROCESS_MODE": "strict",
+	},
+	ProfileStaging: {
+		"GIN_MODE":     "release",
+		"LOG_LEVEL":    "prod",
+		"PROCESS_MODE": "strict",
+	},
+	ProfileProd: {
+		"GIN_MODE":     "release",
+		"LOG_LEVEL":    "prod",
+		"PROCESS_MODE": "lenient",
+	},
+}
+
+// empty means no profile
+func IsValidProfile(profile string) bool {
+	_, ok := profileDefaults[profile]
+	return profile == "" || ok
+}
+
+func profileDefault(envName string, defaultValue string) string {
+	if value, ok := profileDefaults[Profile][envName]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// ProfileOverrides lists the settings of the profile that the environment
+// sets itself, sorted
+func ProfileOverrides() []string {
+	var overrides []string
+	for envName := range profileDefaults[Profile] {
+		if value, found := syscall.Getenv(envName); found && value != "" {
+			overrides = append(overrides, envName)
+		}
+	}
+	sort.Strings(overrides)
+	return overrides
+}
@@ -0,0 +1,79 @@
+package env_test
+
+import (
+	"os"
+	"testing"
+
+	"order-placement-system/env"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unsets key for the rest of the test
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+func TestLoadEnv_Profiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     string
+		set         map[string]string
+		ginMode     string
+		logLevel    string
+		processMode string
+		overridden  []string
+	}{
+		{"No profile", "", nil, "release", "dev", "strict", nil},
+		{"Dev", env.ProfileDev, nil, "debug", "dev", "strict", nil},
+		{"Staging", env.ProfileStaging, nil, "release", "prod", "strict", nil},
+		{"Prod", env.ProfileProd, nil, "release", "prod", "lenient", nil},
+		{
+			name:        "Environment wins over the profile",
+			profile:     env.ProfileProd,
+			set:         map[string]string{"PROCESS_MODE": "strict", "LOG_LEVEL": "dev"},
+			ginMode:     "release",
+			logLevel:    "dev",
+			processMode: "strict",
+			overridden:  []string{"LOG_LEVEL", "PROCESS_MODE"},
+		},
+		{
+			name:        "Empty values take the profile",
+			profile:     env.ProfileDev,
+			set:         map[string]string{"GIN_MODE": "", "LOG_LEVEL": "", "PROCESS_MODE": ""},
+			ginMode:     "debug",
+			logLevel:    "dev",
+			processMode: "strict",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"APP_PROFILE", "GIN_MODE", "LOG_LEVEL", "PROCESS_MODE"} {
+				unsetEnv(t, key)
+			}
+			if tt.profile != "" {
+				t.Setenv("APP_PROFILE", tt.profile)
+			}
+			for key, value := range tt.set {
+				t.Setenv(key, value)
+			}
+
+			env.LoadEnv()
+
+			assert.Equal(t, tt.profile, env.Profile)
+			assert.Equal(t, tt.ginMode, env.GinMode)
+			assert.Equal(t, tt.logLevel, env.LogLevel)
+			assert.Equal(t, tt.processMode, env.ProcessMode)
+			assert.Equal(t, tt.overridden, env.ProfileOverrides())
+		})
+	}
+}
+
+func TestIsValidProfile(t *testing.T) {
+	assert.True(t, env.IsValidProfile(""))
+	assert.True(t, env.IsValidProfile(env.ProfileStaging))
+	assert.False(t, env.IsValidProfile("production"))
+}
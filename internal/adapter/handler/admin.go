@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+type adminHandler struct {
+	configVerifier usecase.ConfigVerifier
+	presenter      presenter.OrderPresenter
+	reports        presenter.ReportPresenter
+	dashboard      usecase.ReportSource
+	clients        usecase.TenantReportSource
+	batchUsage     usecase.HeaviestReportSource
+	garbageTokens  usecase.ReportSource
+	catalogGaps    usecase.CatalogGapReport
+	webhook        usecase.DeliveryLog
+	errorArticles  usecase.ErrorArticleStore
+	runtimeRules   usecase.RuntimeRules
+	rules          *entity.RuleSet
+}
+
+type AdminHandlerInterface interface {
+	VerifyConfig(c *gin.Context)
+	Dashboard(c *gin.Context)
+	Clients(c *gin.Context)
+	BatchUsage(c *gin.Context)
+	TexturePriorities(c *gin.Context)
+	SetTexturePriorities(c *gin.Context)
+	GarbageTokens(c *gin.Context)
+	LintParserRules(c *gin.Context)
+	CatalogGaps(c *gin.Context)
+	AddCatalogTextureAlias(c *gin.Context)
+	ResolveCatalogGap(c *gin.Context)
+	WebhookDeliveries(c *gin.Context)
+	RedeliverWebhook(c *gin.Context)
+	PutErrorArticle(c *gin.Context)
+	DeleteErrorArticle(c *gin.Context)
+}
+
+// AdminHandlerDeps are what the admin endpoints read and change, an endpoint
+// whose field is left nil answers 404
+type AdminHandlerDeps struct {
+	Dashboard     usecase.ReportSource
+	Clients       usecase.TenantReportSource
+	BatchUsage    usecase.HeaviestReportSource
+	GarbageTokens usecase.ReportSource
+	CatalogGaps   usecase.CatalogGapReport
+	// only when a webhook is configured
+	Webhook       usecase.DeliveryLog
+	ErrorArticles usecase.ErrorArticleStore
+	// the texture priorities and aliases the admin endpoints read and change
+	RuntimeRules usecase.RuntimeRules
+	// the running rules, rules posted to lint are laid over them
+	Rules *entity.RuleSet
+}
+
+func NewAdminHandler(
+	configVerifier usecase.ConfigVerifier,
+	presenter presenter.OrderPresenter,
+	reports presenter.ReportPresenter,
+	deps AdminHandlerDeps,
+) AdminHandlerInterface {
+	return &adminHandler{
+		configVerifier: configVerifier,
+		presenter:      presenter,
+		reports:        reports,
+		dashboard:      deps.Dashboard,
+		clients:        deps.Clients,
+		batchUsage:     deps.BatchUsage,
+		garbageTokens:  deps.GarbageTokens,
+		catalogGaps:    deps.CatalogGaps,
+		webhook:        deps.Webhook,
+		errorArticles:  deps.ErrorArticles,
+		runtimeRules:   deps.RuntimeRules,
+		rules:          deps.Rules,
+	}
+}
+
+// runs the canonical cases and the posted samples, the report says which
+// failed and how their output differs
+func (h *adminHandler) VerifyConfig(c *gin.Context) {
+	request, err := model.ParseVerifyConfigRequest(c)
+	if err != nil {
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	options, err := model.ParseProcessOptions(c)
+	if err != nil {
+		log.Errorf("failed to parse process options", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	samples, err := request.ToEntity()
+	if err != nil {
+		log.Errorf("failed to convert samples to entities", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	h.presenter.SuccessResponse(c, h.configVerifier.Verify(samples, options.ToEntity()))
+}
+
+func (h *adminHandler) Dashboard(c *gin.Context) {
+	if h.dashboard == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, h.dashboard.Report())
+}
+
+// a tenant-admin sees its own tenant's clients only
+func (h *adminHandler) Clients(c *gin.Context) {
+	if h.clients == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, gin.H{"clients": h.clients.TenantReport(model.AdminTenant(c))})
+}
+
+// ?top=N heaviest batches by wall time, 10 by default
+func (h *adminHandler) BatchUsage(c *gin.Context) {
+	if h.batchUsage == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	top := 10
+	if raw := c.Query("top"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			log.Errorf("invalid batch usage top", log.S("top", raw))
+			h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+			return
+		}
+		top = parsed
+	}
+	h.reports.ReportResponse(c, http.StatusOK, h.batchUsage.HeaviestReport(top))
+}
+
+func (h *adminHandler) TexturePriorities(c *gin.Context) {
+	if h.runtimeRules == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, h.runtimeRules.TexturePriorities())
+}
+
+// replaces every priority at once and applies to the next batch
+func (h *adminHandler) SetTexturePriorities(c *gin.Context) {
+	if h.runtimeRules == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	var priorities value_object.TexturePriorities
+	if err := c.ShouldBindJSON(&priorities); err != nil {
+		log.Errorf("failed to bind texture priorities", log.E(err))
+		h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.runtimeRules.SetTexturePriorities(priorities); err != nil {
+		h.reports.ErrorResponse(c, err)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, h.runtimeRules.TexturePriorities())
+}
+
+func (h *adminHandler) GarbageTokens(c *gin.Context) {
+	if h.garbageTokens == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, h.garbageTokens.Report())
+}
+
+// rules posted replace the running ones, rules left out are linted as they
+// run, so an empty body lints the running rule set. Nothing is saved
+func (h *adminHandler) LintParserRules(c *gin.Context) {
+	var candidate entity.RuleSet
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&candidate); err != nil {
+			log.Errorf("failed to bind rule set", log.E(err))
+			h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+			return
+		}
+	}
+	h.reports.ReportResponse(c, http.StatusOK, h.rules.WithOverrides(&candidate).Lint())
+}
+
+func (h *adminHandler) CatalogGaps(c *gin.Context) {
+	if h.catalogGaps == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, gin.H{"gaps": h.catalogGaps.Report()})
+}
+
+// reads an unknown texture as a known one from the next batch on and drops
+// its gap
+func (h *adminHandler) AddCatalogTextureAlias(c *gin.Context) {
+	if h.catalogGaps == nil || h.runtimeRules == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	var request struct {
+		Alias   string               `json:"alias" binding:"required"`
+		Texture value_object.Texture `json:"texture" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("failed to bind texture alias", log.E(err))
+		h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+		return
+	}
+	if err := h.runtimeRules.AddTextureAlias(request.Alias, request.Texture); err != nil {
+		h.reports.ErrorResponse(c, err)
+		return
+	}
+	h.catalogGaps.Resolve(entity.CatalogGapTexture, strings.ToUpper(strings.TrimSpace(request.Alias)))
+	h.reports.ReportResponse(c, http.StatusCreated, h.runtimeRules.TextureAliases())
+}
+
+// drops a gap once the catalog has it
+func (h *adminHandler) ResolveCatalogGap(c *gin.Context) {
+	if h.catalogGaps == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	kind := entity.CatalogGapKind(c.Param("kind"))
+	if !kind.IsValid() {
+		log.Errorf("unknown catalog gap kind", log.S("kind", string(kind)))
+		h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+		return
+	}
+	if !h.catalogGaps.Resolve(kind, c.Param("value")) {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.StatusResponse(c, http.StatusNoContent)
+}
+
+// ?status=failed lists the deliveries that have not gone through
+func (h *adminHandler) WebhookDeliveries(c *gin.Context) {
+	if h.webhook == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	status := c.Query("status")
+	if status != "" && status != "failed" {
+		log.Errorf("unknown delivery status filter", log.S("status", status))
+		h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+		return
+	}
+	h.reports.ReportResponse(c, http.StatusOK, gin.H{"deliveries": h.webhook.DeliveryReport(status == "failed")})
+}
+
+func (h *adminHandler) RedeliverWebhook(c *gin.Context) {
+	if h.webhook == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	if err := h.webhook.Redeliver(c.Param("id")); err != nil {
+		h.reports.ErrorResponse(c, err)
+		return
+	}
+	h.reports.StatusResponse(c, http.StatusAccepted)
+}
+
+// creates or replaces the article of a code
+func (h *adminHandler) PutErrorArticle(c *gin.Context) {
+	if h.errorArticles == nil {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	var article entity.ErrorArticle
+	if err := c.ShouldBindJSON(&article); err != nil {
+		log.Errorf("failed to bind error article", log.E(err))
+		h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+		return
+	}
+	if article.Code != "" && article.Code != c.Param("code") {
+		log.Errorf("error article code does not match its path", log.S("code", article.Code))
+		h.reports.ErrorResponse(c, errors.ErrInvalidInput)
+		return
+	}
+	article.Code = c.Param("code")
+
+	stored, created, err := h.errorArticles.Put(&article)
+	if err != nil {
+		h.reports.ErrorResponse(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	h.reports.ReportResponse(c, status, stored)
+}
+
+func (h *adminHandler) DeleteErrorArticle(c *gin.Context) {
+	if h.errorArticles == nil || !h.errorArticles.Delete(c.Param("code")) {
+		h.reports.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.reports.StatusResponse(c, http.StatusNoContent)
+}
@@ -0,0 +1,291 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order-placement-system/internal/adapter/handler"
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	mockPresenters "order-placement-system/internal/mock/presenter"
+	mockUsecases "order-placement-system/internal/mock/usecases"
+	errs "order-placement-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminHandler_VerifyConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	report := &entity.VerificationReport{Passed: true}
+
+	tests := []struct {
+		name      string
+		body      string
+		tenantId  string
+		setupMock func(*mockUsecases.ConfigVerifier, *mockPresenters.OrderPresenter)
+	}{
+		{
+			name: "Empty body runs the canonical cases only",
+			body: "",
+			setupMock: func(v *mockUsecases.ConfigVerifier, p *mockPresenters.OrderPresenter) {
+				v.On("Verify", []*entity.VerificationCase{}, (*entity.ProcessOptions)(nil)).Return(report)
+				p.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), report).Return()
+			},
+		},
+		{
+			name: "Samples are run for the tenant",
+			body: `{"samples":[{"name":"s1","input":[{"no":1,"platformProductId":"FG0A-CLEAR-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}],` +
+				`"expected":[{"no":1,"productId":"FG0A-CLEAR-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]}]}`,
+			tenantId: "acme",
+			setupMock: func(v *mockUsecases.ConfigVerifier, p *mockPresenters.OrderPresenter) {
+				v.On("Verify", mock.MatchedBy(func(samples []*entity.VerificationCase) bool {
+					return len(samples) == 1 && samples[0].Name == "s1" &&
+						samples[0].Input[0].PlatformProductId == "FG0A-CLEAR-OPPOA3" &&
+						samples[0].Expected[0].TotalPrice.Amount() == 50
+				}), &entity.ProcessOptions{TenantId: "acme"}).Return(report)
+				p.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), report).Return()
+			},
+		},
+		{
+			name: "Sample without input is rejected",
+			body: `{"samples":[{"name":"s1","input":[]}]}`,
+			setupMock: func(v *mockUsecases.ConfigVerifier, p *mockPresenters.OrderPresenter) {
+				p.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVerifier := new(mockUsecases.ConfigVerifier)
+			mockPresenter := new(mockPresenters.OrderPresenter)
+			tt.setupMock(mockVerifier, mockPresenter)
+
+			h := handler.NewAdminHandler(mockVerifier, mockPresenter, nil, handler.AdminHandlerDeps{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/config/verify", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.tenantId != "" {
+				c.Request.Header.Set(model.TenantIdHeader, tt.tenantId)
+			}
+			// as the admin authorization does
+			c.Set(model.TenantVerifiedContextKey, true)
+
+			h.VerifyConfig(c)
+
+			mockVerifier.AssertExpectations(t)
+			mockPresenter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_BatchUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	report := map[string]int{"batches": 3}
+
+	tests := []struct {
+		name      string
+		query     string
+		setupMock func(*mockUsecases.HeaviestReportSource, *mockPresenters.ReportPresenter)
+	}{
+		{
+			name:  "Ten heaviest by default",
+			query: "",
+			setupMock: func(s *mockUsecases.HeaviestReportSource, p *mockPresenters.ReportPresenter) {
+				s.On("HeaviestReport", 10).Return(report)
+				p.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, report).Return()
+			},
+		},
+		{
+			name:  "Top is passed on",
+			query: "?top=200",
+			setupMock: func(s *mockUsecases.HeaviestReportSource, p *mockPresenters.ReportPresenter) {
+				s.On("HeaviestReport", 200).Return(report)
+				p.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, report).Return()
+			},
+		},
+		{
+			name:  "Zero top is rejected",
+			query: "?top=0",
+			setupMock: func(s *mockUsecases.HeaviestReportSource, p *mockPresenters.ReportPresenter) {
+				p.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := mockUsecases.NewHeaviestReportSource(t)
+			reports := mockPresenters.NewReportPresenter(t)
+			tt.setupMock(source, reports)
+
+			h := handler.NewAdminHandler(nil, nil, reports, handler.AdminHandlerDeps{BatchUsage: source})
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/batch-usage"+tt.query, nil)
+
+			h.BatchUsage(c)
+		})
+	}
+}
+
+func TestAdminHandler_Clients(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	source := mockUsecases.NewTenantReportSource(t)
+	reports := mockPresenters.NewReportPresenter(t)
+	source.On("TenantReport", "acme").Return([]string{"shopify"})
+	reports.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, gin.H{"clients": []string{"shopify"}}).Return()
+
+	h := handler.NewAdminHandler(nil, nil, reports, handler.AdminHandlerDeps{Clients: source})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/clients", nil)
+	c.Set(model.AdminTenantContextKey, "acme")
+
+	h.Clients(c)
+}
+
+func TestAdminHandler_ResolveCatalogGap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		kind      string
+		setupMock func(*mockUsecases.CatalogGapReport, *mockPresenters.ReportPresenter)
+	}{
+		{
+			name: "Gap is dropped",
+			kind: "film-type",
+			setupMock: func(g *mockUsecases.CatalogGapReport, p *mockPresenters.ReportPresenter) {
+				g.On("Resolve", entity.CatalogGapFilmType, "XX0A").Return(true)
+				p.On("StatusResponse", mock.AnythingOfType("*gin.Context"), http.StatusNoContent).Return()
+			},
+		},
+		{
+			name: "Unknown gap",
+			kind: "film-type",
+			setupMock: func(g *mockUsecases.CatalogGapReport, p *mockPresenters.ReportPresenter) {
+				g.On("Resolve", entity.CatalogGapFilmType, "XX0A").Return(false)
+				p.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrNotFound).Return()
+			},
+		},
+		{
+			name: "Unknown kind",
+			kind: "model",
+			setupMock: func(g *mockUsecases.CatalogGapReport, p *mockPresenters.ReportPresenter) {
+				p.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gaps := mockUsecases.NewCatalogGapReport(t)
+			reports := mockPresenters.NewReportPresenter(t)
+			tt.setupMock(gaps, reports)
+
+			h := handler.NewAdminHandler(nil, nil, reports, handler.AdminHandlerDeps{CatalogGaps: gaps})
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/admin/catalog-gaps/"+tt.kind+"/XX0A", nil)
+			c.Params = gin.Params{{Key: "kind", Value: tt.kind}, {Key: "value", Value: "XX0A"}}
+
+			h.ResolveCatalogGap(c)
+		})
+	}
+}
+
+func TestAdminHandler_WithoutWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reports := mockPresenters.NewReportPresenter(t)
+	reports.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrNotFound).Return().Twice()
+
+	h := handler.NewAdminHandler(nil, nil, reports, handler.AdminHandlerDeps{})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/webhook/deliveries", nil)
+	h.WebhookDeliveries(c)
+	h.RedeliverWebhook(c)
+}
+
+func TestAdminHandler_SetTexturePriorities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	priorities := value_object.TexturePriorities{"CLEAR": 3, "MATTE": 1, "PRIVACY": 2}
+	rules := mockUsecases.NewRuntimeRules(t)
+	reports := mockPresenters.NewReportPresenter(t)
+	rules.On("SetTexturePriorities", priorities).Return(nil)
+	rules.On("TexturePriorities").Return(priorities)
+	reports.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, priorities).Return()
+
+	h := handler.NewAdminHandler(nil, nil, reports, handler.AdminHandlerDeps{RuntimeRules: rules})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/admin/texture-priorities", bytes.NewBufferString(`{"CLEAR":3,"MATTE":1,"PRIVACY":2}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.SetTexturePriorities(c)
+}
+
+func TestAdminHandler_PutErrorArticle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stored := &entity.ErrorArticle{Code: "BUNDLE_TOO_LARGE", Title: "Bundle too large"}
+
+	tests := []struct {
+		name      string
+		body      string
+		setupMock func(*mockUsecases.ErrorArticleStore, *mockPresenters.ReportPresenter)
+	}{
+		{
+			name: "Created",
+			body: `{"title":"Bundle too large"}`,
+			setupMock: func(a *mockUsecases.ErrorArticleStore, p *mockPresenters.ReportPresenter) {
+				a.On("Put", &entity.ErrorArticle{Code: "BUNDLE_TOO_LARGE", Title: "Bundle too large"}).Return(stored, true, nil)
+				p.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusCreated, stored).Return()
+			},
+		},
+		{
+			name: "Replaced",
+			body: `{"code":"BUNDLE_TOO_LARGE","title":"Bundle too large"}`,
+			setupMock: func(a *mockUsecases.ErrorArticleStore, p *mockPresenters.ReportPresenter) {
+				a.On("Put", mock.Anything).Return(stored, false, nil)
+				p.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, stored).Return()
+			},
+		},
+		{
+			name: "Code differs from path",
+			body: `{"code":"OTHER","title":"Other"}`,
+			setupMock: func(a *mockUsecases.ErrorArticleStore, p *mockPresenters.ReportPresenter) {
+				p.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := mockUsecases.NewErrorArticleStore(t)
+			reports := mockPresenters.NewReportPresenter(t)
+			tt.setupMock(articles, reports)
+
+			h := handler.NewAdminHandler(nil, nil, reports, handler.AdminHandlerDeps{ErrorArticles: articles})
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "code", Value: "BUNDLE_TOO_LARGE"}}
+
+			h.PutErrorArticle(c)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/log"
+)
+
+// APIMajorVersion is bumped with every change that breaks clients of the
+// public models: a removed or renamed field, or a field of another JSON type
+const APIMajorVersion = 1
+
+// the public models as of the last release, regenerate with
+// go test ./internal/adapter/handler/model -run TestAPIContract -update
+//
+//go:embed api_contract.json
+var apiContractJson []byte
+
+// ErrorResponse is the body of every error answer
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// APIContract is the JSON shape of the public models: model name to field
+// path to JSON type, e.g. "CleanedOrder" -> "attribution.ruleId" -> "string".
+// Array elements are "[]", e.g. "components[].qty"
+type APIContract struct {
+	Version int                          `json:"version"`
+	Models  map[string]map[string]string `json:"models"`
+}
+
+// types whose JSON is not what their Go kind says
+var contractTypes = map[reflect.Type]string{
+	reflect.TypeOf(value_object.Price{}): "number",
+	reflect.TypeOf(Amount{}):             "number",
+	reflect.TypeOf(time.Time{}):          "string",
+}
+
+func CurrentAPIContract() *APIContract {
+	models := map[string]reflect.Type{
+		"InputOrder":    reflect.TypeOf(InputOrder{}),
+		"CleanedOrder":  reflect.TypeOf(CleanedOrder{}),
+		"ResponseMeta":  reflect.TypeOf(ResponseMeta{}),
+		"ErrorResponse": reflect.TypeOf(ErrorResponse{}),
+	}
+
+	contract := &APIContract{Version: APIMajorVersion, Models: make(map[string]map[string]string)}
+	for name, t := range models {
+		fields := make(map[string]string)
+		describeStruct(t, "", fields)
+		contract.Models[name] = fields
+	}
+	return contract
+}
+
+// SnapshotAPIContract is the contract the last release shipped with
+func SnapshotAPIContract() (*APIContract, error) {
+	var snapshot APIContract
+	if err := json.Unmarshal(apiContractJson, &snapshot); err != nil {
+		log.Errorf("failed to load api contract snapshot", log.E(err))
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// BreakingChanges lists what in current breaks clients written against
+// previous: removed models and fields and fields of another type. Added ones
+// are compatible
+func BreakingChanges(previous, current *APIContract) []string {
+	var changes []string
+	for _, model := range sortedContractKeys(previous.Models) {
+		fields, ok := current.Models[model]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s was removed", model))
+			continue
+		}
+
+		for _, path := range sortedContractKeys(previous.Models[model]) {
+			was := previous.Models[model][path]
+			is, ok := fields[path]
+			switch {
+			case !ok:
+				changes = append(changes, fmt.Sprintf("%s.%s was removed", model, path))
+			case is != was:
+				changes = append(changes, fmt.Sprintf("%s.%s changed from %s to %s", model, path, was, is))
+			}
+		}
+	}
+	return changes
+}
+
+// CheckAPIContract fails when the models break the snapshot and
+// APIMajorVersion was not bumped past it
+func CheckAPIContract() error {
+	snapshot, err := SnapshotAPIContract()
+	if err != nil {
+		return err
+	}
+	if APIMajorVersion > snapshot.Version {
+		return nil
+	}
+
+	if changes := BreakingChanges(snapshot, CurrentAPIContract()); len(changes) > 0 {
+		return fmt.Errorf("breaking api changes without a major version bump: %s", strings.Join(changes, "; "))
+	}
+	return nil
+}
+
+func describeStruct(t reflect.Type, prefix string, fields map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			describeStruct(indirect(field.Type), prefix, fields)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		describeField(field.Type, prefix+name, fields)
+	}
+}
+
+func describeField(t reflect.Type, path string, fields map[string]string) {
+	t = indirect(t)
+	if jsonType, ok := contractTypes[t]; ok {
+		fields[path] = jsonType
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		fields[path] = "string"
+	case reflect.Bool:
+		fields[path] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fields[path] = "integer"
+	case reflect.Float32, reflect.Float64:
+		fields[path] = "number"
+	case reflect.Slice, reflect.Array:
+		fields[path] = "array"
+		describeField(t.Elem(), path+"[]", fields)
+	case reflect.Map:
+		fields[path] = "object"
+	case reflect.Struct:
+		fields[path] = "object"
+		describeStruct(t, path+".", fields)
+	default:
+		fields[path] = "any"
+	}
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func sortedContractKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
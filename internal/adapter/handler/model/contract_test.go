@@ -0,0 +1,100 @@
+package model_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"order-placement-system/internal/adapter/handler/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateContract = flag.Bool("update", false, "rewrite api_contract.json from the current models")
+
+func TestAPIContract(t *testing.T) {
+	current := model.CurrentAPIContract()
+
+	if *updateContract {
+		snapshot, err := model.SnapshotAPIContract()
+		require.NoError(t, err)
+		if snapshot.Version >= model.APIMajorVersion {
+			require.Empty(t, model.BreakingChanges(snapshot, current), "bump APIMajorVersion for a breaking change")
+		}
+
+		data, err := json.MarshalIndent(current, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile("api_contract.json", append(data, '\n'), 0o644))
+		// the test binary still embeds the old snapshot
+		return
+	}
+
+	require.NoError(t, model.CheckAPIContract())
+
+	snapshot, err := model.SnapshotAPIContract()
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, current, "the models changed, run this test with -update and commit api_contract.json")
+}
+
+func TestBreakingChanges(t *testing.T) {
+	previous := &model.APIContract{Version: 1, Models: map[string]map[string]string{
+		"CleanedOrder": {"no": "integer", "productId": "string", "qty": "integer"},
+		"RowError":     {"no": "integer"},
+	}}
+
+	tests := []struct {
+		name          string
+		current       map[string]map[string]string
+		expectChanges []string
+	}{
+		{
+			"Unchanged",
+			previous.Models,
+			nil,
+		},
+		{
+			"Field added",
+			map[string]map[string]string{
+				"CleanedOrder": {"no": "integer", "productId": "string", "qty": "integer", "sku": "string"},
+				"RowError":     {"no": "integer"},
+			},
+			nil,
+		},
+		{
+			"Field renamed",
+			map[string]map[string]string{
+				"CleanedOrder": {"no": "integer", "productID": "string", "qty": "integer"},
+				"RowError":     {"no": "integer"},
+			},
+			[]string{"CleanedOrder.productId was removed"},
+		},
+		{
+			"Type changed and model removed",
+			map[string]map[string]string{
+				"CleanedOrder": {"no": "integer", "productId": "string", "qty": "string"},
+			},
+			[]string{"CleanedOrder.qty changed from integer to string", "RowError was removed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := model.BreakingChanges(previous, &model.APIContract{Version: 1, Models: tt.current})
+			assert.Equal(t, tt.expectChanges, changes)
+		})
+	}
+}
+
+func TestCurrentAPIContract(t *testing.T) {
+	contract := model.CurrentAPIContract()
+
+	assert.Equal(t, model.APIMajorVersion, contract.Version)
+	assert.Equal(t, "number", contract.Models["InputOrder"]["unitPrice"], "amounts are numbers")
+	assert.Equal(t, "number", contract.Models["CleanedOrder"]["totalPrice"], "prices marshal as numbers")
+	assert.Equal(t, "array", contract.Models["CleanedOrder"]["components"])
+	assert.Equal(t, "integer", contract.Models["CleanedOrder"]["components[].qty"])
+	assert.Equal(t, "string", contract.Models["CleanedOrder"]["attribution.ruleId"])
+	assert.Equal(t, map[string]string{"error": "string"}, contract.Models["ErrorResponse"])
+}
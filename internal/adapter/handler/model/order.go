@@ -1,6 +1,11 @@
 package model
 
 import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
 	"order-placement-system/internal/domain/entity"
 	"order-placement-system/internal/domain/value_object"
 	"order-placement-system/pkg/errors"
@@ -9,22 +14,174 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Amount is a price sent as a JSON number or a decimal string ("19.99"). It is
+// read from its decimal text, so it reaches value_object.Price without going
+// through float64, and remembers which of the two it was sent as
+type Amount struct {
+	// exact and without trailing zeros, empty for zero
+	decimal string
+	format  value_object.AmountFormat
+}
+
 type InputOrder struct {
-	No                int     `json:"no" binding:"required,min=1"`
-	PlatformProductId string  `json:"platformProductId" binding:"required"`
-	Qty               int     `json:"qty" binding:"required,min=1"`
-	UnitPrice         float64 `json:"unitPrice" binding:"required,min=0"`
-	TotalPrice        float64 `json:"totalPrice" binding:"required,min=0"`
+	No                int    `json:"no" binding:"required,min=1"`
+	PlatformProductId string `json:"platformProductId" binding:"required"`
+	Qty               int    `json:"qty" binding:"required,min=1"`
+	UnitPrice         Amount `json:"unitPrice"`
+	TotalPrice        Amount `json:"totalPrice"`
+	ExternalRef       string `json:"externalRef,omitempty" binding:"max=128"`
+	Channel           string `json:"channel,omitempty" binding:"max=64"`
+	Discount          Amount `json:"discount,omitempty"`
+	Surcharge         Amount `json:"surcharge,omitempty"`
+}
+
+// NewAmount is an amount sent as a JSON number
+func NewAmount(amount float64) Amount {
+	return Amount{decimal: strconv.FormatFloat(amount, 'f', -1, 64), format: value_object.AmountFormatNumber}
+}
+
+// e.g. 19.99, 0 when the amount was not sent
+func (a Amount) Decimal() string {
+	if a.decimal == "" {
+		return "0"
+	}
+	return a.decimal
+}
+
+// the JSON type the amount was sent as, empty when it was not sent
+func (a Amount) Format() value_object.AmountFormat {
+	return a.format
+}
+
+func (a Amount) IsZero() bool {
+	return a.Decimal() == "0"
+}
+
+func (a Amount) IsNegative() bool {
+	return strings.HasPrefix(a.decimal, "-")
+}
+
+func (a Amount) ToPrice() (*value_object.Price, error) {
+	return value_object.ParsePrice(a.Decimal())
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	price, format, err := value_object.ParseJSONPrice(data)
+	if err != nil {
+		return err
+	}
+
+	*a = Amount{decimal: price.Decimal(), format: format}
+	return nil
+}
+
+// always a JSON number, the exact decimal
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.Decimal()), nil
 }
 
 type CleanedOrder struct {
-	No         int                 `json:"no"`
-	ProductId  string              `json:"productId"`
-	MaterialId string              `json:"materialId,omitempty"`
-	ModelId    string              `json:"modelId,omitempty"`
-	Qty        int                 `json:"qty"`
-	UnitPrice  *value_object.Price `json:"unitPrice"`
-	TotalPrice *value_object.Price `json:"totalPrice"`
+	No            int                 `json:"no"`
+	ProductId     string              `json:"productId"`
+	MaterialId    string              `json:"materialId,omitempty"`
+	ModelId       string              `json:"modelId,omitempty"`
+	Qty           int                 `json:"qty"`
+	UnitPrice     *value_object.Price `json:"unitPrice"`
+	TotalPrice    *value_object.Price `json:"totalPrice"`
+	IsAccessory   bool                `json:"isAccessory,omitempty"`
+	ParentNo      int                 `json:"parentNo,omitempty"`
+	PriceEnriched bool                `json:"priceEnriched,omitempty"`
+	ExternalRef   string              `json:"externalRef,omitempty"`
+	NamespacedNo  string              `json:"namespacedNo,omitempty"`
+
+	Components []*entity.KitComponent `json:"components,omitempty"`
+	Customs    *entity.CustomsInfo    `json:"customs,omitempty"`
+
+	SubstitutedFor string              `json:"substitutedFor,omitempty"`
+	Attribution    *entity.Attribution `json:"attribution,omitempty"`
+	ShippingWeight int                 `json:"shippingWeight,omitempty"`
+
+	GrossUnitPrice  *value_object.Price `json:"grossUnitPrice,omitempty"`
+	GrossTotalPrice *value_object.Price `json:"grossTotalPrice,omitempty"`
+
+	CappedQty int    `json:"cappedQty,omitempty"`
+	Variant   string `json:"variant,omitempty"`
+}
+
+// the batch warnings of the response are kept on the gin context under this
+// key, for middlewares that count them
+const BatchWarningsContextKey = "batchWarnings"
+
+// the client that sent the batch is kept on the gin context under this key by
+// the middleware that tracks clients
+const BatchClientContextKey = "batchClient"
+
+// the fingerprint of the API key the order authentication accepted is kept on
+// the gin context under this key
+const ApiKeyIdContextKey = "apiKeyId"
+
+// the tenant a tenant-admin's request is limited to is kept on the gin
+// context under this key by the admin authorization
+const AdminTenantContextKey = "adminTenant"
+
+// AdminTenant is the only tenant the admin request may see, empty when it is
+// not limited to one
+func AdminTenant(c *gin.Context) string {
+	return c.GetString(AdminTenantContextKey)
+}
+
+// set on the gin context under this key by the middlewares that authenticate
+// the caller once the X-Tenant-Id header is bound to its key
+const TenantVerifiedContextKey = "tenantVerified"
+
+// BatchClient is who sent a batch. ApiKeyId is a fingerprint of the API key
+// the request was authenticated with, never the key itself
+type BatchClient struct {
+	Client     string `json:"client,omitempty"`
+	SDKVersion string `json:"sdkVersion,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	SourceIP   string `json:"sourceIp,omitempty"`
+	ApiKeyId   string `json:"apiKeyId,omitempty"`
+}
+
+// nil when no middleware recorded the client. The key is read when this is
+// called, so after the request was authenticated
+func BatchClientFrom(c *gin.Context) *BatchClient {
+	recorded, ok := c.Get(BatchClientContextKey)
+	if !ok {
+		return nil
+	}
+	client := *recorded.(*BatchClient)
+	client.ApiKeyId = c.GetString(ApiKeyIdContextKey)
+	return &client
+}
+
+type ResponseMeta struct {
+	Cached    bool            `json:"cached,omitempty"`
+	Warnings  []*BatchWarning `json:"warnings,omitempty"`
+	RowErrors []*RowError     `json:"rowErrors,omitempty"`
+
+	ShippingWeight *entity.ShippingWeight `json:"shippingWeight,omitempty"`
+	// only with ?usage=true
+	Usage *entity.BatchUsage `json:"usage,omitempty"`
+
+	Client *BatchClient `json:"client,omitempty"`
+}
+
+// RowError is an input row dropped from the batch
+type RowError struct {
+	No       int             `json:"no"`
+	Error    string          `json:"error"`
+	Category errors.Category `json:"category"`
+	// TIMEOUT for a row out of processing time, dropped in every mode
+	Code string `json:"code,omitempty"`
+}
+
+type BatchWarning struct {
+	Code      string  `json:"code"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Lines     []int   `json:"lines,omitempty"`
 }
 
 func (o *InputOrder) Parse(c *gin.Context) ([]*InputOrder, error) {
@@ -43,24 +200,108 @@ func (o *InputOrder) Parse(c *gin.Context) ([]*InputOrder, error) {
 	return orders, nil
 }
 
+// OrderBatch is the object form of a batch, for clients that send response
+// options along with the orders
+type OrderBatch struct {
+	Orders []*InputOrder `json:"orders" binding:"required,min=1,dive,required"`
+	// same as ?fields, the two cannot be combined
+	Fields []string `json:"fields,omitempty"`
+}
+
+// a batch sent as a bare array of orders, read by Parse, or as an OrderBatch
+func ParseOrderBatch(c *gin.Context) (*OrderBatch, error) {
+	data, err := c.GetRawData()
+	if err != nil {
+		log.Errorf("failed to read request body", log.E(err))
+		return nil, errors.ErrInvalidInput
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		orders, err := new(InputOrder).Parse(c)
+		if err != nil {
+			return nil, err
+		}
+		return &OrderBatch{Orders: orders}, nil
+	}
+
+	var batch OrderBatch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		log.Errorf("failed to bind JSON", log.E(err))
+		return nil, errors.ErrInvalidInput
+	}
+
+	return &batch, nil
+}
+
+// a batch of cleaned lines, e.g. to renumber it. Bound by value as gin's
+// validator panics on null elements of a pointer slice, a null line comes
+// back empty and fails validation later
+func ParseCleanedOrders(c *gin.Context) ([]*CleanedOrder, error) {
+	var orders []CleanedOrder
+
+	if err := c.ShouldBindJSON(&orders); err != nil {
+		log.Errorf("failed to bind JSON", log.E(err))
+		return nil, errors.ErrInvalidInput
+	}
+
+	if len(orders) == 0 {
+		log.Error("empty orders array")
+		return nil, errors.ErrInvalidInput
+	}
+
+	lines := make([]*CleanedOrder, len(orders))
+	for i := range orders {
+		lines[i] = &orders[i]
+	}
+	return lines, nil
+}
+
+// single order object instead of an array, binding rules are the same as Parse
+func (o *InputOrder) ParseSingle(c *gin.Context) ([]*InputOrder, error) {
+	var order InputOrder
+
+	if err := c.ShouldBindJSON(&order); err != nil {
+		log.Errorf("failed to bind JSON", log.E(err))
+		return nil, errors.ErrInvalidInput
+	}
+
+	return []*InputOrder{&order}, nil
+}
+
 func (o *InputOrder) ToEntity() (*entity.InputOrder, error) {
-	unitPrice, err := value_object.NewPrice(o.UnitPrice)
+	unitPrice, err := o.UnitPrice.ToPrice()
 	if err != nil {
 		return nil, errors.ErrInvalidInput
 	}
 
-	totalPrice, err := value_object.NewPrice(o.TotalPrice)
+	totalPrice, err := o.TotalPrice.ToPrice()
 	if err != nil {
 		return nil, errors.ErrInvalidInput
 	}
 
-	return &entity.InputOrder{
+	inputOrder := &entity.InputOrder{
 		No:                o.No,
 		PlatformProductId: o.PlatformProductId,
 		Qty:               o.Qty,
 		UnitPrice:         unitPrice,
 		TotalPrice:        totalPrice,
-	}, nil
+		ExternalRef:       o.ExternalRef,
+		Channel:           o.Channel,
+	}
+
+	if !o.Discount.IsZero() {
+		if inputOrder.Discount, err = o.Discount.ToPrice(); err != nil {
+			return nil, errors.ErrInvalidInput
+		}
+	}
+	if !o.Surcharge.IsZero() {
+		if inputOrder.Surcharge, err = o.Surcharge.ToPrice(); err != nil {
+			return nil, errors.ErrInvalidInput
+		}
+	}
+
+	return inputOrder, nil
 }
 
 func ToEntity(models []*InputOrder) ([]*entity.InputOrder, error) {
@@ -75,15 +316,76 @@ func ToEntity(models []*InputOrder) ([]*entity.InputOrder, error) {
 	return entities, nil
 }
 
+// CheckAmountFormats rejects a batch with an amount sent as a JSON type
+// allowed does not allow, amounts that were not sent are not checked
+func CheckAmountFormats(models []*InputOrder, allowed value_object.AmountFormat) error {
+	for _, model := range models {
+		for _, amount := range []Amount{model.UnitPrice, model.TotalPrice, model.Discount, model.Surcharge} {
+			if amount.Format() != "" && !allowed.Allows(amount.Format()) {
+				log.Errorf("amount sent as a JSON type that is not allowed",
+					log.AtoS("no", model.No), log.S("sent", string(amount.Format())), log.S("allowed", string(allowed)))
+				return errors.ErrInvalidInput
+			}
+		}
+	}
+	return nil
+}
+
 func FromEntity(e *entity.CleanedOrder) *CleanedOrder {
 	return &CleanedOrder{
-		No:         e.No,
-		ProductId:  e.ProductId,
-		MaterialId: e.MaterialId,
-		ModelId:    e.ModelId,
-		Qty:        e.Qty,
-		UnitPrice:  e.UnitPrice,
-		TotalPrice: e.TotalPrice,
+		No:            e.No,
+		ProductId:     e.ProductId,
+		MaterialId:    e.MaterialId,
+		ModelId:       e.ModelId,
+		Qty:           e.Qty,
+		UnitPrice:     e.UnitPrice,
+		TotalPrice:    e.TotalPrice,
+		IsAccessory:   e.IsAccessory,
+		ParentNo:      e.ParentNo,
+		PriceEnriched: e.PriceEnriched,
+		ExternalRef:   e.ExternalRef,
+		NamespacedNo:  e.NamespacedNo,
+		Components:    e.Components,
+		Customs:       e.Customs,
+
+		SubstitutedFor: e.SubstitutedFor,
+		Attribution:    e.Attribution,
+		ShippingWeight: e.ShippingWeight,
+
+		GrossUnitPrice:  e.GrossUnitPrice,
+		GrossTotalPrice: e.GrossTotalPrice,
+
+		CappedQty: e.CappedQty,
+		Variant:   e.Variant,
+	}
+}
+
+func (o *CleanedOrder) ToEntity() *entity.CleanedOrder {
+	return &entity.CleanedOrder{
+		No:            o.No,
+		ProductId:     o.ProductId,
+		MaterialId:    o.MaterialId,
+		ModelId:       o.ModelId,
+		Qty:           o.Qty,
+		UnitPrice:     o.UnitPrice,
+		TotalPrice:    o.TotalPrice,
+		IsAccessory:   o.IsAccessory,
+		ParentNo:      o.ParentNo,
+		PriceEnriched: o.PriceEnriched,
+		ExternalRef:   o.ExternalRef,
+		NamespacedNo:  o.NamespacedNo,
+		Components:    o.Components,
+		Customs:       o.Customs,
+
+		SubstitutedFor: o.SubstitutedFor,
+		Attribution:    o.Attribution,
+		ShippingWeight: o.ShippingWeight,
+
+		GrossUnitPrice:  o.GrossUnitPrice,
+		GrossTotalPrice: o.GrossTotalPrice,
+
+		CappedQty: o.CappedQty,
+		Variant:   o.Variant,
 	}
 }
 
@@ -108,13 +410,39 @@ func (o *InputOrder) Validate() error {
 		return errors.ErrInvalidInput
 	}
 
-	if o.UnitPrice < 0 {
+	if o.UnitPrice.IsNegative() {
 		return errors.ErrInvalidInput
 	}
 
-	if o.TotalPrice < 0 {
+	if o.TotalPrice.IsNegative() {
 		return errors.ErrInvalidInput
 	}
 
 	return nil
 }
+
+func FromBatchWarnings(entities []*entity.BatchWarning) []*BatchWarning {
+	models := make([]*BatchWarning, len(entities))
+	for i, e := range entities {
+		models[i] = &BatchWarning{
+			Code:      string(e.Code),
+			Value:     e.Value,
+			Threshold: e.Threshold,
+			Lines:     e.Lines,
+		}
+	}
+	return models
+}
+
+func FromRowErrors(rows []*errors.RowError) []*RowError {
+	models := make([]*RowError, len(rows))
+	for i, row := range rows {
+		models[i] = &RowError{
+			No:       row.No,
+			Error:    row.Err.Error(),
+			Category: row.Category(),
+			Code:     row.Code(),
+		}
+	}
+	return models
+}
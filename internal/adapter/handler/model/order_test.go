@@ -47,8 +47,8 @@ func TestInputOrder_Parse(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 			},
 		},
@@ -76,15 +76,15 @@ func TestInputOrder_Parse(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 				{
 					No:                2,
 					PlatformProductId: "FG0A-MATTE-OPPOA3",
 					Qty:               1,
-					UnitPrice:         40.0,
-					TotalPrice:        40.0,
+					UnitPrice:         model.NewAmount(40.0),
+					TotalPrice:        model.NewAmount(40.0),
 				},
 			},
 		},
@@ -202,6 +202,99 @@ func TestInputOrder_Parse(t *testing.T) {
 	}
 }
 
+func TestParseOrderBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		expectedOrders int
+		expectedFields []string
+		expectError    bool
+	}{
+		{"Bare array", `[{"no":1,"platformProductId":"FG0A-CLEAR-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]`, 1, nil, false},
+		{"Object with fields", ` {"orders":[{"no":1,"platformProductId":"FG0A-CLEAR-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}],"fields":["no","qty"]}`, 1, []string{"no", "qty"}, false},
+		{"Object without orders", `{"fields":["no"]}`, 0, nil, true},
+		{"Object with empty orders", `{"orders":[]}`, 0, nil, true},
+		{"Object with null order", `{"orders":[null]}`, 0, nil, true},
+		{"Object with invalid order", `{"orders":[{"no":0,"platformProductId":"FG0A-CLEAR-OPPOA3","qty":1}]}`, 0, nil, true},
+		{"Empty array", `[]`, 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			batch, err := model.ParseOrderBatch(c)
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, errors.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, batch.Orders, tt.expectedOrders)
+			assert.Equal(t, tt.expectedFields, batch.Fields)
+		})
+	}
+}
+
+func TestInputOrder_ParseSingle(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestBody string
+		expectError bool
+	}{
+		{
+			name:        "Valid single order object",
+			requestBody: `{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2,"unitPrice":50.0,"totalPrice":100.0}`,
+			expectError: false,
+		},
+		{
+			name:        "Array is rejected",
+			requestBody: `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2,"unitPrice":50.0,"totalPrice":100.0}]`,
+			expectError: true,
+		},
+		{
+			name:        "Missing platformProductId",
+			requestBody: `{"no":1,"qty":2,"unitPrice":50.0,"totalPrice":100.0}`,
+			expectError: true,
+		},
+		{
+			name:        "Empty body",
+			requestBody: ``,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			result, err := new(model.InputOrder).ParseSingle(c)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, errors.ErrInvalidInput, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, 1, result[0].No)
+			assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX", result[0].PlatformProductId)
+		})
+	}
+}
+
 func TestInputOrder_ToEntity(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -215,14 +308,32 @@ func TestInputOrder_ToEntity(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
+			},
+			expectError: false,
+			expected: &entity.InputOrder{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               2,
+			},
+		},
+		{
+			name: "External ref is carried over",
+			inputOrder: &model.InputOrder{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               2,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
+				ExternalRef:       "6f1c2a9e-line-1",
 			},
 			expectError: false,
 			expected: &entity.InputOrder{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
+				ExternalRef:       "6f1c2a9e-line-1",
 			},
 		},
 		{
@@ -231,8 +342,8 @@ func TestInputOrder_ToEntity(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         0.0,
-				TotalPrice:        0.0,
+				UnitPrice:         model.NewAmount(0.0),
+				TotalPrice:        model.NewAmount(0.0),
 			},
 			expectError: false,
 			expected: &entity.InputOrder{
@@ -247,8 +358,8 @@ func TestInputOrder_ToEntity(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         -50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(-50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 			expected:    nil,
@@ -259,8 +370,41 @@ func TestInputOrder_ToEntity(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        -100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(-100.0),
+			},
+			expectError: true,
+			expected:    nil,
+		},
+		{
+			name: "Discount and surcharge are carried over",
+			inputOrder: &model.InputOrder{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               2,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
+				Discount:          model.NewAmount(10.0),
+				Surcharge:         model.NewAmount(2.5),
+			},
+			expectError: false,
+			expected: &entity.InputOrder{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               2,
+				Discount:          value_object.MustNewPrice(10),
+				Surcharge:         value_object.MustNewPrice(2.5),
+			},
+		},
+		{
+			name: "Invalid discount (negative)",
+			inputOrder: &model.InputOrder{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               2,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
+				Discount:          model.NewAmount(-10.0),
 			},
 			expectError: true,
 			expected:    nil,
@@ -280,8 +424,11 @@ func TestInputOrder_ToEntity(t *testing.T) {
 				assert.Equal(t, tt.expected.No, entity.No)
 				assert.Equal(t, tt.expected.PlatformProductId, entity.PlatformProductId)
 				assert.Equal(t, tt.expected.Qty, entity.Qty)
-				assert.Equal(t, tt.inputOrder.UnitPrice, entity.UnitPrice.Amount())
-				assert.Equal(t, tt.inputOrder.TotalPrice, entity.TotalPrice.Amount())
+				assert.Equal(t, tt.expected.ExternalRef, entity.ExternalRef)
+				assert.True(t, tt.expected.Discount.Equals(entity.Discount), "%v", entity.Discount)
+				assert.True(t, tt.expected.Surcharge.Equals(entity.Surcharge), "%v", entity.Surcharge)
+				assert.Equal(t, tt.inputOrder.UnitPrice.Decimal(), entity.UnitPrice.Decimal())
+				assert.Equal(t, tt.inputOrder.TotalPrice.Decimal(), entity.TotalPrice.Decimal())
 			}
 		})
 	}
@@ -301,15 +448,15 @@ func TestToEntity(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 				{
 					No:                2,
 					PlatformProductId: "FG0A-MATTE-OPPOA3",
 					Qty:               1,
-					UnitPrice:         40.0,
-					TotalPrice:        40.0,
+					UnitPrice:         model.NewAmount(40.0),
+					TotalPrice:        model.NewAmount(40.0),
 				},
 			},
 			expectError: false,
@@ -339,15 +486,15 @@ func TestToEntity(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 				{
 					No:                2,
 					PlatformProductId: "FG0A-MATTE-OPPOA3",
 					Qty:               1,
-					UnitPrice:         -40.0, // Invalid negative price
-					TotalPrice:        40.0,
+					UnitPrice:         model.NewAmount(-40.0), // Invalid negative price
+					TotalPrice:        model.NewAmount(40.0),
 				},
 			},
 			expectError: true,
@@ -371,8 +518,8 @@ func TestToEntity(t *testing.T) {
 					assert.Equal(t, expected.No, entities[i].No)
 					assert.Equal(t, expected.PlatformProductId, entities[i].PlatformProductId)
 					assert.Equal(t, expected.Qty, entities[i].Qty)
-					assert.Equal(t, tt.models[i].UnitPrice, entities[i].UnitPrice.Amount())
-					assert.Equal(t, tt.models[i].TotalPrice, entities[i].TotalPrice.Amount())
+					assert.Equal(t, tt.models[i].UnitPrice.Decimal(), entities[i].UnitPrice.Decimal())
+					assert.Equal(t, tt.models[i].TotalPrice.Decimal(), entities[i].TotalPrice.Decimal())
 				}
 			}
 		})
@@ -427,6 +574,25 @@ func TestFromEntity(t *testing.T) {
 				TotalPrice: value_object.ZeroPrice(),
 			},
 		},
+		{
+			name: "External ref is carried over",
+			entity: &entity.CleanedOrder{
+				No:          1,
+				ProductId:   "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:         1,
+				UnitPrice:   value_object.MustNewPrice(50.0),
+				TotalPrice:  value_object.MustNewPrice(50.0),
+				ExternalRef: "6f1c2a9e-line-1",
+			},
+			expected: &model.CleanedOrder{
+				No:          1,
+				ProductId:   "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:         1,
+				UnitPrice:   value_object.MustNewPrice(50.0),
+				TotalPrice:  value_object.MustNewPrice(50.0),
+				ExternalRef: "6f1c2a9e-line-1",
+			},
+		},
 		{
 			name: "Valid cleaner product entity",
 			entity: &entity.CleanedOrder{
@@ -462,6 +628,7 @@ func TestFromEntity(t *testing.T) {
 			assert.Equal(t, tt.expected.Qty, result.Qty)
 			assert.Equal(t, tt.expected.UnitPrice.Amount(), result.UnitPrice.Amount())
 			assert.Equal(t, tt.expected.TotalPrice.Amount(), result.TotalPrice.Amount())
+			assert.Equal(t, tt.expected.ExternalRef, result.ExternalRef)
 		})
 	}
 }
@@ -554,8 +721,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: false,
 		},
@@ -565,8 +732,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         0.0,
-				TotalPrice:        0.0,
+				UnitPrice:         model.NewAmount(0.0),
+				TotalPrice:        model.NewAmount(0.0),
 			},
 			expectError: false,
 		},
@@ -576,8 +743,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                0,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 		},
@@ -587,8 +754,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                -1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 		},
@@ -598,8 +765,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 		},
@@ -609,8 +776,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               0,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 		},
@@ -620,8 +787,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               -2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 		},
@@ -631,8 +798,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         -50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(-50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 			expectError: true,
 		},
@@ -642,8 +809,8 @@ func TestInputOrder_Validate(t *testing.T) {
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        -100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(-100.0),
 			},
 			expectError: true,
 		},
@@ -669,8 +836,8 @@ func BenchmarkInputOrder_ToEntity(b *testing.B) {
 		No:                1,
 		PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 		Qty:               2,
-		UnitPrice:         50.0,
-		TotalPrice:        100.0,
+		UnitPrice:         model.NewAmount(50.0),
+		TotalPrice:        model.NewAmount(100.0),
 	}
 
 	b.ResetTimer()
@@ -704,8 +871,8 @@ func BenchmarkInputOrder_Validate(b *testing.B) {
 		No:                1,
 		PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 		Qty:               2,
-		UnitPrice:         50.0,
-		TotalPrice:        100.0,
+		UnitPrice:         model.NewAmount(50.0),
+		TotalPrice:        model.NewAmount(100.0),
 	}
 
 	b.ResetTimer()
@@ -720,8 +887,8 @@ func createTestInputOrder() *model.InputOrder {
 		No:                1,
 		PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 		Qty:               2,
-		UnitPrice:         50.0,
-		TotalPrice:        100.0,
+		UnitPrice:         model.NewAmount(50.0),
+		TotalPrice:        model.NewAmount(100.0),
 	}
 }
 
@@ -803,6 +970,58 @@ func TestInputOrder_Parse_TableDriven(t *testing.T) {
 	}
 }
 
+func TestInputOrder_Parse_Prices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name          string
+		prices        string
+		expectedUnit  string
+		expectedTotal string
+		expectError   bool
+	}{
+		{"Numeric literals", `"unitPrice":19.99,"totalPrice":39.98`, "19.99", "39.98", false},
+		{"Decimal strings", `"unitPrice":"19.99","totalPrice":"39.98"`, "19.99", "39.98", false},
+		{"Float noise is dropped", `"unitPrice":19.990000000000002,"totalPrice":"39.980000000000004"`, "19.99", "39.98", false},
+		{"Digits past float64 precision are kept", `"unitPrice":"1234567.123456789","totalPrice":2469134.246913578`, "1234567.123456789", "2469134.246913578", false},
+		{"Exponent", `"unitPrice":1.999e1,"totalPrice":"3998e-2"`, "19.99", "39.98", false},
+		{"Missing prices are zero", ``, "0", "0", false},
+		{"Non decimal string", `"unitPrice":"19,99","totalPrice":39.98`, "", "", true},
+		{"NaN string", `"unitPrice":"NaN","totalPrice":39.98`, "", "", true},
+		{"Negative string", `"unitPrice":"-1","totalPrice":39.98`, "", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body := `{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2`
+			if tc.prices != "" {
+				body += "," + tc.prices
+			}
+			body += "}"
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			result, err := (&model.InputOrder{}).ParseSingle(c)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedUnit, result[0].UnitPrice.Decimal())
+			assert.Equal(t, tc.expectedTotal, result[0].TotalPrice.Decimal())
+
+			entity, err := result[0].ToEntity()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedUnit, entity.UnitPrice.Decimal())
+			assert.Equal(t, tc.expectedTotal, entity.TotalPrice.Decimal())
+		})
+	}
+}
+
 // Edge case tests
 func TestInputOrder_EdgeCases(t *testing.T) {
 	t.Run("Very large numbers", func(t *testing.T) {
@@ -810,8 +1029,8 @@ func TestInputOrder_EdgeCases(t *testing.T) {
 			No:                1,
 			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 			Qty:               1000000,
-			UnitPrice:         9999999.99,
-			TotalPrice:        9999999990000.0,
+			UnitPrice:         model.NewAmount(9999999.99),
+			TotalPrice:        model.NewAmount(9999999990000.0),
 		}
 
 		entity, err := inputOrder.ToEntity()
@@ -825,8 +1044,8 @@ func TestInputOrder_EdgeCases(t *testing.T) {
 			No:                1,
 			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 			Qty:               1,
-			UnitPrice:         0.01,
-			TotalPrice:        0.01,
+			UnitPrice:         model.NewAmount(0.01),
+			TotalPrice:        model.NewAmount(0.01),
 		}
 
 		entity, err := inputOrder.ToEntity()
@@ -840,8 +1059,8 @@ func TestInputOrder_EdgeCases(t *testing.T) {
 			No:                1,
 			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX-SPECIAL-EDITION-LIMITED",
 			Qty:               1,
-			UnitPrice:         100.0,
-			TotalPrice:        100.0,
+			UnitPrice:         model.NewAmount(100.0),
+			TotalPrice:        model.NewAmount(100.0),
 		}
 
 		entity, err := inputOrder.ToEntity()
@@ -900,3 +1119,28 @@ func TestInputOrder_Integration(t *testing.T) {
 		assert.Equal(t, 100.0, cleanedModel.TotalPrice.Amount())
 	})
 }
+
+func TestCheckAmountFormats(t *testing.T) {
+	sentAs := func(body string) []*model.InputOrder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		orders, err := (&model.InputOrder{}).ParseSingle(c)
+		require.NoError(t, err)
+		return orders
+	}
+	numbers := sentAs(`{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2,"unitPrice":19.99,"totalPrice":39.98}`)
+	decimalStrings := sentAs(`{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2,"unitPrice":"19.99","totalPrice":"39.98"}`)
+	mixed := sentAs(`{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2,"unitPrice":"19.99","totalPrice":39.98}`)
+	none := sentAs(`{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2}`)
+
+	assert.NoError(t, model.CheckAmountFormats(mixed, ""))
+	assert.NoError(t, model.CheckAmountFormats(mixed, value_object.AmountFormatAny))
+	assert.NoError(t, model.CheckAmountFormats(decimalStrings, value_object.AmountFormatString))
+	assert.NoError(t, model.CheckAmountFormats(numbers, value_object.AmountFormatNumber))
+	assert.NoError(t, model.CheckAmountFormats(none, value_object.AmountFormatString), "amounts not sent are not checked")
+	assert.ErrorIs(t, model.CheckAmountFormats(numbers, value_object.AmountFormatString), errors.ErrInvalidInput)
+	assert.ErrorIs(t, model.CheckAmountFormats(mixed, value_object.AmountFormatNumber), errors.ErrInvalidInput)
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ComplementaryUnitQueryParam      = "complementaryUnit"
+	ComplementaryPlacementQueryParam = "complementaryPlacement"
+	ModeQueryParam                   = "mode"
+	DropTimedOutRowsQueryParam       = "dropTimedOutRows"
+	StartNoQueryParam                = "startNo"
+	NamespaceQueryParam              = "namespace"
+	TenantIdHeader                   = "X-Tenant-Id"
+)
+
+// ProcessOptions are the per request overrides of the processor defaults
+type ProcessOptions struct {
+	ComplementaryUnit      string `json:"complementaryUnit,omitempty"`
+	ComplementaryPlacement string `json:"complementaryPlacement,omitempty"`
+	TenantId               string `json:"tenantId,omitempty"`
+	TenantVerified         bool   `json:"tenantVerified,omitempty"`
+	Mode                   string `json:"mode,omitempty"`
+	DropTimedOutRows       bool   `json:"dropTimedOutRows,omitempty"`
+	StartNo                int    `json:"startNo,omitempty"`
+	Namespace              string `json:"namespace,omitempty"`
+}
+
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// reads ?complementaryUnit=batch|order, ?complementaryPlacement=end|interleaved,
+// ?mode=strict|lenient, ?dropTimedOutRows=true|false, ?startNo=<n>, ?namespace=<prefix> and the
+// X-Tenant-Id header, nil means no overrides were given. The tenant is
+// verified when an authenticating middleware bound it to the caller's key
+func ParseProcessOptions(c *gin.Context) (*ProcessOptions, error) {
+	unit := strings.TrimSpace(c.Query(ComplementaryUnitQueryParam))
+	placement := strings.TrimSpace(c.Query(ComplementaryPlacementQueryParam))
+	mode := strings.TrimSpace(c.Query(ModeQueryParam))
+	dropTimedOut := strings.TrimSpace(c.Query(DropTimedOutRowsQueryParam))
+	startNo := strings.TrimSpace(c.Query(StartNoQueryParam))
+	namespace := strings.TrimSpace(c.Query(NamespaceQueryParam))
+	tenantId := strings.TrimSpace(c.GetHeader(TenantIdHeader))
+	if unit == "" && placement == "" && mode == "" && dropTimedOut == "" && startNo == "" && namespace == "" && tenantId == "" {
+		return nil, nil
+	}
+
+	if unit != "" && !entity.ComplementaryUnit(unit).IsValid() {
+		log.Errorf("unknown complementary unit", log.S("complementary_unit", unit))
+		return nil, errors.ErrInvalidInput
+	}
+
+	if placement != "" && !entity.ComplementaryPlacement(placement).IsValid() {
+		log.Errorf("unknown complementary placement", log.S("complementary_placement", placement))
+		return nil, errors.ErrInvalidInput
+	}
+
+	if mode != "" && !entity.ProcessMode(mode).IsValid() {
+		log.Errorf("unknown process mode", log.S("mode", mode))
+		return nil, errors.ErrInvalidInput
+	}
+
+	dropTimedOutRows := false
+	if dropTimedOut != "" {
+		drop, err := strconv.ParseBool(dropTimedOut)
+		if err != nil {
+			log.Errorf("drop timed out rows must be a boolean", log.S("drop_timed_out_rows", dropTimedOut))
+			return nil, errors.ErrInvalidInput
+		}
+		dropTimedOutRows = drop
+	}
+
+	options := &ProcessOptions{
+		ComplementaryUnit:      unit,
+		ComplementaryPlacement: placement,
+		TenantId:               tenantId,
+		TenantVerified:         tenantId != "" && c.GetBool(TenantVerifiedContextKey),
+		Mode:                   mode,
+		DropTimedOutRows:       dropTimedOutRows,
+		Namespace:              namespace,
+	}
+
+	if startNo != "" {
+		no, err := strconv.Atoi(startNo)
+		if err != nil || no <= 0 {
+			log.Errorf("start number must be a positive integer", log.S("start_no", startNo))
+			return nil, errors.ErrInvalidInput
+		}
+		options.StartNo = no
+	}
+
+	if namespace != "" && !namespacePattern.MatchString(namespace) {
+		log.Errorf("invalid numbering namespace", log.S("namespace", namespace))
+		return nil, errors.ErrInvalidInput
+	}
+
+	return options, nil
+}
+
+// the X-Tenant-Id tenant when it is bound to the caller's key,
+// entity.DefaultTenantId otherwise so a client cannot pick another tenant's
+// settings
+func (o *ProcessOptions) VerifiedTenantId() string {
+	if o == nil || !o.TenantVerified {
+		return entity.DefaultTenantId
+	}
+	return o.TenantId
+}
+
+func (o *ProcessOptions) ToEntity() *entity.ProcessOptions {
+	if o == nil {
+		return nil
+	}
+
+	return &entity.ProcessOptions{
+		ComplementaryUnit:      entity.ComplementaryUnit(o.ComplementaryUnit),
+		ComplementaryPlacement: entity.ComplementaryPlacement(o.ComplementaryPlacement),
+		TenantId:               o.TenantId,
+		UnverifiedTenant:       o.TenantId != "" && !o.TenantVerified,
+		Mode:                   entity.ProcessMode(o.Mode),
+		DropTimedOutRows:       o.DropTimedOutRows,
+		StartNo:                o.StartNo,
+		NumberNamespace:        o.Namespace,
+	}
+}
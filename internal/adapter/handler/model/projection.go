@@ -0,0 +1,133 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	FieldsQueryParam = "fields"
+	// renders the response with a tenant output template
+	TemplateQueryParam = "template"
+	// "true" adds what the batch cost to the response meta
+	UsageQueryParam = "usage"
+)
+
+// ProjectedOrder is a CleanedOrder reduced to the requested fields,
+// keys are written in the order they were requested
+type ProjectedOrder struct {
+	fields []string
+	values map[string]json.RawMessage
+}
+
+// reads ?fields=no,productId,qty or the fields of an OrderBatch body, neither
+// means every field. Setting both is rejected
+func ParseFields(c *gin.Context, bodyFields []string) ([]string, error) {
+	raw := strings.TrimSpace(c.Query(FieldsQueryParam))
+	if raw != "" && len(bodyFields) > 0 {
+		log.Error("fields set in both the query and the body")
+		return nil, errors.ErrInvalidInput
+	}
+
+	names := bodyFields
+	if raw != "" {
+		names = strings.Split(raw, ",")
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := cleanedOrderFields()
+	seen := make(map[string]bool)
+	fields := make([]string, 0)
+
+	for _, field := range names {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+
+		if !known[field] {
+			log.Errorf("unknown output field", log.S("field", field))
+			return nil, errors.ErrInvalidInput
+		}
+
+		seen[field] = true
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		log.Error("fields have no field names")
+		return nil, errors.ErrInvalidInput
+	}
+
+	return fields, nil
+}
+
+func Project(orders []*CleanedOrder, fields []string) ([]*ProjectedOrder, error) {
+	projected := make([]*ProjectedOrder, len(orders))
+
+	for i, order := range orders {
+		encoded, err := json.Marshal(order)
+		if err != nil {
+			log.Errorf("failed to encode cleaned order", log.E(err))
+			return nil, err
+		}
+
+		values := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(encoded, &values); err != nil {
+			log.Errorf("failed to decode cleaned order", log.E(err))
+			return nil, err
+		}
+
+		projected[i] = &ProjectedOrder{fields: fields, values: values}
+	}
+
+	return projected, nil
+}
+
+func (p *ProjectedOrder) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	written := 0
+	for _, field := range p.fields {
+		value, ok := p.values[field]
+		if !ok {
+			continue
+		}
+
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+		written++
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func cleanedOrderFields() map[string]bool {
+	fields := make(map[string]bool)
+
+	t := reflect.TypeOf(CleanedOrder{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,118 @@
+package model_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		body        []string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "No fields parameter",
+			query:    "",
+			expected: nil,
+		},
+		{
+			name:     "Known fields keep requested order",
+			query:    "?fields=qty,no,productId",
+			expected: []string{"qty", "no", "productId"},
+		},
+		{
+			name:     "Spaces and duplicates are ignored",
+			query:    "?fields=no,%20qty,no,,",
+			expected: []string{"no", "qty"},
+		},
+		{
+			name:        "Unknown field",
+			query:       "?fields=no,price",
+			expectError: true,
+		},
+		{
+			name:        "Only separators",
+			query:       "?fields=,,",
+			expectError: true,
+		},
+		{
+			name:     "Body fields",
+			body:     []string{"qty", " no", "qty"},
+			expected: []string{"qty", "no"},
+		},
+		{
+			name:        "Unknown body field",
+			body:        []string{"price"},
+			expectError: true,
+		},
+		{
+			name:        "Query and body both set",
+			query:       "?fields=no",
+			body:        []string{"qty"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process"+tt.query, nil)
+
+			fields, err := model.ParseFields(c, tt.body)
+
+			if tt.expectError {
+				assert.Equal(t, errors.ErrInvalidInput, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	orders := []*model.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-OPPOA3",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "OPPOA3",
+			Qty:        2,
+			UnitPrice:  value_object.MustNewPrice(40),
+			TotalPrice: value_object.MustNewPrice(80),
+		},
+		{
+			No:         2,
+			ProductId:  "WIPING-CLOTH",
+			Qty:        2,
+			UnitPrice:  value_object.ZeroPrice(),
+			TotalPrice: value_object.ZeroPrice(),
+		},
+	}
+
+	projected, err := model.Project(orders, []string{"productId", "no", "materialId", "totalPrice"})
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(projected)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`[{"productId":"FG0A-CLEAR-OPPOA3","no":1,"materialId":"FG0A-CLEAR","totalPrice":80.00},`+
+			`{"productId":"WIPING-CLOTH","no":2,"totalPrice":0.00}]`,
+		string(encoded))
+}
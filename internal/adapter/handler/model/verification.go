@@ -0,0 +1,58 @@
+package model
+
+import (
+	"io"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerificationSample is a tenant provided batch with the lines it must produce
+type VerificationSample struct {
+	Name     string          `json:"name" binding:"required"`
+	Input    []*InputOrder   `json:"input" binding:"required,min=1,dive"`
+	Expected []*CleanedOrder `json:"expected"`
+}
+
+type VerifyConfigRequest struct {
+	Samples []*VerificationSample `json:"samples" binding:"dive"`
+}
+
+// an empty body means only the canonical cases are run
+func ParseVerifyConfigRequest(c *gin.Context) (*VerifyConfigRequest, error) {
+	var request VerifyConfigRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if err == io.EOF {
+			return &request, nil
+		}
+		log.Errorf("failed to bind JSON", log.E(err))
+		return nil, errors.ErrInvalidInput
+	}
+
+	return &request, nil
+}
+
+func (r *VerifyConfigRequest) ToEntity() ([]*entity.VerificationCase, error) {
+	cases := make([]*entity.VerificationCase, len(r.Samples))
+	for i, sample := range r.Samples {
+		input, err := ToEntity(sample.Input)
+		if err != nil {
+			return nil, err
+		}
+
+		expected := make([]*entity.CleanedOrder, len(sample.Expected))
+		for j, line := range sample.Expected {
+			expected[j] = line.ToEntity()
+		}
+
+		cases[i] = &entity.VerificationCase{
+			Name:     sample.Name,
+			Input:    input,
+			Expected: expected,
+		}
+	}
+	return cases, nil
+}
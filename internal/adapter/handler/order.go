@@ -1,43 +1,192 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
 	"order-placement-system/internal/adapter/handler/model"
 	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
 	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/cache"
+	"order-placement-system/pkg/errors"
 	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/usage"
+	"order-placement-system/pkg/utils/canonicaljson"
 
 	"github.com/gin-gonic/gin"
 )
 
 type orderHandler struct {
-	orderProcessor usecase.OrderProcessorUseCase
-	presenter      presenter.OrderPresenter
+	orderProcessor   usecase.OrderProcessorUseCase
+	presenter        presenter.OrderPresenter
+	resultCache      *cache.TTLCache
+	configGeneration usecase.ConfigGeneration
+	batchInspector   usecase.BatchInspector
+	templates        *presenter.OutputTemplates
+	usageRecorder    usecase.BatchUsageRecorder
+	amountFormat     value_object.AmountFormat
+}
+
+type cachedResult struct {
+	cleanedOrders []*model.CleanedOrder
+	warnings      []*model.BatchWarning
+	rowErrors     []*model.RowError
+	weight        *entity.ShippingWeight
 }
 
 type OrderHandlerInterface interface {
 	ProcessOrders(c *gin.Context)
+	ProcessSingleOrder(c *gin.Context)
+	RenumberOrders(c *gin.Context)
+}
+
+// OrderHandlerDeps are the optional collaborators of the order handler,
+// leave a field nil to go without it
+type OrderHandlerDeps struct {
+	// identical batches submitted again while still in the cache are
+	// answered from it
+	ResultCache *cache.TTLCache
+	// part of the cache key, so results cached before a rule changed at
+	// runtime are not served after it
+	ConfigGeneration usecase.ConfigGeneration
+	// its warnings are returned in the response meta
+	BatchInspector usecase.BatchInspector
+	// ?template=<name> renders the response with the tenant's template
+	// instead of the JSON envelope
+	Templates *presenter.OutputTemplates
+	// receives what each processed batch cost. ?usage=true returns it in the
+	// response meta either way
+	UsageRecorder usecase.BatchUsageRecorder
+	// the JSON type order amounts must be sent as, empty accepts numbers and
+	// decimal strings
+	AmountFormat value_object.AmountFormat
 }
 
 func NewOrderHandler(
 	orderProcessor usecase.OrderProcessorUseCase,
 	presenter presenter.OrderPresenter,
+	deps OrderHandlerDeps,
 ) OrderHandlerInterface {
 	return &orderHandler{
-		orderProcessor: orderProcessor,
-		presenter:      presenter,
+		orderProcessor:   orderProcessor,
+		presenter:        presenter,
+		resultCache:      deps.ResultCache,
+		configGeneration: deps.ConfigGeneration,
+		batchInspector:   deps.BatchInspector,
+		templates:        deps.Templates,
+		usageRecorder:    deps.UsageRecorder,
+		amountFormat:     deps.AmountFormat,
 	}
 }
+
 func (h *orderHandler) ProcessOrders(c *gin.Context) {
 
-	var inputOrderModels []*model.InputOrder
-	req, err := new(model.InputOrder).Parse(c)
+	batch, err := model.ParseOrderBatch(c)
+	if err != nil {
+		log.Errorf("failed to parse request body", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	h.process(c, batch.Orders, batch.Fields)
+}
+
+func (h *orderHandler) ProcessSingleOrder(c *gin.Context) {
+
+	req, err := new(model.InputOrder).ParseSingle(c)
 	if err != nil {
 		log.Errorf("failed to parse request body", log.E(err))
 		h.presenter.ErrorResponse(c, err)
 		return
 	}
 
-	inputOrderModels = req
+	h.process(c, req, nil)
+}
+
+// takes a batch this service cleaned before and numbers it again under the
+// request's ?startNo, ?namespace and ?complementaryPlacement
+func (h *orderHandler) RenumberOrders(c *gin.Context) {
+	req, err := model.ParseCleanedOrders(c)
+	if err != nil {
+		log.Errorf("failed to parse request body", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	options, err := model.ParseProcessOptions(c)
+	if err != nil {
+		log.Errorf("failed to parse process options", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	cleanedOrders := make([]*entity.CleanedOrder, len(req))
+	for i, line := range req {
+		cleanedOrders[i] = line.ToEntity()
+	}
+
+	result, err := h.orderProcessor.RenumberOrders(cleanedOrders, options.ToEntity())
+	if err != nil {
+		log.Errorf("failed to renumber orders", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	h.presenter.SuccessResponse(c, model.FromEntities(result))
+}
+
+// bodyFields are the fields of an OrderBatch body, nil for other bodies
+func (h *orderHandler) process(c *gin.Context, inputOrderModels []*model.InputOrder, bodyFields []string) {
+	if err := model.CheckAmountFormats(inputOrderModels, h.amountFormat); err != nil {
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	fields, err := model.ParseFields(c, bodyFields)
+	if err != nil {
+		log.Errorf("failed to parse fields", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	options, err := model.ParseProcessOptions(c)
+	if err != nil {
+		log.Errorf("failed to parse process options", log.E(err))
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	tmpl, err := h.outputTemplate(c, options, fields)
+	if err != nil {
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	meter := usage.Start()
+	cacheKey := h.cacheKey(inputOrderModels, options)
+	if cacheKey != "" {
+		if cached, ok := h.resultCache.Get(cacheKey); ok {
+			log.Debugf("serving cached result", log.S("payload_hash", cacheKey))
+			result := cached.(*cachedResult)
+			meta := &model.ResponseMeta{
+				Cached:         true,
+				Warnings:       result.warnings,
+				RowErrors:      result.rowErrors,
+				ShippingWeight: result.weight,
+				Client:         model.BatchClientFrom(c),
+			}
+			if c.Query(model.UsageQueryParam) == "true" {
+				meta.Usage = measureUsage(meter, options, len(inputOrderModels))
+			}
+			h.respond(c, tmpl, options, result.cleanedOrders, fields, meta)
+			return
+		}
+	}
+
 	inputEntities, err := model.ToEntity(inputOrderModels)
 	if err != nil {
 		log.Errorf("failed to convert models to entities", log.E(err))
@@ -45,12 +194,194 @@ func (h *orderHandler) ProcessOrders(c *gin.Context) {
 		return
 	}
 
-	result, err := h.orderProcessor.ProcessOrders(inputEntities)
+	var result []*entity.CleanedOrder
+	if options == nil {
+		result, err = h.orderProcessor.ProcessOrders(inputEntities)
+	} else {
+		result, err = h.orderProcessor.ProcessOrdersWithOptions(inputEntities, options.ToEntity())
+	}
+	var rowErrors []*model.RowError
+	var partial *errors.PartialError
+	if errors.As(err, &partial) {
+		log.Warnf("rows dropped from batch", log.AtoS("row_errors", len(partial.Rows)))
+		rowErrors = model.FromRowErrors(partial.Rows)
+		// a resubmission gets another chance at the rows
+		if partial.Transient() {
+			cacheKey = ""
+		}
+		err = nil
+	}
 	if err != nil {
 		log.Errorf("failed to process orders", log.E(err))
 		h.presenter.ErrorResponse(c, err)
 		return
 	}
 
-	h.presenter.SuccessResponse(c, model.FromEntities(result))
+	cleanedOrders := model.FromEntities(result)
+	weight := entity.TotalShippingWeight(result)
+
+	var warnings []*model.BatchWarning
+	if h.batchInspector != nil {
+		if batchWarnings := h.batchInspector.Inspect(inputEntities, result, options.ToEntity()); len(batchWarnings) > 0 {
+			warnings = model.FromBatchWarnings(batchWarnings)
+		}
+	}
+
+	batchUsage := h.recordUsage(meter, options, len(inputEntities))
+
+	if cacheKey != "" {
+		h.resultCache.Set(cacheKey, &cachedResult{
+			cleanedOrders: cleanedOrders,
+			warnings:      warnings,
+			rowErrors:     rowErrors,
+			weight:        weight,
+		})
+	}
+
+	if c.Query(model.UsageQueryParam) != "true" {
+		batchUsage = nil
+	}
+
+	client := model.BatchClientFrom(c)
+
+	var meta *model.ResponseMeta
+	if len(warnings) > 0 || len(rowErrors) > 0 || weight != nil || batchUsage != nil || client != nil {
+		meta = &model.ResponseMeta{Warnings: warnings, RowErrors: rowErrors, ShippingWeight: weight, Usage: batchUsage, Client: client}
+	}
+
+	h.respond(c, tmpl, options, cleanedOrders, fields, meta)
+}
+
+// cached answers cost next to nothing, they are measured for ?usage=true
+// but kept out of the recorder so they do not skew capacity planning
+func (h *orderHandler) recordUsage(meter *usage.Meter, options *model.ProcessOptions, rows int) *entity.BatchUsage {
+	batchUsage := measureUsage(meter, options, rows)
+	if h.usageRecorder != nil {
+		h.usageRecorder.RecordBatchUsage(batchUsage)
+	}
+	return batchUsage
+}
+
+func measureUsage(meter *usage.Meter, options *model.ProcessOptions, rows int) *entity.BatchUsage {
+	measured := meter.Stop()
+
+	tenantId := ""
+	if options != nil {
+		tenantId = options.TenantId
+	}
+	return entity.NewBatchUsage(tenantId, rows, measured.Wall, measured.CPU, measured.AllocatedBytes, time.Now())
+}
+
+// nil meta answers without a meta object
+func (h *orderHandler) respond(
+	c *gin.Context,
+	tmpl *presenter.OutputTemplate,
+	options *model.ProcessOptions,
+	cleanedOrders []*model.CleanedOrder,
+	fields []string,
+	meta *model.ResponseMeta,
+) {
+	if meta != nil && len(meta.Warnings) > 0 {
+		c.Set(model.BatchWarningsContextKey, meta.Warnings)
+	}
+
+	if tmpl != nil {
+		data := &presenter.TemplateData{
+			Lines:  cleanedOrders,
+			Locale: value_object.NegotiateLocale(c.GetHeader("Accept-Language")),
+		}
+		if options != nil {
+			data.TenantId = options.TenantId
+		}
+		if meta != nil {
+			data.Warnings = meta.Warnings
+			data.RowErrors = meta.RowErrors
+			data.ShippingWeight = meta.ShippingWeight
+		}
+
+		body, err := h.templates.Render(tmpl, data)
+		if err != nil {
+			h.presenter.ErrorResponse(c, err)
+			return
+		}
+		h.presenter.RawResponse(c, tmpl.ContentType, body)
+		return
+	}
+
+	data, err := h.responseData(cleanedOrders, fields)
+	if err != nil {
+		h.presenter.ErrorResponse(c, err)
+		return
+	}
+
+	if meta != nil {
+		h.presenter.SuccessResponseWithMeta(c, data, meta)
+		return
+	}
+
+	h.presenter.SuccessResponse(c, data)
+}
+
+// nil without ?template, the template replaces the JSON envelope so it does
+// not mix with ?fields
+func (h *orderHandler) outputTemplate(c *gin.Context, options *model.ProcessOptions, fields []string) (*presenter.OutputTemplate, error) {
+	name := strings.TrimSpace(c.Query(model.TemplateQueryParam))
+	if name == "" {
+		return nil, nil
+	}
+
+	if len(fields) > 0 {
+		log.Errorf("output template cannot be combined with fields", log.S("template", name))
+		return nil, errors.ErrInvalidInput
+	}
+
+	tenantId := options.VerifiedTenantId()
+	tmpl, ok := h.templates.Lookup(tenantId, name)
+	if !ok {
+		log.Errorf("unknown output template", log.S("template", name), log.S("tenant_id", tenantId))
+		return nil, errors.ErrInvalidInput
+	}
+
+	return tmpl, nil
+}
+
+func (h *orderHandler) responseData(cleanedOrders []*model.CleanedOrder, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return cleanedOrders, nil
+	}
+
+	projected, err := model.Project(cleanedOrders, fields)
+	if err != nil {
+		log.Errorf("failed to project cleaned orders", log.E(err))
+		return nil, err
+	}
+
+	return projected, nil
+}
+
+// hashes the canonical JSON of the decoded payload so whitespace, key order
+// and number spelling do not matter. The options and the configuration
+// generation are part of it as they change the result
+func (h *orderHandler) cacheKey(inputOrderModels []*model.InputOrder, options *model.ProcessOptions) string {
+	if h.resultCache == nil {
+		return ""
+	}
+
+	var generation uint64
+	if h.configGeneration != nil {
+		generation = h.configGeneration.Generation()
+	}
+
+	normalized, err := canonicaljson.Marshal(struct {
+		Orders     []*model.InputOrder   `json:"orders"`
+		Options    *model.ProcessOptions `json:"options,omitempty"`
+		Generation uint64                `json:"generation,omitempty"`
+	}{inputOrderModels, options, generation})
+	if err != nil {
+		log.Errorf("failed to normalize payload for caching", log.E(err))
+		return ""
+	}
+
+	sum := sha256.Sum256(normalized)
+	return hex.EncodeToString(sum[:])
 }
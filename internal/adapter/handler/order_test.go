@@ -6,60 +6,49 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"order-placement-system/internal/adapter/handler"
 	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/adapter/presenter"
 	"order-placement-system/internal/domain/entity"
 	"order-placement-system/internal/domain/value_object"
+	mockPresenters "order-placement-system/internal/mock/presenter"
+	mockUsecases "order-placement-system/internal/mock/usecases"
+	"order-placement-system/internal/usecases/implementation"
+	"order-placement-system/pkg/cache"
 	errs "order-placement-system/pkg/errors"
 	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/utils/parser"
 )
 
 func init() {
 	log.Init("dev")
 }
 
-type MockOrderProcessor struct {
-	mock.Mock
-}
-
-func (m *MockOrderProcessor) ProcessOrders(inputOrders []*entity.InputOrder) ([]*entity.CleanedOrder, error) {
-	args := m.Called(inputOrders)
-	return args.Get(0).([]*entity.CleanedOrder), args.Error(1)
-}
-
-type MockPresenter struct {
-	mock.Mock
-}
-
-func (m *MockPresenter) SuccessResponse(c *gin.Context, data interface{}) {
-	m.Called(c, data)
-}
-
-func (m *MockPresenter) ErrorResponse(c *gin.Context, err error) {
-	m.Called(c, err)
-}
-
 func TestOrderHandler_ProcessOrders(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("Case 1: Only one product", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 		}
 
@@ -109,18 +98,18 @@ func TestOrderHandler_ProcessOrders(t *testing.T) {
 	})
 
 	t.Run("Case 2: One product with wrong prefix", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "x2-3&FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 		}
 
@@ -170,18 +159,18 @@ func TestOrderHandler_ProcessOrders(t *testing.T) {
 	})
 
 	t.Run("Case 3: One product with wrong prefix and * symbol", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "x2-3&FG0A-MATTE-IPHONE16PROMAX*3",
 				Qty:               1,
-				UnitPrice:         90.0,
-				TotalPrice:        90.0,
+				UnitPrice:         model.NewAmount(90.0),
+				TotalPrice:        model.NewAmount(90.0),
 			},
 		}
 
@@ -231,18 +220,18 @@ func TestOrderHandler_ProcessOrders(t *testing.T) {
 	})
 
 	t.Run("Case 4: Bundle product with / symbol", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-OPPOA3/%20xFG0A-CLEAR-OPPOA3-B",
 				Qty:               1,
-				UnitPrice:         80.0,
-				TotalPrice:        80.0,
+				UnitPrice:         model.NewAmount(80.0),
+				TotalPrice:        model.NewAmount(80.0),
 			},
 		}
 
@@ -301,18 +290,18 @@ func TestOrderHandler_ProcessOrders(t *testing.T) {
 	})
 
 	t.Run("Case 5: Bundle product with three products", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-OPPOA3/%20xFG0A-CLEAR-OPPOA3-B/FG0A-MATTE-OPPOA3",
 				Qty:               1,
-				UnitPrice:         120.0,
-				TotalPrice:        120.0,
+				UnitPrice:         model.NewAmount(120.0),
+				TotalPrice:        model.NewAmount(120.0),
 			},
 		}
 
@@ -387,18 +376,18 @@ func TestOrderHandler_ProcessOrders(t *testing.T) {
 	})
 
 	t.Run("Case 6: Bundle product with / and * symbols", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "--FG0A-CLEAR-OPPOA3*2/FG0A-MATTE-OPPOA3",
 				Qty:               1,
-				UnitPrice:         120.0,
-				TotalPrice:        120.0,
+				UnitPrice:         model.NewAmount(120.0),
+				TotalPrice:        model.NewAmount(120.0),
 			},
 		}
 
@@ -464,25 +453,25 @@ func TestOrderHandler_ProcessOrders(t *testing.T) {
 	})
 
 	t.Run("Case 7: Multiple products with complex combinations", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "--FG0A-CLEAR-OPPOA3*2/FG0A-MATTE-OPPOA3*2",
 				Qty:               1,
-				UnitPrice:         160.0,
-				TotalPrice:        160.0,
+				UnitPrice:         model.NewAmount(160.0),
+				TotalPrice:        model.NewAmount(160.0),
 			},
 			{
 				No:                2,
 				PlatformProductId: "FG0A-PRIVACY-IPHONE16PROMAX",
 				Qty:               1,
-				UnitPrice:         50.0,
-				TotalPrice:        50.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(50.0),
 			},
 		}
 
@@ -568,10 +557,10 @@ func TestOrderHandler_ProcessOrders_ErrorCases(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("Invalid JSON request body", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
 
@@ -587,10 +576,10 @@ func TestOrderHandler_ProcessOrders_ErrorCases(t *testing.T) {
 	})
 
 	t.Run("Empty orders array", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
 
@@ -607,18 +596,18 @@ func TestOrderHandler_ProcessOrders_ErrorCases(t *testing.T) {
 	})
 
 	t.Run("Invalid input order - negative quantity", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               -1,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 		}
 
@@ -637,18 +626,18 @@ func TestOrderHandler_ProcessOrders_ErrorCases(t *testing.T) {
 	})
 
 	t.Run("Order processor returns error", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         50.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(50.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 		}
 
@@ -671,18 +660,18 @@ func TestOrderHandler_ProcessOrders_ErrorCases(t *testing.T) {
 	})
 
 	t.Run("Invalid price values", func(t *testing.T) {
-		mockProcessor := new(MockOrderProcessor)
-		mockPresenter := new(MockPresenter)
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-		handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 		inputData := []*model.InputOrder{
 			{
 				No:                1,
 				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 				Qty:               2,
-				UnitPrice:         -10.0,
-				TotalPrice:        100.0,
+				UnitPrice:         model.NewAmount(-10.0),
+				TotalPrice:        model.NewAmount(100.0),
 			},
 		}
 
@@ -716,8 +705,8 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 			},
 			wantError: false,
@@ -729,8 +718,8 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               0,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 			},
 			wantError: true,
@@ -742,8 +731,8 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 					No:                1,
 					PlatformProductId: "",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 			},
 			wantError: true,
@@ -755,8 +744,8 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         -50.0,
-					TotalPrice:        100.0,
+					UnitPrice:         model.NewAmount(-50.0),
+					TotalPrice:        model.NewAmount(100.0),
 				},
 			},
 			wantError: true,
@@ -768,8 +757,8 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 					No:                1,
 					PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
 					Qty:               2,
-					UnitPrice:         50.0,
-					TotalPrice:        -100.0,
+					UnitPrice:         model.NewAmount(50.0),
+					TotalPrice:        model.NewAmount(-100.0),
 				},
 			},
 			wantError: true,
@@ -778,10 +767,10 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 
 	for _, tt := range validationTests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockProcessor := new(MockOrderProcessor)
-			mockPresenter := new(MockPresenter)
+			mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+			mockPresenter := new(mockPresenters.OrderPresenter)
 
-			handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+			handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
 			if tt.wantError {
 				mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), mock.Anything).Return()
@@ -807,23 +796,222 @@ func TestOrderHandler_ProcessOrders_ValidationErrors(t *testing.T) {
 	}
 }
 
-func BenchmarkOrderHandler_ProcessOrders(b *testing.B) {
+func TestOrderHandler_ProcessSingleOrder(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	mockProcessor := new(MockOrderProcessor)
-	mockPresenter := new(MockPresenter)
+	t.Run("Single order object is processed as a batch of one", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
 
-	handler := handler.NewOrderHandler(mockProcessor, mockPresenter)
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
 
-	inputData := []*model.InputOrder{
-		{
+		expectedResult := []*entity.CleanedOrder{
+			{
+				No:         1,
+				ProductId:  "FG0A-PRIVACY-IPHONE16PROMAX",
+				MaterialId: "FG0A-PRIVACY",
+				ModelId:    "IPHONE16PROMAX",
+				Qty:        1,
+				UnitPrice:  value_object.MustNewPrice(50.0),
+				TotalPrice: value_object.MustNewPrice(50.0),
+			},
+		}
+
+		mockProcessor.On("ProcessOrders", mock.MatchedBy(func(orders []*entity.InputOrder) bool {
+			return len(orders) == 1 && orders[0].PlatformProductId == "FG0A-PRIVACY-IPHONE16PROMAX"
+		})).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		requestBody, _ := json.Marshal(&model.InputOrder{
 			No:                1,
-			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
-			Qty:               2,
-			UnitPrice:         50.0,
-			TotalPrice:        100.0,
+			PlatformProductId: "FG0A-PRIVACY-IPHONE16PROMAX",
+			Qty:               1,
+			UnitPrice:         model.NewAmount(50.0),
+			TotalPrice:        model.NewAmount(50.0),
+		})
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process/single", bytes.NewBuffer(requestBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessSingleOrder(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Invalid single order is rejected before processing", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process/single", bytes.NewBufferString(`{"no":1,"qty":0}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessSingleOrder(c)
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrders", mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_RenumberOrders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Lines are renumbered with the request options", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		expectedResult := []*entity.CleanedOrder{
+			{
+				No:           11,
+				ProductId:    "WIPING-CLOTH",
+				Qty:          1,
+				UnitPrice:    value_object.ZeroPrice(),
+				TotalPrice:   value_object.ZeroPrice(),
+				NamespacedNo: "B-11",
+			},
+		}
+
+		mockProcessor.On("RenumberOrders",
+			mock.MatchedBy(func(orders []*entity.CleanedOrder) bool {
+				return len(orders) == 1 && orders[0].ProductId == "WIPING-CLOTH" && orders[0].No == 3
+			}),
+			mock.MatchedBy(func(options *entity.ProcessOptions) bool {
+				return options.StartNo == 11 && options.NumberNamespace == "B"
+			})).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/renumber?startNo=11&namespace=B",
+			bytes.NewBufferString(`[{"no":3,"productId":"WIPING-CLOTH","qty":1,"unitPrice":0,"totalPrice":0}]`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.RenumberOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	for _, body := range []string{`[]`, `{"no":1}`} {
+		t.Run("Rejects "+body, func(t *testing.T) {
+			mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+			mockPresenter := new(mockPresenters.OrderPresenter)
+
+			h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+			mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/renumber", bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.RenumberOrders(c)
+
+			mockProcessor.AssertNotCalled(t, "RenumberOrders", mock.Anything, mock.Anything)
+			mockPresenter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderHandler_ProcessOrders_FieldsProjection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        1,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
 		},
 	}
+	body := `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`
+
+	t.Run("Requested fields only", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(data []*model.ProjectedOrder) bool {
+			encoded, _ := json.Marshal(data)
+			return string(encoded) == `[{"no":1,"productId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1}]`
+		})).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?fields=no,productId,qty", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Unknown field is rejected before processing", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?fields=no,bogus", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrders", mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Fields sent in the body", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(data []*model.ProjectedOrder) bool {
+			encoded, _ := json.Marshal(data)
+			return string(encoded) == `[{"productId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1}]`
+		})).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process",
+			bytes.NewBufferString(`{"orders":`+body+`,"fields":["productId","qty"]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_ProcessOrders_OutputTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
 	expectedResult := []*entity.CleanedOrder{
 		{
@@ -831,25 +1019,882 @@ func BenchmarkOrderHandler_ProcessOrders(b *testing.B) {
 			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
 			MaterialId: "FG0A-CLEAR",
 			ModelId:    "IPHONE16PROMAX",
-			Qty:        2,
+			Qty:        1,
 			UnitPrice:  value_object.MustNewPrice(50.0),
-			TotalPrice: value_object.MustNewPrice(100.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
 		},
 	}
+	body := `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`
 
-	mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
-	mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+	templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{
+		"acme": {"csv": {ContentType: "text/csv", Body: "{{range .Lines}}{{.No}},{{.ProductId}}\n{{end}}"}},
+	}, presenter.OutputTemplateOptions{Timeout: time.Second, MaxBytes: 1024})
+	if err != nil {
+		t.Fatalf("failed to compile templates: %v", err)
+	}
 
-	requestBody, _ := json.Marshal(inputData)
+	send := func(h handler.OrderHandlerInterface, target string, verified bool) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(model.TenantIdHeader, "acme")
+		c.Set(model.TenantVerifiedContextKey, verified)
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
+		h.ProcessOrders(c)
+	}
+
+	t.Run("Tenant template replaces the JSON envelope", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{Templates: templates})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), mock.AnythingOfType("*entity.ProcessOptions")).Return(expectedResult, nil)
+		mockPresenter.On("RawResponse", mock.AnythingOfType("*gin.Context"), "text/csv", []byte("1,FG0A-CLEAR-IPHONE16PROMAX\n")).Return()
+
+		send(h, "/api/v1/orders/process?template=csv", true)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	for name, target := range map[string]string{
+		"Unknown template is rejected before processing": "/api/v1/orders/process?template=sap",
+		"Template cannot be combined with fields":        "/api/v1/orders/process?template=csv&fields=no",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+			mockPresenter := new(mockPresenters.OrderPresenter)
+
+			h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{Templates: templates})
+
+			mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+			send(h, target, true)
+
+			mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+			mockPresenter.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Handler without templates rejects the parameter", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		send(h, "/api/v1/orders/process?template=csv", true)
+
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Unverified tenant does not get its templates", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{Templates: templates})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		send(h, "/api/v1/orders/process?template=csv", false)
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_ProcessOrders_ResultCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        1,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
+		},
+	}
+
+	send := func(h handler.OrderHandlerInterface, body string) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
-		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", bytes.NewBuffer(requestBody))
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", bytes.NewBufferString(body))
 		c.Request.Header.Set("Content-Type", "application/json")
 
-		handler.ProcessOrders(c)
+		h.ProcessOrders(c)
+	}
+
+	t.Run("Identical batch is served from cache", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{ResultCache: cache.NewTTLCache(time.Minute, 10)})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Once()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return().Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), &model.ResponseMeta{Cached: true}).Return().Once()
+
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`)
+		// same batch with different key order and spacing
+		send(h, `[ {"qty":1, "no":1, "totalPrice":50, "unitPrice":50, "platformProductId":"FG0A-CLEAR-IPHONE16PROMAX"} ]`)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Different batch is processed again", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{ResultCache: cache.NewTTLCache(time.Minute, 10)})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Twice()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return().Twice()
+
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`)
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":2,"unitPrice":50,"totalPrice":100}]`)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Batch is processed again after a runtime rule changed", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockGeneration := new(mockUsecases.ConfigGeneration)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{
+			ResultCache:      cache.NewTTLCache(time.Minute, 10),
+			ConfigGeneration: mockGeneration,
+		})
+
+		mockGeneration.On("Generation").Return(uint64(1)).Once()
+		mockGeneration.On("Generation").Return(uint64(2)).Once()
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Twice()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return().Twice()
+
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`)
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`)
+
+		mockGeneration.AssertExpectations(t)
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Failed batch is not cached", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{ResultCache: cache.NewTTLCache(time.Minute, 10)})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return([]*entity.CleanedOrder(nil), errs.ErrInvalidInput).Twice()
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return().Twice()
+
+		body := `[{"no":1,"platformProductId":"INVALID","qty":1,"unitPrice":50,"totalPrice":50}]`
+		send(h, body)
+		send(h, body)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Batch with timed out rows is not cached", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{ResultCache: cache.NewTTLCache(time.Minute, 10)})
+
+		partial := errs.NewPartialError([]*errs.RowError{errs.NewRowError(2, errs.ErrProcessingTimeout)})
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, partial).Once()
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), mock.MatchedBy(func(meta *model.ResponseMeta) bool {
+			return !meta.Cached && len(meta.RowErrors) == 1 && meta.RowErrors[0].Code == errs.RowCodeTimeout
+		})).Return().Once()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return().Once()
+
+		body := `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50},` +
+			`{"no":2,"platformProductId":"FG0A-MATTE-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]`
+		send(h, body)
+		send(h, body)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_ProcessOrders_ProcessOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        1,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
+			ParentNo:   1,
+		},
 	}
+	body := `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`
+
+	t.Run("Query parameter is passed to the processor", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), &entity.ProcessOptions{
+			ComplementaryUnit: entity.ComplementaryPerOrder,
+		}).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(data []*model.CleanedOrder) bool {
+			return len(data) == 1 && data[0].ParentNo == 1
+		})).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?complementaryUnit=order", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Unknown unit is rejected before processing", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?complementaryUnit=week", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Complementary placement is passed to the processor", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), &entity.ProcessOptions{
+			ComplementaryUnit:      entity.ComplementaryPerOrder,
+			ComplementaryPlacement: entity.ComplementaryInterleaved,
+		}).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?complementaryUnit=order&complementaryPlacement=interleaved", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Unknown placement is rejected before processing", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?complementaryPlacement=middle", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Tenant header without a key is not verified", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), &entity.ProcessOptions{
+			TenantId:         "acme",
+			UnverifiedTenant: true,
+		}).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process",
+			bytes.NewBufferString(`[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1}]`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(model.TenantIdHeader, "acme")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Tenant bound to a key is verified", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), &entity.ProcessOptions{
+			TenantId: "acme",
+		}).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process",
+			bytes.NewBufferString(`[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1}]`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set(model.TenantIdHeader, "acme")
+		c.Set(model.TenantVerifiedContextKey, true)
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Numbering options are passed to the processor", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), &entity.ProcessOptions{
+			StartNo:         101,
+			NumberNamespace: "B123",
+		}).Return(expectedResult, nil)
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?startNo=101&namespace=B123", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Invalid numbering options are rejected before processing", func(t *testing.T) {
+		for _, query := range []string{"startNo=0", "startNo=ten", "namespace=B%20123", "namespace=" + strings.Repeat("B", 65)} {
+			mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+			mockPresenter := new(mockPresenters.OrderPresenter)
+
+			h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+			mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process?"+query, bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.ProcessOrders(c)
+
+			mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+			mockPresenter.AssertExpectations(t)
+		}
+	})
+
+	t.Run("Unit is part of the cache key", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{ResultCache: cache.NewTTLCache(time.Minute, 10)})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Once()
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), mock.AnythingOfType("*entity.ProcessOptions")).Return(expectedResult, nil).Once()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return().Twice()
+
+		for _, target := range []string{"/api/v1/orders/process", "/api/v1/orders/process?complementaryUnit=order"} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.ProcessOrders(c)
+		}
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_ProcessOrders_BatchWarnings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        1,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
+		},
+	}
+	body := `[{"no":1,"platformProductId":"--FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`
+
+	send := func(h handler.OrderHandlerInterface) *gin.Context {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+		return c
+	}
+
+	t.Run("Warnings are returned in meta", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockInspector := new(mockUsecases.BatchInspector)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{BatchInspector: mockInspector})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+		mockInspector.On("Inspect", mock.AnythingOfType("[]*entity.InputOrder"), expectedResult, (*entity.ProcessOptions)(nil)).
+			Return([]*entity.BatchWarning{entity.NewBatchWarning(entity.WarningPrefixedRows, 1, 0.5)})
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), &model.ResponseMeta{
+			Warnings: []*model.BatchWarning{{Code: "PREFIXED_ROWS", Value: 1, Threshold: 0.5}},
+		}).Return()
+
+		c := send(h)
+
+		mockProcessor.AssertExpectations(t)
+		mockInspector.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+		warnings, ok := c.Get(model.BatchWarningsContextKey)
+		assert.True(t, ok, "warnings are left on the context for the client metrics")
+		assert.Len(t, warnings, 1)
+	})
+
+	t.Run("No warnings keeps the plain response", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockInspector := new(mockUsecases.BatchInspector)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{BatchInspector: mockInspector})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+		mockInspector.On("Inspect", mock.Anything, mock.Anything, mock.Anything).Return([]*entity.BatchWarning{})
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		send(h)
+
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Cached result keeps its warnings", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockInspector := new(mockUsecases.BatchInspector)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{
+			ResultCache:    cache.NewTTLCache(time.Minute, 10),
+			BatchInspector: mockInspector,
+		})
+
+		warnings := []*model.BatchWarning{{Code: "PREFIXED_ROWS", Value: 1, Threshold: 0.5}}
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Once()
+		mockInspector.On("Inspect", mock.Anything, mock.Anything, mock.Anything).
+			Return([]*entity.BatchWarning{entity.NewBatchWarning(entity.WarningPrefixedRows, 1, 0.5)}).Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), &model.ResponseMeta{
+			Warnings: warnings,
+		}).Return().Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), &model.ResponseMeta{
+			Cached:   true,
+			Warnings: warnings,
+		}).Return().Once()
+
+		send(h)
+		send(h)
+
+		mockProcessor.AssertExpectations(t)
+		mockInspector.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_ProcessOrders_BatchUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        1,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
+		},
+	}
+	body := `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`
+
+	send := func(h handler.OrderHandlerInterface, path string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+	}
+	oneRow := mock.MatchedBy(func(usage *entity.BatchUsage) bool { return usage.Rows == 1 })
+
+	t.Run("Usage is recorded and returned when asked for", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockRecorder := new(mockUsecases.BatchUsageRecorder)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{UsageRecorder: mockRecorder})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+		mockRecorder.On("RecordBatchUsage", oneRow).Return()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"),
+			mock.MatchedBy(func(meta *model.ResponseMeta) bool { return meta.Usage != nil && meta.Usage.Rows == 1 })).Return()
+
+		send(h, "/api/v1/orders/process?usage=true")
+
+		mockRecorder.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Usage is recorded but not returned by default", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockRecorder := new(mockUsecases.BatchUsageRecorder)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{UsageRecorder: mockRecorder})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+		mockRecorder.On("RecordBatchUsage", oneRow).Return()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+		send(h, "/api/v1/orders/process")
+
+		mockRecorder.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Cached result is measured but not recorded", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+		mockRecorder := new(mockUsecases.BatchUsageRecorder)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{
+			ResultCache:   cache.NewTTLCache(time.Minute, 10),
+			UsageRecorder: mockRecorder,
+		})
+
+		mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil).Once()
+		mockRecorder.On("RecordBatchUsage", oneRow).Return().Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), mock.MatchedBy(func(meta *model.ResponseMeta) bool {
+			return !meta.Cached && meta.Usage != nil && meta.Usage.Rows == 1
+		})).Return().Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), mock.MatchedBy(func(meta *model.ResponseMeta) bool {
+			return meta.Cached && meta.Usage != nil && meta.Usage.Rows == 1
+		})).Return().Once()
+
+		send(h, "/api/v1/orders/process?usage=true")
+		send(h, "/api/v1/orders/process?usage=true")
+
+		mockProcessor.AssertExpectations(t)
+		mockRecorder.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func BenchmarkOrderHandler_ProcessOrders(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+	mockPresenter := new(mockPresenters.OrderPresenter)
+
+	handler := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+	inputData := []*model.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         model.NewAmount(50.0),
+			TotalPrice:        model.NewAmount(100.0),
+		},
+	}
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        2,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(100.0),
+		},
+	}
+
+	mockProcessor.On("ProcessOrders", mock.AnythingOfType("[]*entity.InputOrder")).Return(expectedResult, nil)
+	mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return()
+
+	requestBody, _ := json.Marshal(inputData)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", bytes.NewBuffer(requestBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ProcessOrders(c)
+	}
+}
+
+func TestOrderHandler_ProcessOrders_LenientMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expectedResult := []*entity.CleanedOrder{
+		{
+			No:         1,
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			MaterialId: "FG0A-CLEAR",
+			ModelId:    "IPHONE16PROMAX",
+			Qty:        1,
+			UnitPrice:  value_object.MustNewPrice(50.0),
+			TotalPrice: value_object.MustNewPrice(50.0),
+			ParentNo:   1,
+		},
+	}
+	body := `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50},` +
+		`{"no":2,"platformProductId":"FG0A-CLEAR-OPPOA3*2000","qty":1,"unitPrice":50,"totalPrice":50}]`
+	partial := errs.NewPartialError([]*errs.RowError{errs.NewRowError(2, errs.ErrBundleTooLarge)})
+	rowErrors := []*model.RowError{{No: 2, Error: "bundle too large", Category: errs.CategoryBusinessRule}}
+
+	send := func(h handler.OrderHandlerInterface, target string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+	}
+
+	t.Run("Dropped rows are returned in meta", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{ResultCache: cache.NewTTLCache(time.Minute, 10)})
+
+		mockProcessor.On("ProcessOrdersWithOptions", mock.AnythingOfType("[]*entity.InputOrder"), &entity.ProcessOptions{
+			Mode: entity.ProcessModeLenient,
+		}).Return(expectedResult, partial).Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), &model.ResponseMeta{
+			RowErrors: rowErrors,
+		}).Return().Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), &model.ResponseMeta{
+			Cached:    true,
+			RowErrors: rowErrors,
+		}).Return().Once()
+
+		send(h, "/api/v1/orders/process?mode=lenient")
+		send(h, "/api/v1/orders/process?mode=lenient")
+
+		mockProcessor.AssertExpectations(t)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Unknown mode is rejected before processing", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		send(h, "/api/v1/orders/process?mode=loose")
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_ProcessOrders_AmountFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(h handler.OrderHandlerInterface, body string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ProcessOrders(c)
+	}
+
+	t.Run("Numbers are rejected when amounts must be strings", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{AmountFormat: value_object.AmountFormatString})
+
+		mockPresenter.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrInvalidInput).Return()
+
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":"50.00","totalPrice":50}]`)
+
+		mockProcessor.AssertNotCalled(t, "ProcessOrdersWithOptions", mock.Anything, mock.Anything)
+		mockProcessor.AssertNotCalled(t, "ProcessOrders", mock.Anything)
+		mockPresenter.AssertExpectations(t)
+	})
+
+	t.Run("Decimal strings reach the processor exactly", func(t *testing.T) {
+		mockProcessor := new(mockUsecases.OrderProcessorUseCase)
+		mockPresenter := new(mockPresenters.OrderPresenter)
+
+		h := handler.NewOrderHandler(mockProcessor, mockPresenter, handler.OrderHandlerDeps{AmountFormat: value_object.AmountFormatString})
+
+		exact := mock.MatchedBy(func(orders []*entity.InputOrder) bool {
+			return len(orders) == 1 &&
+				orders[0].UnitPrice.Decimal() == "1234567.123456789" &&
+				orders[0].TotalPrice.Decimal() == "1234567.123456789"
+		})
+		mockProcessor.On("ProcessOrders", exact).Return([]*entity.CleanedOrder{}, nil).Once()
+		mockPresenter.On("SuccessResponseWithMeta", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder"), mock.Anything).Return().Maybe()
+		mockPresenter.On("SuccessResponse", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("[]*model.CleanedOrder")).Return().Maybe()
+
+		send(h, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":"1234567.123456789","totalPrice":"1234567.1234567890"}]`)
+
+		mockProcessor.AssertExpectations(t)
+	})
+}
+
+// processor, inspector and presenter are the real ones, so the warning is
+// the one a client gets
+func TestOrderHandler_ProcessOrders_RewriteWarnings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(parserOptions parser.ProductParserOptions, options *entity.ProcessOptions, body string) map[string]any {
+		productParser := parser.NewProductParserWithOptions(parserOptions)
+		processor := implementation.NewOrderProcessor(productParser, implementation.NewComplementaryCalculator(),
+			implementation.OrderProcessorOptions{ProcessOptions: options})
+		inspector := implementation.NewBatchInspector(productParser, implementation.BatchInspectorOptions{
+			Thresholds: entity.BatchWarningThresholds{MinRows: 10},
+			Defaults:   options,
+		})
+		h := handler.NewOrderHandler(processor, presenter.NewOrderPresenter(), handler.OrderHandlerDeps{BatchInspector: inspector})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.ProcessOrders(c)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	warningsOf := func(response map[string]any) []any {
+		meta, _ := response["meta"].(map[string]any)
+		warnings, _ := meta["warnings"].([]any)
+		return warnings
+	}
+
+	t.Run("Model suffix normalized", func(t *testing.T) {
+		options := &entity.ProcessOptions{ModelSuffixAliases: entity.ModelSuffixAliases{"*": {"-BLK": "-B"}}}
+		response := send(parser.ProductParserOptions{}, options, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX-BLK","qty":1,"unitPrice":50,"totalPrice":50},`+
+			`{"no":2,"platformProductId":"FG0A-MATTE-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]`)
+
+		warnings := warningsOf(response)
+		require.Len(t, warnings, 1)
+		warning := warnings[0].(map[string]any)
+		assert.Equal(t, "MODEL_SUFFIX_NORMALIZED", warning["code"])
+		assert.Equal(t, 1.0, warning["value"])
+		assert.Equal(t, []any{1.0}, warning["lines"])
+	})
+
+	t.Run("Texture aliased", func(t *testing.T) {
+		parserOptions := parser.ProductParserOptions{TextureAliases: value_object.TextureAliases{"PRIV": value_object.TexturePrivacy}}
+		response := send(parserOptions, &entity.ProcessOptions{}, `[{"no":1,"platformProductId":"FG0A-MATTE-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50},`+
+			`{"no":2,"platformProductId":"FG0A-PRIV-IPHONE16PROMAX","qty":2,"unitPrice":50,"totalPrice":100}]`)
+
+		warnings := warningsOf(response)
+		require.Len(t, warnings, 1)
+		warning := warnings[0].(map[string]any)
+		assert.Equal(t, "TEXTURE_ALIASED", warning["code"])
+		assert.Equal(t, 1.0, warning["value"])
+	})
+
+	t.Run("Underscore separators", func(t *testing.T) {
+		parserOptions := parser.ProductParserOptions{UnderscoreSeparators: true}
+		response := send(parserOptions, &entity.ProcessOptions{}, `[{"no":1,"platformProductId":"FG0A_CLEAR_OPPOA3/FG0A_MATTE_OPPOA3","qty":1,"unitPrice":100,"totalPrice":100},`+
+			`{"no":2,"platformProductId":"FG0A-MATTE-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]`)
+
+		warnings := warningsOf(response)
+		require.Len(t, warnings, 1)
+		warning := warnings[0].(map[string]any)
+		assert.Equal(t, "UNDERSCORE_SEPARATORS", warning["code"])
+		assert.Equal(t, 2.0, warning["value"], "every component of the row")
+	})
+
+	t.Run("Empty bundle segments", func(t *testing.T) {
+		response := send(parser.ProductParserOptions{}, &entity.ProcessOptions{}, `[{"no":1,"platformProductId":"FG0A-CLEAR-OPPOA3/","qty":1,"unitPrice":50,"totalPrice":50},`+
+			`{"no":2,"platformProductId":"FG0A-MATTE-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]`)
+
+		warnings := warningsOf(response)
+		require.Len(t, warnings, 1)
+		warning := warnings[0].(map[string]any)
+		assert.Equal(t, "EMPTY_BUNDLE_SEGMENTS", warning["code"])
+		assert.Equal(t, 1.0, warning["value"])
+		assert.Equal(t, []any{1.0}, warning["lines"])
+	})
+
+	t.Run("Variant extracted", func(t *testing.T) {
+		options := &entity.ProcessOptions{VariantTokens: entity.VariantTokens{"*": {"RED"}}}
+		response := send(parser.ProductParserOptions{}, options, `[{"no":1,"platformProductId":"FG0A-CLEAR-RED-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50},`+
+			`{"no":2,"platformProductId":"FG0A-MATTE-OPPOA3","qty":1,"unitPrice":50,"totalPrice":50}]`)
+
+		warnings := warningsOf(response)
+		require.Len(t, warnings, 1)
+		warning := warnings[0].(map[string]any)
+		assert.Equal(t, "VARIANT_EXTRACTED", warning["code"])
+		assert.Equal(t, 1.0, warning["value"])
+		assert.Equal(t, []any{1.0}, warning["lines"])
+	})
+
+	t.Run("Nothing rewritten", func(t *testing.T) {
+		response := send(parser.ProductParserOptions{}, &entity.ProcessOptions{}, `[{"no":1,"platformProductId":"FG0A-CLEAR-IPHONE16PROMAX","qty":1,"unitPrice":50,"totalPrice":50}]`)
+		assert.Empty(t, warningsOf(response))
+	})
 }
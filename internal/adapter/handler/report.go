@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+
+	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type reportHandler struct {
+	presenter     presenter.ReportPresenter
+	priceSplits   usecase.ReportSource
+	batchWarnings usecase.ReportSource
+	business      usecase.OpenMetricsSource
+	errorArticles usecase.ErrorArticleStore
+	pricePolicy   *value_object.PricePolicy
+	runtimeRules  usecase.RuntimeRules
+}
+
+// ReportHandlerInterface serves what anyone may read: the metrics, the docs
+// and the error knowledge base
+type ReportHandlerInterface interface {
+	PriceSplits(c *gin.Context)
+	BatchWarnings(c *gin.Context)
+	BusinessMetrics(c *gin.Context)
+	ComplementaryOrdering(c *gin.Context)
+	PricePolicy(c *gin.Context)
+	SearchErrorArticles(c *gin.Context)
+	GetErrorArticle(c *gin.Context)
+}
+
+// ReportHandlerDeps are what the reports are read from, a report whose field
+// is left nil answers 404
+type ReportHandlerDeps struct {
+	PriceSplits   usecase.ReportSource
+	BatchWarnings usecase.ReportSource
+	Business      usecase.OpenMetricsSource
+	ErrorArticles usecase.ErrorArticleStore
+	PricePolicy   *value_object.PricePolicy
+	// the texture priorities the complementary ordering follows, the
+	// defaults when nil
+	RuntimeRules usecase.RuntimeRules
+}
+
+func NewReportHandler(
+	presenter presenter.ReportPresenter,
+	deps ReportHandlerDeps,
+) ReportHandlerInterface {
+	return &reportHandler{
+		presenter:     presenter,
+		priceSplits:   deps.PriceSplits,
+		batchWarnings: deps.BatchWarnings,
+		business:      deps.Business,
+		errorArticles: deps.ErrorArticles,
+		pricePolicy:   deps.PricePolicy,
+		runtimeRules:  deps.RuntimeRules,
+	}
+}
+
+func (h *reportHandler) PriceSplits(c *gin.Context) {
+	h.report(c, h.priceSplits)
+}
+
+func (h *reportHandler) BatchWarnings(c *gin.Context) {
+	h.report(c, h.batchWarnings)
+}
+
+func (h *reportHandler) BusinessMetrics(c *gin.Context) {
+	if h.business == nil {
+		h.presenter.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.presenter.OpenMetricsResponse(c, h.business.WriteOpenMetrics)
+}
+
+func (h *reportHandler) ComplementaryOrdering(c *gin.Context) {
+	var priorities value_object.TexturePriorities
+	if h.runtimeRules != nil {
+		priorities = h.runtimeRules.TexturePriorities()
+	}
+	h.presenter.ReportResponse(c, http.StatusOK, gin.H{
+		"groupedBy": "parentNo",
+		"order":     entity.ComplementaryOrdering(priorities),
+		"unlisted":  "after the listed items, in the order their rule produced them",
+	})
+}
+
+func (h *reportHandler) PricePolicy(c *gin.Context) {
+	if h.pricePolicy == nil {
+		h.presenter.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.presenter.ReportResponse(c, http.StatusOK, h.pricePolicy)
+}
+
+// ?q= searches the articles, every article without it
+func (h *reportHandler) SearchErrorArticles(c *gin.Context) {
+	if h.errorArticles == nil {
+		h.presenter.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.presenter.ReportResponse(c, http.StatusOK, gin.H{"errors": h.errorArticles.Search(c.Query("q"))})
+}
+
+func (h *reportHandler) GetErrorArticle(c *gin.Context) {
+	if h.errorArticles == nil {
+		h.presenter.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+
+	article, ok := h.errorArticles.Get(c.Param("code"))
+	if !ok {
+		h.presenter.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.presenter.ReportResponse(c, http.StatusOK, article)
+}
+
+func (h *reportHandler) report(c *gin.Context, source usecase.ReportSource) {
+	if source == nil {
+		h.presenter.ErrorResponse(c, errors.ErrNotFound)
+		return
+	}
+	h.presenter.ReportResponse(c, http.StatusOK, source.Report())
+}
@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order-placement-system/internal/adapter/handler"
+	"order-placement-system/internal/domain/entity"
+	mockPresenters "order-placement-system/internal/mock/presenter"
+	mockUsecases "order-placement-system/internal/mock/usecases"
+	errs "order-placement-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportHandler_PriceSplits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Report is served as is", func(t *testing.T) {
+		source := mockUsecases.NewReportSource(t)
+		reports := mockPresenters.NewReportPresenter(t)
+		source.On("Report").Return(map[string]int{"rows": 1})
+		reports.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, map[string]int{"rows": 1}).Return()
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		handler.NewReportHandler(reports, handler.ReportHandlerDeps{PriceSplits: source}).PriceSplits(c)
+	})
+
+	t.Run("Without a source", func(t *testing.T) {
+		reports := mockPresenters.NewReportPresenter(t)
+		reports.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrNotFound).Return()
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		handler.NewReportHandler(reports, handler.ReportHandlerDeps{}).PriceSplits(c)
+	})
+}
+
+func TestReportHandler_BusinessMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	business := mockUsecases.NewOpenMetricsSource(t)
+	reports := mockPresenters.NewReportPresenter(t)
+	business.On("WriteOpenMetrics", mock.Anything).Return(nil)
+	reports.On("OpenMetricsResponse", mock.AnythingOfType("*gin.Context"), mock.Anything).Return().Run(func(args mock.Arguments) {
+		args.Get(1).(func(w io.Writer) error)(httptest.NewRecorder())
+	})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	handler.NewReportHandler(reports, handler.ReportHandlerDeps{Business: business}).BusinessMetrics(c)
+}
+
+func TestReportHandler_GetErrorArticle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	article := &entity.ErrorArticle{Code: "system_error", Title: "System error"}
+
+	tests := []struct {
+		name      string
+		code      string
+		setupMock func(*mockUsecases.ErrorArticleStore, *mockPresenters.ReportPresenter)
+	}{
+		{
+			name: "Article",
+			code: "SYSTEM_ERROR",
+			setupMock: func(a *mockUsecases.ErrorArticleStore, p *mockPresenters.ReportPresenter) {
+				a.On("Get", "SYSTEM_ERROR").Return(article, true)
+				p.On("ReportResponse", mock.AnythingOfType("*gin.Context"), http.StatusOK, article).Return()
+			},
+		},
+		{
+			name: "Unknown article",
+			code: "UNKNOWN",
+			setupMock: func(a *mockUsecases.ErrorArticleStore, p *mockPresenters.ReportPresenter) {
+				a.On("Get", "UNKNOWN").Return(nil, false)
+				p.On("ErrorResponse", mock.AnythingOfType("*gin.Context"), errs.ErrNotFound).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := mockUsecases.NewErrorArticleStore(t)
+			reports := mockPresenters.NewReportPresenter(t)
+			tt.setupMock(articles, reports)
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Params = gin.Params{{Key: "code", Value: tt.code}}
+
+			handler.NewReportHandler(reports, handler.ReportHandlerDeps{ErrorArticles: articles}).GetErrorArticle(c)
+		})
+	}
+}
@@ -9,7 +9,10 @@ import (
 
 type OrderPresenter interface {
 	SuccessResponse(c *gin.Context, data interface{})
+	SuccessResponseWithMeta(c *gin.Context, data interface{}, meta interface{})
 	ErrorResponse(c *gin.Context, err error)
+	// body is sent as is, e.g. a rendered output template
+	RawResponse(c *gin.Context, contentType string, body []byte)
 }
 
 type orderPresenter struct{}
@@ -25,6 +28,18 @@ func (p *orderPresenter) SuccessResponse(c *gin.Context, data interface{}) {
 	})
 }
 
+func (p *orderPresenter) SuccessResponseWithMeta(c *gin.Context, data interface{}, meta interface{}) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   data,
+		"meta":   meta,
+	})
+}
+
 func (p *orderPresenter) ErrorResponse(c *gin.Context, err error) {
 	errors.MapJsonError(c, err)
 }
+
+func (p *orderPresenter) RawResponse(c *gin.Context, contentType string, body []byte) {
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -406,3 +406,22 @@ func assertJSONResponse(t *testing.T, w *httptest.ResponseRecorder, expectedStat
 
 	assert.Equal(t, expectedBody, responseBody)
 }
+
+func TestOrderPresenter_SuccessResponseWithMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	orderPresenter := presenter.NewOrderPresenter()
+	orderPresenter.SuccessResponseWithMeta(c, []string{"a"}, map[string]interface{}{"cached": true})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.Equal(t, "success", response["status"])
+	assert.Equal(t, []interface{}{"a"}, response["data"])
+	assert.Equal(t, map[string]interface{}{"cached": true}, response["meta"])
+}
@@ -0,0 +1,100 @@
+package presenter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/domain/entity"
+)
+
+// PickListTemplate is the built-in template for warehouse staff, every tenant
+// gets it unless "*" configures a template of the same name
+const PickListTemplate = "pick-list"
+
+const pickListTemplateBody = `bin,sku,model,texture,qty,refs
+{{range .PickList}}{{csv .Bin}},{{csv .Sku}},{{csv .ModelId}},{{csv .Texture}},{{.Qty}},{{csv (join .Refs " ")}}
+{{end}}`
+
+// PickListRow is one SKU to pick, summed over every line of the batch
+type PickListRow struct {
+	// empty when PICK_LIST_BINS does not map the SKU
+	Bin     string
+	Sku     string
+	ModelId string
+	Texture string
+	Qty     int
+	// the externalRef of the rows the units are for, or their parentNo when
+	// the row has none
+	Refs []string
+}
+
+// BuildPickList sums the lines by SKU and sorts them in walking order: by
+// bin, then model, texture and SKU. SKUs without a bin come last
+func BuildPickList(lines []*model.CleanedOrder, bins entity.PickBins) []*PickListRow {
+	rows := make([]*PickListRow, 0, len(lines))
+	bySku := make(map[string]*PickListRow, len(lines))
+	for _, line := range lines {
+		row, ok := bySku[line.ProductId]
+		if !ok {
+			row = &PickListRow{
+				Bin:     bins.Bin(line.ProductId, line.ModelId),
+				Sku:     line.ProductId,
+				ModelId: line.ModelId,
+				Texture: texture(line.MaterialId),
+			}
+			bySku[line.ProductId] = row
+			rows = append(rows, row)
+		}
+
+		row.Qty += line.Qty
+		if ref := pickRef(line); ref != "" && !containsRef(row.Refs, ref) {
+			row.Refs = append(row.Refs, ref)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if (a.Bin == "") != (b.Bin == "") {
+			return a.Bin != ""
+		}
+		if a.Bin != b.Bin {
+			return a.Bin < b.Bin
+		}
+		if a.ModelId != b.ModelId {
+			return a.ModelId < b.ModelId
+		}
+		if a.Texture != b.Texture {
+			return a.Texture < b.Texture
+		}
+		return a.Sku < b.Sku
+	})
+	return rows
+}
+
+// film-texture
+func texture(materialId string) string {
+	_, texture, _ := strings.Cut(materialId, "-")
+	return texture
+}
+
+// lines summed over the batch have neither
+func pickRef(line *model.CleanedOrder) string {
+	if line.ExternalRef != "" {
+		return line.ExternalRef
+	}
+	if line.ParentNo > 0 {
+		return strconv.Itoa(line.ParentNo)
+	}
+	return ""
+}
+
+func containsRef(refs []string, ref string) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
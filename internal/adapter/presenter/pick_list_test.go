@@ -0,0 +1,76 @@
+package presenter_test
+
+import (
+	"testing"
+	"time"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pickLines() []*model.CleanedOrder {
+	return []*model.CleanedOrder{
+		{No: 1, ProductId: "FG0A-MATTE-OPPOA3", MaterialId: "FG0A-MATTE", ModelId: "OPPOA3", Qty: 1, ParentNo: 1, ExternalRef: "PO-7"},
+		{No: 2, ProductId: "FG0A-CLEAR-IPHONE16PROMAX", MaterialId: "FG0A-CLEAR", ModelId: "IPHONE16PROMAX", Qty: 2, ParentNo: 2},
+		{No: 3, ProductId: "FG0A-CLEAR-OPPOA3", MaterialId: "FG0A-CLEAR", ModelId: "OPPOA3", Qty: 1, ParentNo: 3},
+		{No: 4, ProductId: "FG0A-CLEAR-IPHONE16PROMAX", MaterialId: "FG0A-CLEAR", ModelId: "IPHONE16PROMAX", Qty: 3, ParentNo: 3},
+		{No: 5, ProductId: "WIPING-CLOTH", Qty: 7},
+		{No: 6, ProductId: "CLEAR-CLEANNER", Qty: 6},
+	}
+}
+
+func TestBuildPickList(t *testing.T) {
+	bins := entity.PickBins{"OPPOA3": "A-02", "IPHONE16PROMAX": "A-01", "WIPING-CLOTH": "Z-99"}
+
+	rows := presenter.BuildPickList(pickLines(), bins)
+
+	assert.Equal(t, []*presenter.PickListRow{
+		{Bin: "A-01", Sku: "FG0A-CLEAR-IPHONE16PROMAX", ModelId: "IPHONE16PROMAX", Texture: "CLEAR", Qty: 5, Refs: []string{"2", "3"}},
+		{Bin: "A-02", Sku: "FG0A-CLEAR-OPPOA3", ModelId: "OPPOA3", Texture: "CLEAR", Qty: 1, Refs: []string{"3"}},
+		{Bin: "A-02", Sku: "FG0A-MATTE-OPPOA3", ModelId: "OPPOA3", Texture: "MATTE", Qty: 1, Refs: []string{"PO-7"}},
+		{Bin: "Z-99", Sku: "WIPING-CLOTH", Qty: 7},
+		{Sku: "CLEAR-CLEANNER", Qty: 6},
+	}, rows)
+}
+
+func TestOutputTemplates_PickList(t *testing.T) {
+	templates, err := presenter.NewOutputTemplates(nil, presenter.OutputTemplateOptions{
+		Timeout:     time.Second,
+		MaxBytes:    1024,
+		PricePolicy: value_object.DefaultPricePolicy(),
+		PickBins:    entity.PickBins{"OPPOA3": "A,02"},
+	})
+	require.NoError(t, err)
+
+	tmpl, ok := templates.Lookup("acme", presenter.PickListTemplate)
+	require.True(t, ok)
+	assert.Equal(t, "text/csv", tmpl.ContentType)
+
+	body, err := templates.Render(tmpl, &presenter.TemplateData{Lines: pickLines()[:3]})
+	require.NoError(t, err)
+	assert.Equal(t, "bin,sku,model,texture,qty,refs\n"+
+		`"A,02",FG0A-CLEAR-OPPOA3,OPPOA3,CLEAR,1,3`+"\n"+
+		`"A,02",FG0A-MATTE-OPPOA3,OPPOA3,MATTE,1,PO-7`+"\n"+
+		",FG0A-CLEAR-IPHONE16PROMAX,IPHONE16PROMAX,CLEAR,2,2\n", string(body))
+}
+
+func TestOutputTemplates_PickListOverride(t *testing.T) {
+	templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{
+		"*": {presenter.PickListTemplate: {Body: "{{range .PickList}}{{.Sku}};{{end}}"}, "sap": {Body: "sap"}},
+	}, presenter.OutputTemplateOptions{Timeout: time.Second, MaxBytes: 1024})
+	require.NoError(t, err)
+
+	tmpl, ok := templates.Lookup("acme", presenter.PickListTemplate)
+	require.True(t, ok)
+	body, err := templates.Render(tmpl, &presenter.TemplateData{Lines: pickLines()[4:]})
+	require.NoError(t, err)
+	assert.Equal(t, "CLEAR-CLEANNER;WIPING-CLOTH;", string(body))
+
+	_, ok = templates.Lookup("acme", "sap")
+	assert.True(t, ok)
+}
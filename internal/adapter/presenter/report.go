@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"io"
+	"net/http"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// ReportPresenter answers the admin, metrics and docs endpoints. Their reports
+// are sent bare, without the envelope of the order responses
+type ReportPresenter interface {
+	ReportResponse(c *gin.Context, status int, report interface{})
+	// no body, e.g. 204 once something is gone or 202 once work is queued
+	StatusResponse(c *gin.Context, status int)
+	// write is handed the body once the headers are sent
+	OpenMetricsResponse(c *gin.Context, write func(w io.Writer) error)
+	ErrorResponse(c *gin.Context, err error)
+}
+
+type reportPresenter struct{}
+
+func NewReportPresenter() ReportPresenter {
+	return &reportPresenter{}
+}
+
+func (p *reportPresenter) ReportResponse(c *gin.Context, status int, report interface{}) {
+	c.JSON(status, report)
+}
+
+func (p *reportPresenter) StatusResponse(c *gin.Context, status int) {
+	c.Status(status)
+}
+
+func (p *reportPresenter) OpenMetricsResponse(c *gin.Context, write func(w io.Writer) error) {
+	c.Header("Content-Type", OpenMetricsContentType)
+	c.Status(http.StatusOK)
+	if err := write(c.Writer); err != nil {
+		log.Errorf("failed to write open metrics", log.E(err))
+	}
+}
+
+func (p *reportPresenter) ErrorResponse(c *gin.Context, err error) {
+	errors.MapJsonError(c, err)
+}
@@ -0,0 +1,87 @@
+package presenter_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-placement-system/internal/adapter/presenter"
+	pkgErrors "order-placement-system/pkg/errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportPresenter_ReportResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	presenter.NewReportPresenter().ReportResponse(c, http.StatusCreated, gin.H{"gaps": []string{}})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"gaps":[]}`, w.Body.String())
+}
+
+func TestReportPresenter_StatusResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	presenter.NewReportPresenter().StatusResponse(c, http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestReportPresenter_OpenMetricsResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name  string
+		write func(w io.Writer) error
+		body  string
+	}{
+		{
+			name: "Body is written as is",
+			write: func(w io.Writer) error {
+				_, err := io.WriteString(w, "# EOF\n")
+				return err
+			},
+			body: "# EOF\n",
+		},
+		{
+			name: "A failed write keeps what was written",
+			write: func(w io.Writer) error {
+				io.WriteString(w, "# TYPE")
+				return errors.New("broken")
+			},
+			body: "# TYPE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			presenter.NewReportPresenter().OpenMetricsResponse(c, tt.write)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, presenter.OpenMetricsContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestReportPresenter_ErrorResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	presenter.NewReportPresenter().ErrorResponse(c, pkgErrors.ErrNotFound)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,294 @@
+package presenter
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+const defaultTemplateContentType = "application/json"
+
+// renders running at once when OutputTemplateOptions.MaxRenders is zero
+const DefaultMaxRenders = 16
+
+var (
+	errTemplateOutputTooLarge = stderrors.New("template output too large")
+	errTemplateCancelled      = stderrors.New("template render cancelled")
+)
+
+// tenant -> template name -> template, as configured in OUTPUT_TEMPLATES
+type OutputTemplateSpecs map[string]map[string]*OutputTemplateSpec
+
+type OutputTemplateSpec struct {
+	// defaults to application/json
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+}
+
+type OutputTemplate struct {
+	Name        string
+	ContentType string
+	tmpl        *template.Template
+}
+
+// TemplateData is what a template is executed with
+type TemplateData struct {
+	TenantId  string
+	Lines     []*model.CleanedOrder
+	Warnings  []*model.BatchWarning
+	RowErrors []*model.RowError
+	// nil when no line was weighed
+	ShippingWeight *entity.ShippingWeight
+	// negotiated from Accept-Language, Money formats prices in it
+	Locale value_object.Locale
+	Money  *value_object.PriceFormatter
+	// the lines summed by SKU in bin order, see BuildPickList
+	PickList []*PickListRow
+}
+
+// OutputTemplates renders cleaned orders into the shape a tenant's consumer
+// expects. A render is cut off when it runs past timeout or writes more than
+// maxBytes
+type OutputTemplates struct {
+	templates   map[string]map[string]*OutputTemplate
+	timeout     time.Duration
+	maxBytes    int
+	pricePolicy *value_object.PricePolicy
+	pickBins    entity.PickBins
+	// a render keeps its slot until its template returns, timed out or not
+	renders chan struct{}
+}
+
+type OutputTemplateOptions struct {
+	// a render running past it is cut off, must be positive
+	Timeout time.Duration
+	// a render writing more is cut off, must be positive
+	MaxBytes int
+	// the currency .Money formats, nil means the THB default
+	PricePolicy *value_object.PricePolicy
+	// sort the .PickList, SKUs without a bin come last
+	PickBins entity.PickBins
+	// renders running at once, DefaultMaxRenders when zero. A render that
+	// finds every slot taken fails with errors.ErrServiceUnavailable
+	MaxRenders int
+}
+
+func NewOutputTemplates(specs OutputTemplateSpecs, options OutputTemplateOptions) (*OutputTemplates, error) {
+	if options.Timeout <= 0 || options.MaxBytes <= 0 {
+		return nil, fmt.Errorf("template timeout and max bytes must be positive")
+	}
+	if options.MaxRenders < 0 {
+		return nil, fmt.Errorf("template max renders cannot be negative")
+	}
+	maxRenders := options.MaxRenders
+	if maxRenders == 0 {
+		maxRenders = DefaultMaxRenders
+	}
+
+	templates := make(map[string]map[string]*OutputTemplate, len(specs)+1)
+	if _, ok := specs[entity.DefaultTenantId][PickListTemplate]; !ok {
+		specs = withPickListTemplate(specs)
+	}
+	for tenantId, named := range specs {
+		templates[tenantId] = make(map[string]*OutputTemplate, len(named))
+		for name, spec := range named {
+			if spec == nil || strings.TrimSpace(spec.Body) == "" {
+				return nil, fmt.Errorf("template %s of tenant %s has no body", name, tenantId)
+			}
+
+			tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(spec.Body)
+			if err != nil {
+				return nil, fmt.Errorf("template %s of tenant %s: %w", name, tenantId, err)
+			}
+
+			contentType := spec.ContentType
+			if contentType == "" {
+				contentType = defaultTemplateContentType
+			}
+			templates[tenantId][name] = &OutputTemplate{Name: name, ContentType: contentType, tmpl: tmpl}
+		}
+	}
+
+	return &OutputTemplates{
+		templates:   templates,
+		timeout:     options.Timeout,
+		maxBytes:    options.MaxBytes,
+		pricePolicy: options.PricePolicy,
+		pickBins:    options.PickBins,
+		renders:     make(chan struct{}, maxRenders),
+	}, nil
+}
+
+// a copy of specs with the built-in pick list under "*"
+func withPickListTemplate(specs OutputTemplateSpecs) OutputTemplateSpecs {
+	withPickList := make(OutputTemplateSpecs, len(specs)+1)
+	for tenantId, named := range specs {
+		withPickList[tenantId] = named
+	}
+
+	defaults := make(map[string]*OutputTemplateSpec, len(specs[entity.DefaultTenantId])+1)
+	for name, spec := range specs[entity.DefaultTenantId] {
+		defaults[name] = spec
+	}
+	defaults[PickListTemplate] = &OutputTemplateSpec{ContentType: "text/csv", Body: pickListTemplateBody}
+	withPickList[entity.DefaultTenantId] = defaults
+	return withPickList
+}
+
+// the tenant's own template first, then the one under "*"
+func (t *OutputTemplates) Lookup(tenantId, name string) (*OutputTemplate, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if tmpl, ok := t.templates[tenantId][name]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := t.templates[entity.DefaultTenantId][name]
+	return tmpl, ok
+}
+
+// a template that fails, times out or outgrows the limit is a configuration
+// problem, it is logged and reported as an internal error
+func (t *OutputTemplates) Render(tmpl *OutputTemplate, data *TemplateData) ([]byte, error) {
+	type result struct {
+		body []byte
+		err  error
+	}
+
+	if data.Money == nil {
+		data.Money = value_object.NewPriceFormatter(t.pricePolicy, data.Locale)
+	}
+	if data.PickList == nil {
+		data.PickList = BuildPickList(data.Lines, t.pickBins)
+	}
+
+	// a template cannot be stopped, so timed out renders that are still
+	// running hold their slot and cannot pile up goroutines without bound
+	select {
+	case t.renders <- struct{}{}:
+	default:
+		log.Errorf("no output template render slot free",
+			log.S("template", tmpl.Name),
+			log.S("tenant_id", data.TenantId))
+		return nil, errors.ErrServiceUnavailable
+	}
+
+	out := &limitedBuffer{max: t.maxBytes}
+	// buffered, a render that timed out finishes into it and is dropped
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-t.renders }()
+		err := tmpl.tmpl.Execute(out, data)
+		done <- result{body: out.Bytes(), err: err}
+	}()
+
+	timer := time.NewTimer(t.timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			log.Errorf("failed to render output template",
+				log.S("template", tmpl.Name),
+				log.S("tenant_id", data.TenantId),
+				log.E(res.err))
+			return nil, errors.ErrInternalServer
+		}
+		return res.body, nil
+	case <-timer.C:
+		// its next write fails, which ends the render early
+		out.cancelled.Store(true)
+		log.Errorf("output template timed out",
+			log.S("template", tmpl.Name),
+			log.S("tenant_id", data.TenantId),
+			log.S("timeout", t.timeout.String()))
+		return nil, errors.ErrInternalServer
+	}
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	cancelled atomic.Bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.cancelled.Load() {
+		return 0, errTemplateCancelled
+	}
+	if b.Len()+len(p) > b.max {
+		return 0, errTemplateOutputTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+// arithmetic takes any number, prices included, so derived columns such as
+// {{mul .UnitPrice.Amount .Qty}} need no conversion
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"add": func(a, b interface{}) (float64, error) {
+		return applyNumbers(a, b, func(x, y float64) float64 { return x + y })
+	},
+	"sub": func(a, b interface{}) (float64, error) {
+		return applyNumbers(a, b, func(x, y float64) float64 { return x - y })
+	},
+	"mul": func(a, b interface{}) (float64, error) {
+		return applyNumbers(a, b, func(x, y float64) float64 { return x * y })
+	},
+	"round": func(v interface{}, places int) (float64, error) {
+		x, err := toNumber(v)
+		if err != nil {
+			return 0, err
+		}
+		scale := math.Pow(10, float64(places))
+		return math.Round(x*scale) / scale, nil
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	// quotes a CSV field when it holds a comma, quote or line break
+	"csv": func(field string) string {
+		if !strings.ContainsAny(field, ",\"\r\n") {
+			return field
+		}
+		return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+	},
+}
+
+func applyNumbers(a, b interface{}, op func(x, y float64) float64) (float64, error) {
+	x, err := toNumber(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	return op(x, y), nil
+}
+
+func toNumber(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	default:
+		return 0, fmt.Errorf("not a number: %v", v)
+	}
+}
@@ -0,0 +1,212 @@
+package presenter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/value_object"
+	pkgErrors "order-placement-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOutputTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		specs     presenter.OutputTemplateSpecs
+		timeout   time.Duration
+		maxBytes  int
+		expectErr bool
+	}{
+		{"Valid", presenter.OutputTemplateSpecs{"acme": {"sap": {Body: "{{range .Lines}}{{.ProductId}}{{end}}"}}}, time.Second, 1024, false},
+		{"Empty body", presenter.OutputTemplateSpecs{"acme": {"sap": {Body: " "}}}, time.Second, 1024, true},
+		{"Syntax error", presenter.OutputTemplateSpecs{"acme": {"sap": {Body: "{{range .Lines}}"}}}, time.Second, 1024, true},
+		{"Unknown function", presenter.OutputTemplateSpecs{"acme": {"sap": {Body: "{{exec .Lines}}"}}}, time.Second, 1024, true},
+		{"No timeout", presenter.OutputTemplateSpecs{}, 0, 1024, true},
+		{"No size limit", presenter.OutputTemplateSpecs{}, time.Second, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := presenter.NewOutputTemplates(tt.specs, presenter.OutputTemplateOptions{Timeout: tt.timeout, MaxBytes: tt.maxBytes})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOutputTemplates_Lookup(t *testing.T) {
+	templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{
+		"*":    {"csv": {ContentType: "text/csv", Body: "default"}, "sap": {Body: "default"}},
+		"acme": {"sap": {Body: "acme"}},
+	}, presenter.OutputTemplateOptions{Timeout: time.Second, MaxBytes: 1024})
+	require.NoError(t, err)
+
+	tmpl, ok := templates.Lookup("acme", "sap")
+	require.True(t, ok)
+	assert.Equal(t, "application/json", tmpl.ContentType)
+	body, err := templates.Render(tmpl, &presenter.TemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", string(body), "the tenant's own template wins")
+
+	tmpl, ok = templates.Lookup("acme", "csv")
+	require.True(t, ok)
+	assert.Equal(t, "text/csv", tmpl.ContentType)
+
+	_, ok = templates.Lookup("acme", "unknown")
+	assert.False(t, ok)
+
+	var none *presenter.OutputTemplates
+	_, ok = none.Lookup("acme", "sap")
+	assert.False(t, ok)
+}
+
+func TestOutputTemplates_Render(t *testing.T) {
+	data := &presenter.TemplateData{
+		TenantId: "acme",
+		Lines: []*model.CleanedOrder{
+			{No: 1, ProductId: "FG0A-CLEAR-OPPOA3", Qty: 2, UnitPrice: value_object.MustNewPrice(12.5), TotalPrice: value_object.MustNewPrice(25)},
+			{No: 2, ProductId: "wiping-cloth", Qty: 2, UnitPrice: value_object.ZeroPrice(), TotalPrice: value_object.ZeroPrice()},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		maxBytes  int
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "Renamed fields and derived column",
+			body:     `{{.TenantId}}{{range .Lines}};{{upper .ProductId}},{{.Qty}},{{round (mul .UnitPrice.Amount .Qty) 2}}{{end}}`,
+			maxBytes: 1024,
+			expected: "acme;FG0A-CLEAR-OPPOA3,2,25;WIPING-CLOTH,2,0",
+		},
+		{
+			name:     "Nested JSON",
+			body:     `{"order":{"items":[{{range $i, $l := .Lines}}{{if $i}},{{end}}{"sku":{{json $l.ProductId}}}{{end}}]}}`,
+			maxBytes: 1024,
+			expected: `{"order":{"items":[{"sku":"FG0A-CLEAR-OPPOA3"},{"sku":"wiping-cloth"}]}}`,
+		},
+		{
+			name:      "Missing field fails",
+			body:      `{{range .Lines}}{{.Sku}}{{end}}`,
+			maxBytes:  1024,
+			expectErr: true,
+		},
+		{
+			name:      "Output over the limit fails",
+			body:      `{{range .Lines}}{{.ProductId}}{{end}}`,
+			maxBytes:  10,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{"acme": {"t": {Body: tt.body}}}, presenter.OutputTemplateOptions{Timeout: time.Second, MaxBytes: tt.maxBytes})
+			require.NoError(t, err)
+			tmpl, ok := templates.Lookup("acme", "t")
+			require.True(t, ok)
+
+			body, err := templates.Render(tmpl, data)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, pkgErrors.ErrInternalServer)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+}
+
+func TestOutputTemplates_Render_Money(t *testing.T) {
+	eur, err := value_object.NewPricePolicy("EUR", 0)
+	require.NoError(t, err)
+
+	body := `{{range .Lines}}{{$.Money.Format .UnitPrice}}|{{$.Money.FormatAmount (mul .UnitPrice.Amount .Qty)}}{{end}}`
+	lines := []*model.CleanedOrder{{ProductId: "FG0A-CLEAR-OPPOA3", Qty: 2, UnitPrice: value_object.MustNewPrice(1250)}}
+
+	t.Run("Thai baht by default", func(t *testing.T) {
+		templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{"*": {"receipt": {Body: body}}}, presenter.OutputTemplateOptions{Timeout: time.Second, MaxBytes: 1024})
+		require.NoError(t, err)
+		tmpl, _ := templates.Lookup("acme", "receipt")
+
+		out, err := templates.Render(tmpl, &presenter.TemplateData{Lines: lines})
+		require.NoError(t, err)
+		assert.Equal(t, "฿1,250.00|฿2,500.00", string(out))
+	})
+
+	t.Run("Policy currency in the negotiated locale", func(t *testing.T) {
+		templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{"*": {"receipt": {Body: body}}}, presenter.OutputTemplateOptions{
+			Timeout:     time.Second,
+			MaxBytes:    1024,
+			PricePolicy: eur,
+		})
+		require.NoError(t, err)
+		tmpl, _ := templates.Lookup("acme", "receipt")
+
+		out, err := templates.Render(tmpl, &presenter.TemplateData{Lines: lines, Locale: value_object.LocaleGerman})
+		require.NoError(t, err)
+		assert.Equal(t, "1.250,00 €|2.500,00 €", string(out))
+	})
+}
+
+func TestOutputTemplates_Render_Timeout(t *testing.T) {
+	lines := make([]*model.CleanedOrder, 2000)
+	for i := range lines {
+		lines[i] = &model.CleanedOrder{No: i + 1}
+	}
+
+	// four million iterations, far longer than the timeout
+	templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{
+		"acme": {"slow": {Body: `{{range .Lines}}{{range $.Lines}}{{end}}{{end}}`}},
+	}, presenter.OutputTemplateOptions{Timeout: time.Millisecond, MaxBytes: 1024})
+	require.NoError(t, err)
+	tmpl, _ := templates.Lookup("acme", "slow")
+
+	_, err = templates.Render(tmpl, &presenter.TemplateData{Lines: lines})
+	assert.ErrorIs(t, err, pkgErrors.ErrInternalServer)
+}
+
+func TestOutputTemplates_Render_TimedOutRenderKeepsItsSlot(t *testing.T) {
+	lines := make([]*model.CleanedOrder, 2000)
+	for i := range lines {
+		lines[i] = &model.CleanedOrder{No: i + 1}
+	}
+
+	templates, err := presenter.NewOutputTemplates(presenter.OutputTemplateSpecs{
+		"acme": {"slow": {Body: `{{range .Lines}}{{range $.Lines}}{{end}}{{end}}`}, "fast": {Body: "ok"}},
+	}, presenter.OutputTemplateOptions{Timeout: time.Millisecond, MaxBytes: 1024, MaxRenders: 1})
+	require.NoError(t, err)
+	slow, _ := templates.Lookup("acme", "slow")
+	fast, _ := templates.Lookup("acme", "fast")
+
+	_, err = templates.Render(slow, &presenter.TemplateData{Lines: lines})
+	require.ErrorIs(t, err, pkgErrors.ErrInternalServer)
+
+	_, err = templates.Render(fast, &presenter.TemplateData{})
+	assert.ErrorIs(t, err, pkgErrors.ErrServiceUnavailable, "the timed out render still runs")
+}
+
+func TestOrderPresenter_RawResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	presenter.NewOrderPresenter().RawResponse(c, "text/csv", []byte("no,productId\n1,FG0A-CLEAR-OPPOA3\n"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no,productId\n1,FG0A-CLEAR-OPPOA3\n", w.Body.String())
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// what the row's discount and surcharge add to its total, negative for a
+// net discount
+func (o *InputOrder) Adjustment() float64 {
+	adjustment := 0.0
+	if o.Surcharge != nil {
+		adjustment += o.Surcharge.Amount()
+	}
+	if o.Discount != nil {
+		adjustment -= o.Discount.Amount()
+	}
+	return adjustment
+}
+
+func (o *InputOrder) HasAdjustment() bool {
+	return (o.Discount != nil && o.Discount.IsPositive()) || (o.Surcharge != nil && o.Surcharge.IsPositive())
+}
+
+// AllocateAdjustment spreads adjustment across products in proportion to
+// their totals (their units when every total is zero). Each product keeps its
+// gross prices in GrossUnitPrice and GrossTotalPrice, the last one takes the
+// rounding remainder so the net totals add up to the adjusted row total. A
+// discount larger than the row is rejected
+func AllocateAdjustment(products []*Product, adjustment float64, policy *value_object.PricePolicy) error {
+	if len(products) == 0 || adjustment == 0 {
+		return nil
+	}
+	if policy == nil {
+		policy = value_object.DefaultPricePolicy()
+	}
+
+	gross, units := 0.0, 0
+	for _, product := range products {
+		gross += product.TotalPrice.Amount()
+		units += product.Quantity
+	}
+
+	if policy.Round(gross+adjustment) < 0 {
+		log.Errorf("discount exceeds row total",
+			log.AtoS("gross", gross),
+			log.AtoS("adjustment", adjustment))
+		return errors.Categorize(errors.CategoryBusinessRule, errors.ErrInvalidInput)
+	}
+
+	netTotals := make([]float64, len(products))
+	allocated := 0.0
+	for i, product := range products {
+		share := float64(product.Quantity) / float64(units)
+		if gross > 0 {
+			share = product.TotalPrice.Amount() / gross
+		}
+
+		if i == len(products)-1 {
+			netTotals[i] = policy.Round(gross + adjustment - allocated)
+		} else {
+			netTotals[i] = policy.Round(product.TotalPrice.Amount() + adjustment*share)
+			allocated += netTotals[i]
+		}
+
+		if netTotals[i] < 0 {
+			log.Errorf("adjustment leaves a negative line total", log.S("product_id", product.ProductId))
+			return errors.ErrInvalidInput
+		}
+	}
+
+	for i, product := range products {
+		totalPrice, err := value_object.NewPrice(netTotals[i])
+		if err != nil {
+			return err
+		}
+		unitPrice, err := value_object.NewPrice(policy.Round(netTotals[i] / float64(product.Quantity)))
+		if err != nil {
+			return err
+		}
+
+		product.GrossUnitPrice = product.UnitPrice
+		product.GrossTotalPrice = product.TotalPrice
+		product.UnitPrice = unitPrice
+		product.TotalPrice = totalPrice
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputOrder_Adjustment(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    *entity.InputOrder
+		expected float64
+		adjusted bool
+	}{
+		{"None", &entity.InputOrder{}, 0, false},
+		{"Zero discount", &entity.InputOrder{Discount: value_object.ZeroPrice()}, 0, false},
+		{"Discount", &entity.InputOrder{Discount: value_object.MustNewPrice(20)}, -20, true},
+		{"Surcharge", &entity.InputOrder{Surcharge: value_object.MustNewPrice(5)}, 5, true},
+		{"Both", &entity.InputOrder{Discount: value_object.MustNewPrice(20), Surcharge: value_object.MustNewPrice(5)}, -15, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.order.Adjustment())
+			assert.Equal(t, tt.adjusted, tt.order.HasAdjustment())
+		})
+	}
+}
+
+func TestAllocateAdjustment(t *testing.T) {
+	product := func(qty int, total float64) *entity.Product {
+		return &entity.Product{
+			ProductId:  "FG0A-CLEAR-OPPOA3",
+			Quantity:   qty,
+			UnitPrice:  value_object.MustNewPrice(total / float64(qty)),
+			TotalPrice: value_object.MustNewPrice(total),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		products       []*entity.Product
+		adjustment     float64
+		expectErr      bool
+		expectedTotals []float64
+		expectedUnits  []float64
+	}{
+		{
+			name:           "Discount split by line total",
+			products:       []*entity.Product{product(1, 60), product(2, 40)},
+			adjustment:     -10,
+			expectedTotals: []float64{54, 36},
+			expectedUnits:  []float64{54, 18},
+		},
+		{
+			name:           "Surcharge split by line total",
+			products:       []*entity.Product{product(1, 75), product(1, 25)},
+			adjustment:     4,
+			expectedTotals: []float64{78, 26},
+			expectedUnits:  []float64{78, 26},
+		},
+		{
+			name:           "Last line takes the rounding remainder",
+			products:       []*entity.Product{product(1, 100), product(1, 100), product(1, 100)},
+			adjustment:     -10,
+			expectedTotals: []float64{96.67, 96.67, 96.66},
+			expectedUnits:  []float64{96.67, 96.67, 96.66},
+		},
+		{
+			name:           "Zero totals split by units",
+			products:       []*entity.Product{product(1, 0), product(3, 0)},
+			adjustment:     8,
+			expectedTotals: []float64{2, 6},
+			expectedUnits:  []float64{2, 2},
+		},
+		{
+			name:           "Discount down to zero",
+			products:       []*entity.Product{product(1, 50)},
+			adjustment:     -50,
+			expectedTotals: []float64{0},
+			expectedUnits:  []float64{0},
+		},
+		{
+			name:       "Discount above the row total",
+			products:   []*entity.Product{product(1, 50)},
+			adjustment: -50.01,
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gross := make([]float64, len(tt.products))
+			for i, p := range tt.products {
+				gross[i] = p.TotalPrice.Amount()
+			}
+
+			err := entity.AllocateAdjustment(tt.products, tt.adjustment, nil)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for i, p := range tt.products {
+				assert.InDelta(t, tt.expectedTotals[i], p.TotalPrice.Amount(), 0.001, "line %d total", i)
+				assert.InDelta(t, tt.expectedUnits[i], p.UnitPrice.Amount(), 0.001, "line %d unit", i)
+				assert.Equal(t, gross[i], p.GrossTotalPrice.Amount(), "line %d gross", i)
+			}
+		})
+	}
+
+	t.Run("No adjustment leaves products alone", func(t *testing.T) {
+		products := []*entity.Product{product(1, 50)}
+		require.NoError(t, entity.AllocateAdjustment(products, 0, nil))
+		assert.Nil(t, products[0].GrossTotalPrice)
+		assert.Equal(t, 50.0, products[0].TotalPrice.Amount())
+	})
+}
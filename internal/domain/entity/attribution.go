@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// PromoRule identifies the rule that generated a complementary line
+type PromoRule string
+
+const (
+	// a wiping cloth per film (or cloth accessory) unit
+	RuleWipingCloth PromoRule = "wiping-cloth-per-unit"
+	// a cleaner of the film's texture per film unit
+	RuleTextureCleaner PromoRule = "cleaner-per-texture"
+	// a cloth and a cleaner packed into one kit
+	RuleCareKit PromoRule = "care-kit"
+)
+
+var AllPromoRules = []PromoRule{RuleWipingCloth, RuleTextureCleaner, RuleCareKit}
+
+func (r PromoRule) IsValid() bool {
+	for _, rule := range AllPromoRules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Attribution is what generated a complementary line, for giveaway costs
+type Attribution struct {
+	RuleId       PromoRule `json:"ruleId"`
+	CampaignCode string    `json:"campaignCode,omitempty"`
+}
+
+// PromoCampaigns maps a rule to the campaign code its lines are attributed to,
+// e.g. {"cleaner-per-texture": "SCREEN-CARE-2026"}
+type PromoCampaigns map[PromoRule]string
+
+func (c PromoCampaigns) Validate() error {
+	for rule, campaignCode := range c {
+		if !rule.IsValid() {
+			log.Errorf("campaign for an unknown promo rule", log.S("rule", string(rule)))
+			return errors.ErrInvalidInput
+		}
+		if campaignCode == "" {
+			log.Errorf("campaign code cannot be empty", log.S("rule", string(rule)))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// Attribute sets the campaign code of every line whose rule has a campaign,
+// lines without a rule are left as they are
+func (c PromoCampaigns) Attribute(lines []*CleanedOrder) {
+	for _, line := range lines {
+		if line.Attribution == nil {
+			continue
+		}
+		if campaignCode, ok := c[line.Attribution.RuleId]; ok {
+			line.Attribution = &Attribution{RuleId: line.Attribution.RuleId, CampaignCode: campaignCode}
+		}
+	}
+}
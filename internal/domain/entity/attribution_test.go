@@ -0,0 +1,67 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoCampaigns_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		campaigns entity.PromoCampaigns
+		expectErr bool
+	}{
+		{"Every rule", entity.PromoCampaigns{entity.RuleWipingCloth: "A", entity.RuleTextureCleaner: "B", entity.RuleCareKit: "C"}, false},
+		{"Empty", entity.PromoCampaigns{}, false},
+		{"Unknown rule", entity.PromoCampaigns{"buy-one-get-one": "A"}, true},
+		{"Empty campaign code", entity.PromoCampaigns{entity.RuleCareKit: ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.campaigns.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPromoCampaigns_Attribute(t *testing.T) {
+	cleanerRule := &entity.Attribution{RuleId: entity.RuleTextureCleaner}
+	lines := []*entity.CleanedOrder{
+		{ProductId: "WIPING-CLOTH", Qty: 2, Attribution: &entity.Attribution{RuleId: entity.RuleWipingCloth}},
+		{ProductId: "CLEAR-CLEANNER", Qty: 1, Attribution: cleanerRule},
+		{ProductId: "PROMO-STICKER", Qty: 1},
+	}
+
+	entity.PromoCampaigns{entity.RuleWipingCloth: "SCREEN-CARE-2026"}.Attribute(lines)
+
+	assert.Equal(t, &entity.Attribution{RuleId: entity.RuleWipingCloth, CampaignCode: "SCREEN-CARE-2026"}, lines[0].Attribution)
+	assert.Equal(t, &entity.Attribution{RuleId: entity.RuleTextureCleaner}, lines[1].Attribution, "rules without a campaign keep only their id")
+	assert.Nil(t, lines[2].Attribution)
+}
+
+func TestComplementaryRules(t *testing.T) {
+	film, err := entity.NewProduct("FG0A-CLEAR-IPHONE16PROMAX", 2, value_object.ZeroPrice(), value_object.ZeroPrice())
+	require.NoError(t, err)
+	calculation, err := entity.CalculateComplementaryItems([]*entity.Product{film})
+	require.NoError(t, err)
+
+	lines := calculation.ToCleanedOrders(1)
+	require.Len(t, lines, 2)
+	assert.Equal(t, entity.RuleWipingCloth, lines[0].Attribution.RuleId)
+	assert.Equal(t, entity.RuleTextureCleaner, lines[1].Attribution.RuleId)
+
+	kits := entity.BundleIntoKits(lines)
+	require.Len(t, kits, 1)
+	assert.Equal(t, "CARE-KIT-CLEAR", kits[0].ProductId)
+	assert.Equal(t, entity.RuleCareKit, kits[0].Attribution.RuleId)
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// BatchUsage is what processing one batch cost. CPU time and allocated bytes
+// are the whole process's while the batch ran, so batches running at the same
+// time inflate each other's
+type BatchUsage struct {
+	TenantId       string    `json:"tenantId,omitempty"`
+	Rows           int       `json:"rows"`
+	WallTimeMs     float64   `json:"wallTimeMs"`
+	CPUTimeMs      float64   `json:"cpuTimeMs"`
+	AllocatedBytes uint64    `json:"allocatedBytes"`
+	RowsPerSecond  float64   `json:"rowsPerSecond"`
+	ProcessedAt    time.Time `json:"processedAt"`
+}
+
+func NewBatchUsage(tenantId string, rows int, wall, cpu time.Duration, allocatedBytes uint64, processedAt time.Time) *BatchUsage {
+	usage := &BatchUsage{
+		TenantId:       tenantId,
+		Rows:           rows,
+		WallTimeMs:     float64(wall) / float64(time.Millisecond),
+		CPUTimeMs:      float64(cpu) / float64(time.Millisecond),
+		AllocatedBytes: allocatedBytes,
+		ProcessedAt:    processedAt,
+	}
+	if wall > 0 {
+		usage.RowsPerSecond = float64(rows) / wall.Seconds()
+	}
+	return usage
+}
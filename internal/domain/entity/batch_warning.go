@@ -0,0 +1,82 @@
+package entity
+
+type BatchWarningCode string
+
+const (
+	// average unit price of the batch is far from the catalog price
+	WarningUnitPriceDeviation BatchWarningCode = "UNIT_PRICE_DEVIATION"
+	// too many rows carry platform prefixes such as "--" or "x2-"
+	WarningPrefixedRows BatchWarningCode = "PREFIXED_ROWS"
+	// too many rows are "/" bundles
+	WarningBundleRatio BatchWarningCode = "BUNDLE_RATIO"
+	// out of stock cleaners were replaced, raised for any batch
+	WarningCleanerSubstituted BatchWarningCode = "CLEANER_SUBSTITUTED"
+	// lines whose unit price is outside the tolerance band around the price
+	// list, e.g. a total typed as 8000 instead of 80. Raised for any batch
+	WarningUnitPriceOutlier BatchWarningCode = "UNIT_PRICE_OUTLIER"
+	// complementary caps withheld free items from low value orders, raised
+	// for any batch
+	WarningComplementaryCapped BatchWarningCode = "COMPLEMENTARY_CAPPED"
+	// main lines in a texture their film type is not made in, raised for any
+	// batch when the strictness is warn
+	WarningIncompatibleTexture BatchWarningCode = "INCOMPATIBLE_TEXTURE"
+	// lines whose product code was separated with underscores, raised for
+	// any batch
+	WarningUnderscoreSeparators BatchWarningCode = "UNDERSCORE_SEPARATORS"
+	// lines of bundles sent with empty "/" segments, which were dropped.
+	// Raised for any batch
+	WarningEmptyBundleSegments BatchWarningCode = "EMPTY_BUNDLE_SEGMENTS"
+	// main lines whose texture was read through TEXTURE_ALIASES or an alias
+	// added at runtime, raised for any batch
+	WarningTextureAliased BatchWarningCode = "TEXTURE_ALIASED"
+	// main lines whose seller variant token was moved out of the model id,
+	// raised for any batch
+	WarningVariantExtracted BatchWarningCode = "VARIANT_EXTRACTED"
+	// main lines whose model suffix MODEL_SUFFIX_ALIASES rewrote, raised for
+	// any batch
+	WarningModelSuffixNormalized BatchWarningCode = "MODEL_SUFFIX_NORMALIZED"
+)
+
+// the codes of the rewrites a line can carry, in the order they are reported
+var rewriteWarnings = []BatchWarningCode{
+	WarningUnderscoreSeparators,
+	WarningEmptyBundleSegments,
+	WarningTextureAliased,
+	WarningVariantExtracted,
+	WarningModelSuffixNormalized,
+}
+
+// RewriteWarnings returns a copy of the codes a line's Rewrites can hold
+func RewriteWarnings() []BatchWarningCode {
+	return append([]BatchWarningCode(nil), rewriteWarnings...)
+}
+
+// BatchWarning flags a batch that processed fine but looks like a corrupted
+// export, or that did not ship exactly what was asked for
+type BatchWarning struct {
+	Code      BatchWarningCode `json:"code"`
+	Value     float64          `json:"value"`
+	Threshold float64          `json:"threshold"`
+	// the output lines the warning is about, when it is about some
+	Lines []int `json:"lines,omitempty"`
+}
+
+// zero disables a check, rates and the price deviation are fractions (0.5 = 50%)
+type BatchWarningThresholds struct {
+	MinRows            int
+	UnitPriceDeviation float64
+	PrefixedRowRate    float64
+	BundleRatio        float64
+	// tolerance band of a line's unit price over its price list price, e.g.
+	// 0.5 and 2 accept half to twice the listed price
+	UnitPriceMinRatio float64
+	UnitPriceMaxRatio float64
+}
+
+func NewBatchWarning(code BatchWarningCode, value, threshold float64) *BatchWarning {
+	return &BatchWarning{
+		Code:      code,
+		Value:     value,
+		Threshold: threshold,
+	}
+}
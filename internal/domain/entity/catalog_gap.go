@@ -0,0 +1,22 @@
+package entity
+
+// CatalogGapKind is the part of a product code the catalog did not know
+type CatalogGapKind string
+
+const (
+	CatalogGapFilmType CatalogGapKind = "film-type"
+	CatalogGapTexture  CatalogGapKind = "texture"
+)
+
+func (k CatalogGapKind) IsValid() bool {
+	return k == CatalogGapFilmType || k == CatalogGapTexture
+}
+
+// CatalogGap is one product code that failed on an unknown film type or
+// texture, Value is the unknown part as the parser saw it
+type CatalogGap struct {
+	Kind              CatalogGapKind `json:"kind"`
+	Value             string         `json:"value"`
+	No                int            `json:"no"`
+	PlatformProductId string         `json:"platformProductId"`
+}
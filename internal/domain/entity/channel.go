@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// ComplementaryPolicy decides whether rows of a sales channel get free items
+type ComplementaryPolicy string
+
+const (
+	// wiping cloths and cleaners as the complementary rules say
+	ComplementaryFull ComplementaryPolicy = "full"
+	// no complementary items at all, e.g. for wholesale
+	ComplementaryNone ComplementaryPolicy = "none"
+)
+
+func (p ComplementaryPolicy) IsValid() bool {
+	return p == ComplementaryFull || p == ComplementaryNone
+}
+
+// ChannelPolicies maps a sales channel to its complementary policy, e.g.
+// {"wholesale": "none"}. Channels are matched case insensitively, rows of
+// other channels and rows without one get the full policy
+type ChannelPolicies map[string]ComplementaryPolicy
+
+func (c ChannelPolicies) Validate() error {
+	for channel, policy := range c {
+		if strings.TrimSpace(channel) == "" {
+			log.Errorf("complementary policy for an empty channel")
+			return errors.ErrInvalidInput
+		}
+		if !policy.IsValid() {
+			log.Errorf("unknown complementary policy", log.S("channel", channel), log.S("policy", string(policy)))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+func (c ChannelPolicies) For(channel string) ComplementaryPolicy {
+	channel = strings.TrimSpace(channel)
+	if channel == "" {
+		return ComplementaryFull
+	}
+	for configured, policy := range c {
+		if strings.EqualFold(strings.TrimSpace(configured), channel) {
+			return policy
+		}
+	}
+	return ComplementaryFull
+}
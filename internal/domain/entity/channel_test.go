@@ -0,0 +1,56 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelPolicies_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		policies  entity.ChannelPolicies
+		expectErr bool
+	}{
+		{"Empty", nil, false},
+		{"Known policies", entity.ChannelPolicies{"wholesale": entity.ComplementaryNone, "retail": entity.ComplementaryFull}, false},
+		{"Unknown policy", entity.ChannelPolicies{"wholesale": "half"}, true},
+		{"Empty channel", entity.ChannelPolicies{" ": entity.ComplementaryNone}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policies.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChannelPolicies_For(t *testing.T) {
+	policies := entity.ChannelPolicies{"wholesale": entity.ComplementaryNone}
+
+	tests := []struct {
+		name    string
+		channel string
+		expect  entity.ComplementaryPolicy
+	}{
+		{"Listed channel", "wholesale", entity.ComplementaryNone},
+		{"Case insensitive", " WholeSale ", entity.ComplementaryNone},
+		{"Unlisted channel", "retail", entity.ComplementaryFull},
+		{"No channel", "", entity.ComplementaryFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, policies.For(tt.channel))
+		})
+	}
+
+	assert.Equal(t, entity.ComplementaryFull, entity.ChannelPolicies(nil).For("wholesale"))
+}
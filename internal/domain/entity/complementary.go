@@ -4,6 +4,7 @@ import (
 	"order-placement-system/internal/domain/value_object"
 	"order-placement-system/pkg/errors"
 	"order-placement-system/pkg/log"
+	"sort"
 	"strings"
 )
 
@@ -35,6 +36,15 @@ func (c *ComplementaryCalculation) AddProduct(product *Product) error {
 		return errors.ErrInvalidInput
 	}
 
+	// accessories are not films and have no texture, so no cleaner comes with
+	// them. Only those configured to complement cloths get one per unit
+	if product.IsAccessory {
+		if product.ComplementsCloth {
+			c.addWipingCloths(product.Quantity)
+		}
+		return nil
+	}
+
 	texture := product.GetTexture()
 	if texture == "" {
 		log.Errorf("product does not have a valid texture", log.S("productId", product.ProductId))
@@ -47,13 +57,7 @@ func (c *ComplementaryCalculation) AddProduct(product *Product) error {
 	}
 
 	// add Wiping Cloth 1:1
-	if c.WipingCloth == nil {
-		c.WipingCloth = &ComplementaryItem{
-			ProductId: WipingClothProductId,
-			Quantity:  0,
-		}
-	}
-	c.WipingCloth.Quantity += product.Quantity
+	c.addWipingCloths(product.Quantity)
 
 	// add Cleaner based on texture
 	cleanerId := generateCleanerId(texture)
@@ -68,6 +72,16 @@ func (c *ComplementaryCalculation) AddProduct(product *Product) error {
 	return nil
 }
 
+func (c *ComplementaryCalculation) addWipingCloths(quantity int) {
+	if c.WipingCloth == nil {
+		c.WipingCloth = &ComplementaryItem{
+			ProductId: WipingClothProductId,
+			Quantity:  0,
+		}
+	}
+	c.WipingCloth.Quantity += quantity
+}
+
 // converts the complementary calculation to a list of cleaned orders
 func (c *ComplementaryCalculation) ToCleanedOrders(startingNo int) []*CleanedOrder {
 	var orders []*CleanedOrder
@@ -75,25 +89,25 @@ func (c *ComplementaryCalculation) ToCleanedOrders(startingNo int) []*CleanedOrd
 
 	if c.WipingCloth != nil && c.WipingCloth.Quantity > 0 {
 		orders = append(orders, &CleanedOrder{
-			No:         currentNo,
-			ProductId:  c.WipingCloth.ProductId,
-			Qty:        c.WipingCloth.Quantity,
-			UnitPrice:  value_object.ZeroPrice(),
-			TotalPrice: value_object.ZeroPrice(),
+			No:          currentNo,
+			ProductId:   c.WipingCloth.ProductId,
+			Qty:         c.WipingCloth.Quantity,
+			UnitPrice:   value_object.ZeroPrice(),
+			TotalPrice:  value_object.ZeroPrice(),
+			Attribution: &Attribution{RuleId: RuleWipingCloth},
 		})
 		currentNo++
 	}
 
-	// FIXME: Improve memory space usage
-	textures := []string{"CLEAR", "MATTE", "PRIVACY"}
-	for _, texture := range textures {
-		if cleaner, exists := c.Cleaners[texture]; exists && cleaner.Quantity > 0 {
+	for _, texture := range value_object.DefaultTexturePriorities().Sorted() {
+		if cleaner, exists := c.Cleaners[texture.String()]; exists && cleaner.Quantity > 0 {
 			orders = append(orders, &CleanedOrder{
-				No:         currentNo,
-				ProductId:  cleaner.ProductId,
-				Qty:        cleaner.Quantity,
-				UnitPrice:  value_object.ZeroPrice(),
-				TotalPrice: value_object.ZeroPrice(),
+				No:          currentNo,
+				ProductId:   cleaner.ProductId,
+				Qty:         cleaner.Quantity,
+				UnitPrice:   value_object.ZeroPrice(),
+				TotalPrice:  value_object.ZeroPrice(),
+				Attribution: &Attribution{RuleId: RuleTextureCleaner},
 			})
 			currentNo++
 		}
@@ -141,6 +155,44 @@ func (c *ComplementaryCalculation) GetTotalComplementaryValue(
 	return totalValue, nil
 }
 
+// ComplementaryOrdering lists complementary product ids in the order they are
+// returned: the wiping cloth, then cleaners and then kits by texture priority,
+// nil priorities are the defaults. Lines not listed (promo items) come after
+// them in the order their rule produced them
+func ComplementaryOrdering(priorities value_object.TexturePriorities) []string {
+	textures := priorities.Sorted()
+	ordering := []string{WipingClothProductId}
+	for _, texture := range textures {
+		ordering = append(ordering, texture.GetCleanerProductId())
+	}
+	for _, texture := range textures {
+		ordering = append(ordering, KitProductId(texture))
+	}
+	return ordering
+}
+
+// sorts lines by ParentNo, then by ComplementaryOrdering. The sort is stable
+// so unlisted lines keep their relative order
+func SortComplementaryLines(lines []*CleanedOrder, priorities value_object.TexturePriorities) {
+	ranks := make(map[string]int)
+	for i, productId := range ComplementaryOrdering(priorities) {
+		ranks[productId] = i
+	}
+	rank := func(productId string) int {
+		if r, ok := ranks[productId]; ok {
+			return r
+		}
+		return len(ranks)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].ParentNo != lines[j].ParentNo {
+			return lines[i].ParentNo < lines[j].ParentNo
+		}
+		return rank(lines[i].ProductId) < rank(lines[j].ProductId)
+	})
+}
+
 func generateCleanerId(texture string) string {
 	return strings.ToUpper(texture) + CleanerSuffix
 }
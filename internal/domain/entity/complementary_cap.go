@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"math"
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// ComplementaryCapItem is the kind of free item a cap limits
+type ComplementaryCapItem string
+
+const (
+	ComplementaryCapCleaner ComplementaryCapItem = "cleaner"
+	ComplementaryCapCloth   ComplementaryCapItem = "cloth"
+)
+
+func (i ComplementaryCapItem) IsValid() bool {
+	return i == ComplementaryCapCleaner || i == ComplementaryCapCloth
+}
+
+func (i ComplementaryCapItem) Matches(productId string) bool {
+	switch i {
+	case ComplementaryCapCleaner:
+		return strings.HasSuffix(productId, CleanerSuffix)
+	case ComplementaryCapCloth:
+		return productId == WipingClothProductId
+	}
+	return false
+}
+
+// ComplementaryCapScope is the order value a cap is measured against
+type ComplementaryCapScope string
+
+const (
+	// every row earns its own allowance from its own value
+	ComplementaryCapPerRow ComplementaryCapScope = "row"
+	// the batch earns one allowance from the value of all its rows
+	ComplementaryCapPerBatch ComplementaryCapScope = "batch"
+)
+
+func (s ComplementaryCapScope) IsValid() bool {
+	return s == ComplementaryCapPerRow || s == ComplementaryCapPerBatch
+}
+
+// ComplementaryCap allows at most Units free items per PerValue of order
+// value, e.g. 1 cleaner per 100 THB. Order value is what the main lines cost
+// after discounts, complemented rows only
+type ComplementaryCap struct {
+	Item     ComplementaryCapItem  `json:"item"`
+	Units    int                   `json:"units"`
+	PerValue float64               `json:"perValue"`
+	Scope    ComplementaryCapScope `json:"scope"`
+}
+
+// ComplementaryCaps maps a tenant to its caps, caps under DefaultTenantId
+// apply to tenants without caps of their own
+type ComplementaryCaps map[string][]*ComplementaryCap
+
+func (c ComplementaryCaps) Validate() error {
+	for tenantId, caps := range c {
+		if strings.TrimSpace(tenantId) == "" {
+			log.Errorf("complementary caps for an empty tenant")
+			return errors.ErrInvalidInput
+		}
+		for _, valueCap := range caps {
+			if valueCap == nil || !valueCap.Item.IsValid() || !valueCap.Scope.IsValid() || valueCap.Units <= 0 || valueCap.PerValue <= 0 {
+				log.Errorf("invalid complementary cap", log.S("tenantId", tenantId), log.AtoS("cap", valueCap))
+				return errors.ErrInvalidInput
+			}
+		}
+	}
+	return nil
+}
+
+func (c ComplementaryCaps) For(tenantId string) []*ComplementaryCap {
+	if caps, ok := c[tenantId]; ok {
+		return caps
+	}
+	return c[DefaultTenantId]
+}
+
+// units allowed for an order value, a value short of PerValue earns none
+func (c *ComplementaryCap) Allowance(value float64) int {
+	// a value of exactly 3 × PerValue must not come out as 2.9999
+	steps := math.Floor(value/c.PerValue + 1e-9)
+	if steps <= 0 {
+		return 0
+	}
+	return int(steps) * c.Units
+}
+
+// Trim cuts the lines of the cap's item down to allowance units, taking from
+// the last line back and dropping lines left with nothing. withheld has the
+// units taken from each line, by the line's ParentNo. The lines passed in are
+// not changed
+func (c *ComplementaryCap) Trim(lines []*CleanedOrder, allowance int) (trimmed []*CleanedOrder, withheld map[int]int, err error) {
+	total := 0
+	for _, line := range lines {
+		if c.Item.Matches(line.ProductId) {
+			total += line.Qty
+		}
+	}
+	excess := total - allowance
+	if excess <= 0 {
+		return lines, nil, nil
+	}
+
+	withheld = make(map[int]int)
+	keep := make([]bool, len(lines))
+	cut := make([]*CleanedOrder, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := lines[i]
+		keep[i], cut[i] = true, line
+		if excess == 0 || !c.Item.Matches(line.ProductId) {
+			continue
+		}
+
+		taken := min(excess, line.Qty)
+		excess -= taken
+		withheld[line.ParentNo] += taken
+		if taken == line.Qty {
+			keep[i] = false
+			continue
+		}
+
+		copied := *line
+		copied.Qty -= taken
+		if copied.TotalPrice, err = line.UnitPrice.MultiplyByInt(copied.Qty); err != nil {
+			log.Errorf("failed to price capped line", log.S("productId", line.ProductId), log.E(err))
+			return nil, nil, err
+		}
+		cut[i] = &copied
+	}
+
+	trimmed = make([]*CleanedOrder, 0, len(lines))
+	for i := range lines {
+		if keep[i] {
+			trimmed = append(trimmed, cut[i])
+		}
+	}
+	return trimmed, withheld, nil
+}
@@ -0,0 +1,110 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplementaryCaps_Validate(t *testing.T) {
+	valid := &entity.ComplementaryCap{Item: entity.ComplementaryCapCleaner, Units: 1, PerValue: 100, Scope: entity.ComplementaryCapPerRow}
+
+	tests := []struct {
+		name    string
+		caps    entity.ComplementaryCaps
+		wantErr bool
+	}{
+		{"Valid", entity.ComplementaryCaps{"*": {valid}}, false},
+		{"Empty tenant", entity.ComplementaryCaps{" ": {valid}}, true},
+		{"Unknown item", entity.ComplementaryCaps{"*": {{Item: "sticker", Units: 1, PerValue: 100, Scope: entity.ComplementaryCapPerRow}}}, true},
+		{"Unknown scope", entity.ComplementaryCaps{"*": {{Item: entity.ComplementaryCapCloth, Units: 1, PerValue: 100, Scope: "order"}}}, true},
+		{"No units", entity.ComplementaryCaps{"*": {{Item: entity.ComplementaryCapCloth, PerValue: 100, Scope: entity.ComplementaryCapPerRow}}}, true},
+		{"No value", entity.ComplementaryCaps{"*": {{Item: entity.ComplementaryCapCloth, Units: 1, Scope: entity.ComplementaryCapPerRow}}}, true},
+		{"Nil cap", entity.ComplementaryCaps{"*": {nil}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.caps.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestComplementaryCaps_For(t *testing.T) {
+	all := []*entity.ComplementaryCap{{Item: entity.ComplementaryCapCleaner, Units: 1, PerValue: 100}}
+	acme := []*entity.ComplementaryCap{{Item: entity.ComplementaryCapCloth, Units: 2, PerValue: 100}}
+	caps := entity.ComplementaryCaps{"*": all, "acme": acme}
+
+	assert.Equal(t, acme, caps.For("acme"))
+	assert.Equal(t, all, caps.For("globex"))
+	assert.Nil(t, entity.ComplementaryCaps(nil).For("acme"))
+}
+
+func TestComplementaryCap_Allowance(t *testing.T) {
+	valueCap := &entity.ComplementaryCap{Units: 2, PerValue: 100}
+
+	assert.Equal(t, 0, valueCap.Allowance(0))
+	assert.Equal(t, 0, valueCap.Allowance(99.99))
+	assert.Equal(t, 2, valueCap.Allowance(100))
+	assert.Equal(t, 6, valueCap.Allowance(0.1*3000))
+	assert.Equal(t, 6, valueCap.Allowance(399))
+}
+
+func TestComplementaryCap_Trim(t *testing.T) {
+	line := func(productId string, qty, parentNo int) *entity.CleanedOrder {
+		return &entity.CleanedOrder{
+			ProductId:  productId,
+			Qty:        qty,
+			UnitPrice:  value_object.MustNewPrice(2),
+			TotalPrice: value_object.MustNewPrice(2 * float64(qty)),
+			ParentNo:   parentNo,
+		}
+	}
+	lines := []*entity.CleanedOrder{
+		line("WIPING-CLOTH", 4, 1),
+		line("CLEAR-CLEANNER", 2, 1),
+		line("MATTE-CLEANNER", 2, 2),
+	}
+	valueCap := &entity.ComplementaryCap{Item: entity.ComplementaryCapCleaner, Units: 1, PerValue: 100}
+
+	t.Run("Within the allowance", func(t *testing.T) {
+		trimmed, withheld, err := valueCap.Trim(lines, 4)
+
+		require.NoError(t, err)
+		assert.Equal(t, lines, trimmed)
+		assert.Nil(t, withheld)
+	})
+
+	t.Run("Taken from the last line back", func(t *testing.T) {
+		trimmed, withheld, err := valueCap.Trim(lines, 1)
+
+		require.NoError(t, err)
+		require.Len(t, trimmed, 2)
+		assert.Equal(t, "WIPING-CLOTH", trimmed[0].ProductId)
+		assert.Equal(t, 4, trimmed[0].Qty)
+		assert.Equal(t, "CLEAR-CLEANNER", trimmed[1].ProductId)
+		assert.Equal(t, 1, trimmed[1].Qty)
+		assert.Equal(t, 2.0, trimmed[1].TotalPrice.Amount())
+		assert.Equal(t, map[int]int{1: 1, 2: 2}, withheld)
+
+		assert.Equal(t, 2, lines[1].Qty, "lines passed in are not changed")
+	})
+
+	t.Run("Nothing allowed", func(t *testing.T) {
+		trimmed, withheld, err := valueCap.Trim(lines, 0)
+
+		require.NoError(t, err)
+		require.Len(t, trimmed, 1)
+		assert.Equal(t, "WIPING-CLOTH", trimmed[0].ProductId)
+		assert.Equal(t, map[int]int{1: 2, 2: 2}, withheld)
+	})
+}
@@ -1,6 +1,7 @@
 package entity_test
 
 import (
+	"math/rand"
 	"strings"
 	"testing"
 
@@ -173,6 +174,34 @@ func TestComplementaryCalculation_AddProduct_ErrorCases(t *testing.T) {
 	}
 }
 
+func TestComplementaryCalculation_AddProduct_SkipsAccessory(t *testing.T) {
+	calc := entity.NewComplementaryCalculation()
+
+	accessory := entity.NewAccessoryProduct("ACC-CABLE-USBC-1M", 3, value_object.MustNewPrice(10), value_object.MustNewPrice(30))
+
+	require.NoError(t, calc.AddProduct(accessory))
+	assert.Nil(t, calc.WipingCloth)
+	assert.Empty(t, calc.Cleaners)
+	assert.Empty(t, calc.ToCleanedOrders(1))
+}
+
+func TestComplementaryCalculation_AddProduct_AccessoryComplementsCloth(t *testing.T) {
+	calc := entity.NewComplementaryCalculation()
+
+	lensFilm := entity.NewAccessoryProduct("ACC-LENS-IPHONE16PRO", 2, value_object.MustNewPrice(10), value_object.MustNewPrice(20))
+	lensFilm.ComplementsCloth = true
+	film, err := entity.NewProduct("FG0A-MATTE-IPHONE16PRO", 1, value_object.MustNewPrice(50), value_object.MustNewPrice(50))
+	require.NoError(t, err)
+
+	require.NoError(t, calc.AddProduct(lensFilm))
+	require.NoError(t, calc.AddProduct(film))
+
+	require.NotNil(t, calc.WipingCloth)
+	assert.Equal(t, 3, calc.WipingCloth.Quantity)
+	assert.Len(t, calc.Cleaners, 1, "accessories get no cleaner")
+	assert.Equal(t, 1, calc.Cleaners["MATTE"].Quantity)
+}
+
 func TestComplementaryCalculation_ToCleanedOrders(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -697,3 +726,62 @@ func createValidProduct(tb testing.TB, productId string, quantity int) *entity.P
 
 	return product
 }
+
+func TestComplementaryOrdering(t *testing.T) {
+	assert.Equal(t, []string{
+		"WIPING-CLOTH", "CLEAR-CLEANNER", "MATTE-CLEANNER", "PRIVACY-CLEANNER",
+		"CARE-KIT-CLEAR", "CARE-KIT-MATTE", "CARE-KIT-PRIVACY",
+	}, entity.ComplementaryOrdering(nil))
+}
+
+func TestComplementaryOrdering_TexturePriorities(t *testing.T) {
+	priorities := value_object.TexturePriorities{"CLEAR": 1, "MATTE": 3, "PRIVACY": 2}
+
+	assert.Equal(t, []string{
+		"WIPING-CLOTH", "CLEAR-CLEANNER", "PRIVACY-CLEANNER", "MATTE-CLEANNER",
+		"CARE-KIT-CLEAR", "CARE-KIT-PRIVACY", "CARE-KIT-MATTE",
+	}, entity.ComplementaryOrdering(priorities))
+}
+
+// property: for any input order the sorted lines are a permutation of it,
+// grouped by ParentNo and ranked by ComplementaryOrdering, with unlisted
+// lines keeping their relative order
+func TestSortComplementaryLines_Property(t *testing.T) {
+	productIds := append(entity.ComplementaryOrdering(nil), "PROMO-A", "PROMO-B")
+	rank := func(productId string) int {
+		for i, id := range entity.ComplementaryOrdering(nil) {
+			if id == productId {
+				return i
+			}
+		}
+		return len(entity.ComplementaryOrdering(nil))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 200; run++ {
+		lines := make([]*entity.CleanedOrder, rng.Intn(12))
+		for i := range lines {
+			lines[i] = &entity.CleanedOrder{
+				No:        i,
+				ProductId: productIds[rng.Intn(len(productIds))],
+				ParentNo:  rng.Intn(3),
+			}
+		}
+		original := append([]*entity.CleanedOrder(nil), lines...)
+
+		entity.SortComplementaryLines(lines, nil)
+
+		require.ElementsMatch(t, original, lines)
+		for i := 1; i < len(lines); i++ {
+			prev, curr := lines[i-1], lines[i]
+			require.LessOrEqual(t, prev.ParentNo, curr.ParentNo)
+			if prev.ParentNo != curr.ParentNo {
+				continue
+			}
+			require.LessOrEqual(t, rank(prev.ProductId), rank(curr.ProductId))
+			if rank(prev.ProductId) == rank(curr.ProductId) {
+				require.Less(t, prev.No, curr.No, "equal lines must keep their order")
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"regexp"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// 6 to 10 digits, dots between the digit groups are allowed
+var hsCodePattern = regexp.MustCompile(`^\d{4}(\.?\d{2}){1,3}$`)
+
+// CustomsInfo is what a customs declaration needs for one unit of a free item
+type CustomsInfo struct {
+	HSCode string `json:"hsCode"`
+	// declared value per unit, in the price policy currency
+	UnitValue float64 `json:"unitValue"`
+}
+
+// CustomsClassification maps a complementary product id (kits included) to
+// its customs info, e.g. {"WIPING-CLOTH": {"hsCode": "6307.10", "unitValue": 5}}
+type CustomsClassification map[string]*CustomsInfo
+
+func (c CustomsClassification) Validate() error {
+	for productId, info := range c {
+		if info == nil || !hsCodePattern.MatchString(info.HSCode) {
+			log.Errorf("invalid hs code", log.S("productId", productId))
+			return errors.ErrInvalidInput
+		}
+		if info.UnitValue < 0 {
+			log.Errorf("customs value cannot be negative", log.S("productId", productId))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// Classify sets the customs info of every line whose product is classified,
+// other lines are left as they are
+func (c CustomsClassification) Classify(lines []*CleanedOrder) {
+	for _, line := range lines {
+		if info, ok := c[line.ProductId]; ok {
+			classified := *info
+			line.Customs = &classified
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomsClassification_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		info      *entity.CustomsInfo
+		expectErr bool
+	}{
+		{"Six digits", &entity.CustomsInfo{HSCode: "630710", UnitValue: 5}, false},
+		{"Dotted ten digits", &entity.CustomsInfo{HSCode: "3405.90.00.10", UnitValue: 0}, false},
+		{"Too short", &entity.CustomsInfo{HSCode: "6307", UnitValue: 5}, true},
+		{"Letters", &entity.CustomsInfo{HSCode: "6307AB", UnitValue: 5}, true},
+		{"Negative value", &entity.CustomsInfo{HSCode: "630710", UnitValue: -1}, true},
+		{"Missing info", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := entity.CustomsClassification{"WIPING-CLOTH": tt.info}.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCustomsClassification_Classify(t *testing.T) {
+	classification := entity.CustomsClassification{
+		"WIPING-CLOTH": {HSCode: "6307.10", UnitValue: 5},
+	}
+	lines := []*entity.CleanedOrder{
+		{ProductId: "WIPING-CLOTH", Qty: 2},
+		{ProductId: "CLEAR-CLEANNER", Qty: 1},
+	}
+
+	classification.Classify(lines)
+
+	require.NotNil(t, lines[0].Customs)
+	assert.Equal(t, entity.CustomsInfo{HSCode: "6307.10", UnitValue: 5}, *lines[0].Customs)
+	assert.Nil(t, lines[1].Customs)
+
+	lines[0].Customs.UnitValue = 7
+	assert.Equal(t, 5.0, classification["WIPING-CLOTH"].UnitValue, "lines get their own copy")
+}
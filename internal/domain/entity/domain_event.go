@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"order-placement-system/pkg/errors"
+)
+
+type EventName string
+
+const (
+	EventOrderBatchProcessed         EventName = "OrderBatchProcessed"
+	EventComplementaryItemsGenerated EventName = "ComplementaryItemsGenerated"
+	// a lenient batch dropped a failing row
+	EventRowDropped EventName = "RowDropped"
+)
+
+// DomainEvent is something that happened while processing a batch, the
+// payload is one of the *Payload types below. Receivers can dedupe
+// redelivered events on Id
+type DomainEvent struct {
+	Id         string      `json:"id"`
+	Name       EventName   `json:"name"`
+	TenantId   string      `json:"tenantId,omitempty"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Payload    interface{} `json:"payload"`
+}
+
+type OrderBatchProcessedPayload struct {
+	Rows        int `json:"rows"`
+	DroppedRows int `json:"droppedRows"`
+	Lines       int `json:"lines"`
+}
+
+type ComplementaryItemsGeneratedPayload struct {
+	Lines []*CleanedOrder `json:"lines"`
+}
+
+type RowDroppedPayload struct {
+	No       int             `json:"no"`
+	Error    string          `json:"error"`
+	Category errors.Category `json:"category"`
+}
+
+// BatchEvents lists what a completed batch publishes: one event per dropped
+// row, the complementary lines when there are any, then the batch itself
+func BatchEvents(batch *ProcessBatch, occurredAt time.Time) []*DomainEvent {
+	tenantId := ""
+	if batch.Options != nil {
+		tenantId = batch.Options.TenantId
+	}
+
+	event := func(name EventName, payload interface{}) *DomainEvent {
+		return &DomainEvent{Id: newEventId(), Name: name, TenantId: tenantId, OccurredAt: occurredAt, Payload: payload}
+	}
+
+	events := make([]*DomainEvent, 0, len(batch.RowErrors)+2)
+	for _, rowErr := range batch.RowErrors {
+		events = append(events, event(EventRowDropped, &RowDroppedPayload{
+			No:       rowErr.No,
+			Error:    rowErr.Err.Error(),
+			Category: rowErr.Category(),
+		}))
+	}
+
+	if len(batch.ComplementaryLines) > 0 {
+		events = append(events, event(EventComplementaryItemsGenerated, &ComplementaryItemsGeneratedPayload{
+			Lines: batch.ComplementaryLines,
+		}))
+	}
+
+	events = append(events, event(EventOrderBatchProcessed, &OrderBatchProcessedPayload{
+		Rows:        len(batch.Rows),
+		DroppedRows: len(batch.RowErrors),
+		Lines:       len(batch.CleanedOrders),
+	}))
+
+	return events
+}
+
+func newEventId() string {
+	id := make([]byte, 16)
+	// crypto/rand does not fail on supported platforms
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
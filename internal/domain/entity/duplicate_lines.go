@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"order-placement-system/internal/domain/value_object"
+)
+
+// ComplementaryDuplicates decides what happens to complementary lines of the
+// same product, e.g. two cleaners substituted by the same universal cleaner
+type ComplementaryDuplicates string
+
+const (
+	// lines of one product for the same row and unit price become one line
+	ComplementaryDuplicatesMerge ComplementaryDuplicates = "merge"
+	// every line keeps its own attribution and substitution
+	ComplementaryDuplicatesSeparate ComplementaryDuplicates = "separate"
+)
+
+func (d ComplementaryDuplicates) IsValid() bool {
+	return d == ComplementaryDuplicatesMerge || d == ComplementaryDuplicatesSeparate
+}
+
+type duplicateLineKey struct {
+	productId   string
+	parentNo    int
+	externalRef string
+	unitPrice   float64
+}
+
+// MergeDuplicateLines sums the quantities and totals of lines with the same
+// product, ParentNo, ExternalRef and unit price into the first of them, which
+// keeps its place. Attribution and SubstitutedFor stay only when every merged
+// line agrees on them, Rewrites are combined. The lines passed in are not
+// changed
+func MergeDuplicateLines(lines []*CleanedOrder, policy *value_object.PricePolicy) ([]*CleanedOrder, error) {
+	merged := make([]*CleanedOrder, 0, len(lines))
+	byKey := make(map[duplicateLineKey]*CleanedOrder)
+	for _, line := range lines {
+		key := duplicateLineKey{
+			productId:   line.ProductId,
+			parentNo:    line.ParentNo,
+			externalRef: line.ExternalRef,
+			unitPrice:   policy.Round(line.UnitPrice.Amount()),
+		}
+
+		first, seen := byKey[key]
+		if !seen {
+			copied := *line
+			byKey[key] = &copied
+			merged = append(merged, &copied)
+			continue
+		}
+
+		total, err := first.TotalPrice.Add(line.TotalPrice)
+		if err != nil {
+			return nil, err
+		}
+		first.Qty += line.Qty
+		first.TotalPrice = total
+		if !sameAttribution(first.Attribution, line.Attribution) {
+			first.Attribution = nil
+		}
+		if first.SubstitutedFor != line.SubstitutedFor {
+			first.SubstitutedFor = ""
+		}
+		for _, rewrite := range line.Rewrites {
+			if !first.IsRewritten(rewrite) {
+				// the slice is still shared with the line first was copied from
+				first.Rewrites = append(append([]BatchWarningCode(nil), first.Rewrites...), rewrite)
+			}
+		}
+	}
+	return merged, nil
+}
+
+func sameAttribution(a, b *Attribution) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,115 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func complementaryLine(productId string, qty, parentNo int, rule entity.PromoRule, substitutedFor string) *entity.CleanedOrder {
+	return &entity.CleanedOrder{
+		ProductId:      productId,
+		Qty:            qty,
+		UnitPrice:      value_object.ZeroPrice(),
+		TotalPrice:     value_object.ZeroPrice(),
+		ParentNo:       parentNo,
+		Attribution:    &entity.Attribution{RuleId: rule},
+		SubstitutedFor: substitutedFor,
+	}
+}
+
+func TestMergeDuplicateLines(t *testing.T) {
+	tests := []struct {
+		name               string
+		lines              []*entity.CleanedOrder
+		expectIds          []string
+		expectQtys         []int
+		expectAttributions []*entity.Attribution
+		expectSubstituted  []string
+	}{
+		{
+			"No duplicates",
+			[]*entity.CleanedOrder{
+				complementaryLine("WIPING-CLOTH", 2, 0, entity.RuleWipingCloth, ""),
+				complementaryLine("CLEAR-CLEANNER", 2, 0, entity.RuleTextureCleaner, ""),
+			},
+			[]string{"WIPING-CLOTH", "CLEAR-CLEANNER"},
+			[]int{2, 2},
+			[]*entity.Attribution{{RuleId: entity.RuleWipingCloth}, {RuleId: entity.RuleTextureCleaner}},
+			[]string{"", ""},
+		},
+		{
+			"Same substitution",
+			[]*entity.CleanedOrder{
+				complementaryLine("UNIVERSAL-CLEANNER", 1, 0, entity.RuleTextureCleaner, "PRIVACY-CLEANNER"),
+				complementaryLine("WIPING-CLOTH", 2, 0, entity.RuleWipingCloth, ""),
+				complementaryLine("UNIVERSAL-CLEANNER", 3, 0, entity.RuleTextureCleaner, "PRIVACY-CLEANNER"),
+			},
+			[]string{"UNIVERSAL-CLEANNER", "WIPING-CLOTH"},
+			[]int{4, 2},
+			[]*entity.Attribution{{RuleId: entity.RuleTextureCleaner}, {RuleId: entity.RuleWipingCloth}},
+			[]string{"PRIVACY-CLEANNER", ""},
+		},
+		{
+			"Disagreeing lines lose attribution and substitution",
+			[]*entity.CleanedOrder{
+				complementaryLine("CLEAR-CLEANNER", 1, 0, entity.RuleTextureCleaner, ""),
+				complementaryLine("CLEAR-CLEANNER", 1, 0, entity.RuleCareKit, "MATTE-CLEANNER"),
+			},
+			[]string{"CLEAR-CLEANNER"},
+			[]int{2},
+			[]*entity.Attribution{nil},
+			[]string{""},
+		},
+		{
+			"Different rows stay apart",
+			[]*entity.CleanedOrder{
+				complementaryLine("WIPING-CLOTH", 1, 1, entity.RuleWipingCloth, ""),
+				complementaryLine("WIPING-CLOTH", 1, 2, entity.RuleWipingCloth, ""),
+			},
+			[]string{"WIPING-CLOTH", "WIPING-CLOTH"},
+			[]int{1, 1},
+			[]*entity.Attribution{{RuleId: entity.RuleWipingCloth}, {RuleId: entity.RuleWipingCloth}},
+			[]string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := entity.MergeDuplicateLines(tt.lines, value_object.DefaultPricePolicy())
+			require.NoError(t, err)
+
+			var ids, substituted []string
+			var qtys []int
+			var attributions []*entity.Attribution
+			for _, line := range merged {
+				ids = append(ids, line.ProductId)
+				qtys = append(qtys, line.Qty)
+				attributions = append(attributions, line.Attribution)
+				substituted = append(substituted, line.SubstitutedFor)
+			}
+			assert.Equal(t, tt.expectIds, ids)
+			assert.Equal(t, tt.expectQtys, qtys)
+			assert.Equal(t, tt.expectAttributions, attributions)
+			assert.Equal(t, tt.expectSubstituted, substituted)
+		})
+	}
+}
+
+func TestMergeDuplicateLines_SumsPricedLines(t *testing.T) {
+	first := &entity.CleanedOrder{ProductId: "GIFT-BOX", Qty: 1, UnitPrice: value_object.MustNewPrice(5), TotalPrice: value_object.MustNewPrice(5)}
+	second := &entity.CleanedOrder{ProductId: "GIFT-BOX", Qty: 2, UnitPrice: value_object.MustNewPrice(5), TotalPrice: value_object.MustNewPrice(10)}
+	other := &entity.CleanedOrder{ProductId: "GIFT-BOX", Qty: 1, UnitPrice: value_object.MustNewPrice(6), TotalPrice: value_object.MustNewPrice(6)}
+
+	merged, err := entity.MergeDuplicateLines([]*entity.CleanedOrder{first, second, other}, value_object.DefaultPricePolicy())
+
+	require.NoError(t, err)
+	require.Len(t, merged, 2, "lines at another unit price stay apart")
+	assert.Equal(t, 3, merged[0].Qty)
+	assert.Equal(t, 15.0, merged[0].TotalPrice.Amount())
+	assert.Equal(t, 1, first.Qty, "the lines passed in are not changed")
+}
@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+const (
+	maxErrorArticleText  = 4000
+	maxErrorArticleItems = 20
+)
+
+// error categories, batch warning codes or any other code support looks up
+var errorCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ErrorArticle explains one stable error code to support agents: what it
+// means, what usually causes it and how the client fixes it
+type ErrorArticle struct {
+	Code        string          `json:"code"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Causes      []string        `json:"causes"`
+	Examples    []*ErrorExample `json:"examples,omitempty"`
+	Remediation []string        `json:"remediation"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+// ErrorExample is an input that fails with the code. Examples are shown to
+// anyone, so they carry product ids only and no seller data
+type ErrorExample struct {
+	PlatformProductId string `json:"platformProductId"`
+	// what is wrong with it
+	Note string `json:"note,omitempty"`
+}
+
+func (a *ErrorArticle) Validate() error {
+	if !errorCodePattern.MatchString(a.Code) {
+		log.Errorf("invalid error article code", log.S("code", a.Code))
+		return errors.ErrInvalidInput
+	}
+	if strings.TrimSpace(a.Title) == "" || len(a.Title) > maxErrorArticleText || len(a.Description) > maxErrorArticleText {
+		log.Errorf("invalid error article text", log.S("code", a.Code))
+		return errors.ErrInvalidInput
+	}
+	if len(a.Causes) > maxErrorArticleItems || len(a.Examples) > maxErrorArticleItems || len(a.Remediation) > maxErrorArticleItems {
+		log.Errorf("too many error article items", log.S("code", a.Code))
+		return errors.ErrInvalidInput
+	}
+	for _, item := range append(append([]string{}, a.Causes...), a.Remediation...) {
+		if strings.TrimSpace(item) == "" || len(item) > maxErrorArticleText {
+			log.Errorf("invalid error article item", log.S("code", a.Code))
+			return errors.ErrInvalidInput
+		}
+	}
+	for _, example := range a.Examples {
+		if example == nil || example.PlatformProductId == "" || len(example.PlatformProductId) > maxErrorArticleText || len(example.Note) > maxErrorArticleText {
+			log.Errorf("invalid error article example", log.S("code", a.Code))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// Matches looks for query in every text of the article, case insensitively.
+// An empty query matches everything
+func (a *ErrorArticle) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+
+	texts := []string{a.Code, a.Title, a.Description}
+	texts = append(texts, a.Causes...)
+	texts = append(texts, a.Remediation...)
+	for _, example := range a.Examples {
+		texts = append(texts, example.PlatformProductId, example.Note)
+	}
+	for _, text := range texts {
+		if strings.Contains(strings.ToLower(text), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ErrorArticle) Clone() *ErrorArticle {
+	copied := *a
+	copied.Causes = append([]string(nil), a.Causes...)
+	copied.Remediation = append([]string(nil), a.Remediation...)
+	copied.Examples = make([]*ErrorExample, 0, len(a.Examples))
+	for _, example := range a.Examples {
+		e := *example
+		copied.Examples = append(copied.Examples, &e)
+	}
+	return &copied
+}
+
+// DefaultErrorArticles explain the error categories every row error carries
+func DefaultErrorArticles() []*ErrorArticle {
+	return []*ErrorArticle{
+		{
+			Code:        string(errors.CategoryCatalogMismatch),
+			Title:       "Unknown film type or texture",
+			Description: "The product code is well formed, but its film type or texture is not in the catalog.",
+			Causes: []string{
+				"The seller spells a texture their own way, e.g. GLOSSY or PRIV",
+				"A new texture was listed on the platform before it was added to the catalog",
+			},
+			Examples: []*ErrorExample{
+				{PlatformProductId: "FG0A-GLOSSY-IPHONE16PROMAX", Note: "GLOSSY is not a texture"},
+				{PlatformProductId: "FG05-SHINY-OPPOA3", Note: "SHINY is not a texture"},
+			},
+			Remediation: []string{
+				"Check GET /api/v1/admin/catalog-gaps for the unknown value and how often it comes up",
+				"Map a texture spelling to a known texture with POST /api/v1/admin/catalog-gaps/texture-aliases, then add it to TEXTURE_ALIASES",
+				"A new texture needs a release, escalate it to engineering",
+			},
+		},
+		{
+			Code:        string(errors.CategoryFormatError),
+			Title:       "Row cannot be read",
+			Description: "The row is missing something or its product code does not have the film type, texture and model parts.",
+			Causes: []string{
+				"The product code ends before its model",
+				"The export separates the code with underscores",
+				"Quantity is zero or negative, or a price is negative",
+			},
+			Examples: []*ErrorExample{
+				{PlatformProductId: "FG0A-CLEAR-", Note: "no model"},
+				{PlatformProductId: "FG0A_CLEAR_OPPOA3", Note: "underscores instead of dashes"},
+			},
+			Remediation: []string{
+				"Fix the row in the platform export and send the batch again",
+				"Check GET /api/v1/admin/parser/garbage-tokens for prefixes the parser does not strip",
+				"Exports with underscores are read with PARSER_UNDERSCORE_SEPARATORS=true",
+			},
+		},
+		{
+			Code:        string(errors.CategoryBusinessRule),
+			Title:       "Row breaks a processing rule",
+			Description: "The row is understood but is over a limit, e.g. a bundle with too many components or units.",
+			Causes: []string{
+				"A \"/\" bundle has more components than MAX_BUNDLE_COMPONENTS",
+				"The \"*N\" multipliers of a bundle add up to more than MAX_BUNDLE_UNITS",
+				"The film type is not made in the texture, see FILM_TEXTURE_MATRIX, and FILM_TEXTURE_STRICTNESS is reject",
+			},
+			Examples: []*ErrorExample{
+				{PlatformProductId: "FG0A-CLEAR-OPPOA3*2000/FG0A-MATTE-OPPOA3", Note: "2001 units in a bundle over a MAX_BUNDLE_UNITS of 1000"},
+			},
+			Remediation: []string{
+				"Split the bundle over several rows",
+				"Raise the limit only if such bundles are real orders",
+			},
+		},
+		{
+			Code:        string(errors.CategorySystemError),
+			Title:       "Processing failed on our side",
+			Description: "The row timed out or processing it failed unexpectedly. The input is usually fine.",
+			Causes: []string{
+				"A row took longer than ROW_PROCESSING_TIMEOUT or the batch longer than BATCH_PROCESSING_TIMEOUT",
+				"Processing the row panicked",
+			},
+			Remediation: []string{
+				"Send the batch again, smaller batches finish within the deadline more easily",
+				"If the same row keeps failing, escalate it to engineering with the row",
+			},
+		},
+	}
+}
@@ -0,0 +1,115 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/orderproc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorArticle_Validate(t *testing.T) {
+	valid := func() *entity.ErrorArticle {
+		return &entity.ErrorArticle{
+			Code:        "PREFIXED_ROWS",
+			Title:       "Rows with platform prefixes",
+			Causes:      []string{"The export adds dashes in front of product codes"},
+			Examples:    []*entity.ErrorExample{{PlatformProductId: "--FG0A-CLEAR-OPPOA3"}},
+			Remediation: []string{"Nothing, the prefix is stripped"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(a *entity.ErrorArticle)
+		wantErr bool
+	}{
+		{"Valid", func(a *entity.ErrorArticle) {}, false},
+		{"Code with spaces", func(a *entity.ErrorArticle) { a.Code = "prefixed rows" }, true},
+		{"Empty code", func(a *entity.ErrorArticle) { a.Code = "" }, true},
+		{"No title", func(a *entity.ErrorArticle) { a.Title = " " }, true},
+		{"Empty cause", func(a *entity.ErrorArticle) { a.Causes = []string{""} }, true},
+		{"Too many steps", func(a *entity.ErrorArticle) { a.Remediation = make([]string, 21) }, true},
+		{"Long description", func(a *entity.ErrorArticle) { a.Description = strings.Repeat("x", 4001) }, true},
+		{"Example without product id", func(a *entity.ErrorArticle) { a.Examples = []*entity.ErrorExample{{Note: "x"}} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := valid()
+			tt.modify(article)
+
+			err := article.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errors.ErrInvalidInput)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestErrorArticle_Matches(t *testing.T) {
+	article := &entity.ErrorArticle{
+		Code:        "format_error",
+		Title:       "Row cannot be read",
+		Causes:      []string{"The export separates the code with underscores"},
+		Examples:    []*entity.ErrorExample{{PlatformProductId: "FG0A_CLEAR_OPPOA3"}},
+		Remediation: []string{"Fix the row"},
+	}
+
+	assert.True(t, article.Matches(""))
+	assert.True(t, article.Matches("FORMAT"))
+	assert.True(t, article.Matches("underscores"))
+	assert.True(t, article.Matches("fg0a_clear"))
+	assert.False(t, article.Matches("timeout"))
+}
+
+func TestErrorArticle_Clone(t *testing.T) {
+	article := entity.DefaultErrorArticles()[0]
+
+	cloned := article.Clone()
+	cloned.Causes[0] = "changed"
+	cloned.Examples[0].Note = "changed"
+
+	assert.NotEqual(t, "changed", article.Causes[0])
+	assert.NotEqual(t, "changed", article.Examples[0].Note)
+}
+
+// every example of a default article fails with the article's category
+func TestDefaultErrorArticles(t *testing.T) {
+	processor, err := orderproc.New(orderproc.WithOptions(orderproc.ProcessOptions{
+		Mode:           string(entity.ProcessModeLenient),
+		MaxBundleUnits: 1000,
+	}))
+	require.NoError(t, err)
+
+	articles := entity.DefaultErrorArticles()
+	for _, category := range errors.AllCategories {
+		var article *entity.ErrorArticle
+		for _, a := range articles {
+			if a.Code == string(category) {
+				article = a
+			}
+		}
+		require.NotNil(t, article, "no article for %s", category)
+		require.NoError(t, article.Validate())
+
+		for _, example := range article.Examples {
+			t.Run(example.PlatformProductId, func(t *testing.T) {
+				_, err := processor.Process([]*orderproc.InputOrder{
+					{No: 1, PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX", Qty: 1, UnitPrice: 50, TotalPrice: 50},
+					{No: 2, PlatformProductId: example.PlatformProductId, Qty: 1, UnitPrice: 50, TotalPrice: 50},
+				})
+
+				var partial *errors.PartialError
+				require.True(t, errors.As(err, &partial), "example does not fail: %v", err)
+				assert.Equal(t, category, partial.Rows[0].Category())
+			})
+		}
+	}
+}
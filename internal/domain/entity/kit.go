@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"strings"
+
+	"order-placement-system/internal/domain/value_object"
+)
+
+const KitPrefix = "CARE-KIT-"
+
+// KitComponent is what one unit of a kit line contains
+type KitComponent struct {
+	ProductId string `json:"productId"`
+	Qty       int    `json:"qty"`
+}
+
+// KitTenants lists the tenants whose complementary items ship as kits,
+// DefaultTenantId turns kits on for every tenant
+type KitTenants []string
+
+func (k KitTenants) Includes(tenantId string) bool {
+	for _, tenant := range k {
+		if tenant == DefaultTenantId || tenant == tenantId {
+			return true
+		}
+	}
+	return false
+}
+
+func KitProductId(texture value_object.Texture) string {
+	return KitPrefix + texture.String()
+}
+
+// BundleIntoKits packs each cleaner with a wiping cloth into one
+// CARE-KIT-<TEXTURE> line, separately for each ParentNo. Whatever cannot be
+// paired stays a line of its own
+func BundleIntoKits(lines []*CleanedOrder) []*CleanedOrder {
+	var parents []int
+	groups := make(map[int][]*CleanedOrder)
+	for _, line := range lines {
+		if _, seen := groups[line.ParentNo]; !seen {
+			parents = append(parents, line.ParentNo)
+		}
+		groups[line.ParentNo] = append(groups[line.ParentNo], line)
+	}
+
+	bundled := make([]*CleanedOrder, 0, len(lines))
+	for _, parentNo := range parents {
+		bundled = append(bundled, bundleGroup(groups[parentNo])...)
+	}
+	return bundled
+}
+
+func bundleGroup(lines []*CleanedOrder) []*CleanedOrder {
+	var cloth *CleanedOrder
+	for _, line := range lines {
+		if line.ProductId == WipingClothProductId {
+			cloth = line
+		}
+	}
+	if cloth == nil {
+		return lines
+	}
+
+	clothLeft := cloth.Qty
+	var bundled, rest []*CleanedOrder
+	for _, line := range lines {
+		if line == cloth {
+			continue
+		}
+
+		if !strings.HasSuffix(line.ProductId, CleanerSuffix) || clothLeft == 0 {
+			rest = append(rest, line)
+			continue
+		}
+
+		kitQty := min(line.Qty, clothLeft)
+		clothLeft -= kitQty
+		bundled = append(bundled, &CleanedOrder{
+			ProductId:   KitPrefix + strings.TrimSuffix(line.ProductId, CleanerSuffix),
+			Qty:         kitQty,
+			UnitPrice:   value_object.ZeroPrice(),
+			TotalPrice:  value_object.ZeroPrice(),
+			ParentNo:    line.ParentNo,
+			ExternalRef: line.ExternalRef,
+			Attribution: &Attribution{RuleId: RuleCareKit},
+			Components: []*KitComponent{
+				{ProductId: WipingClothProductId, Qty: 1},
+				{ProductId: line.ProductId, Qty: 1},
+			},
+		})
+
+		if line.Qty > kitQty {
+			line.Qty -= kitQty
+			rest = append(rest, line)
+		}
+	}
+
+	if clothLeft > 0 {
+		cloth.Qty = clothLeft
+		rest = append([]*CleanedOrder{cloth}, rest...)
+	}
+
+	return append(bundled, rest...)
+}
@@ -0,0 +1,121 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKitTenants_Includes(t *testing.T) {
+	assert.True(t, entity.KitTenants{"acme"}.Includes("acme"))
+	assert.False(t, entity.KitTenants{"acme"}.Includes("other"))
+	assert.False(t, entity.KitTenants{"acme"}.Includes(""))
+	assert.True(t, entity.KitTenants{entity.DefaultTenantId}.Includes(""))
+	assert.False(t, entity.KitTenants(nil).Includes("acme"))
+}
+
+func TestBundleIntoKits(t *testing.T) {
+	line := func(productId string, qty, parentNo int) *entity.CleanedOrder {
+		return &entity.CleanedOrder{
+			ProductId:  productId,
+			Qty:        qty,
+			UnitPrice:  value_object.ZeroPrice(),
+			TotalPrice: value_object.ZeroPrice(),
+			ParentNo:   parentNo,
+		}
+	}
+
+	type kit struct {
+		productId string
+		qty       int
+		parentNo  int
+	}
+
+	tests := []struct {
+		name     string
+		lines    []*entity.CleanedOrder
+		expected []kit
+	}{
+		{
+			name: "Each cleaner takes one cloth per unit",
+			lines: []*entity.CleanedOrder{
+				line("WIPING-CLOTH", 3, 0),
+				line("CLEAR-CLEANNER", 2, 0),
+				line("MATTE-CLEANNER", 1, 0),
+			},
+			expected: []kit{
+				{"CARE-KIT-CLEAR", 2, 0},
+				{"CARE-KIT-MATTE", 1, 0},
+			},
+		},
+		{
+			name: "Cloths short of cleaners leave the rest of the cleaners",
+			lines: []*entity.CleanedOrder{
+				line("WIPING-CLOTH", 1, 0),
+				line("CLEAR-CLEANNER", 3, 0),
+			},
+			expected: []kit{
+				{"CARE-KIT-CLEAR", 1, 0},
+				{"CLEAR-CLEANNER", 2, 0},
+			},
+		},
+		{
+			name: "Cloths beyond the cleaners stay a cloth line",
+			lines: []*entity.CleanedOrder{
+				line("WIPING-CLOTH", 2, 0),
+				line("CLEAR-CLEANNER", 1, 0),
+				line("PROMO-STICKER", 1, 0),
+			},
+			expected: []kit{
+				{"CARE-KIT-CLEAR", 1, 0},
+				{"WIPING-CLOTH", 1, 0},
+				{"PROMO-STICKER", 1, 0},
+			},
+		},
+		{
+			name: "Kits never pair lines of different parents",
+			lines: []*entity.CleanedOrder{
+				line("WIPING-CLOTH", 1, 1),
+				line("CLEAR-CLEANNER", 1, 1),
+				line("MATTE-CLEANNER", 1, 2),
+			},
+			expected: []kit{
+				{"CARE-KIT-CLEAR", 1, 1},
+				{"MATTE-CLEANNER", 1, 2},
+			},
+		},
+		{
+			name:     "No lines",
+			lines:    []*entity.CleanedOrder{},
+			expected: []kit{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := entity.BundleIntoKits(tt.lines)
+
+			actual := make([]kit, len(result))
+			for i, r := range result {
+				actual[i] = kit{r.ProductId, r.Qty, r.ParentNo}
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("Kit lines list one of each component", func(t *testing.T) {
+		result := entity.BundleIntoKits([]*entity.CleanedOrder{
+			line("WIPING-CLOTH", 2, 0),
+			line("PRIVACY-CLEANNER", 2, 0),
+		})
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, []*entity.KitComponent{
+			{ProductId: "WIPING-CLOTH", Qty: 1},
+			{ProductId: "PRIVACY-CLEANNER", Qty: 1},
+		}, result[0].Components)
+	})
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/log"
+)
+
+// SplitLines splits every line of more than maxQty units into lines of maxQty
+// and one with the rest, e.g. 250 at a max of 100 is 100, 100 and 50. Parts
+// keep the unit price and share the total by quantity, the last part takes
+// the rounding remainder so they add up to the original total. maxQty <= 0
+// splits nothing
+func SplitLines(lines []*CleanedOrder, maxQty int, policy *value_object.PricePolicy) ([]*CleanedOrder, error) {
+	if maxQty <= 0 {
+		return lines, nil
+	}
+	if policy == nil {
+		policy = value_object.DefaultPricePolicy()
+	}
+
+	split := make([]*CleanedOrder, 0, len(lines))
+	for _, line := range lines {
+		if line.Qty <= maxQty {
+			split = append(split, line)
+			continue
+		}
+
+		parts, err := splitLine(line, maxQty, policy)
+		if err != nil {
+			log.Errorf("failed to split line", log.S("product_id", line.ProductId), log.AtoS("qty", line.Qty), log.E(err))
+			return nil, err
+		}
+		split = append(split, parts...)
+	}
+
+	return split, nil
+}
+
+func splitLine(line *CleanedOrder, maxQty int, policy *value_object.PricePolicy) ([]*CleanedOrder, error) {
+	var parts []*CleanedOrder
+	allocatedTotal, allocatedGross, allocatedWeight := 0.0, 0.0, 0
+
+	for left := line.Qty; left > 0; left -= maxQty {
+		qty := min(left, maxQty)
+		last := left == qty
+
+		part := *line
+		part.Qty = qty
+		part.Components = make([]*KitComponent, len(line.Components))
+		for i, component := range line.Components {
+			copied := *component
+			part.Components[i] = &copied
+		}
+
+		total := policy.Round(line.TotalPrice.Amount() * float64(qty) / float64(line.Qty))
+		if last {
+			total = policy.Round(line.TotalPrice.Amount() - allocatedTotal)
+		}
+		totalPrice, err := value_object.NewPrice(total)
+		if err != nil {
+			return nil, err
+		}
+		part.TotalPrice = totalPrice
+		part.UnitPrice = line.UnitPrice.Clone()
+		allocatedTotal += total
+
+		if line.GrossTotalPrice != nil {
+			gross := policy.Round(line.GrossTotalPrice.Amount() * float64(qty) / float64(line.Qty))
+			if last {
+				gross = policy.Round(line.GrossTotalPrice.Amount() - allocatedGross)
+			}
+			grossTotalPrice, err := value_object.NewPrice(gross)
+			if err != nil {
+				return nil, err
+			}
+			part.GrossTotalPrice = grossTotalPrice
+			part.GrossUnitPrice = line.GrossUnitPrice.Clone()
+			allocatedGross += gross
+		}
+
+		part.ShippingWeight = line.ShippingWeight * qty / line.Qty
+		if last {
+			part.ShippingWeight = line.ShippingWeight - allocatedWeight
+		}
+		allocatedWeight += part.ShippingWeight
+
+		parts = append(parts, &part)
+	}
+
+	return parts, nil
+}
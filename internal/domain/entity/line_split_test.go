@@ -0,0 +1,79 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitLines(t *testing.T) {
+	newLine := func(qty int, total float64) *entity.CleanedOrder {
+		return &entity.CleanedOrder{
+			ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:        qty,
+			UnitPrice:  value_object.MustNewPrice(total / float64(qty)),
+			TotalPrice: value_object.MustNewPrice(total),
+			ParentNo:   1,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		line         *entity.CleanedOrder
+		maxQty       int
+		expectQtys   []int
+		expectTotals []float64
+	}{
+		{"No limit", newLine(250, 500), 0, []int{250}, []float64{500}},
+		{"Within limit", newLine(100, 200), 100, []int{100}, []float64{200}},
+		{"Over limit", newLine(250, 500), 100, []int{100, 100, 50}, []float64{200, 200, 100}},
+		{"Exact multiple", newLine(200, 400), 100, []int{100, 100}, []float64{200, 200}},
+		{"Last part takes the rounding remainder", newLine(3, 100), 1, []int{1, 1, 1}, []float64{33.33, 33.33, 33.34}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := entity.SplitLines([]*entity.CleanedOrder{tt.line}, tt.maxQty, nil)
+			require.NoError(t, err)
+
+			var qtys []int
+			var totals []float64
+			for _, line := range lines {
+				qtys = append(qtys, line.Qty)
+				totals = append(totals, line.TotalPrice.Amount())
+				assert.Equal(t, tt.line.ProductId, line.ProductId)
+				assert.Equal(t, tt.line.ParentNo, line.ParentNo)
+			}
+			assert.Equal(t, tt.expectQtys, qtys)
+			assert.InDeltaSlice(t, tt.expectTotals, totals, 0.001)
+		})
+	}
+}
+
+func TestSplitLines_CopiesComponentsAndGrossPrices(t *testing.T) {
+	line := &entity.CleanedOrder{
+		ProductId:       "CARE-KIT",
+		Qty:             3,
+		UnitPrice:       value_object.MustNewPrice(0),
+		TotalPrice:      value_object.MustNewPrice(0),
+		GrossUnitPrice:  value_object.MustNewPrice(10),
+		GrossTotalPrice: value_object.MustNewPrice(30),
+		ShippingWeight:  45,
+		Components:      []*entity.KitComponent{{ProductId: "WIPING-CLOTH", Qty: 1}},
+	}
+
+	lines, err := entity.SplitLines([]*entity.CleanedOrder{line}, 2, nil)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+
+	assert.Equal(t, 20.0, lines[0].GrossTotalPrice.Amount())
+	assert.Equal(t, 10.0, lines[1].GrossTotalPrice.Amount())
+	assert.Equal(t, []int{30, 15}, []int{lines[0].ShippingWeight, lines[1].ShippingWeight})
+
+	lines[0].Components[0].ProductId = "ACME-WIPING-CLOTH"
+	assert.Equal(t, "WIPING-CLOTH", lines[1].Components[0].ProductId)
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "strings"
+
+// tenant id whose settings apply to every tenant without its own
+const DefaultTenantId = "*"
+
+// ModelSuffixAliases maps tenant to a model suffix spelling and the suffix it
+// is normalized to, e.g. {"*": {"-BLACK": "-B", "-BLK": "-B"}}
+type ModelSuffixAliases map[string]map[string]string
+
+// rewrites the longest matching suffix of modelId, tenant aliases take
+// precedence over the default ones, ok is false when nothing matched
+func (a ModelSuffixAliases) Normalize(tenantId, modelId string) (normalized string, ok bool) {
+	alias, canonical := a.longestMatch(a[tenantId], modelId)
+	if alias == "" && tenantId != DefaultTenantId {
+		alias, canonical = a.longestMatch(a[DefaultTenantId], modelId)
+	}
+
+	if alias == "" || alias == canonical {
+		return modelId, false
+	}
+
+	return strings.TrimSuffix(modelId, alias) + canonical, true
+}
+
+func (a ModelSuffixAliases) longestMatch(aliases map[string]string, modelId string) (alias, canonical string) {
+	for candidate, target := range aliases {
+		// the suffix alone is not a model
+		if len(candidate) >= len(modelId) || !strings.HasSuffix(modelId, candidate) {
+			continue
+		}
+
+		if len(candidate) > len(alias) {
+			alias, canonical = candidate, target
+		}
+	}
+
+	return alias, canonical
+}
@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelSuffixAliases_Normalize(t *testing.T) {
+	aliases := entity.ModelSuffixAliases{
+		entity.DefaultTenantId: {
+			"-BLACK": "-B",
+			"-BLK":   "-B",
+			"-B":     "-B",
+		},
+		"acme": {
+			"-BLK": "-BK",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		tenantId   string
+		modelId    string
+		expected   string
+		normalized bool
+	}{
+		{"Default alias", "", "IPHONE16PROMAX-BLACK", "IPHONE16PROMAX-B", true},
+		{"Second spelling", "", "IPHONE16PROMAX-BLK", "IPHONE16PROMAX-B", true},
+		{"Already canonical", "", "IPHONE16PROMAX-B", "IPHONE16PROMAX-B", false},
+		{"No suffix", "", "IPHONE16PROMAX", "IPHONE16PROMAX", false},
+		{"Tenant alias wins", "acme", "IPHONE16PROMAX-BLK", "IPHONE16PROMAX-BK", true},
+		{"Tenant falls back to default", "acme", "IPHONE16PROMAX-BLACK", "IPHONE16PROMAX-B", true},
+		{"Suffix alone is left alone", "", "-BLACK", "-BLACK", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := aliases.Normalize(tt.tenantId, tt.modelId)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.normalized, ok)
+		})
+	}
+}
+
+func TestModelSuffixAliases_NormalizeNil(t *testing.T) {
+	var aliases entity.ModelSuffixAliases
+
+	result, ok := aliases.Normalize("acme", "IPHONE16PROMAX-BLACK")
+	assert.Equal(t, "IPHONE16PROMAX-BLACK", result)
+	assert.False(t, ok)
+}
@@ -0,0 +1,43 @@
+package entity
+
+// NumberLines returns main lines in the order given followed by complementary
+// lines in ComplementaryOrdering, and numbers them from options.FirstNo().
+// With ComplementaryInterleaved a row's complementary lines come right after
+// its last main line instead, lines without a row stay at the end
+func NumberLines(mainLines, complementaryLines []*CleanedOrder, options *ProcessOptions) []*CleanedOrder {
+	SortComplementaryLines(complementaryLines, options.TexturePrioritiesInEffect())
+
+	byParent := make(map[int][]*CleanedOrder)
+	if options.InterleavesComplementary() {
+		for _, line := range complementaryLines {
+			if line.ParentNo != 0 {
+				byParent[line.ParentNo] = append(byParent[line.ParentNo], line)
+			}
+		}
+	}
+
+	lines := make([]*CleanedOrder, 0, len(mainLines)+len(complementaryLines))
+	placed := make(map[int]bool)
+	for i, line := range mainLines {
+		lines = append(lines, line)
+
+		lastOfRow := i == len(mainLines)-1 || mainLines[i+1].ParentNo != line.ParentNo
+		if lastOfRow && len(byParent[line.ParentNo]) > 0 && !placed[line.ParentNo] {
+			lines = append(lines, byParent[line.ParentNo]...)
+			placed[line.ParentNo] = true
+		}
+	}
+
+	for _, line := range complementaryLines {
+		if line.ParentNo == 0 || !placed[line.ParentNo] {
+			lines = append(lines, line)
+		}
+	}
+
+	for i, line := range lines {
+		line.No = options.FirstNo() + i
+		line.NamespacedNo = options.NamespacedNo(line.No)
+	}
+
+	return lines
+}
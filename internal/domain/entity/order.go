@@ -12,16 +12,51 @@ type InputOrder struct {
 	Qty               int                 `json:"qty"`
 	UnitPrice         *value_object.Price `json:"unitPrice"`
 	TotalPrice        *value_object.Price `json:"totalPrice"`
+	// the client's own id for the row, copied to every line derived from it
+	ExternalRef string `json:"externalRef,omitempty"`
+	// sales channel of the row, picks its complementary policy
+	Channel string `json:"channel,omitempty"`
+	// platform voucher and fee of the row, spread across its lines
+	Discount  *value_object.Price `json:"discount,omitempty"`
+	Surcharge *value_object.Price `json:"surcharge,omitempty"`
 }
 
 type CleanedOrder struct {
-	No         int                 `json:"no"`
-	ProductId  string              `json:"productId"`
-	MaterialId string              `json:"materialId,omitempty"`
-	ModelId    string              `json:"modelId,omitempty"`
-	Qty        int                 `json:"qty"`
-	UnitPrice  *value_object.Price `json:"unitPrice"`
-	TotalPrice *value_object.Price `json:"totalPrice"`
+	No            int                 `json:"no"`
+	ProductId     string              `json:"productId"`
+	MaterialId    string              `json:"materialId,omitempty"`
+	ModelId       string              `json:"modelId,omitempty"`
+	Qty           int                 `json:"qty"`
+	UnitPrice     *value_object.Price `json:"unitPrice"`
+	TotalPrice    *value_object.Price `json:"totalPrice"`
+	IsAccessory   bool                `json:"isAccessory,omitempty"`
+	ParentNo      int                 `json:"parentNo,omitempty"`
+	PriceEnriched bool                `json:"priceEnriched,omitempty"`
+	ExternalRef   string              `json:"externalRef,omitempty"`
+	NamespacedNo  string              `json:"namespacedNo,omitempty"`
+	// set on kit lines only
+	Components []*KitComponent `json:"components,omitempty"`
+	// set on classified complementary lines only
+	Customs *CustomsInfo `json:"customs,omitempty"`
+	// the out of stock product this line replaces
+	SubstitutedFor string `json:"substitutedFor,omitempty"`
+	// the rule and campaign behind a complementary line
+	Attribution *Attribution `json:"attribution,omitempty"`
+	// grams for the line's quantity, set when the weight catalog knows it
+	ShippingWeight int `json:"shippingWeight,omitempty"`
+	// prices before the row's discount and surcharge, set on adjusted lines only
+	GrossUnitPrice  *value_object.Price `json:"grossUnitPrice,omitempty"`
+	GrossTotalPrice *value_object.Price `json:"grossTotalPrice,omitempty"`
+	// free units the complementary caps withheld on account of the row, set
+	// on the row's first main line only
+	CappedQty int `json:"cappedQty,omitempty"`
+	// color or variant token taken out of the product code, main lines only
+	Variant string `json:"variant,omitempty"`
+	// how the product code was rewritten on its way in, complementary lines
+	// carry none
+	Rewrites []BatchWarningCode `json:"-"`
+	// the product id before the tenant's SKU affix, set on affixed lines only
+	CatalogProductId string `json:"-"`
 }
 
 type OrderBatch struct {
@@ -60,9 +95,22 @@ func (o *InputOrder) IsValid() error {
 		return errors.ErrInvalidInput
 	}
 
+	if (o.Discount != nil && o.Discount.Amount() < 0) || (o.Surcharge != nil && o.Surcharge.Amount() < 0) {
+		log.Errorf("discount and surcharge cannot be negative")
+		return errors.ErrInvalidInput
+	}
+
 	return nil
 }
 
+// the product id price lists and catalogs know, without the tenant's SKU affix
+func (c *CleanedOrder) CatalogId() string {
+	if c.CatalogProductId != "" {
+		return c.CatalogProductId
+	}
+	return c.ProductId
+}
+
 func (c *CleanedOrder) IsValid() error {
 	if c.No <= 0 {
 		log.Errorf("order number must be positive")
@@ -91,3 +139,8 @@ func (c *CleanedOrder) IsValid() error {
 
 	return nil
 }
+
+// rows sent with no total are priced from the tenant price list when one is configured
+func (o *InputOrder) NeedsPriceEnrichment() bool {
+	return o.TotalPrice == nil || o.TotalPrice.IsZero()
+}
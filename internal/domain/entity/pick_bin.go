@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// PickBins maps a product id (complementary SKUs, kits) or a model id to the
+// warehouse bin it is picked from, e.g. {"IPHONE16PROMAX": "A-01",
+// "WIPING-CLOTH": "Z-99"}
+type PickBins map[string]string
+
+func (b PickBins) Validate() error {
+	for key, bin := range b {
+		if key == "" || strings.TrimSpace(bin) == "" {
+			log.Errorf("invalid pick bin", log.S("key", key), log.S("bin", bin))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// Bin looks the product id up first and then the model id, empty when
+// neither is mapped
+func (b PickBins) Bin(productId, modelId string) string {
+	if bin, ok := b[productId]; ok {
+		return bin
+	}
+	if modelId == "" {
+		return ""
+	}
+	return b[modelId]
+}
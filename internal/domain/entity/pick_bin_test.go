@@ -0,0 +1,22 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickBins(t *testing.T) {
+	bins := entity.PickBins{"OPPOA3": "A-02", "FG0A-MATTE-OPPOA3": "B-01"}
+
+	assert.NoError(t, bins.Validate())
+	assert.Equal(t, "B-01", bins.Bin("FG0A-MATTE-OPPOA3", "OPPOA3"), "the product id is looked up first")
+	assert.Equal(t, "A-02", bins.Bin("FG0A-CLEAR-OPPOA3", "OPPOA3"))
+	assert.Equal(t, "", bins.Bin("WIPING-CLOTH", ""))
+
+	assert.ErrorIs(t, entity.PickBins{"OPPOA3": " "}.Validate(), errors.ErrInvalidInput)
+	assert.ErrorIs(t, entity.PickBins{"": "A-01"}.Validate(), errors.ErrInvalidInput)
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"math"
+
+	"order-placement-system/internal/domain/value_object"
+)
+
+// PriceSplit describes how one input row's total was allocated across the
+// lines derived from it, Remainder is what got lost (or added) once every
+// line total is rounded to the minor unit of the price policy
+type PriceSplit struct {
+	No                int     `json:"no"`
+	PlatformProductId string  `json:"platformProductId"`
+	Lines             int     `json:"lines"`
+	TotalPrice        float64 `json:"totalPrice"`
+	AllocatedTotal    float64 `json:"allocatedTotal"`
+	Remainder         float64 `json:"remainder"`
+}
+
+// a nil policy means value_object.DefaultPricePolicy, remainders within its
+// epsilon count as none
+func NewPriceSplit(inputOrder *InputOrder, products []*Product, policy *value_object.PricePolicy) *PriceSplit {
+	if policy == nil {
+		policy = value_object.DefaultPricePolicy()
+	}
+
+	allocated := 0.0
+	for _, product := range products {
+		allocated += policy.Round(product.TotalPrice.Amount())
+	}
+
+	total := inputOrder.TotalPrice.Amount()
+	remainder := policy.Round(total - allocated)
+	if math.Abs(remainder) < policy.Epsilon {
+		remainder = 0
+	}
+
+	return &PriceSplit{
+		No:                inputOrder.No,
+		PlatformProductId: inputOrder.PlatformProductId,
+		Lines:             len(products),
+		TotalPrice:        total,
+		AllocatedTotal:    policy.Round(allocated),
+		Remainder:         remainder,
+	}
+}
+
+func (s *PriceSplit) HasRemainder() bool {
+	return s.Remainder != 0
+}
+
+func (s *PriceSplit) Magnitude() float64 {
+	return math.Abs(s.Remainder)
+}
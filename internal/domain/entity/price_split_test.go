@@ -0,0 +1,90 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPriceSplit(t *testing.T) {
+	tests := []struct {
+		name              string
+		policy            *value_object.PricePolicy
+		totalPrice        float64
+		lineTotals        []float64
+		expectedAllocated float64
+		expectedRemainder float64
+	}{
+		{
+			name:              "Even split has no remainder",
+			totalPrice:        160,
+			lineTotals:        []float64{80, 80},
+			expectedAllocated: 160,
+			expectedRemainder: 0,
+		},
+		{
+			name:              "Three way split loses a cent",
+			totalPrice:        100,
+			lineTotals:        []float64{100.0 / 3, 100.0 / 3, 100.0 / 3},
+			expectedAllocated: 99.99,
+			expectedRemainder: 0.01,
+		},
+		{
+			name:              "Rounding up allocates more than the total",
+			totalPrice:        0.02,
+			lineTotals:        []float64{0.02 / 3, 0.02 / 3, 0.02 / 3},
+			expectedAllocated: 0.03,
+			expectedRemainder: -0.01,
+		},
+		{
+			name:              "Yen rounds to whole units",
+			policy:            &value_object.PricePolicy{Currency: "JPY", MinorUnits: 0, Epsilon: 0.5},
+			totalPrice:        100,
+			lineTotals:        []float64{100.0 / 3, 100.0 / 3, 100.0 / 3},
+			expectedAllocated: 99,
+			expectedRemainder: 1,
+		},
+		{
+			name:              "Remainder within the epsilon counts as none",
+			policy:            &value_object.PricePolicy{Currency: "THB", MinorUnits: 2, Epsilon: 0.02},
+			totalPrice:        100,
+			lineTotals:        []float64{100.0 / 3, 100.0 / 3, 100.0 / 3},
+			expectedAllocated: 99.99,
+			expectedRemainder: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputOrder := &entity.InputOrder{
+				No:                7,
+				PlatformProductId: "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3",
+				TotalPrice:        value_object.MustNewPrice(tt.totalPrice),
+			}
+
+			products := make([]*entity.Product, len(tt.lineTotals))
+			for i, lineTotal := range tt.lineTotals {
+				products[i] = &entity.Product{TotalPrice: value_object.MustNewPrice(lineTotal)}
+			}
+
+			split := entity.NewPriceSplit(inputOrder, products, tt.policy)
+
+			assert.Equal(t, 7, split.No)
+			assert.Equal(t, len(tt.lineTotals), split.Lines)
+			assert.InDelta(t, tt.expectedAllocated, split.AllocatedTotal, 1e-9)
+			assert.InDelta(t, tt.expectedRemainder, split.Remainder, 1e-9)
+			assert.Equal(t, tt.expectedRemainder != 0, split.HasRemainder())
+			assert.InDelta(t, abs(tt.expectedRemainder), split.Magnitude(), 1e-9)
+		})
+	}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
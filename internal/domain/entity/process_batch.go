@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"time"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+type StageName string
+
+const (
+	StageNormalize  StageName = "normalize"
+	StageParse      StageName = "parse"
+	StageAllocate   StageName = "allocate"
+	StageValidate   StageName = "validate"
+	StageComplement StageName = "complement"
+	StageNumber     StageName = "number"
+	StageWeigh      StageName = "weigh"
+	StageAffix      StageName = "affix"
+)
+
+// ProcessRow is one input order and the products it was parsed into
+type ProcessRow struct {
+	Input         *InputOrder
+	Products      []*Product
+	PriceEnriched bool
+	// free units the complementary caps withheld on account of the row
+	CappedQty int
+	// set when the row was dropped
+	Err *errors.RowError
+}
+
+// ProcessBatch carries a batch through the processing stages, each stage
+// reads what the earlier ones filled in. Options is never nil
+type ProcessBatch struct {
+	Options     *ProcessOptions
+	InputOrders []*InputOrder
+	// zero means no batch deadline
+	Deadline time.Time
+
+	Rows               []*ProcessRow
+	RowErrors          []*errors.RowError
+	PriceSplits        []*PriceSplit
+	ComplementaryLines []*CleanedOrder
+	CleanedOrders      []*CleanedOrder
+}
+
+// nil options are DefaultProcessOptions
+func NewProcessBatch(inputOrders []*InputOrder, options *ProcessOptions) *ProcessBatch {
+	if options == nil {
+		options = DefaultProcessOptions()
+	}
+
+	batch := &ProcessBatch{
+		Options:     options,
+		InputOrders: inputOrders,
+	}
+
+	if options.HasBatchDeadline() {
+		batch.Deadline = time.Now().Add(options.BatchDeadline)
+	}
+
+	return batch
+}
+
+func (r *ProcessRow) Failed() bool {
+	return r.Err != nil
+}
+
+// what the row's products cost after its discount and surcharge
+func (r *ProcessRow) Value() float64 {
+	value := 0.0
+	for _, product := range r.Products {
+		if product.TotalPrice != nil {
+			value += product.TotalPrice.Amount()
+		}
+	}
+	return value
+}
+
+// rows not dropped by a lenient batch
+func (b *ProcessBatch) ActiveRows() []*ProcessRow {
+	rows := make([]*ProcessRow, 0, len(b.Rows))
+	for _, row := range b.Rows {
+		if !row.Failed() {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// active rows whose sales channel gets complementary items
+func (b *ProcessBatch) ComplementedRows() []*ProcessRow {
+	var rows []*ProcessRow
+	for _, row := range b.ActiveRows() {
+		if b.Options.ComplementsChannel(row.Input.Channel) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// a lenient batch drops the row and carries on (nil is returned),
+// otherwise err is handed back to fail the batch. A strict batch drops a row
+// out of time only when ProcessOptions.DropTimedOutRows asks for it
+func (b *ProcessBatch) FailRow(row *ProcessRow, err error) error {
+	if !b.Options.IsLenient() && !(b.Options.DropsTimedOutRows() && errors.Is(err, errors.ErrProcessingTimeout)) {
+		return err
+	}
+
+	var rowErr *errors.RowError
+	if !errors.As(err, &rowErr) {
+		rowErr = errors.NewRowError(row.Input.No, err)
+	}
+
+	log.Warnf("dropping failed row", log.AtoS("order_no", row.Input.No), log.E(err))
+	row.Err = rowErr
+	row.Products = nil
+	b.RowErrors = append(b.RowErrors, rowErr)
+
+	return nil
+}
@@ -0,0 +1,485 @@
+package entity
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"order-placement-system/internal/domain/value_object"
+)
+
+// ComplementaryUnit is the scope complementary quantities are counted over
+type ComplementaryUnit string
+
+const (
+	ComplementaryPerBatch ComplementaryUnit = "batch"
+	ComplementaryPerOrder ComplementaryUnit = "order"
+)
+
+func (u ComplementaryUnit) IsValid() bool {
+	return u == ComplementaryPerBatch || u == ComplementaryPerOrder
+}
+
+// ComplementaryPlacement is where complementary lines go in the output
+type ComplementaryPlacement string
+
+const (
+	// every complementary line after the last main line
+	ComplementaryAtEnd ComplementaryPlacement = "end"
+	// a row's complementary lines right after its main lines, lines that
+	// belong to no single row (per batch counting) still go at the end
+	ComplementaryInterleaved ComplementaryPlacement = "interleaved"
+)
+
+func (p ComplementaryPlacement) IsValid() bool {
+	return p == ComplementaryAtEnd || p == ComplementaryInterleaved
+}
+
+// QuantitySemantics decides how a row's Qty combines with a bundle
+// component's "*N" multiplier
+type QuantitySemantics string
+
+const (
+	// the component ships Qty × N units, "A*3" on a row of Qty 2 is 6
+	QuantityMultiply QuantitySemantics = "multiply"
+	// the multiplier adds to the row quantity, Qty + N - 1 units, so "A*3" on
+	// a row of Qty 2 is 4. Kept for tenants that send it that way
+	QuantityAdditive QuantitySemantics = "additive"
+)
+
+func (q QuantitySemantics) IsValid() bool {
+	return q == QuantityMultiply || q == QuantityAdditive
+}
+
+// units of a component on a row of rowQty, a component without a multiplier
+// always gets rowQty. ok is false when the result does not fit in an int
+func (q QuantitySemantics) ComponentQty(rowQty, multiplier int, hasMultiplier bool) (int, bool) {
+	if !hasMultiplier {
+		return rowQty, true
+	}
+	if q == QuantityAdditive {
+		if multiplier-1 > math.MaxInt-rowQty {
+			return 0, false
+		}
+		return rowQty + multiplier - 1, true
+	}
+	if rowQty != 0 && multiplier > math.MaxInt/rowQty {
+		return 0, false
+	}
+	return rowQty * multiplier, true
+}
+
+// QuantityTenants lists the tenants whose rows use additive quantities,
+// DefaultTenantId makes every tenant additive
+type QuantityTenants []string
+
+// QuantityMultiply unless the tenant is listed
+func (t QuantityTenants) For(tenantId string) QuantitySemantics {
+	for _, tenant := range t {
+		if tenant == DefaultTenantId || tenant == tenantId {
+			return QuantityAdditive
+		}
+	}
+	return QuantityMultiply
+}
+
+// ProcessMode decides what a failing row does to its batch
+type ProcessMode string
+
+const (
+	// the first failing row fails the whole batch
+	ProcessModeStrict ProcessMode = "strict"
+	// failing rows are dropped and reported, the rest of the batch completes
+	ProcessModeLenient ProcessMode = "lenient"
+)
+
+func (m ProcessMode) IsValid() bool {
+	return m == ProcessModeStrict || m == ProcessModeLenient
+}
+
+// FilmTextureStrictness decides what a texture the film type is not made in
+// does, see value_object.FilmTextureMatrix
+type FilmTextureStrictness string
+
+const (
+	// the combination is not checked
+	FilmTextureOff FilmTextureStrictness = "off"
+	// the line is kept and reported in an INCOMPATIBLE_TEXTURE warning
+	FilmTextureWarn FilmTextureStrictness = "warn"
+	// the row fails like any other invalid row
+	FilmTextureReject FilmTextureStrictness = "reject"
+)
+
+func (s FilmTextureStrictness) IsValid() bool {
+	return s == FilmTextureOff || s == FilmTextureWarn || s == FilmTextureReject
+}
+
+// zero budgets mean the processor never times out
+type ProcessOptions struct {
+	RowTimeout    time.Duration
+	BatchDeadline time.Duration
+
+	// product ids matching this pattern are accessories, nil disables it
+	AccessoryPattern *regexp.Regexp
+	// accessories matching this pattern get a wiping cloth per unit, the
+	// others are left out of complementary items. nil leaves them all out
+	ClothAccessoryPattern *regexp.Regexp
+
+	ComplementaryUnit      ComplementaryUnit
+	ComplementaryPlacement ComplementaryPlacement
+	// complementary lines of the same product are merged unless this is
+	// ComplementaryDuplicatesSeparate
+	ComplementaryDuplicates ComplementaryDuplicates
+
+	// whose price list fills rows sent without prices and whose per-tenant
+	// settings apply
+	TenantId string
+	// the tenant was named by the client but not bound to its credentials,
+	// rows are then processed with the DefaultTenantId prices and settings
+	UnverifiedTenant bool
+
+	// applied to model ids after the product code is split
+	ModelSuffixAliases ModelSuffixAliases
+
+	// tokens taken off the front of model ids into the line's variant,
+	// before the model suffix is normalized
+	VariantTokens VariantTokens
+
+	// tenants whose "*N" multipliers add to the row quantity instead of
+	// multiplying it
+	AdditiveQuantityTenants QuantityTenants
+
+	// limits on the components of a "/" bundle and on the units their "*N"
+	// multipliers add up to, a component without one counts 1. The row
+	// quantity and rows that are no bundle are not limited. zero means no
+	// limit
+	MaxBundleComponents int
+	MaxBundleUnits      int
+
+	// lines over this many units are split, for warehouses that cap line
+	// quantities. zero means no limit
+	MaxLineQty int
+
+	Mode ProcessMode
+	// a strict batch drops rows out of time instead of failing, the others
+	// still fail it
+	DropTimedOutRows bool
+
+	// empty is FilmTextureOff
+	FilmTextureStrictness FilmTextureStrictness
+	// what FilmTextureStrictness checks against, nil allows every texture
+	FilmTextureMatrix value_object.FilmTextureMatrix
+
+	// rounding and tolerance of price splits, nil means the THB default
+	PricePolicy *value_object.PricePolicy
+
+	// tenants whose cloths and cleaners are packed into kits
+	KitTenants KitTenants
+
+	// per tenant limits on free items by order value
+	ComplementaryCaps ComplementaryCaps
+
+	// customs metadata attached to complementary lines
+	CustomsClassification CustomsClassification
+
+	// which sales channels get complementary items, nil gives every row the
+	// full policy
+	ChannelPolicies ChannelPolicies
+
+	// campaign codes complementary lines are attributed to, by rule
+	PromoCampaigns PromoCampaigns
+
+	// unit weights of models and complementary SKUs, nil weighs nothing
+	WeightCatalog WeightCatalog
+
+	// what replaces an out of stock cleaner, needs a stock checker
+	CleanerSubstitutions CleanerSubstitutions
+
+	// per tenant prefix and suffix of every output product id
+	SkuAffixes SkuAffixes
+
+	// order of cleaner and kit lines, nil is value_object.DefaultTexturePriorities
+	TexturePriorities value_object.TexturePriorities
+
+	// number of the first line, zero means 1
+	StartNo int
+	// when set, every line is also numbered "<namespace>-<no>"
+	NumberNamespace string
+}
+
+func DefaultProcessOptions() *ProcessOptions {
+	return &ProcessOptions{
+		ComplementaryUnit: ComplementaryPerBatch,
+		Mode:              ProcessModeStrict,
+	}
+}
+
+// returns a copy of o with every non-zero field of overrides applied
+func (o *ProcessOptions) WithOverrides(overrides *ProcessOptions) *ProcessOptions {
+	merged := *DefaultProcessOptions()
+	if o != nil {
+		merged = *o
+	}
+
+	if overrides == nil {
+		return &merged
+	}
+
+	if overrides.RowTimeout > 0 {
+		merged.RowTimeout = overrides.RowTimeout
+	}
+	if overrides.BatchDeadline > 0 {
+		merged.BatchDeadline = overrides.BatchDeadline
+	}
+	if overrides.AccessoryPattern != nil {
+		merged.AccessoryPattern = overrides.AccessoryPattern
+	}
+	if overrides.ClothAccessoryPattern != nil {
+		merged.ClothAccessoryPattern = overrides.ClothAccessoryPattern
+	}
+	if overrides.ComplementaryUnit != "" {
+		merged.ComplementaryUnit = overrides.ComplementaryUnit
+	}
+	if overrides.ComplementaryPlacement != "" {
+		merged.ComplementaryPlacement = overrides.ComplementaryPlacement
+	}
+	if overrides.ComplementaryDuplicates != "" {
+		merged.ComplementaryDuplicates = overrides.ComplementaryDuplicates
+	}
+	if overrides.TenantId != "" {
+		merged.TenantId = overrides.TenantId
+	}
+	if overrides.UnverifiedTenant {
+		merged.UnverifiedTenant = true
+	}
+	if overrides.ModelSuffixAliases != nil {
+		merged.ModelSuffixAliases = overrides.ModelSuffixAliases
+	}
+	if overrides.VariantTokens != nil {
+		merged.VariantTokens = overrides.VariantTokens
+	}
+	if overrides.AdditiveQuantityTenants != nil {
+		merged.AdditiveQuantityTenants = overrides.AdditiveQuantityTenants
+	}
+	if overrides.MaxBundleComponents > 0 {
+		merged.MaxBundleComponents = overrides.MaxBundleComponents
+	}
+	if overrides.MaxBundleUnits > 0 {
+		merged.MaxBundleUnits = overrides.MaxBundleUnits
+	}
+	if overrides.MaxLineQty > 0 {
+		merged.MaxLineQty = overrides.MaxLineQty
+	}
+	if overrides.Mode != "" {
+		merged.Mode = overrides.Mode
+	}
+	if overrides.DropTimedOutRows {
+		merged.DropTimedOutRows = true
+	}
+	if overrides.FilmTextureStrictness != "" {
+		merged.FilmTextureStrictness = overrides.FilmTextureStrictness
+	}
+	if overrides.FilmTextureMatrix != nil {
+		merged.FilmTextureMatrix = overrides.FilmTextureMatrix
+	}
+	if overrides.PricePolicy != nil {
+		merged.PricePolicy = overrides.PricePolicy
+	}
+	if overrides.KitTenants != nil {
+		merged.KitTenants = overrides.KitTenants
+	}
+	if overrides.ComplementaryCaps != nil {
+		merged.ComplementaryCaps = overrides.ComplementaryCaps
+	}
+	if overrides.CustomsClassification != nil {
+		merged.CustomsClassification = overrides.CustomsClassification
+	}
+	if overrides.WeightCatalog != nil {
+		merged.WeightCatalog = overrides.WeightCatalog
+	}
+	if overrides.ChannelPolicies != nil {
+		merged.ChannelPolicies = overrides.ChannelPolicies
+	}
+	if overrides.PromoCampaigns != nil {
+		merged.PromoCampaigns = overrides.PromoCampaigns
+	}
+	if overrides.CleanerSubstitutions != nil {
+		merged.CleanerSubstitutions = overrides.CleanerSubstitutions
+	}
+	if overrides.SkuAffixes != nil {
+		merged.SkuAffixes = overrides.SkuAffixes
+	}
+	if overrides.TexturePriorities != nil {
+		merged.TexturePriorities = overrides.TexturePriorities
+	}
+	if overrides.StartNo > 0 {
+		merged.StartNo = overrides.StartNo
+	}
+	if overrides.NumberNamespace != "" {
+		merged.NumberNamespace = overrides.NumberNamespace
+	}
+
+	return &merged
+}
+
+func (o *ProcessOptions) HasRowTimeout() bool {
+	return o != nil && o.RowTimeout > 0
+}
+
+func (o *ProcessOptions) HasBatchDeadline() bool {
+	return o != nil && o.BatchDeadline > 0
+}
+
+func (o *ProcessOptions) IsAccessory(productId string) bool {
+	return o != nil && o.AccessoryPattern != nil && o.AccessoryPattern.MatchString(productId)
+}
+
+func (o *ProcessOptions) AccessoryComplementsCloth(productId string) bool {
+	return o != nil && o.ClothAccessoryPattern != nil && o.ClothAccessoryPattern.MatchString(productId)
+}
+
+func (o *ProcessOptions) IsComplementaryPerOrder() bool {
+	return o != nil && o.ComplementaryUnit == ComplementaryPerOrder
+}
+
+func (o *ProcessOptions) InterleavesComplementary() bool {
+	return o != nil && o.ComplementaryPlacement == ComplementaryInterleaved
+}
+
+func (o *ProcessOptions) MergesComplementaryDuplicates() bool {
+	return o == nil || o.ComplementaryDuplicates != ComplementaryDuplicatesSeparate
+}
+
+func (o *ProcessOptions) EffectivePricePolicy() *value_object.PricePolicy {
+	if o == nil || o.PricePolicy == nil {
+		return value_object.DefaultPricePolicy()
+	}
+	return o.PricePolicy
+}
+
+// nil means the defaults
+func (o *ProcessOptions) TexturePrioritiesInEffect() value_object.TexturePriorities {
+	if o == nil {
+		return nil
+	}
+	return o.TexturePriorities
+}
+
+func (o *ProcessOptions) ComplementsChannel(channel string) bool {
+	return o == nil || o.ChannelPolicies.For(channel) != ComplementaryNone
+}
+
+// the tenant whose contract prices apply, DefaultTenantId for an unverified
+// tenant so a client cannot pick another tenant's prices
+func (o *ProcessOptions) PriceListTenantId() string {
+	return o.verifiedTenantId()
+}
+
+// the tenant whose pipeline runs the batch, DefaultTenantId for an unverified
+// tenant so a client cannot pick another tenant's stages
+func (o *ProcessOptions) PipelineTenantId() string {
+	return o.verifiedTenantId()
+}
+
+func (o *ProcessOptions) verifiedTenantId() string {
+	if o == nil {
+		return ""
+	}
+	if o.UnverifiedTenant {
+		return DefaultTenantId
+	}
+	return o.TenantId
+}
+
+func (o *ProcessOptions) EmitsKits() bool {
+	return o != nil && o.KitTenants.Includes(o.verifiedTenantId())
+}
+
+// the caps of the tenant bound to the caller's key, an unverified tenant gets
+// the DefaultTenantId caps so a client cannot pick another tenant's allowance
+func (o *ProcessOptions) ValueCaps() []*ComplementaryCap {
+	if o == nil {
+		return nil
+	}
+	return o.ComplementaryCaps.For(o.verifiedTenantId())
+}
+
+func (o *ProcessOptions) FirstNo() int {
+	if o == nil || o.StartNo <= 0 {
+		return 1
+	}
+	return o.StartNo
+}
+
+// empty without a namespace
+func (o *ProcessOptions) NamespacedNo(no int) string {
+	if o == nil || o.NumberNamespace == "" {
+		return ""
+	}
+	return o.NumberNamespace + "-" + strconv.Itoa(no)
+}
+
+// nil when the tenant has no affix
+func (o *ProcessOptions) SkuAffix() *SkuAffix {
+	if o == nil {
+		return nil
+	}
+	return o.SkuAffixes.For(o.verifiedTenantId())
+}
+
+func (o *ProcessOptions) QuantitySemantics() QuantitySemantics {
+	if o == nil {
+		return QuantityMultiply
+	}
+	return o.AdditiveQuantityTenants.For(o.verifiedTenantId())
+}
+
+func (o *ProcessOptions) IsLenient() bool {
+	return o != nil && o.Mode == ProcessModeLenient
+}
+
+func (o *ProcessOptions) DropsTimedOutRows() bool {
+	return o != nil && o.DropTimedOutRows
+}
+
+func (o *ProcessOptions) ChecksFilmTexture(strictness FilmTextureStrictness) bool {
+	return o != nil && o.FilmTextureStrictness == strictness
+}
+
+// nil allows every texture
+func (o *ProcessOptions) FilmTextureMatrixInEffect() value_object.FilmTextureMatrix {
+	if o == nil {
+		return nil
+	}
+	return o.FilmTextureMatrix
+}
+
+func (o *ProcessOptions) NormalizeModelSuffix(modelId string) (string, bool) {
+	if o == nil {
+		return modelId, false
+	}
+	return o.ModelSuffixAliases.Normalize(o.verifiedTenantId(), modelId)
+}
+
+func (o *ProcessOptions) ExtractVariant(modelId string) (model, variant string, ok bool) {
+	if o == nil {
+		return modelId, "", false
+	}
+	return o.VariantTokens.Extract(o.verifiedTenantId(), modelId)
+}
+
+func (o *ProcessOptions) LineQtyLimit() int {
+	if o == nil {
+		return 0
+	}
+	return o.MaxLineQty
+}
+
+func (o *ProcessOptions) ExceedsBundleLimits(components, units int) bool {
+	if o == nil {
+		return false
+	}
+	return (o.MaxBundleComponents > 0 && components > o.MaxBundleComponents) ||
+		(o.MaxBundleUnits > 0 && units > o.MaxBundleUnits)
+}
@@ -0,0 +1,170 @@
+package entity_test
+
+import (
+	"math"
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantitySemantics_ComponentQty(t *testing.T) {
+	tests := []struct {
+		name          string
+		semantics     entity.QuantitySemantics
+		rowQty        int
+		multiplier    int
+		hasMultiplier bool
+		expectedQty   int
+		expectedOk    bool
+	}{
+		{"No multiplier takes the row quantity", entity.QuantityMultiply, 2, 1, false, 2, true},
+		{"Multiply on a single row", entity.QuantityMultiply, 1, 3, true, 3, true},
+		{"Multiply row quantity by multiplier", entity.QuantityMultiply, 2, 3, true, 6, true},
+		{"Multiply overflow", entity.QuantityMultiply, 2, math.MaxInt/2 + 1, true, 0, false},
+		{"Additive on a single row", entity.QuantityAdditive, 1, 3, true, 3, true},
+		{"Additive adds the extra units", entity.QuantityAdditive, 2, 3, true, 4, true},
+		{"Additive without multiplier", entity.QuantityAdditive, 2, 1, false, 2, true},
+		{"Additive overflow", entity.QuantityAdditive, 2, math.MaxInt, true, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qty, ok := tt.semantics.ComponentQty(tt.rowQty, tt.multiplier, tt.hasMultiplier)
+			assert.Equal(t, tt.expectedOk, ok)
+			if tt.expectedOk {
+				assert.Equal(t, tt.expectedQty, qty)
+			}
+		})
+	}
+}
+
+func TestProcessOptions_QuantitySemantics(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *entity.ProcessOptions
+		expected entity.QuantitySemantics
+	}{
+		{"Nil options multiply", nil, entity.QuantityMultiply},
+		{"Unlisted tenant multiplies", &entity.ProcessOptions{TenantId: "beta", AdditiveQuantityTenants: entity.QuantityTenants{"legacy"}}, entity.QuantityMultiply},
+		{"Listed tenant adds", &entity.ProcessOptions{TenantId: "legacy", AdditiveQuantityTenants: entity.QuantityTenants{"legacy"}}, entity.QuantityAdditive},
+		{"Wildcard makes every tenant add", &entity.ProcessOptions{TenantId: "beta", AdditiveQuantityTenants: entity.QuantityTenants{entity.DefaultTenantId}}, entity.QuantityAdditive},
+		{"Unverified listed tenant multiplies", &entity.ProcessOptions{TenantId: "legacy", UnverifiedTenant: true, AdditiveQuantityTenants: entity.QuantityTenants{"legacy"}}, entity.QuantityMultiply},
+		{"Unverified tenant falls back to the wildcard", &entity.ProcessOptions{TenantId: "beta", UnverifiedTenant: true, AdditiveQuantityTenants: entity.QuantityTenants{entity.DefaultTenantId}}, entity.QuantityAdditive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.options.QuantitySemantics())
+		})
+	}
+}
+
+func TestProcessOptions_EmitsKits(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *entity.ProcessOptions
+		expected bool
+	}{
+		{"Nil options", nil, false},
+		{"Listed tenant gets kits", &entity.ProcessOptions{TenantId: "acme", KitTenants: entity.KitTenants{"acme"}}, true},
+		{"Unverified listed tenant gets none", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, KitTenants: entity.KitTenants{"acme"}}, false},
+		{"Unverified tenant falls back to the wildcard", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, KitTenants: entity.KitTenants{entity.DefaultTenantId}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.options.EmitsKits())
+		})
+	}
+}
+
+func TestProcessOptions_SkuAffix(t *testing.T) {
+	acmeAffix := &entity.SkuAffix{Prefix: "TH-"}
+	defaultAffix := &entity.SkuAffix{Suffix: "-X"}
+
+	tests := []struct {
+		name     string
+		options  *entity.ProcessOptions
+		expected *entity.SkuAffix
+	}{
+		{"Nil options", nil, nil},
+		{"Verified tenant gets its affix", &entity.ProcessOptions{TenantId: "acme", SkuAffixes: entity.SkuAffixes{"acme": acmeAffix}}, acmeAffix},
+		{"Unverified tenant gets no affix of its own", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, SkuAffixes: entity.SkuAffixes{"acme": acmeAffix}}, nil},
+		{"Unverified tenant falls back to the default affix", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, SkuAffixes: entity.SkuAffixes{"acme": acmeAffix, entity.DefaultTenantId: defaultAffix}}, defaultAffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.options.SkuAffix())
+		})
+	}
+}
+
+func TestProcessOptions_NormalizeModelSuffix(t *testing.T) {
+	aliases := entity.ModelSuffixAliases{entity.DefaultTenantId: {"-BLACK": "-B"}, "acme": {"-BLACK": "-BK"}}
+
+	tests := []struct {
+		name     string
+		options  *entity.ProcessOptions
+		expected string
+	}{
+		{"Nil options", nil, "IPHONE16-BLACK"},
+		{"Verified tenant gets its aliases", &entity.ProcessOptions{TenantId: "acme", ModelSuffixAliases: aliases}, "IPHONE16-BK"},
+		{"Unverified tenant falls back to the default aliases", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, ModelSuffixAliases: aliases}, "IPHONE16-B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, _ := tt.options.NormalizeModelSuffix("IPHONE16-BLACK")
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+}
+
+func TestProcessOptions_ExtractVariant(t *testing.T) {
+	tokens := entity.VariantTokens{entity.DefaultTenantId: {"RED"}, "acme": {"NOIR"}}
+
+	tests := []struct {
+		name     string
+		options  *entity.ProcessOptions
+		modelId  string
+		expected string
+	}{
+		{"Nil options", nil, "NOIR-IPHONE16", ""},
+		{"Verified tenant gets its tokens", &entity.ProcessOptions{TenantId: "acme", VariantTokens: tokens}, "NOIR-IPHONE16", "NOIR"},
+		{"Unverified tenant gets no tokens of its own", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, VariantTokens: tokens}, "NOIR-IPHONE16", ""},
+		{"Unverified tenant falls back to the default tokens", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, VariantTokens: tokens}, "RED-IPHONE16", "RED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, variant, _ := tt.options.ExtractVariant(tt.modelId)
+			assert.Equal(t, tt.expected, variant)
+		})
+	}
+}
+
+func TestProcessOptions_ValueCaps(t *testing.T) {
+	defaultCaps := []*entity.ComplementaryCap{{Item: entity.ComplementaryCapCleaner, Units: 1, PerValue: 500, Scope: entity.ComplementaryCapPerRow}}
+	acmeCaps := []*entity.ComplementaryCap{{Item: entity.ComplementaryCapCleaner, Units: 1, PerValue: 50, Scope: entity.ComplementaryCapPerRow}}
+	caps := entity.ComplementaryCaps{entity.DefaultTenantId: defaultCaps, "acme": acmeCaps}
+
+	tests := []struct {
+		name     string
+		options  *entity.ProcessOptions
+		expected []*entity.ComplementaryCap
+	}{
+		{"Nil options", nil, nil},
+		{"Verified tenant gets its own caps", &entity.ProcessOptions{TenantId: "acme", ComplementaryCaps: caps}, acmeCaps},
+		{"Unverified tenant gets the default caps", &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true, ComplementaryCaps: caps}, defaultCaps},
+		{"No tenant gets the default caps", &entity.ProcessOptions{ComplementaryCaps: caps}, defaultCaps},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.options.ValueCaps())
+		})
+	}
+}
@@ -8,20 +8,43 @@ import (
 )
 
 type ParsedProduct struct {
-	CleanProductId string              `json:"cleanProductId"`
-	Quantity       int                 `json:"quantity"`
-	OriginalQty    int                 `json:"originalQty"`
-	UnitPrice      *value_object.Price `json:"unitPrice"`
-	TotalPrice     *value_object.Price `json:"totalPrice"`
+	CleanProductId string `json:"cleanProductId"`
+	Quantity       int    `json:"quantity"`
+	OriginalQty    int    `json:"originalQty"`
+	// the component's "*N", 0 when it has none
+	Multiplier int                 `json:"multiplier,omitempty"`
+	UnitPrice  *value_object.Price `json:"unitPrice"`
+	TotalPrice *value_object.Price `json:"totalPrice"`
+	// how the parser rewrote the component, see RewriteWarnings
+	Rewrites []BatchWarningCode `json:"-"`
+}
+
+// the units the component adds to its bundle, its "*N" or 1, whatever the
+// row quantity
+func (p *ParsedProduct) BundleUnits() int {
+	if p.Multiplier < 1 {
+		return 1
+	}
+	return p.Multiplier
 }
 
 type Product struct {
-	ProductId  string              `json:"productId"`
-	MaterialId string              `json:"materialId"`
-	ModelId    string              `json:"modelId"`
-	Quantity   int                 `json:"quantity"`
-	UnitPrice  *value_object.Price `json:"unitPrice"`
-	TotalPrice *value_object.Price `json:"totalPrice"`
+	ProductId        string              `json:"productId"`
+	MaterialId       string              `json:"materialId"`
+	ModelId          string              `json:"modelId"`
+	Quantity         int                 `json:"quantity"`
+	UnitPrice        *value_object.Price `json:"unitPrice"`
+	TotalPrice       *value_object.Price `json:"totalPrice"`
+	IsAccessory      bool                `json:"isAccessory"`
+	PriceEnriched    bool                `json:"priceEnriched"`
+	ComplementsCloth bool                `json:"complementsCloth"`
+	// color or variant token the seller put before the model
+	Variant string `json:"variant,omitempty"`
+	// how the product code was rewritten on its way in, see RewriteWarnings
+	Rewrites []BatchWarningCode `json:"-"`
+	// prices before the row's discount and surcharge, nil when not adjusted
+	GrossUnitPrice  *value_object.Price `json:"grossUnitPrice,omitempty"`
+	GrossTotalPrice *value_object.Price `json:"grossTotalPrice,omitempty"`
 }
 
 func NewProduct(productId string, quantity int, unitPrice, totalPrice *value_object.Price) (*Product, error) {
@@ -41,6 +64,17 @@ func NewProduct(productId string, quantity int, unitPrice, totalPrice *value_obj
 	}, nil
 }
 
+// non-film SKU (e.g. ACC-CABLE-USBC-1M), kept whole without material/model split
+func NewAccessoryProduct(productId string, quantity int, unitPrice, totalPrice *value_object.Price) *Product {
+	return &Product{
+		ProductId:   productId,
+		Quantity:    quantity,
+		UnitPrice:   unitPrice,
+		TotalPrice:  totalPrice,
+		IsAccessory: true,
+	}
+}
+
 // material-model-texture
 func parseProductCode(productId string) (materialId, modelId string, err error) {
 	if productId == "" {
@@ -60,6 +94,25 @@ func parseProductCode(productId string) (materialId, modelId string, err error)
 	return materialId, modelId, nil
 }
 
+// false when matrix says the film type is not made in the texture,
+// accessories and textures the parser does not know are not checked
+func (p *Product) IsTextureCompatible(matrix value_object.FilmTextureMatrix) bool {
+	return p.IsAccessory || filmTextureCompatible(p.MaterialId, matrix)
+}
+
+func (c *CleanedOrder) IsTextureCompatible(matrix value_object.FilmTextureMatrix) bool {
+	return c.IsAccessory || filmTextureCompatible(c.MaterialId, matrix)
+}
+
+func filmTextureCompatible(materialId string, matrix value_object.FilmTextureMatrix) bool {
+	filmType, texture, ok := strings.Cut(materialId, "-")
+	if !ok {
+		return true
+	}
+	t := value_object.Texture(texture)
+	return !t.IsValid() || t.IsCompatibleWithFilmType(filmType, matrix)
+}
+
 func (p *Product) GetTexture() string {
 	parts := strings.Split(p.MaterialId, "-")
 	if len(parts) >= 2 {
@@ -70,13 +123,20 @@ func (p *Product) GetTexture() string {
 
 func (p *Product) ToCleanedOrder(orderNo int) *CleanedOrder {
 	return &CleanedOrder{
-		No:         orderNo,
-		ProductId:  p.ProductId,
-		MaterialId: p.MaterialId,
-		ModelId:    p.ModelId,
-		Qty:        p.Quantity,
-		UnitPrice:  p.UnitPrice,
-		TotalPrice: p.TotalPrice,
+		No:            orderNo,
+		ProductId:     p.ProductId,
+		MaterialId:    p.MaterialId,
+		ModelId:       p.ModelId,
+		Variant:       p.Variant,
+		Qty:           p.Quantity,
+		UnitPrice:     p.UnitPrice,
+		TotalPrice:    p.TotalPrice,
+		IsAccessory:   p.IsAccessory,
+		PriceEnriched: p.PriceEnriched,
+		Rewrites:      p.Rewrites,
+
+		GrossUnitPrice:  p.GrossUnitPrice,
+		GrossTotalPrice: p.GrossTotalPrice,
 	}
 }
 
@@ -86,12 +146,12 @@ func (p *Product) IsValid() error {
 		return errors.ErrInvalidInput
 	}
 
-	if p.MaterialId == "" {
+	if !p.IsAccessory && p.MaterialId == "" {
 		log.Error("Material ID cannot be empty")
 		return errors.ErrInvalidInput
 	}
 
-	if p.ModelId == "" {
+	if !p.IsAccessory && p.ModelId == "" {
 		log.Error("Model ID cannot be empty")
 		return errors.ErrInvalidInput
 	}
@@ -115,20 +175,50 @@ func (p *Product) IsValid() error {
 }
 
 func (c *CleanedOrder) IsMainProduct() bool {
-	return c.MaterialId != "" && c.ModelId != ""
+	return c.IsAccessory || (c.MaterialId != "" && c.ModelId != "")
 }
 
 func (c *CleanedOrder) IsComplementaryProduct() bool {
 	return !c.IsMainProduct()
 }
 
+func (c *CleanedOrder) IsRewritten(code BatchWarningCode) bool {
+	for _, rewrite := range c.Rewrites {
+		if rewrite == code {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Product) Clone() *Product {
 	return &Product{
-		ProductId:  p.ProductId,
-		MaterialId: p.MaterialId,
-		ModelId:    p.ModelId,
-		Quantity:   p.Quantity,
-		UnitPrice:  p.UnitPrice.Clone(),
-		TotalPrice: p.TotalPrice.Clone(),
+		ProductId:        p.ProductId,
+		MaterialId:       p.MaterialId,
+		ModelId:          p.ModelId,
+		Quantity:         p.Quantity,
+		UnitPrice:        p.UnitPrice.Clone(),
+		TotalPrice:       p.TotalPrice.Clone(),
+		IsAccessory:      p.IsAccessory,
+		PriceEnriched:    p.PriceEnriched,
+		ComplementsCloth: p.ComplementsCloth,
+		Variant:          p.Variant,
+		Rewrites:         append([]BatchWarningCode(nil), p.Rewrites...),
+		GrossUnitPrice:   p.GrossUnitPrice.Clone(),
+		GrossTotalPrice:  p.GrossTotalPrice.Clone(),
 	}
 }
+
+// takes the unit price from a price list and recomputes the total for the quantity
+func (p *Product) EnrichPrice(unitPrice *value_object.Price) error {
+	totalPrice, err := unitPrice.MultiplyByInt(p.Quantity)
+	if err != nil {
+		return err
+	}
+
+	p.UnitPrice = unitPrice
+	p.TotalPrice = totalPrice
+	p.PriceEnriched = true
+
+	return nil
+}
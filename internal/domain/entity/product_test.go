@@ -343,6 +343,7 @@ func TestTexture_IsCompatibleWithFilmType(t *testing.T) {
 		name     string
 		texture  value_object.Texture
 		filmType string
+		matrix   value_object.FilmTextureMatrix
 		expected bool
 	}{
 		{
@@ -363,6 +364,27 @@ func TestTexture_IsCompatibleWithFilmType(t *testing.T) {
 			filmType: "FG1A",
 			expected: true,
 		},
+		{
+			name:     "Texture the matrix lists for the film type",
+			texture:  value_object.TextureMatte,
+			filmType: "FG05",
+			matrix:   value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear, value_object.TextureMatte}},
+			expected: true,
+		},
+		{
+			name:     "Texture the film type is not made in",
+			texture:  value_object.TexturePrivacy,
+			filmType: "fg05",
+			matrix:   value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear, value_object.TextureMatte}},
+			expected: false,
+		},
+		{
+			name:     "Film type the matrix does not list",
+			texture:  value_object.TexturePrivacy,
+			filmType: "FG0A",
+			matrix:   value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear}},
+			expected: true,
+		},
 		{
 			name:     "Invalid texture with any film type",
 			texture:  value_object.Texture("INVALID"),
@@ -379,7 +401,7 @@ func TestTexture_IsCompatibleWithFilmType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := tt.texture.IsCompatibleWithFilmType(tt.filmType)
+			result := tt.texture.IsCompatibleWithFilmType(tt.filmType, tt.matrix)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -877,6 +899,15 @@ func TestCleanedOrder_IsMainProduct(t *testing.T) {
 			expected:    true,
 			description: "Order with complex model ID should be main product",
 		},
+		{
+			name: "Accessory product without material and model ID",
+			order: &entity.CleanedOrder{
+				ProductId:   "ACC-CABLE-USBC-1M",
+				IsAccessory: true,
+			},
+			expected:    true,
+			description: "Accessory should be main product",
+		},
 		{
 			name: "Complementary product - wiping cloth",
 			order: &entity.CleanedOrder{
@@ -1616,3 +1647,27 @@ func TestProduct_Memory(t *testing.T) {
 		assert.Equal(t, "MODIFIED", clones[0].ProductId)
 	})
 }
+
+func TestNewAccessoryProduct(t *testing.T) {
+	product := entity.NewAccessoryProduct(
+		"ACC-CABLE-USBC-1M",
+		2,
+		value_object.MustNewPrice(25),
+		value_object.MustNewPrice(50),
+	)
+
+	require.NoError(t, product.IsValid())
+	assert.True(t, product.IsAccessory)
+	assert.Empty(t, product.MaterialId)
+	assert.Empty(t, product.ModelId)
+	assert.Empty(t, product.GetTexture())
+
+	cleaned := product.ToCleanedOrder(4)
+	assert.Equal(t, 4, cleaned.No)
+	assert.Equal(t, "ACC-CABLE-USBC-1M", cleaned.ProductId)
+	assert.True(t, cleaned.IsAccessory)
+	assert.True(t, cleaned.IsMainProduct())
+
+	clone := product.Clone()
+	assert.True(t, clone.IsAccessory)
+}
@@ -0,0 +1,219 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"order-placement-system/internal/domain/value_object"
+)
+
+// a product code every accessory pattern must leave alone
+const lintProductCode = "FG0A-CLEAR-OPPOA3"
+
+// RuleSet is the parsing rules a lint looks at, any subset of them
+type RuleSet struct {
+	// garbage prefixes in the order they are tried
+	PlatformPrefixes      []string                    `json:"platformPrefixes,omitempty"`
+	TextureAliases        value_object.TextureAliases `json:"textureAliases,omitempty"`
+	ModelSuffixAliases    ModelSuffixAliases          `json:"modelSuffixAliases,omitempty"`
+	AccessoryPattern      string                      `json:"accessoryPattern,omitempty"`
+	ClothAccessoryPattern string                      `json:"clothAccessoryPattern,omitempty"`
+}
+
+type LintSeverity string
+
+const (
+	// the rule breaks parsing or contradicts another one
+	LintError LintSeverity = "error"
+	// the rule does nothing or something likely unintended
+	LintWarning LintSeverity = "warning"
+)
+
+type LintFinding struct {
+	Severity LintSeverity `json:"severity"`
+	// which rule, e.g. "platformPrefixes[3]" or "textureAliases.PRIV"
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// LintReport passes when it has no errors, warnings alone do not fail it
+type LintReport struct {
+	Passed   bool           `json:"passed"`
+	Findings []*LintFinding `json:"findings"`
+}
+
+// WithOverrides returns a copy of r with every non-empty rule of overrides
+// in place of its own
+func (r *RuleSet) WithOverrides(overrides *RuleSet) *RuleSet {
+	merged := RuleSet{}
+	if r != nil {
+		merged = *r
+	}
+	if overrides == nil {
+		return &merged
+	}
+
+	if overrides.PlatformPrefixes != nil {
+		merged.PlatformPrefixes = overrides.PlatformPrefixes
+	}
+	if overrides.TextureAliases != nil {
+		merged.TextureAliases = overrides.TextureAliases
+	}
+	if overrides.ModelSuffixAliases != nil {
+		merged.ModelSuffixAliases = overrides.ModelSuffixAliases
+	}
+	if overrides.AccessoryPattern != "" {
+		merged.AccessoryPattern = overrides.AccessoryPattern
+	}
+	if overrides.ClothAccessoryPattern != "" {
+		merged.ClothAccessoryPattern = overrides.ClothAccessoryPattern
+	}
+	return &merged
+}
+
+// Lint looks for rules that conflict, can never apply or match far more than
+// intended. Go regular expressions run in linear time, so patterns are not
+// checked for catastrophic backtracking
+func (r *RuleSet) Lint() *LintReport {
+	var findings []*LintFinding
+	findings = append(findings, r.lintPrefixes()...)
+	findings = append(findings, r.lintTextureAliases()...)
+	findings = append(findings, r.lintModelSuffixAliases()...)
+	findings = append(findings, r.lintPatterns()...)
+
+	report := &LintReport{Passed: true, Findings: make([]*LintFinding, 0, len(findings))}
+	for _, finding := range findings {
+		if finding.Severity == LintError {
+			report.Passed = false
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+	return report
+}
+
+// prefixes are tried in order and the first match is stripped, so a prefix
+// that starts with an earlier one is never reached
+func (r *RuleSet) lintPrefixes() []*LintFinding {
+	var findings []*LintFinding
+	for i, prefix := range r.PlatformPrefixes {
+		rule := fmt.Sprintf("platformPrefixes[%d]", i)
+		if prefix == "" {
+			findings = append(findings, lintError(rule, "an empty prefix matches every product id and strips nothing, cleaning never ends"))
+			continue
+		}
+		if strings.HasPrefix(prefix, "FG") {
+			findings = append(findings, lintError(rule, fmt.Sprintf("%q would strip the film type of product codes", prefix)))
+		}
+
+		for j, earlier := range r.PlatformPrefixes[:i] {
+			if earlier != "" && strings.HasPrefix(prefix, earlier) {
+				findings = append(findings, lintWarning(rule, fmt.Sprintf("%q is never reached, platformPrefixes[%d] %q matches first", prefix, j, earlier)))
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func (r *RuleSet) lintTextureAliases() []*LintFinding {
+	var findings []*LintFinding
+	seen := make(map[string]string)
+	for _, alias := range sortedKeys(r.TextureAliases) {
+		texture := r.TextureAliases[alias]
+		rule := "textureAliases." + alias
+		key := strings.ToUpper(strings.TrimSpace(alias))
+
+		switch {
+		case !texture.IsValid():
+			findings = append(findings, lintError(rule, fmt.Sprintf("points at the unknown texture %q", texture)))
+		case value_object.Texture(key).IsValid() || key == "MAT":
+			findings = append(findings, lintWarning(rule, "is a texture name and is never used as an alias"))
+		case strings.Contains(key, "-"):
+			findings = append(findings, lintWarning(rule, "contains \"-\", product codes are split on it so the alias never matches"))
+		}
+
+		if other, ok := seen[key]; ok && r.TextureAliases[other] != texture {
+			findings = append(findings, lintError(rule, fmt.Sprintf("matches the same texture as textureAliases.%s, which points at %q", other, r.TextureAliases[other])))
+		}
+		seen[key] = alias
+	}
+	return findings
+}
+
+// aliases are applied once, the longest matching suffix wins
+func (r *RuleSet) lintModelSuffixAliases() []*LintFinding {
+	var findings []*LintFinding
+	for _, tenant := range sortedKeys(r.ModelSuffixAliases) {
+		aliases := r.ModelSuffixAliases[tenant]
+		for _, alias := range sortedKeys(aliases) {
+			canonical := aliases[alias]
+			rule := fmt.Sprintf("modelSuffixAliases.%s.%s", tenant, alias)
+
+			switch {
+			case alias == "":
+				findings = append(findings, lintError(rule, "an empty suffix matches every model"))
+			case alias == canonical:
+				findings = append(findings, lintWarning(rule, "rewrites the suffix to itself"))
+			default:
+				if next, ok := aliases[canonical]; ok && canonical != "" && next != canonical {
+					findings = append(findings, lintWarning(rule, fmt.Sprintf("rewrites to %q, which is itself an alias of %q but aliases are not chained", canonical, next)))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func (r *RuleSet) lintPatterns() []*LintFinding {
+	var findings []*LintFinding
+	accessory := compileLintPattern(r.AccessoryPattern, "accessoryPattern", &findings)
+	cloth := compileLintPattern(r.ClothAccessoryPattern, "clothAccessoryPattern", &findings)
+
+	if cloth != nil && r.AccessoryPattern == "" {
+		findings = append(findings, lintWarning("clothAccessoryPattern", "only applies to accessories and accessoryPattern is not set"))
+	}
+	if accessory != nil && cloth != nil && cloth.String() == accessory.String() {
+		findings = append(findings, lintWarning("clothAccessoryPattern", "is the same as accessoryPattern, every accessory gets a wiping cloth"))
+	}
+	return findings
+}
+
+// nil when the pattern is empty or broken, a broken one is reported
+func compileLintPattern(pattern, rule string, findings *[]*LintFinding) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		*findings = append(*findings, lintError(rule, "does not compile: "+err.Error()))
+		return nil
+	}
+
+	switch {
+	case compiled.MatchString(""):
+		*findings = append(*findings, lintError(rule, "matches the empty string, so every product would match"))
+	case compiled.MatchString(lintProductCode):
+		*findings = append(*findings, lintWarning(rule, fmt.Sprintf("matches the product code %s, product codes it matches are no longer parsed", lintProductCode)))
+	}
+	return compiled
+}
+
+func lintError(rule, message string) *LintFinding {
+	return &LintFinding{Severity: LintError, Rule: rule, Message: message}
+}
+
+func lintWarning(rule, message string) *LintFinding {
+	return &LintFinding{Severity: LintWarning, Rule: rule, Message: message}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,147 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSet_Lint(t *testing.T) {
+	tests := []struct {
+		name         string
+		rules        *entity.RuleSet
+		expectPassed bool
+		// severity of the finding on each rule
+		expectFindings map[string]entity.LintSeverity
+	}{
+		{"Empty", &entity.RuleSet{}, true, nil},
+		{
+			"Clean rule set",
+			&entity.RuleSet{
+				PlatformPrefixes:      []string{"%20--%20x", "%20--", "--"},
+				TextureAliases:        value_object.TextureAliases{"PRIV": value_object.TexturePrivacy},
+				ModelSuffixAliases:    entity.ModelSuffixAliases{"*": {"PLUS": "+"}},
+				AccessoryPattern:      `^ACC-`,
+				ClothAccessoryPattern: `^ACC-LENS-`,
+			},
+			true, nil,
+		},
+		{
+			"Shadowed prefix",
+			&entity.RuleSet{PlatformPrefixes: []string{"%20--", "%20--%20x"}},
+			true, map[string]entity.LintSeverity{"platformPrefixes[1]": entity.LintWarning},
+		},
+		{
+			"Empty prefix",
+			&entity.RuleSet{PlatformPrefixes: []string{"--", ""}},
+			false, map[string]entity.LintSeverity{"platformPrefixes[1]": entity.LintError},
+		},
+		{
+			"Prefix strips the film type",
+			&entity.RuleSet{PlatformPrefixes: []string{"FG0"}},
+			false, map[string]entity.LintSeverity{"platformPrefixes[0]": entity.LintError},
+		},
+		{
+			"Unknown texture",
+			&entity.RuleSet{TextureAliases: value_object.TextureAliases{"GLOSSY": "SHINY"}},
+			false, map[string]entity.LintSeverity{"textureAliases.GLOSSY": entity.LintError},
+		},
+		{
+			"Alias is a texture name",
+			&entity.RuleSet{TextureAliases: value_object.TextureAliases{"clear": value_object.TextureMatte, "MAT": value_object.TextureClear}},
+			true, map[string]entity.LintSeverity{"textureAliases.clear": entity.LintWarning, "textureAliases.MAT": entity.LintWarning},
+		},
+		{
+			"Alias with a separator",
+			&entity.RuleSet{TextureAliases: value_object.TextureAliases{"ANTI-GLARE": value_object.TextureMatte}},
+			true, map[string]entity.LintSeverity{"textureAliases.ANTI-GLARE": entity.LintWarning},
+		},
+		{
+			"Aliases differing only in case",
+			&entity.RuleSet{TextureAliases: value_object.TextureAliases{"PRIV": value_object.TexturePrivacy, "priv": value_object.TextureMatte}},
+			false, map[string]entity.LintSeverity{"textureAliases.priv": entity.LintError},
+		},
+		{
+			"Aliases differing only in case agree",
+			&entity.RuleSet{TextureAliases: value_object.TextureAliases{"PRIV": value_object.TexturePrivacy, "priv": value_object.TexturePrivacy}},
+			true, nil,
+		},
+		{
+			"Chained model suffix",
+			&entity.RuleSet{ModelSuffixAliases: entity.ModelSuffixAliases{"acme": {"PLS": "PLUS", "PLUS": "+"}}},
+			true, map[string]entity.LintSeverity{"modelSuffixAliases.acme.PLS": entity.LintWarning},
+		},
+		{
+			"Model suffix to itself",
+			&entity.RuleSet{ModelSuffixAliases: entity.ModelSuffixAliases{"*": {"PRO": "PRO"}}},
+			true, map[string]entity.LintSeverity{"modelSuffixAliases.*.PRO": entity.LintWarning},
+		},
+		{
+			"Empty model suffix",
+			&entity.RuleSet{ModelSuffixAliases: entity.ModelSuffixAliases{"*": {"": "PRO"}}},
+			false, map[string]entity.LintSeverity{"modelSuffixAliases.*.": entity.LintError},
+		},
+		{
+			"Broken pattern",
+			&entity.RuleSet{AccessoryPattern: `^ACC-(`},
+			false, map[string]entity.LintSeverity{"accessoryPattern": entity.LintError},
+		},
+		{
+			"Pattern matches everything",
+			&entity.RuleSet{AccessoryPattern: `^(ACC-)?`},
+			false, map[string]entity.LintSeverity{"accessoryPattern": entity.LintError},
+		},
+		{
+			"Pattern matches product codes",
+			&entity.RuleSet{AccessoryPattern: `OPPO`},
+			true, map[string]entity.LintSeverity{"accessoryPattern": entity.LintWarning},
+		},
+		{
+			"Cloth pattern without accessory pattern",
+			&entity.RuleSet{ClothAccessoryPattern: `^ACC-LENS-`},
+			true, map[string]entity.LintSeverity{"clothAccessoryPattern": entity.LintWarning},
+		},
+		{
+			"Cloth pattern same as accessory pattern",
+			&entity.RuleSet{AccessoryPattern: `^ACC-`, ClothAccessoryPattern: `^ACC-`},
+			true, map[string]entity.LintSeverity{"clothAccessoryPattern": entity.LintWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := tt.rules.Lint()
+
+			assert.Equal(t, tt.expectPassed, report.Passed)
+			findings := make(map[string]entity.LintSeverity)
+			for _, finding := range report.Findings {
+				assert.NotEmpty(t, finding.Message)
+				findings[finding.Rule] = finding.Severity
+			}
+			if tt.expectFindings == nil {
+				assert.Empty(t, findings)
+			} else {
+				assert.Equal(t, tt.expectFindings, findings)
+			}
+		})
+	}
+}
+
+func TestRuleSet_WithOverrides(t *testing.T) {
+	running := &entity.RuleSet{
+		PlatformPrefixes: []string{"--"},
+		TextureAliases:   value_object.TextureAliases{"PRIV": value_object.TexturePrivacy},
+		AccessoryPattern: `^ACC-`,
+	}
+
+	merged := running.WithOverrides(&entity.RuleSet{AccessoryPattern: `^X-`})
+
+	assert.Equal(t, []string{"--"}, merged.PlatformPrefixes)
+	assert.Equal(t, value_object.TextureAliases{"PRIV": value_object.TexturePrivacy}, merged.TextureAliases)
+	assert.Equal(t, `^X-`, merged.AccessoryPattern)
+	assert.Equal(t, `^ACC-`, running.AccessoryPattern)
+	assert.Equal(t, running, running.WithOverrides(nil))
+}
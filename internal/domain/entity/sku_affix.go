@@ -0,0 +1,28 @@
+package entity
+
+// SkuAffix is put around every output product id of a tenant to fit its
+// WMS SKU namespace
+type SkuAffix struct {
+	Prefix string `json:"prefix"`
+	Suffix string `json:"suffix"`
+}
+
+// SkuAffixes maps tenant to its SkuAffix, e.g. {"acme": {"prefix": "TH-"}}.
+// Only listed tenants are affected, there is no "*" default
+type SkuAffixes map[string]*SkuAffix
+
+// nil when the tenant has no affix
+func (a SkuAffixes) For(tenantId string) *SkuAffix {
+	affix, ok := a[tenantId]
+	if !ok || affix == nil || (affix.Prefix == "" && affix.Suffix == "") {
+		return nil
+	}
+	return affix
+}
+
+func (a *SkuAffix) Apply(productId string) string {
+	if a == nil {
+		return productId
+	}
+	return a.Prefix + productId + a.Suffix
+}
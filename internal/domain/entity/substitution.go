@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// CleanerSubstitutions maps a cleaner to the product shipped instead when it
+// is out of stock, e.g. {"PRIVACY-CLEANNER": "UNIVERSAL-CLEANNER"}
+type CleanerSubstitutions map[string]string
+
+func (s CleanerSubstitutions) Validate() error {
+	for productId, substitute := range s {
+		if substitute == "" || substitute == productId {
+			log.Errorf("invalid cleaner substitute", log.S("productId", productId))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// Substitute swaps every out of stock cleaner line for its substitute and
+// records what it replaced. A line is left as it is when its substitute is
+// out of stock too. Kits are not substituted
+func (s CleanerSubstitutions) Substitute(lines []*CleanedOrder, inStock func(productId string) bool) {
+	for _, line := range lines {
+		substitute, ok := s[line.ProductId]
+		if !ok || inStock(line.ProductId) {
+			continue
+		}
+
+		if !inStock(substitute) {
+			log.Warnf("cleaner and its substitute are out of stock",
+				log.S("productId", line.ProductId),
+				log.S("substitute", substitute))
+			continue
+		}
+
+		log.Warnf("substituted out of stock cleaner",
+			log.S("productId", line.ProductId),
+			log.S("substitute", substitute))
+		line.SubstitutedFor = line.ProductId
+		line.ProductId = substitute
+	}
+}
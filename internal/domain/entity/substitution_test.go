@@ -0,0 +1,72 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanerSubstitutions_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		substitutions entity.CleanerSubstitutions
+		expectErr     bool
+	}{
+		{"Valid", entity.CleanerSubstitutions{"PRIVACY-CLEANNER": "UNIVERSAL-CLEANNER"}, false},
+		{"Empty substitute", entity.CleanerSubstitutions{"PRIVACY-CLEANNER": ""}, true},
+		{"Substitute is itself", entity.CleanerSubstitutions{"PRIVACY-CLEANNER": "PRIVACY-CLEANNER"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.substitutions.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCleanerSubstitutions_Substitute(t *testing.T) {
+	substitutions := entity.CleanerSubstitutions{
+		"MATTE-CLEANNER":   "UNIVERSAL-CLEANNER",
+		"PRIVACY-CLEANNER": "UNIVERSAL-CLEANNER",
+		"CLEAR-CLEANNER":   "SPARE-CLEANNER",
+	}
+
+	tests := []struct {
+		name             string
+		outOfStock       []string
+		productId        string
+		expectProductId  string
+		expectSubstitute string
+	}{
+		{"In stock is kept", nil, "MATTE-CLEANNER", "MATTE-CLEANNER", ""},
+		{"Out of stock is substituted", []string{"MATTE-CLEANNER"}, "MATTE-CLEANNER", "UNIVERSAL-CLEANNER", "MATTE-CLEANNER"},
+		{"Substitute out of stock too", []string{"CLEAR-CLEANNER", "SPARE-CLEANNER"}, "CLEAR-CLEANNER", "CLEAR-CLEANNER", ""},
+		{"Product without a substitute", []string{"WIPING-CLOTH"}, "WIPING-CLOTH", "WIPING-CLOTH", ""},
+		{"Kits are not substituted", []string{"CARE-KIT-MATTE"}, "CARE-KIT-MATTE", "CARE-KIT-MATTE", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outOfStock := make(map[string]bool)
+			for _, productId := range tt.outOfStock {
+				outOfStock[productId] = true
+			}
+			line := &entity.CleanedOrder{ProductId: tt.productId, Qty: 2}
+
+			substitutions.Substitute([]*entity.CleanedOrder{line}, func(productId string) bool {
+				return !outOfStock[productId]
+			})
+
+			assert.Equal(t, tt.expectProductId, line.ProductId)
+			assert.Equal(t, tt.expectSubstitute, line.SubstitutedFor)
+			assert.Equal(t, 2, line.Qty)
+		})
+	}
+}
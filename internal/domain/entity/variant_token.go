@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// VariantTokens maps tenant to the color or variant tokens sellers put
+// between the material and the model, e.g. {"*": ["RED", "BLACK"]} for
+// FG0A-CLEAR-RED-IPHONE16PROMAX
+type VariantTokens map[string][]string
+
+// every token is one part of a product code, so it cannot hold a "-"
+func (v VariantTokens) Validate() error {
+	for tenantId, tokens := range v {
+		for _, token := range tokens {
+			if strings.TrimSpace(token) == "" || strings.Contains(token, "-") {
+				log.Errorf("invalid variant token", log.S("tenantId", tenantId), log.S("token", token))
+				return errors.ErrInvalidInput
+			}
+		}
+	}
+	return nil
+}
+
+// Extract takes a leading variant token off modelId, case insensitively.
+// Tenant tokens take precedence over the default ones, ok is false when
+// modelId does not start with one or the token is all there is
+func (v VariantTokens) Extract(tenantId, modelId string) (model, variant string, ok bool) {
+	first, rest, found := strings.Cut(modelId, "-")
+	if !found || rest == "" {
+		return modelId, "", false
+	}
+
+	if token, ok := v.match(v[tenantId], first); ok {
+		return rest, token, true
+	}
+	if tenantId != DefaultTenantId {
+		if token, ok := v.match(v[DefaultTenantId], first); ok {
+			return rest, token, true
+		}
+	}
+	return modelId, "", false
+}
+
+// the token as configured, so every spelling comes out the same
+func (v VariantTokens) match(tokens []string, part string) (string, bool) {
+	for _, token := range tokens {
+		if strings.EqualFold(token, part) {
+			return token, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariantTokens_Extract(t *testing.T) {
+	tokens := entity.VariantTokens{
+		entity.DefaultTenantId: {"RED", "BLACK"},
+		"acme":                 {"ROUGE"},
+	}
+
+	tests := []struct {
+		name      string
+		tenantId  string
+		modelId   string
+		model     string
+		variant   string
+		extracted bool
+	}{
+		{"Default token", "", "RED-IPHONE16PROMAX", "IPHONE16PROMAX", "RED", true},
+		{"Token spelled in lower case", "", "black-IPHONE16PROMAX", "IPHONE16PROMAX", "BLACK", true},
+		{"No token", "", "IPHONE16PROMAX", "IPHONE16PROMAX", "", false},
+		{"Token only in the suffix", "", "IPHONE16PROMAX-RED", "IPHONE16PROMAX-RED", "", false},
+		{"Token alone is the model", "", "RED", "RED", "", false},
+		{"Token with nothing after it", "", "RED-", "RED-", "", false},
+		{"Tenant token", "acme", "ROUGE-OPPOA3", "OPPOA3", "ROUGE", true},
+		{"Tenant falls back to default", "acme", "RED-OPPOA3", "OPPOA3", "RED", true},
+		{"Other tenants do not see tenant tokens", "globex", "ROUGE-OPPOA3", "ROUGE-OPPOA3", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, variant, ok := tokens.Extract(tt.tenantId, tt.modelId)
+			assert.Equal(t, tt.model, model)
+			assert.Equal(t, tt.variant, variant)
+			assert.Equal(t, tt.extracted, ok)
+		})
+	}
+}
+
+func TestVariantTokens_Validate(t *testing.T) {
+	assert.NoError(t, entity.VariantTokens{"*": {"RED"}}.Validate())
+	assert.NoError(t, entity.VariantTokens(nil).Validate())
+	assert.ErrorIs(t, entity.VariantTokens{"*": {""}}.Validate(), errors.ErrInvalidInput)
+	assert.ErrorIs(t, entity.VariantTokens{"*": {"DARK-RED"}}.Validate(), errors.ErrInvalidInput)
+}
@@ -0,0 +1,19 @@
+package entity
+
+// VerificationCase is a batch with the lines it must produce
+type VerificationCase struct {
+	Name     string          `json:"name"`
+	Input    []*InputOrder   `json:"input"`
+	Expected []*CleanedOrder `json:"expected"`
+}
+
+type VerificationResult struct {
+	Name   string   `json:"name"`
+	Passed bool     `json:"passed"`
+	Diffs  []string `json:"diffs,omitempty"`
+}
+
+type VerificationReport struct {
+	Passed bool                  `json:"passed"`
+	Cases  []*VerificationResult `json:"cases"`
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// WeightCatalog maps a product id (complementary SKUs, kits) or a model id to
+// the shipping weight of one unit in grams, e.g. {"IPHONE16PROMAX": 12,
+// "WIPING-CLOTH": 5}
+type WeightCatalog map[string]int
+
+// ShippingWeight is the weight of a batch in grams, lines the catalog does not
+// know are counted but not weighed
+type ShippingWeight struct {
+	Total          int `json:"total"`
+	UnweighedLines int `json:"unweighedLines,omitempty"`
+}
+
+func (c WeightCatalog) Validate() error {
+	for key, grams := range c {
+		if key == "" || grams <= 0 {
+			log.Errorf("invalid unit weight", log.S("key", key), log.AtoS("grams", grams))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// UnitWeight looks the product id up first and then the model id, a kit that
+// is not listed weighs what its components weigh
+func (c WeightCatalog) UnitWeight(line *CleanedOrder) (int, bool) {
+	if grams, ok := c[line.ProductId]; ok {
+		return grams, true
+	}
+	if grams, ok := c[line.ModelId]; ok && line.ModelId != "" {
+		return grams, true
+	}
+
+	if len(line.Components) == 0 {
+		return 0, false
+	}
+	total := 0
+	for _, component := range line.Components {
+		grams, ok := c[component.ProductId]
+		if !ok {
+			return 0, false
+		}
+		total += grams * component.Qty
+	}
+	return total, true
+}
+
+// Weigh sets the shipping weight of every line the catalog knows
+func (c WeightCatalog) Weigh(lines []*CleanedOrder) {
+	for _, line := range lines {
+		if grams, ok := c.UnitWeight(line); ok {
+			line.ShippingWeight = grams * line.Qty
+		}
+	}
+}
+
+// TotalShippingWeight adds up the weighed lines, nil when none was weighed
+func TotalShippingWeight(lines []*CleanedOrder) *ShippingWeight {
+	weight := &ShippingWeight{}
+	for _, line := range lines {
+		if line.ShippingWeight > 0 {
+			weight.Total += line.ShippingWeight
+		} else {
+			weight.UnweighedLines++
+		}
+	}
+
+	if weight.Total == 0 {
+		return nil
+	}
+	return weight
+}
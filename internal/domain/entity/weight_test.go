@@ -0,0 +1,69 @@
+package entity_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightCatalog_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		catalog   entity.WeightCatalog
+		expectErr bool
+	}{
+		{"Positive weights", entity.WeightCatalog{"IPHONE16PROMAX": 12, "WIPING-CLOTH": 5}, false},
+		{"Zero weight", entity.WeightCatalog{"WIPING-CLOTH": 0}, true},
+		{"Negative weight", entity.WeightCatalog{"WIPING-CLOTH": -5}, true},
+		{"Empty key", entity.WeightCatalog{"": 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.catalog.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWeightCatalog_Weigh(t *testing.T) {
+	catalog := entity.WeightCatalog{
+		"IPHONE16PROMAX":              12,
+		"FG0A-PRIVACY-IPHONE16PROMAX": 15,
+		"WIPING-CLOTH":                5,
+		"CLEAR-CLEANNER":              40,
+	}
+	lines := []*entity.CleanedOrder{
+		{ProductId: "FG0A-CLEAR-IPHONE16PROMAX", ModelId: "IPHONE16PROMAX", Qty: 2},
+		{ProductId: "FG0A-PRIVACY-IPHONE16PROMAX", ModelId: "IPHONE16PROMAX", Qty: 1},
+		{ProductId: "FG0A-CLEAR-OPPOA3", ModelId: "OPPOA3", Qty: 1},
+		{ProductId: "CARE-KIT-CLEAR", Qty: 3, Components: []*entity.KitComponent{
+			{ProductId: "WIPING-CLOTH", Qty: 1},
+			{ProductId: "CLEAR-CLEANNER", Qty: 1},
+		}},
+		{ProductId: "CARE-KIT-MATTE", Qty: 1, Components: []*entity.KitComponent{
+			{ProductId: "WIPING-CLOTH", Qty: 1},
+			{ProductId: "MATTE-CLEANNER", Qty: 1},
+		}},
+	}
+
+	catalog.Weigh(lines)
+
+	weights := make([]int, len(lines))
+	for i, line := range lines {
+		weights[i] = line.ShippingWeight
+	}
+	assert.Equal(t, []int{24, 15, 0, 135, 0}, weights, "product id before model id, kits by their components")
+	assert.Equal(t, &entity.ShippingWeight{Total: 174, UnweighedLines: 2}, entity.TotalShippingWeight(lines))
+}
+
+func TestTotalShippingWeight_NothingWeighed(t *testing.T) {
+	assert.Nil(t, entity.TotalShippingWeight([]*entity.CleanedOrder{{ProductId: "WIPING-CLOTH", Qty: 1}}))
+	assert.Nil(t, entity.TotalShippingWeight(nil))
+}
@@ -7,6 +7,7 @@ import (
 
 type ProductParser interface {
 	Parse(platformProductId string, originalQty int, totalPrice *value_object.Price) ([]*entity.ParsedProduct, error)
+	ParseWithQuantities(platformProductId string, originalQty int, totalPrice *value_object.Price, quantities entity.QuantitySemantics) ([]*entity.ParsedProduct, error)
 	ParseFromFloat64(platformProductId string, originalQty int, totalPrice float64) ([]*entity.ParsedProduct, error)
 	CleanPrefix(productId string) string
 	ExtractQuantity(productId string) (cleanId string, quantity int, hasQuantity bool)
@@ -14,3 +15,9 @@ type ProductParser interface {
 	ParseProductCode(productId string) (materialId, modelId string, err error)
 	Validate(productId string) error
 }
+
+// TextureAliasResolver reads texture spellings that may change while the
+// service runs, ok is false for a spelling it does not know
+type TextureAliasResolver interface {
+	ResolveTextureAlias(s string) (texture value_object.Texture, ok bool)
+}
@@ -0,0 +1,46 @@
+package value_object
+
+import (
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// FilmTextureMatrix lists the textures each film type is made in, e.g.
+// {"FG05": ["CLEAR", "MATTE"]} for a film type with no privacy version. Film
+// types that are not listed come in every texture, so nil allows everything
+type FilmTextureMatrix map[string][]Texture
+
+func (m FilmTextureMatrix) Validate() error {
+	for filmType, textures := range m {
+		if strings.TrimSpace(filmType) == "" || len(textures) == 0 {
+			log.Errorf("film type without textures", log.S("filmType", filmType))
+			return errors.ErrInvalidInput
+		}
+		for _, texture := range textures {
+			if !texture.IsValid() {
+				log.Errorf("film type made in an unknown texture", log.S("filmType", filmType), log.S("texture", texture.String()))
+				return errors.ErrInvalidInput
+			}
+		}
+	}
+	return nil
+}
+
+// film types are matched case insensitively
+func (m FilmTextureMatrix) Allows(filmType string, texture Texture) bool {
+	filmType = strings.TrimSpace(filmType)
+	for listed, textures := range m {
+		if !strings.EqualFold(strings.TrimSpace(listed), filmType) {
+			continue
+		}
+		for _, allowed := range textures {
+			if allowed == texture {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
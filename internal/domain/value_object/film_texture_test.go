@@ -0,0 +1,46 @@
+package value_object_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilmTextureMatrix_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		matrix    value_object.FilmTextureMatrix
+		expectErr bool
+	}{
+		{"Valid", value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear, value_object.TextureMatte}}, false},
+		{"Empty", value_object.FilmTextureMatrix{}, false},
+		{"No textures", value_object.FilmTextureMatrix{"FG05": {}}, true},
+		{"Empty film type", value_object.FilmTextureMatrix{" ": {value_object.TextureClear}}, true},
+		{"Unknown texture", value_object.FilmTextureMatrix{"FG05": {"GLOSSY"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.matrix.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFilmTextureMatrix_Allows(t *testing.T) {
+	matrix := value_object.FilmTextureMatrix{"fg05": {value_object.TextureClear, value_object.TextureMatte}}
+
+	assert.True(t, matrix.Allows("FG05", value_object.TextureMatte))
+	assert.False(t, matrix.Allows("FG05", value_object.TexturePrivacy))
+	assert.False(t, matrix.Allows("Fg05", value_object.TexturePrivacy), "film types are matched case insensitively")
+	assert.True(t, matrix.Allows("FG0A", value_object.TexturePrivacy), "unlisted film types take every texture")
+
+	var none value_object.FilmTextureMatrix
+	assert.True(t, none.Allows("FG05", value_object.TexturePrivacy))
+}
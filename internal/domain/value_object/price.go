@@ -4,12 +4,46 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"order-placement-system/pkg/errors"
 	"order-placement-system/pkg/log"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
+// decimal digits kept when reading an amount, anything finer is float noise
+const amountDecimals = 9
+
+var decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+
+// AmountFormat is the JSON type an amount is sent as
+type AmountFormat string
+
+const (
+	// a JSON number, 19.99
+	AmountFormatNumber AmountFormat = "number"
+	// a decimal string, "19.99"
+	AmountFormatString AmountFormat = "string"
+	// either of them, as a setting
+	AmountFormatAny AmountFormat = "any"
+)
+
+func (f AmountFormat) IsValid() bool {
+	return f == AmountFormatNumber || f == AmountFormatString || f == AmountFormatAny
+}
+
+// whether an amount sent as sent is allowed under the setting f, an empty
+// setting allows both
+func (f AmountFormat) Allows(sent AmountFormat) bool {
+	return f == "" || f == AmountFormatAny || f == sent
+}
+
 type Price struct {
 	amount float64
+	// the exact amount of a price read from decimal text, see ParsePrice.
+	// Arithmetic works on amount and leaves it empty
+	decimal string
 }
 
 func NewPrice(amount float64) (*Price, error) {
@@ -48,6 +82,19 @@ func (p *Price) Amount() float64 {
 	return p.amount
 }
 
+// the exact amount of a price read with ParsePrice or from JSON, e.g.
+// 1234567.123456789 where Amount is 1234567.1234567892, and the shortest
+// decimal of Amount for a price computed in float64
+func (p *Price) Decimal() string {
+	if p == nil {
+		return "0"
+	}
+	if p.decimal != "" {
+		return p.decimal
+	}
+	return strconv.FormatFloat(p.amount, 'f', -1, 64)
+}
+
 func (p *Price) IsZero() bool {
 	return p == nil || p.amount == 0
 }
@@ -117,7 +164,14 @@ func (p *Price) DivideByInt(divisor int) (*Price, error) {
 	return p.Divide(float64(divisor))
 }
 
+// exact up to float noise, use a PricePolicy to compare within a currency's
+// tolerance
 func (p *Price) Equals(other *Price) bool {
+	const epsilon = 1e-9
+	return p.EqualsWithin(other, epsilon)
+}
+
+func (p *Price) EqualsWithin(other *Price, epsilon float64) bool {
 	if p == nil && other == nil {
 		return true
 	}
@@ -126,7 +180,6 @@ func (p *Price) Equals(other *Price) bool {
 		return false
 	}
 
-	const epsilon = 1e-9
 	return math.Abs(p.amount-other.amount) < epsilon
 }
 
@@ -159,13 +212,9 @@ func (p *Price) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("%.2f", p.amount)), nil
 }
 
+// accepts a JSON number or a decimal string, see ParseJSONPrice
 func (p *Price) UnmarshalJSON(data []byte) error {
-	var amount float64
-	if err := json.Unmarshal(data, &amount); err != nil {
-		return err
-	}
-
-	price, err := NewPrice(amount)
+	price, _, err := ParseJSONPrice(data)
 	if err != nil {
 		return err
 	}
@@ -179,7 +228,7 @@ func (p *Price) Clone() *Price {
 		return nil
 	}
 
-	return &Price{amount: p.amount}
+	return &Price{amount: p.amount, decimal: p.decimal}
 }
 
 func (p *Price) Round(precision int) *Price {
@@ -193,10 +242,143 @@ func (p *Price) Round(precision int) *Price {
 	return MustNewPrice(rounded)
 }
 
+// code and plain number, e.g. THB 50.00. Use a PriceFormatter for amounts
+// people read
 func (p *Price) ToDisplayString(currency string) string {
 	if currency == "" {
-		currency = "THB"
+		currency = DefaultCurrency
+	}
+
+	minorUnits, ok := currencyMinorUnits[currency]
+	if !ok {
+		minorUnits = 2
 	}
+	policy := &PricePolicy{Currency: currency, MinorUnits: minorUnits}
+	return NewPriceFormatter(policy, DefaultLocale).FormatCode(p)
+}
+
+// ParseAmount reads a decimal amount such as "19.99". Digits past the ninth
+// decimal are rounded off, so a client's float noise like
+// "19.990000000000002" reads as 19.99. The result is the nearest float64, use
+// ParsePrice to keep the exact decimal
+func ParseAmount(s string) (float64, error) {
+	decimal, err := parseDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+
+	amount, err := strconv.ParseFloat(decimal, 64)
+	if err != nil || math.IsInf(amount, 0) {
+		log.Errorf("amount is out of range", log.S("amount", s))
+		return 0, errors.ErrInvalidInput
+	}
+	return amount, nil
+}
 
-	return fmt.Sprintf("%s %.2f", currency, p.Amount())
+// ParsePrice reads a decimal amount like ParseAmount and keeps its exact
+// decimal, see Price.Decimal
+func ParsePrice(s string) (*Price, error) {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := NewPrice(amount)
+	if err != nil {
+		return nil, err
+	}
+	if price.decimal, err = parseDecimal(s); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// ParseJSONPrice reads an amount sent as a JSON number or a decimal string
+// from its decimal text, null reads as zero. format is the JSON type it was
+// sent as, empty for null
+func ParseJSONPrice(data []byte) (price *Price, format AmountFormat, err error) {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return ZeroPrice(), "", nil
+	}
+
+	format = AmountFormatNumber
+	if strings.HasPrefix(text, `"`) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, "", err
+		}
+		text = strings.TrimSpace(s)
+		format = AmountFormatString
+	}
+
+	if price, err = ParsePrice(text); err != nil {
+		return nil, "", err
+	}
+	return price, format, nil
+}
+
+// s rounded half away from zero to amountDecimals decimals and written
+// without exponent and trailing zeros, e.g. "1.5e1" is "15". The digits are
+// never held in a float64
+func parseDecimal(s string) (string, error) {
+	if !decimalPattern.MatchString(s) {
+		log.Errorf("amount is not a decimal number", log.S("amount", s))
+		return "", errors.ErrInvalidInput
+	}
+
+	mantissa, exponentText, _ := strings.Cut(strings.ToLower(s), "e")
+	negative := strings.HasPrefix(mantissa, "-")
+	mantissa = strings.TrimPrefix(mantissa, "-")
+
+	exponent := 0
+	if exponentText != "" {
+		var err error
+		if exponent, err = strconv.Atoi(exponentText); err != nil {
+			log.Errorf("amount is out of range", log.S("amount", s))
+			return "", errors.ErrInvalidInput
+		}
+	}
+
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(integer+fraction, "0")
+	// the amount is digits × 10^-shift units of 10^-amountDecimals
+	shift := len(fraction) - exponent - amountDecimals
+
+	if digits == "" || shift > len(digits) {
+		return "0", nil
+	}
+	// past float64's range, ParseAmount rejects it
+	if len(digits)-shift > 400 {
+		log.Errorf("amount is out of range", log.S("amount", s))
+		return "", errors.ErrInvalidInput
+	}
+
+	units, _ := new(big.Int).SetString(digits, 10)
+	if shift < 0 {
+		units.Mul(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil))
+	} else if shift > 0 {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil)
+		var remainder big.Int
+		units.QuoRem(units, divisor, &remainder)
+		if remainder.Lsh(&remainder, 1).Cmp(divisor) >= 0 {
+			units.Add(units, big.NewInt(1))
+		}
+	}
+
+	text := units.String()
+	if text == "0" {
+		return "0", nil
+	}
+	if len(text) <= amountDecimals {
+		text = strings.Repeat("0", amountDecimals-len(text)+1) + text
+	}
+	whole, decimals := text[:len(text)-amountDecimals], strings.TrimRight(text[len(text)-amountDecimals:], "0")
+	if decimals != "" {
+		whole += "." + decimals
+	}
+	if negative {
+		whole = "-" + whole
+	}
+	return whole, nil
 }
@@ -0,0 +1,169 @@
+package value_object
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a BCP 47 language tag such as th-TH
+type Locale string
+
+const (
+	LocaleThai     Locale = "th-TH"
+	LocaleEnglish  Locale = "en-US"
+	LocaleGerman   Locale = "de-DE"
+	LocaleJapanese Locale = "ja-JP"
+
+	DefaultLocale = LocaleThai
+)
+
+// in the order a bare language picks them
+var SupportedLocales = []Locale{LocaleThai, LocaleEnglish, LocaleGerman, LocaleJapanese}
+
+// how a locale writes an amount
+type localeConvention struct {
+	decimal     string
+	group       string
+	symbolAfter bool
+}
+
+var localeConventions = map[Locale]localeConvention{
+	LocaleThai:     {decimal: ".", group: ","},
+	LocaleEnglish:  {decimal: ".", group: ","},
+	LocaleGerman:   {decimal: ",", group: ".", symbolAfter: true},
+	LocaleJapanese: {decimal: ".", group: ","},
+}
+
+var currencySymbols = map[string]string{
+	"THB": "฿",
+	"USD": "$",
+	"EUR": "€",
+	"JPY": "¥",
+	"KRW": "₩",
+	"BTC": "₿",
+}
+
+// PriceFormatter writes amounts of the policy's currency for people to read,
+// in the conventions of its locale
+type PriceFormatter struct {
+	policy     *PricePolicy
+	convention localeConvention
+	Locale     Locale
+}
+
+// nil policy means the THB default, an unsupported locale the Thai one
+func NewPriceFormatter(policy *PricePolicy, locale Locale) *PriceFormatter {
+	if policy == nil {
+		policy = DefaultPricePolicy()
+	}
+	convention, ok := localeConventions[locale]
+	if !ok {
+		locale, convention = DefaultLocale, localeConventions[DefaultLocale]
+	}
+	return &PriceFormatter{policy: policy, convention: convention, Locale: locale}
+}
+
+// e.g. ฿1,234.50 in th-TH and 1.234,50 € in de-DE, nil formats as zero
+func (f *PriceFormatter) Format(price *Price) string {
+	return f.FormatAmount(price.Amount())
+}
+
+func (f *PriceFormatter) FormatAmount(amount float64) string {
+	number := f.number(amount)
+	symbol := currencySymbols[f.policy.Currency]
+	if symbol == "" {
+		return f.policy.Currency + " " + number
+	}
+
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	if f.convention.symbolAfter {
+		return sign + number + " " + symbol
+	}
+	return sign + symbol + number
+}
+
+// ISO code and plain number, e.g. THB 1234.50, for logs and machine readers
+func (f *PriceFormatter) FormatCode(price *Price) string {
+	return f.policy.Currency + " " + strconv.FormatFloat(f.policy.Round(price.Amount()), 'f', f.policy.MinorUnits, 64)
+}
+
+// rounded to the currency's minor unit, grouped by thousands
+func (f *PriceFormatter) number(amount float64) string {
+	rounded := f.policy.Round(amount)
+	digits := strconv.FormatFloat(math.Abs(rounded), 'f', f.policy.MinorUnits, 64)
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(f.convention.group)
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction != "" {
+		grouped.WriteString(f.convention.decimal)
+		grouped.WriteString(fraction)
+	}
+
+	if rounded < 0 {
+		return "-" + grouped.String()
+	}
+	return grouped.String()
+}
+
+// NegotiateLocale picks the supported locale an Accept-Language header
+// prefers most, a language matches the first supported locale of it (en picks
+// en-US). DefaultLocale when nothing matches
+func NegotiateLocale(acceptLanguage string) Locale {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag != "" && quality > 0 {
+			tags = append(tags, weighted{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	for _, tag := range tags {
+		if locale, ok := supportedLocale(tag.tag); ok {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+func supportedLocale(tag string) (Locale, bool) {
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range SupportedLocales {
+		if strings.EqualFold(tag, string(locale)) {
+			return locale, true
+		}
+	}
+	for _, locale := range SupportedLocales {
+		localeLanguage, _, _ := strings.Cut(string(locale), "-")
+		if strings.EqualFold(language, localeLanguage) {
+			return locale, true
+		}
+	}
+	return "", false
+}
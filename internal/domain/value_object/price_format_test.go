@@ -0,0 +1,71 @@
+package value_object_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceFormatter_Format(t *testing.T) {
+	policy := func(currency string) *value_object.PricePolicy {
+		p, err := value_object.NewPricePolicy(currency, 0)
+		require.NoError(t, err)
+		return p
+	}
+
+	tests := []struct {
+		name     string
+		policy   *value_object.PricePolicy
+		locale   value_object.Locale
+		amount   float64
+		expected string
+	}{
+		{"Thai baht", nil, value_object.LocaleThai, 1234.5, "฿1,234.50"},
+		{"Millions", nil, value_object.LocaleThai, 1234567.891, "฿1,234,567.89"},
+		{"Below a thousand", nil, value_object.LocaleThai, 50, "฿50.00"},
+		{"Negative", nil, value_object.LocaleThai, -1234.5, "-฿1,234.50"},
+		{"German conventions", policy("EUR"), value_object.LocaleGerman, 1234.5, "1.234,50 €"},
+		{"Yen has no minor unit", policy("JPY"), value_object.LocaleJapanese, 1234.4, "¥1,234"},
+		{"Bitcoin", policy("BTC"), value_object.LocaleEnglish, 0.5, "₿0.50000000"},
+		{"Unsupported locale is Thai", policy("USD"), "fr-FR", 1000, "$1,000.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := value_object.NewPriceFormatter(tt.policy, tt.locale)
+			assert.Equal(t, tt.expected, formatter.FormatAmount(tt.amount))
+		})
+	}
+}
+
+func TestPriceFormatter_FormatCode(t *testing.T) {
+	formatter := value_object.NewPriceFormatter(nil, value_object.LocaleGerman)
+	assert.Equal(t, "THB 1234.50", formatter.FormatCode(value_object.MustNewPrice(1234.5)))
+	assert.Equal(t, "0,00 ฿", formatter.Format(nil))
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       value_object.Locale
+	}{
+		{"", value_object.LocaleThai},
+		{"de-DE", value_object.LocaleGerman},
+		{"EN-us", value_object.LocaleEnglish},
+		{"en-GB,en;q=0.9", value_object.LocaleEnglish},
+		{"fr-FR,ja;q=0.8,de;q=0.9", value_object.LocaleGerman},
+		{"ja;q=0.3, th;q=0.7", value_object.LocaleThai},
+		{"de;q=0, ja", value_object.LocaleJapanese},
+		{"fr-FR, *;q=0.5", value_object.LocaleThai},
+		{"en;q=abc", value_object.LocaleThai},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.expected, value_object.NegotiateLocale(tt.acceptLanguage))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package value_object
+
+import (
+	"math"
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+const DefaultCurrency = "THB"
+
+// decimals of the smallest unit of each supported currency
+var currencyMinorUnits = map[string]int{
+	"THB": 2,
+	"USD": 2,
+	"EUR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"BTC": 8,
+}
+
+// PricePolicy is the precision prices of one currency are rounded to and the
+// tolerance two amounts may differ by and still be equal
+type PricePolicy struct {
+	Currency   string  `json:"currency"`
+	MinorUnits int     `json:"minorUnits"`
+	Epsilon    float64 `json:"epsilon"`
+}
+
+// zero epsilon means half of the currency's minor unit
+func NewPricePolicy(currency string, epsilon float64) (*PricePolicy, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	minorUnits, ok := currencyMinorUnits[currency]
+	if !ok {
+		log.Errorf("unsupported currency", log.S("currency", currency))
+		return nil, errors.ErrInvalidInput
+	}
+
+	if epsilon < 0 || math.IsNaN(epsilon) || math.IsInf(epsilon, 0) {
+		log.Errorf("invalid price epsilon", log.AtoS("epsilon", epsilon))
+		return nil, errors.ErrInvalidInput
+	}
+
+	if epsilon == 0 {
+		epsilon = math.Pow(10, -float64(minorUnits)) / 2
+	}
+
+	return &PricePolicy{Currency: currency, MinorUnits: minorUnits, Epsilon: epsilon}, nil
+}
+
+// THB, equal within half a satang
+func DefaultPricePolicy() *PricePolicy {
+	return &PricePolicy{Currency: DefaultCurrency, MinorUnits: 2, Epsilon: 0.005}
+}
+
+func (p *PricePolicy) Round(amount float64) float64 {
+	multiplier := math.Pow(10, float64(p.MinorUnits))
+	return math.Round(amount*multiplier) / multiplier
+}
+
+func (p *PricePolicy) Equal(a, b *Price) bool {
+	return a.EqualsWithin(b, p.Epsilon)
+}
@@ -0,0 +1,68 @@
+package value_object_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPricePolicy(t *testing.T) {
+	tests := []struct {
+		name               string
+		currency           string
+		epsilon            float64
+		expectedMinorUnits int
+		expectedEpsilon    float64
+		expectError        bool
+	}{
+		{"THB defaults to half a satang", "THB", 0, 2, 0.005, false},
+		{"Currency is case insensitive", " jpy ", 0, 0, 0.5, false},
+		{"BTC uses satoshis", "BTC", 0, 8, 0.000000005, false},
+		{"Explicit epsilon wins", "THB", 0.01, 2, 0.01, false},
+		{"Unknown currency", "XYZ", 0, 0, 0, true},
+		{"Negative epsilon", "THB", -1, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := value_object.NewPricePolicy(tt.currency, tt.epsilon)
+			if tt.expectError {
+				assert.ErrorIs(t, err, errors.ErrInvalidInput)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMinorUnits, policy.MinorUnits)
+			assert.InDelta(t, tt.expectedEpsilon, policy.Epsilon, 1e-12)
+		})
+	}
+}
+
+func TestPricePolicy_Equal(t *testing.T) {
+	thb := value_object.DefaultPricePolicy()
+	jpy, err := value_object.NewPricePolicy("JPY", 0)
+	require.NoError(t, err)
+
+	a := value_object.MustNewPrice(100)
+	b := value_object.MustNewPrice(100.004)
+	c := value_object.MustNewPrice(100.4)
+
+	assert.True(t, thb.Equal(a, b))
+	assert.False(t, thb.Equal(a, c))
+	assert.True(t, jpy.Equal(a, c))
+	assert.False(t, a.Equals(b), "Equals stays exact")
+	assert.True(t, thb.Equal(nil, nil))
+	assert.False(t, thb.Equal(a, nil))
+}
+
+func TestPricePolicy_Round(t *testing.T) {
+	jpy, err := value_object.NewPricePolicy("JPY", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, 33.33, value_object.DefaultPricePolicy().Round(100.0/3))
+	assert.Equal(t, 33.0, jpy.Round(100.0/3))
+}
@@ -843,6 +843,152 @@ func TestPriceJSON(t *testing.T) {
 	})
 }
 
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  float64
+		expectErr bool
+	}{
+		{"19.99", 19.99, false},
+		{"19.990000000000002", 19.99, false},
+		{"0.1", 0.1, false},
+		{"100", 100, false},
+		{"1e2", 100, false},
+		{"0.000000001", 0.000000001, false},
+		{"-5", -5, false},
+		{"", 0, true},
+		{"19,99", 0, true},
+		{"NaN", 0, true},
+		{"Inf", 0, true},
+		{"0x10", 0, true},
+		{"1e400", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := value_object.ParseAmount(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("ParseAmount(%q) should error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseAmount(%q) error = %v", tt.input, err)
+				return
+			}
+			if amount != tt.expected {
+				t.Errorf("ParseAmount(%q) = %v, want %v", tt.input, amount, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrice_UnmarshalJSON_String(t *testing.T) {
+	t.Run("unmarshal decimal string", func(t *testing.T) {
+		var price value_object.Price
+		if err := json.Unmarshal([]byte(`"19.990000000000002"`), &price); err != nil {
+			t.Errorf("UnmarshalJSON() error = %v", err)
+			return
+		}
+		if price.Amount() != 19.99 {
+			t.Errorf("UnmarshalJSON() amount = %v, want 19.99", price.Amount())
+		}
+	})
+
+	t.Run("unmarshal invalid strings", func(t *testing.T) {
+		for _, data := range []string{`"abc"`, `"-1"`} {
+			var price value_object.Price
+			if err := json.Unmarshal([]byte(data), &price); err == nil {
+				t.Errorf("UnmarshalJSON(%s) should error", data)
+			}
+		}
+	})
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		input     string
+		decimal   string
+		amount    float64
+		expectErr bool
+	}{
+		{"19.99", "19.99", 19.99, false},
+		{"19.990000000000002", "19.99", 19.99, false},
+		{"19.9899999999999", "19.99", 19.99, false},
+		{"50.00", "50", 50, false},
+		{"1.5e1", "15", 15, false},
+		{"1234567.123456789", "1234567.123456789", 1234567.123456789, false},
+		{"12345678901234567890.5", "12345678901234567890.5", 12345678901234567890.5, false},
+		{"0.0000000004", "0", 0, false},
+		{"0.0000000005", "0.000000001", 0.000000001, false},
+		{"-5", "", 0, true},
+		{"19,99", "", 0, true},
+		{"1e400", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			price, err := value_object.ParsePrice(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("ParsePrice(%q) should error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParsePrice(%q) error = %v", tt.input, err)
+				return
+			}
+			if price.Decimal() != tt.decimal || price.Amount() != tt.amount {
+				t.Errorf("ParsePrice(%q) = %s (%v), want %s (%v)", tt.input, price.Decimal(), price.Amount(), tt.decimal, tt.amount)
+			}
+		})
+	}
+
+	t.Run("arithmetic leaves the float64 decimal", func(t *testing.T) {
+		price, _ := value_object.ParsePrice("0.1")
+		sum, _ := price.Add(value_object.MustNewPrice(0.2))
+		if sum.Decimal() != "0.30000000000000004" {
+			t.Errorf("Decimal() = %s", sum.Decimal())
+		}
+	})
+}
+
+func TestParseJSONPrice(t *testing.T) {
+	tests := []struct {
+		data    string
+		decimal string
+		format  value_object.AmountFormat
+	}{
+		{`19.99`, "19.99", value_object.AmountFormatNumber},
+		{`"19.99"`, "19.99", value_object.AmountFormatString},
+		{`null`, "0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			price, format, err := value_object.ParseJSONPrice([]byte(tt.data))
+			if err != nil {
+				t.Errorf("ParseJSONPrice(%s) error = %v", tt.data, err)
+				return
+			}
+			if price.Decimal() != tt.decimal || format != tt.format {
+				t.Errorf("ParseJSONPrice(%s) = %s %q, want %s %q", tt.data, price.Decimal(), format, tt.decimal, tt.format)
+			}
+		})
+	}
+}
+
+func TestAmountFormat_Allows(t *testing.T) {
+	if !value_object.AmountFormatAny.Allows(value_object.AmountFormatString) || !value_object.AmountFormat("").Allows(value_object.AmountFormatNumber) {
+		t.Error("any format should allow both")
+	}
+	if value_object.AmountFormatString.Allows(value_object.AmountFormatNumber) || value_object.AmountFormatNumber.Allows(value_object.AmountFormatString) {
+		t.Error("a format should only allow itself")
+	}
+}
+
 // Benchmark tests
 func BenchmarkPriceAdd(b *testing.B) {
 	price1 := value_object.MustNewPrice(50.0)
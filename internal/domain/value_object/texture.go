@@ -21,15 +21,63 @@ var AllTextures = []Texture{
 	TexturePrivacy,
 }
 
+// TextureAliases maps shortened or localized texture strings to a texture,
+// e.g. "PRIV" to TexturePrivacy
+type TextureAliases map[string]Texture
+
+// every alias must point at a valid texture. Aliases are matched case
+// insensitively, so two keys that differ only by case would resolve to
+// whichever the map yields first and are refused
+func (a TextureAliases) Validate() error {
+	seen := make(map[string]string, len(a))
+	for alias, texture := range a {
+		if !texture.IsValid() {
+			log.Errorf("texture alias points at an invalid texture",
+				log.S("alias", alias),
+				log.S("texture", texture.String()))
+			return errors.ErrInvalidInput
+		}
+		key := strings.ToUpper(strings.TrimSpace(alias))
+		if other, ok := seen[key]; ok {
+			log.Errorf("texture aliases differ only by case",
+				log.S("alias", alias),
+				log.S("other", other))
+			return errors.ErrInvalidInput
+		}
+		seen[key] = alias
+	}
+	return nil
+}
+
+// aliases are matched case insensitively, every hit is logged as a warning
+func (a TextureAliases) Resolve(s string) (Texture, bool) {
+	key := strings.ToUpper(strings.TrimSpace(s))
+	for alias, texture := range a {
+		if strings.ToUpper(alias) == key {
+			log.Warnf("texture alias applied", log.S("alias", s), log.S("texture", texture.String()))
+			return texture, true
+		}
+	}
+	return "", false
+}
+
 func NewTexture(s string) (Texture, error) {
+	return NewTextureWithAliases(s, nil)
+}
+
+// s is tried as a texture first and only then against aliases
+func NewTextureWithAliases(s string, aliases TextureAliases) (Texture, error) {
 	texture := Texture(strings.ToUpper(strings.TrimSpace(s)))
+	if texture.IsValid() {
+		return texture, nil
+	}
 
-	if !texture.IsValid() {
-		log.Errorf("invalid texture", log.S("texture", s))
-		return "", errors.ErrInvalidInput
+	if aliased, ok := aliases.Resolve(s); ok {
+		return aliased, nil
 	}
 
-	return texture, nil
+	log.Errorf("invalid texture", log.S("texture", s))
+	return "", errors.ErrInvalidInput
 }
 
 func (t Texture) IsValid() bool {
@@ -72,9 +120,10 @@ func (t *Texture) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (t Texture) IsCompatibleWithFilmType(filmType string) bool {
-
-	return t.IsValid()
+// false for unknown textures and when matrix says the film type is not made
+// in t, film types the matrix does not list come in every valid texture
+func (t Texture) IsCompatibleWithFilmType(filmType string, matrix FilmTextureMatrix) bool {
+	return t.IsValid() && matrix.Allows(filmType, t)
 }
 
 func (t Texture) GetDisplayName() string {
@@ -90,6 +139,7 @@ func (t Texture) GetDisplayName() string {
 	}
 }
 
+// the default priority, see TexturePriorities for the configured ones
 func (t Texture) GetPriority() int {
 	switch t {
 	case TextureClear:
@@ -105,6 +155,10 @@ func (t Texture) GetPriority() int {
 
 // FG0A-CLEAR to TextureClear get texture from material id
 func ParseTextureFromMaterialId(materialId string) (Texture, error) {
+	return ParseTextureFromMaterialIdWithAliases(materialId, nil)
+}
+
+func ParseTextureFromMaterialIdWithAliases(materialId string, aliases TextureAliases) (Texture, error) {
 	if materialId == "" {
 		log.Error("material id cannot be empty")
 		return "", errors.ErrInvalidInput
@@ -117,5 +171,5 @@ func ParseTextureFromMaterialId(materialId string) (Texture, error) {
 	}
 
 	textureStr := parts[1] // texture part
-	return NewTexture(textureStr)
+	return NewTextureWithAliases(textureStr, aliases)
 }
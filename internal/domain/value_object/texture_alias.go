@@ -0,0 +1,22 @@
+package value_object
+
+import (
+	"strings"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// NewTextureAlias returns alias the way it is stored, trimmed and upper case,
+// once it may read as texture. A texture name cannot become an alias
+func NewTextureAlias(alias string, texture Texture) (string, error) {
+	alias = strings.ToUpper(strings.TrimSpace(alias))
+	if alias == "" || Texture(alias).IsValid() {
+		log.Errorf("invalid texture alias", log.S("alias", alias))
+		return "", errors.ErrInvalidInput
+	}
+	if err := (TextureAliases{alias: texture}).Validate(); err != nil {
+		return "", err
+	}
+	return alias, nil
+}
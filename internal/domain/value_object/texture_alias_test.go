@@ -0,0 +1,36 @@
+package value_object_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTextureAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		alias     string
+		texture   value_object.Texture
+		expected  string
+		expectErr bool
+	}{
+		{"Alias of a texture", " glossy-rt ", value_object.TextureMatte, "GLOSSY-RT", false},
+		{"Empty alias", " ", value_object.TextureMatte, "", true},
+		{"Texture name as alias", "clear", value_object.TextureMatte, "", true},
+		{"Alias of an unknown texture", "SHINY-RT", value_object.Texture("SHINY"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alias, err := value_object.NewTextureAlias(tt.alias, tt.texture)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, alias)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package value_object
+
+import (
+	"sort"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// TexturePriorities orders textures for cleaner and kit lines, lower first.
+// nil is DefaultTexturePriorities
+type TexturePriorities map[Texture]int
+
+// CLEAR, MATTE, PRIVACY
+func DefaultTexturePriorities() TexturePriorities {
+	priorities := make(TexturePriorities, len(AllTextures))
+	for _, texture := range AllTextures {
+		priorities[texture] = texture.GetPriority()
+	}
+	return priorities
+}
+
+// every texture needs a positive priority of its own
+func (p TexturePriorities) Validate() error {
+	seen := make(map[int]Texture, len(p))
+	for texture, priority := range p {
+		if !texture.IsValid() {
+			log.Errorf("priority for an unknown texture", log.S("texture", texture.String()))
+			return errors.ErrInvalidInput
+		}
+		if priority <= 0 {
+			log.Errorf("texture priority must be positive", log.S("texture", texture.String()), log.AtoS("priority", priority))
+			return errors.ErrInvalidInput
+		}
+		if other, ok := seen[priority]; ok {
+			log.Errorf("texture priority is not unique",
+				log.S("texture", texture.String()),
+				log.S("other_texture", other.String()),
+				log.AtoS("priority", priority))
+			return errors.ErrInvalidInput
+		}
+		seen[priority] = texture
+	}
+
+	for _, texture := range AllTextures {
+		if _, ok := p[texture]; !ok {
+			log.Errorf("texture has no priority", log.S("texture", texture.String()))
+			return errors.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+// priority of texture, 0 for an unknown texture
+func (p TexturePriorities) Of(texture Texture) int {
+	if p == nil {
+		return texture.GetPriority()
+	}
+	return p[texture]
+}
+
+// AllTextures, lower priority first
+func (p TexturePriorities) Sorted() []Texture {
+	textures := append([]Texture(nil), AllTextures...)
+	sort.SliceStable(textures, func(i, j int) bool {
+		return p.Of(textures[i]) < p.Of(textures[j])
+	})
+	return textures
+}
+
+// a copy, nil stays nil
+func (p TexturePriorities) Clone() TexturePriorities {
+	if p == nil {
+		return nil
+	}
+	priorities := make(TexturePriorities, len(p))
+	for texture, priority := range p {
+		priorities[texture] = priority
+	}
+	return priorities
+}
@@ -0,0 +1,52 @@
+package value_object_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/value_object"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTexturePriorities_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities value_object.TexturePriorities
+		expectErr  bool
+	}{
+		{"Defaults", value_object.DefaultTexturePriorities(), false},
+		{"Gaps are allowed", value_object.TexturePriorities{"CLEAR": 10, "MATTE": 20, "PRIVACY": 30}, false},
+		{"Duplicate priority", value_object.TexturePriorities{"CLEAR": 1, "MATTE": 2, "PRIVACY": 2}, true},
+		{"Missing texture", value_object.TexturePriorities{"CLEAR": 1, "MATTE": 2}, true},
+		{"Unknown texture", value_object.TexturePriorities{"CLEAR": 1, "MATTE": 2, "PRIVACY": 3, "GLOSSY": 4}, true},
+		{"Zero priority", value_object.TexturePriorities{"CLEAR": 0, "MATTE": 2, "PRIVACY": 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.priorities.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTexturePriorities_Sorted(t *testing.T) {
+	var defaults value_object.TexturePriorities
+	assert.Equal(t, []value_object.Texture{"CLEAR", "MATTE", "PRIVACY"}, defaults.Sorted())
+	assert.Equal(t, 2, defaults.Of(value_object.TextureMatte))
+
+	priorities := value_object.TexturePriorities{"CLEAR": 3, "MATTE": 1, "PRIVACY": 2}
+	require.NoError(t, priorities.Validate())
+	assert.Equal(t, []value_object.Texture{"MATTE", "PRIVACY", "CLEAR"}, priorities.Sorted())
+	assert.Equal(t, 1, priorities.Of(value_object.TextureMatte))
+	assert.Equal(t, 1, value_object.TextureClear.GetPriority(), "the defaults are left as they are")
+
+	clone := priorities.Clone()
+	clone[value_object.TextureClear] = 99
+	assert.Equal(t, 3, priorities.Of(value_object.TextureClear), "the clone is a copy")
+}
@@ -2,6 +2,7 @@ package value_object_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"order-placement-system/internal/domain/value_object"
@@ -336,6 +337,7 @@ func TestTexture_IsCompatibleWithFilmType(t *testing.T) {
 		name     string
 		texture  value_object.Texture
 		filmType string
+		matrix   value_object.FilmTextureMatrix
 		expected bool
 	}{
 		{
@@ -356,6 +358,27 @@ func TestTexture_IsCompatibleWithFilmType(t *testing.T) {
 			filmType: "FG1A",
 			expected: true,
 		},
+		{
+			name:     "Texture the matrix lists for the film type",
+			texture:  value_object.TextureMatte,
+			filmType: "FG05",
+			matrix:   value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear, value_object.TextureMatte}},
+			expected: true,
+		},
+		{
+			name:     "Texture the film type is not made in",
+			texture:  value_object.TexturePrivacy,
+			filmType: "fg05",
+			matrix:   value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear, value_object.TextureMatte}},
+			expected: false,
+		},
+		{
+			name:     "Film type the matrix does not list",
+			texture:  value_object.TexturePrivacy,
+			filmType: "FG0A",
+			matrix:   value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear}},
+			expected: true,
+		},
 		{
 			name:     "Invalid texture with any film type",
 			texture:  value_object.Texture("INVALID"),
@@ -372,7 +395,7 @@ func TestTexture_IsCompatibleWithFilmType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := tt.texture.IsCompatibleWithFilmType(tt.filmType)
+			result := tt.texture.IsCompatibleWithFilmType(tt.filmType, tt.matrix)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -616,3 +639,51 @@ func TestTexture_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestTextureAliases(t *testing.T) {
+	aliases := value_object.TextureAliases{
+		"MAT":          value_object.TextureMatte,
+		"PRIVACYGLASS": value_object.TexturePrivacy,
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    value_object.Texture
+		expectError bool
+	}{
+		{"Texture wins over aliases", "CLEAR", value_object.TextureClear, false},
+		{"Alias resolves", "mat", value_object.TextureMatte, false},
+		{"Alias from material id", "FG0A-PRIVACYGLASS", value_object.TexturePrivacy, false},
+		{"Unknown string", "GLOSS", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result value_object.Texture
+			var err error
+			if strings.Contains(tt.input, "-") {
+				result, err = value_object.ParseTextureFromMaterialIdWithAliases(tt.input, aliases)
+			} else {
+				result, err = value_object.NewTextureWithAliases(tt.input, aliases)
+			}
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, errors.ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("Aliases must point at a texture", func(t *testing.T) {
+		assert.NoError(t, aliases.Validate())
+		assert.ErrorIs(t, value_object.TextureAliases{"GL": "GLOSS"}.Validate(), errors.ErrInvalidInput)
+	})
+
+	t.Run("Aliases must differ by more than case", func(t *testing.T) {
+		duplicated := value_object.TextureAliases{"Priv": value_object.TexturePrivacy, "PRIV": value_object.TextureMatte}
+		assert.ErrorIs(t, duplicated.Validate(), errors.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"sync"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/log"
+)
+
+type Subscriber func(event *entity.DomainEvent)
+
+// Bus hands every published event to the subscribers of its name, in the
+// order they subscribed and on the publisher's goroutine. A panicking
+// subscriber is logged and the others still get the event
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[entity.EventName][]Subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[entity.EventName][]Subscriber),
+	}
+}
+
+func (b *Bus) Subscribe(name entity.EventName, subscriber Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[name] = append(b.subscribers[name], subscriber)
+}
+
+func (b *Bus) Publish(events ...*entity.DomainEvent) {
+	for _, event := range events {
+		b.mu.RLock()
+		subscribers := b.subscribers[event.Name]
+		b.mu.RUnlock()
+
+		for _, subscriber := range subscribers {
+			deliver(subscriber, event)
+		}
+	}
+}
+
+func deliver(subscriber Subscriber, event *entity.DomainEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("event subscriber panicked",
+				log.S("event", string(event.Name)),
+				log.AtoS("panic", r))
+		}
+	}()
+
+	subscriber(event)
+}
@@ -0,0 +1,40 @@
+package events_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/events"
+	"order-placement-system/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := events.NewBus()
+
+	var received []string
+	bus.Subscribe(entity.EventOrderBatchProcessed, func(event *entity.DomainEvent) {
+		received = append(received, "first:"+event.TenantId)
+	})
+	bus.Subscribe(entity.EventOrderBatchProcessed, func(event *entity.DomainEvent) {
+		panic("broken subscriber")
+	})
+	bus.Subscribe(entity.EventOrderBatchProcessed, func(event *entity.DomainEvent) {
+		received = append(received, "third:"+event.TenantId)
+	})
+	bus.Subscribe(entity.EventRowDropped, func(event *entity.DomainEvent) {
+		received = append(received, "dropped:"+event.TenantId)
+	})
+
+	bus.Publish(
+		&entity.DomainEvent{Name: entity.EventOrderBatchProcessed, TenantId: "acme"},
+		&entity.DomainEvent{Name: entity.EventComplementaryItemsGenerated, TenantId: "acme"},
+	)
+
+	assert.Equal(t, []string{"first:acme", "third:acme"}, received)
+}
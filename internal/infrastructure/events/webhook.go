@@ -0,0 +1,220 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+const (
+	// deliveries kept in the log, the oldest are forgotten first
+	maxLoggedDeliveries = 1000
+	// bytes of the receiver's response body kept per attempt
+	responseSnippetSize = 256
+)
+
+type DeliveryAttempt struct {
+	At        time.Time `json:"at"`
+	Status    int       `json:"status,omitempty"`
+	LatencyMs int64     `json:"latencyMs"`
+	Response  string    `json:"response,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func (a *DeliveryAttempt) Succeeded() bool {
+	return a.Error == "" && a.Status < http.StatusMultipleChoices
+}
+
+// Delivery is one event sent to the webhook, with every attempt at it
+type Delivery struct {
+	Id        string              `json:"id"`
+	Event     *entity.DomainEvent `json:"event"`
+	Delivered bool                `json:"delivered"`
+	Attempts  []*DeliveryAttempt  `json:"attempts"`
+}
+
+// Webhook posts events as JSON to a URL from a background worker, so a slow
+// receiver never holds up a request. Events that find the queue full or
+// fail to deliver are logged and not retried automatically. The last
+// deliveries and their attempts are kept in memory so failures can be
+// looked up and redelivered by hand
+type Webhook struct {
+	url    string
+	client *http.Client
+	queue  chan *Delivery
+	once   sync.Once
+	done   chan struct{}
+
+	mu         sync.Mutex
+	lastId     int
+	deliveries []*Delivery
+	byId       map[string]*Delivery
+}
+
+func NewWebhook(url string, timeout time.Duration, queueSize int) *Webhook {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+
+	w := &Webhook{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan *Delivery, queueSize),
+		done:   make(chan struct{}),
+		byId:   make(map[string]*Delivery),
+	}
+	go w.run()
+
+	return w
+}
+
+// Deliver is a Subscriber
+func (w *Webhook) Deliver(event *entity.DomainEvent) {
+	delivery := w.logDelivery(event)
+
+	select {
+	case w.queue <- delivery:
+	default:
+		log.Warnf("webhook queue full, event dropped",
+			log.S("event", string(event.Name)),
+			log.S("delivery_id", delivery.Id))
+		w.recordAttempt(delivery, &DeliveryAttempt{At: time.Now(), Error: "queue full"})
+	}
+}
+
+// Redeliver queues a logged delivery again, whether or not it succeeded
+func (w *Webhook) Redeliver(id string) error {
+	w.mu.Lock()
+	delivery, ok := w.byId[id]
+	w.mu.Unlock()
+	if !ok {
+		log.Errorf("unknown webhook delivery", log.S("delivery_id", id))
+		return errors.ErrNotFound
+	}
+
+	select {
+	case w.queue <- delivery:
+		return nil
+	default:
+		log.Warnf("webhook queue full, redelivery refused", log.S("delivery_id", id))
+		return errors.ErrTooManyRequests
+	}
+}
+
+// newest first, failedOnly leaves out the deliveries that went through
+func (w *Webhook) Deliveries(failedOnly bool) []*Delivery {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	deliveries := make([]*Delivery, 0, len(w.deliveries))
+	for i := len(w.deliveries) - 1; i >= 0; i-- {
+		delivery := w.deliveries[i]
+		// not attempted yet is not a failure
+		if failedOnly && (delivery.Delivered || len(delivery.Attempts) == 0) {
+			continue
+		}
+
+		copied := *delivery
+		copied.Attempts = append([]*DeliveryAttempt(nil), delivery.Attempts...)
+		deliveries = append(deliveries, &copied)
+	}
+
+	return deliveries
+}
+
+// DeliveryReport is Deliveries for the endpoint that serves them
+func (w *Webhook) DeliveryReport(failedOnly bool) any {
+	return w.Deliveries(failedOnly)
+}
+
+// events waiting to be posted
+func (w *Webhook) QueueDepth() int {
+	return len(w.queue)
+}
+
+// Close delivers what is queued and stops the worker, Deliver and
+// Redeliver must not be called afterwards
+func (w *Webhook) Close() {
+	w.once.Do(func() { close(w.queue) })
+	<-w.done
+}
+
+func (w *Webhook) run() {
+	defer close(w.done)
+
+	for delivery := range w.queue {
+		w.recordAttempt(delivery, w.post(delivery))
+	}
+}
+
+func (w *Webhook) logDelivery(event *entity.DomainEvent) *Delivery {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastId++
+	delivery := &Delivery{Id: strconv.Itoa(w.lastId), Event: event}
+	w.deliveries = append(w.deliveries, delivery)
+	w.byId[delivery.Id] = delivery
+
+	if len(w.deliveries) > maxLoggedDeliveries {
+		delete(w.byId, w.deliveries[0].Id)
+		w.deliveries = w.deliveries[1:]
+	}
+
+	return delivery
+}
+
+func (w *Webhook) recordAttempt(delivery *Delivery, attempt *DeliveryAttempt) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	if attempt.Succeeded() {
+		delivery.Delivered = true
+	}
+}
+
+func (w *Webhook) post(delivery *Delivery) *DeliveryAttempt {
+	event := delivery.Event
+	attempt := &DeliveryAttempt{At: time.Now()}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("failed to encode event", log.S("event", string(event.Name)), log.E(err))
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	attempt.LatencyMs = time.Since(attempt.At).Milliseconds()
+	if err != nil {
+		log.Errorf("failed to deliver event",
+			log.S("event", string(event.Name)),
+			log.S("delivery_id", delivery.Id),
+			log.E(err))
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseSnippetSize))
+	attempt.Status = resp.StatusCode
+	attempt.Response = string(snippet)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		log.Errorf("event rejected by webhook",
+			log.S("event", string(event.Name)),
+			log.S("delivery_id", delivery.Id),
+			log.S("status", strconv.Itoa(resp.StatusCode)))
+	}
+
+	return attempt
+}
@@ -0,0 +1,140 @@
+package events_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/events"
+	"order-placement-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Deliver(t *testing.T) {
+	var mu sync.Mutex
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		received = append(received, body)
+		mu.Unlock()
+
+		if body["tenantId"] == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	webhook := events.NewWebhook(server.URL, time.Second, 10)
+	webhook.Deliver(&entity.DomainEvent{Name: entity.EventRowDropped, TenantId: "broken"})
+	webhook.Deliver(&entity.DomainEvent{
+		Name:     entity.EventRowDropped,
+		TenantId: "acme",
+		Payload:  &entity.RowDroppedPayload{No: 2, Error: "invalid product code", Category: errors.CategoryCatalogMismatch},
+	})
+	webhook.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2, "a rejected event does not stop the ones after it")
+	assert.Equal(t, "RowDropped", received[1]["name"])
+	assert.Equal(t, "acme", received[1]["tenantId"])
+	assert.Equal(t, map[string]interface{}{"no": float64(2), "error": "invalid product code", "category": "catalog_mismatch"}, received[1]["payload"])
+}
+
+func TestWebhook_Deliver_QueueFull(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+	}))
+	defer server.Close()
+
+	webhook := events.NewWebhook(server.URL, time.Second, 1)
+	event := &entity.DomainEvent{Name: entity.EventOrderBatchProcessed}
+
+	// the first event is picked up by the worker, the second fills the queue
+	webhook.Deliver(event)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls == 1
+	}, time.Second, 10*time.Millisecond)
+	webhook.Deliver(event)
+	webhook.Deliver(event)
+
+	close(release)
+	webhook.Close()
+
+	assert.Equal(t, 2, calls)
+
+	failed := webhook.Deliveries(true)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "3", failed[0].Id)
+	assert.Equal(t, "queue full", failed[0].Attempts[0].Error)
+}
+
+func TestWebhook_Redeliver(t *testing.T) {
+	var mu sync.Mutex
+	fail := true
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event entity.DomainEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.Id)
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("maintenance"))
+		}
+	}))
+	defer server.Close()
+
+	webhook := events.NewWebhook(server.URL, time.Second, 10)
+	webhook.Deliver(&entity.DomainEvent{Id: "evt-1", Name: entity.EventOrderBatchProcessed})
+
+	var failed []*events.Delivery
+	require.Eventually(t, func() bool {
+		failed = webhook.Deliveries(true)
+		return len(failed) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	delivery := failed[0]
+	assert.Equal(t, "evt-1", delivery.Event.Id)
+	assert.False(t, delivery.Delivered)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.Attempts[0].Status)
+	assert.Equal(t, "maintenance", delivery.Attempts[0].Response)
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+
+	require.NoError(t, webhook.Redeliver(delivery.Id))
+	assert.ErrorIs(t, webhook.Redeliver("unknown"), errors.ErrNotFound)
+	webhook.Close()
+
+	assert.Empty(t, webhook.Deliveries(true))
+	all := webhook.Deliveries(false)
+	require.Len(t, all, 1)
+	assert.True(t, all[0].Delivered)
+	assert.Len(t, all[0].Attempts, 2)
+	assert.Equal(t, []string{"evt-1", "evt-1"}, received, "redelivery sends the same event id")
+}
@@ -0,0 +1,104 @@
+package knowledgebase
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// articles kept, a new code past it is refused
+const maxErrorArticles = 500
+
+// ErrorArticles is the error knowledge base support agents look codes up
+// in. Articles are kept in memory, edits are lost on restart
+type ErrorArticles struct {
+	mu       sync.RWMutex
+	now      func() time.Time
+	articles map[string]*entity.ErrorArticle
+}
+
+// defaults are the articles it starts with, now is the clock, time.Now
+// outside tests
+func NewErrorArticles(now func() time.Time, defaults []*entity.ErrorArticle) *ErrorArticles {
+	a := &ErrorArticles{now: now, articles: make(map[string]*entity.ErrorArticle, len(defaults))}
+	for _, article := range defaults {
+		stored := article.Clone()
+		stored.UpdatedAt = now()
+		a.articles[codeKey(article.Code)] = stored
+	}
+	return a
+}
+
+// codes are looked up case insensitively
+func (a *ErrorArticles) Get(code string) (*entity.ErrorArticle, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	article, ok := a.articles[codeKey(code)]
+	if !ok {
+		return nil, false
+	}
+	return article.Clone(), true
+}
+
+// articles matching query by code, see entity.ErrorArticle.Matches
+func (a *ErrorArticles) Search(query string) []*entity.ErrorArticle {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	found := make([]*entity.ErrorArticle, 0)
+	for _, article := range a.articles {
+		if article.Matches(query) {
+			found = append(found, article.Clone())
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return codeKey(found[i].Code) < codeKey(found[j].Code)
+	})
+	return found
+}
+
+// Put adds the article or replaces the one with its code, created is false
+// when it replaced one
+func (a *ErrorArticles) Put(article *entity.ErrorArticle) (stored *entity.ErrorArticle, created bool, err error) {
+	if err := article.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := codeKey(article.Code)
+	_, exists := a.articles[key]
+	if !exists && len(a.articles) >= maxErrorArticles {
+		log.Errorf("error knowledge base is full", log.S("code", article.Code))
+		return nil, false, errors.ErrUnprocessableEntity
+	}
+
+	stored = article.Clone()
+	stored.UpdatedAt = a.now()
+	a.articles[key] = stored
+	return stored.Clone(), !exists, nil
+}
+
+// false when there was no article for the code
+func (a *ErrorArticles) Delete(code string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := codeKey(code)
+	if _, ok := a.articles[key]; !ok {
+		return false
+	}
+	delete(a.articles, key)
+	return true
+}
+
+func codeKey(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
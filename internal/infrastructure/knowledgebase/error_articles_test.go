@@ -0,0 +1,64 @@
+package knowledgebase_test
+
+import (
+	"testing"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/knowledgebase"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func TestErrorArticles(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	now := start
+	articles := knowledgebase.NewErrorArticles(func() time.Time { return now }, entity.DefaultErrorArticles())
+
+	article, ok := articles.Get("FORMAT_ERROR")
+	require.True(t, ok)
+	assert.Equal(t, "format_error", article.Code)
+	assert.Equal(t, start, article.UpdatedAt)
+
+	// callers get copies
+	article.Title = "changed"
+	article, _ = articles.Get("format_error")
+	assert.NotEqual(t, "changed", article.Title)
+
+	codes := func(found []*entity.ErrorArticle) []string {
+		result := make([]string, len(found))
+		for i, a := range found {
+			result[i] = a.Code
+		}
+		return result
+	}
+	assert.Equal(t, []string{"business_rule_violation", "catalog_mismatch", "format_error", "system_error"}, codes(articles.Search("")))
+	assert.Equal(t, []string{"format_error"}, codes(articles.Search("underscore")))
+
+	now = start.Add(time.Hour)
+	stored, created, err := articles.Put(&entity.ErrorArticle{Code: "COMPLEMENTARY_CAPPED", Title: "Free items withheld"})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, now, stored.UpdatedAt)
+
+	_, created, err = articles.Put(&entity.ErrorArticle{Code: "complementary_capped", Title: "Free items capped"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	article, _ = articles.Get("COMPLEMENTARY_CAPPED")
+	assert.Equal(t, "Free items capped", article.Title)
+
+	_, _, err = articles.Put(&entity.ErrorArticle{Code: "no title"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	assert.True(t, articles.Delete("Complementary_Capped"))
+	assert.False(t, articles.Delete("complementary_capped"))
+	_, ok = articles.Get("complementary_capped")
+	assert.False(t, ok)
+}
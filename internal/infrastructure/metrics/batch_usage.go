@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	"order-placement-system/internal/domain/entity"
+)
+
+// heaviest batches kept, the top query cannot ask for more
+const MaxHeaviestBatches = 50
+
+type BatchUsageSnapshot struct {
+	Batches           int                  `json:"batches"`
+	Rows              int                  `json:"rows"`
+	AverageWallTimeMs float64              `json:"averageWallTimeMs"`
+	AverageCPUTimeMs  float64              `json:"averageCpuTimeMs"`
+	RowsPerSecond     float64              `json:"rowsPerSecond"`
+	Heaviest          []*entity.BatchUsage `json:"heaviest"`
+}
+
+// BatchUsageMetrics sums what processed batches cost and keeps the heaviest
+// of them by wall time, for capacity planning
+type BatchUsageMetrics struct {
+	mu       sync.Mutex
+	batches  int
+	rows     int
+	wallMs   float64
+	cpuMs    float64
+	heaviest []*entity.BatchUsage
+}
+
+func NewBatchUsageMetrics() *BatchUsageMetrics {
+	return &BatchUsageMetrics{}
+}
+
+func (m *BatchUsageMetrics) RecordBatchUsage(usage *entity.BatchUsage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.batches++
+	m.rows += usage.Rows
+	m.wallMs += usage.WallTimeMs
+	m.cpuMs += usage.CPUTimeMs
+
+	if len(m.heaviest) == MaxHeaviestBatches && usage.WallTimeMs <= m.heaviest[len(m.heaviest)-1].WallTimeMs {
+		return
+	}
+	copied := *usage
+	i := sort.Search(len(m.heaviest), func(i int) bool { return m.heaviest[i].WallTimeMs < usage.WallTimeMs })
+	m.heaviest = append(m.heaviest, nil)
+	copy(m.heaviest[i+1:], m.heaviest[i:])
+	m.heaviest[i] = &copied
+	if len(m.heaviest) > MaxHeaviestBatches {
+		m.heaviest = m.heaviest[:MaxHeaviestBatches]
+	}
+}
+
+// top heaviest batches first, top is capped at MaxHeaviestBatches
+func (m *BatchUsageMetrics) Snapshot(top int) *BatchUsageSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	top = max(min(top, len(m.heaviest)), 0)
+	snapshot := &BatchUsageSnapshot{
+		Batches:  m.batches,
+		Rows:     m.rows,
+		Heaviest: make([]*entity.BatchUsage, 0, top),
+	}
+	if m.batches > 0 {
+		snapshot.AverageWallTimeMs = m.wallMs / float64(m.batches)
+		snapshot.AverageCPUTimeMs = m.cpuMs / float64(m.batches)
+	}
+	if m.wallMs > 0 {
+		snapshot.RowsPerSecond = float64(m.rows) / (m.wallMs / 1000)
+	}
+	for _, usage := range m.heaviest[:top] {
+		copied := *usage
+		snapshot.Heaviest = append(snapshot.Heaviest, &copied)
+	}
+
+	return snapshot
+}
+
+// HeaviestReport is Snapshot for the endpoints that serve it
+func (m *BatchUsageMetrics) HeaviestReport(top int) any {
+	return m.Snapshot(top)
+}
@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUsageMetrics_Snapshot(t *testing.T) {
+	processedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	m := metrics.NewBatchUsageMetrics()
+
+	m.RecordBatchUsage(entity.NewBatchUsage("acme", 10, 100*time.Millisecond, 80*time.Millisecond, 1024, processedAt))
+	m.RecordBatchUsage(entity.NewBatchUsage("globex", 40, 300*time.Millisecond, 200*time.Millisecond, 4096, processedAt))
+	m.RecordBatchUsage(entity.NewBatchUsage("", 50, 200*time.Millisecond, 100*time.Millisecond, 2048, processedAt))
+
+	snapshot := m.Snapshot(2)
+
+	assert.Equal(t, 3, snapshot.Batches)
+	assert.Equal(t, 100, snapshot.Rows)
+	assert.InDelta(t, 200, snapshot.AverageWallTimeMs, 0.001)
+	assert.InDelta(t, 126.667, snapshot.AverageCPUTimeMs, 0.001)
+	assert.InDelta(t, 166.667, snapshot.RowsPerSecond, 0.001)
+	if assert.Len(t, snapshot.Heaviest, 2) {
+		assert.Equal(t, "globex", snapshot.Heaviest[0].TenantId)
+		assert.Equal(t, 50, snapshot.Heaviest[1].Rows)
+	}
+}
+
+func TestBatchUsageMetrics_KeepsHeaviest(t *testing.T) {
+	m := metrics.NewBatchUsageMetrics()
+	for i := 1; i <= metrics.MaxHeaviestBatches+10; i++ {
+		m.RecordBatchUsage(entity.NewBatchUsage("", i, time.Duration(i)*time.Millisecond, 0, 0, time.Now()))
+	}
+
+	snapshot := m.Snapshot(metrics.MaxHeaviestBatches + 10)
+
+	assert.Equal(t, metrics.MaxHeaviestBatches+10, snapshot.Batches)
+	if assert.Len(t, snapshot.Heaviest, metrics.MaxHeaviestBatches) {
+		assert.Equal(t, metrics.MaxHeaviestBatches+10, snapshot.Heaviest[0].Rows)
+		assert.Equal(t, 11, snapshot.Heaviest[metrics.MaxHeaviestBatches-1].Rows)
+	}
+}
+
+func TestBatchUsageMetrics_Empty(t *testing.T) {
+	snapshot := metrics.NewBatchUsageMetrics().Snapshot(10)
+
+	assert.Equal(t, &metrics.BatchUsageSnapshot{Heaviest: []*entity.BatchUsage{}}, snapshot)
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"sync"
+
+	"order-placement-system/internal/domain/entity"
+)
+
+type BatchWarningSnapshot struct {
+	Batches        int                             `json:"batches"`
+	Rows           int                             `json:"rows"`
+	FlaggedBatches int                             `json:"flaggedBatches"`
+	FlaggedRate    float64                         `json:"flaggedRate"`
+	ByCode         map[entity.BatchWarningCode]int `json:"byCode"`
+}
+
+// BatchWarningMetrics counts inspected batches and the warnings raised on them
+type BatchWarningMetrics struct {
+	mu             sync.Mutex
+	batches        int
+	rows           int
+	flaggedBatches int
+	byCode         map[entity.BatchWarningCode]int
+}
+
+func NewBatchWarningMetrics() *BatchWarningMetrics {
+	return &BatchWarningMetrics{
+		byCode: make(map[entity.BatchWarningCode]int),
+	}
+}
+
+func (m *BatchWarningMetrics) RecordWarnings(rows int, warnings []*entity.BatchWarning) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.batches++
+	m.rows += rows
+	if len(warnings) > 0 {
+		m.flaggedBatches++
+	}
+	for _, warning := range warnings {
+		m.byCode[warning.Code]++
+	}
+}
+
+func (m *BatchWarningMetrics) Snapshot() *BatchWarningSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &BatchWarningSnapshot{
+		Batches:        m.batches,
+		Rows:           m.rows,
+		FlaggedBatches: m.flaggedBatches,
+		ByCode:         make(map[entity.BatchWarningCode]int, len(m.byCode)),
+	}
+
+	if m.batches > 0 {
+		snapshot.FlaggedRate = float64(m.flaggedBatches) / float64(m.batches)
+	}
+
+	for code, count := range m.byCode {
+		snapshot.ByCode[code] = count
+	}
+
+	return snapshot
+}
+
+// Report is Snapshot for the endpoints that serve it
+func (m *BatchWarningMetrics) Report() any {
+	return m.Snapshot()
+}
@@ -0,0 +1,40 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchWarningMetrics_RecordWarnings(t *testing.T) {
+	m := metrics.NewBatchWarningMetrics()
+
+	m.RecordWarnings(10, nil)
+	m.RecordWarnings(20, []*entity.BatchWarning{
+		entity.NewBatchWarning(entity.WarningPrefixedRows, 0.8, 0.5),
+		entity.NewBatchWarning(entity.WarningBundleRatio, 0.9, 0.5),
+	})
+	m.RecordWarnings(5, []*entity.BatchWarning{
+		entity.NewBatchWarning(entity.WarningPrefixedRows, 0.6, 0.5),
+	})
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, 3, snapshot.Batches)
+	assert.Equal(t, 35, snapshot.Rows)
+	assert.Equal(t, 2, snapshot.FlaggedBatches)
+	assert.InDelta(t, 2.0/3.0, snapshot.FlaggedRate, 1e-9)
+	assert.Equal(t, 2, snapshot.ByCode[entity.WarningPrefixedRows])
+	assert.Equal(t, 1, snapshot.ByCode[entity.WarningBundleRatio])
+	assert.Zero(t, snapshot.ByCode[entity.WarningUnitPriceDeviation])
+}
+
+func TestBatchWarningMetrics_EmptySnapshot(t *testing.T) {
+	snapshot := metrics.NewBatchWarningMetrics().Snapshot()
+
+	assert.Zero(t, snapshot.Batches)
+	assert.Zero(t, snapshot.FlaggedRate)
+	assert.NotNil(t, snapshot.ByCode)
+}
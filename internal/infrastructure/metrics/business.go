@@ -0,0 +1,221 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"order-placement-system/internal/domain/entity"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+)
+
+type freeItemKey struct {
+	tenant    string
+	productId string
+}
+
+type revenueKey struct {
+	tenant   string
+	currency string
+}
+
+type rowFailedKey struct {
+	tenant   string
+	category errors.Category
+}
+
+// BusinessMetrics counts what completed batches produced, labelled by
+// tenant. Counters only grow, rates are left to the dashboard
+type BusinessMetrics struct {
+	mu          sync.Mutex
+	rowsCleaned map[string]int
+	freeItems   map[freeItemKey]int
+	revenue     map[revenueKey]float64
+	rowsFailed  map[rowFailedKey]int
+
+	// values free items, nil leaves them unvalued
+	priceList     usecase.PriceList
+	freeItemValue map[revenueKey]float64
+	unpricedItems map[freeItemKey]int
+}
+
+// free items are valued at what priceList charges for them, in the currency
+// of their batch. priceList is optional, without it free items are counted
+// but not valued
+func NewBusinessMetrics(priceList usecase.PriceList) *BusinessMetrics {
+	return &BusinessMetrics{
+		rowsCleaned:   make(map[string]int),
+		freeItems:     make(map[freeItemKey]int),
+		revenue:       make(map[revenueKey]float64),
+		rowsFailed:    make(map[rowFailedKey]int),
+		priceList:     priceList,
+		freeItemValue: make(map[revenueKey]float64),
+		unpricedItems: make(map[freeItemKey]int),
+	}
+}
+
+func (m *BusinessMetrics) RecordBusinessBatch(batch *entity.ProcessBatch) {
+	tenant := batch.Options.TenantId
+	currency := batch.Options.EffectivePricePolicy().Currency
+
+	rows := batch.ActiveRows()
+	revenue := 0.0
+	for _, row := range rows {
+		for _, product := range row.Products {
+			revenue += product.TotalPrice.Amount()
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rowsCleaned[tenant] += len(rows)
+	m.revenue[revenueKey{tenant, currency}] += revenue
+	for _, line := range batch.ComplementaryLines {
+		m.freeItems[freeItemKey{tenant, line.CatalogId()}] += line.Qty
+		m.valueFreeItem(tenant, currency, line)
+	}
+	for _, rowErr := range batch.RowErrors {
+		m.rowsFailed[rowFailedKey{tenant, rowErr.Category()}]++
+	}
+}
+
+// call with mu held
+func (m *BusinessMetrics) valueFreeItem(tenant, currency string, line *entity.CleanedOrder) {
+	if m.priceList == nil {
+		return
+	}
+	unitPrice, ok := m.priceList.UnitPrice(tenant, line.CatalogId())
+	if !ok {
+		m.unpricedItems[freeItemKey{tenant, line.CatalogId()}] += line.Qty
+		return
+	}
+	m.freeItemValue[revenueKey{tenant, currency}] += unitPrice.Amount() * float64(line.Qty)
+}
+
+// WriteOpenMetrics writes every counter in the OpenMetrics text format,
+// series sorted by their labels
+func (m *BusinessMetrics) WriteOpenMetrics(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# TYPE orders_cleaned counter\n")
+	b.WriteString("# HELP orders_cleaned Input rows cleaned successfully.\n")
+	tenants := make([]string, 0, len(m.rowsCleaned))
+	for tenant := range m.rowsCleaned {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	for _, tenant := range tenants {
+		fmt.Fprintf(&b, "orders_cleaned_total{tenant=%s} %d\n", labelValue(tenant), m.rowsCleaned[tenant])
+	}
+
+	b.WriteString("# TYPE free_items_issued counter\n")
+	b.WriteString("# HELP free_items_issued Complementary units issued, by product.\n")
+	freeItems := make([]freeItemKey, 0, len(m.freeItems))
+	for key := range m.freeItems {
+		freeItems = append(freeItems, key)
+	}
+	sort.Slice(freeItems, func(i, j int) bool {
+		if freeItems[i].tenant != freeItems[j].tenant {
+			return freeItems[i].tenant < freeItems[j].tenant
+		}
+		return freeItems[i].productId < freeItems[j].productId
+	})
+	for _, key := range freeItems {
+		fmt.Fprintf(&b, "free_items_issued_total{tenant=%s,product_id=%s} %d\n",
+			labelValue(key.tenant), labelValue(key.productId), m.freeItems[key])
+	}
+
+	if m.priceList != nil {
+		m.writeFreeItemValue(&b)
+	}
+
+	b.WriteString("# TYPE revenue_processed counter\n")
+	b.WriteString("# HELP revenue_processed Total price of the cleaned main lines.\n")
+	revenue := make([]revenueKey, 0, len(m.revenue))
+	for key := range m.revenue {
+		revenue = append(revenue, key)
+	}
+	sort.Slice(revenue, func(i, j int) bool {
+		if revenue[i].tenant != revenue[j].tenant {
+			return revenue[i].tenant < revenue[j].tenant
+		}
+		return revenue[i].currency < revenue[j].currency
+	})
+	for _, key := range revenue {
+		fmt.Fprintf(&b, "revenue_processed_total{tenant=%s,currency=%s} %s\n",
+			labelValue(key.tenant), labelValue(key.currency), strconv.FormatFloat(m.revenue[key], 'f', -1, 64))
+	}
+
+	b.WriteString("# TYPE orders_failed counter\n")
+	b.WriteString("# HELP orders_failed Input rows dropped from lenient batches, by error category.\n")
+	rowsFailed := make([]rowFailedKey, 0, len(m.rowsFailed))
+	for key := range m.rowsFailed {
+		rowsFailed = append(rowsFailed, key)
+	}
+	sort.Slice(rowsFailed, func(i, j int) bool {
+		if rowsFailed[i].tenant != rowsFailed[j].tenant {
+			return rowsFailed[i].tenant < rowsFailed[j].tenant
+		}
+		return rowsFailed[i].category < rowsFailed[j].category
+	})
+	for _, key := range rowsFailed {
+		fmt.Fprintf(&b, "orders_failed_total{tenant=%s,category=%s} %d\n",
+			labelValue(key.tenant), labelValue(string(key.category)), m.rowsFailed[key])
+	}
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m *BusinessMetrics) writeFreeItemValue(b *strings.Builder) {
+	b.WriteString("# TYPE free_item_value_issued counter\n")
+	b.WriteString("# HELP free_item_value_issued Price list value of the complementary units issued.\n")
+	values := make([]revenueKey, 0, len(m.freeItemValue))
+	for key := range m.freeItemValue {
+		values = append(values, key)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].tenant != values[j].tenant {
+			return values[i].tenant < values[j].tenant
+		}
+		return values[i].currency < values[j].currency
+	})
+	for _, key := range values {
+		fmt.Fprintf(b, "free_item_value_issued_total{tenant=%s,currency=%s} %s\n",
+			labelValue(key.tenant), labelValue(key.currency), strconv.FormatFloat(m.freeItemValue[key], 'f', -1, 64))
+	}
+
+	b.WriteString("# TYPE free_items_unpriced counter\n")
+	b.WriteString("# HELP free_items_unpriced Complementary units issued without a price list price, by product.\n")
+	unpriced := make([]freeItemKey, 0, len(m.unpricedItems))
+	for key := range m.unpricedItems {
+		unpriced = append(unpriced, key)
+	}
+	sort.Slice(unpriced, func(i, j int) bool {
+		if unpriced[i].tenant != unpriced[j].tenant {
+			return unpriced[i].tenant < unpriced[j].tenant
+		}
+		return unpriced[i].productId < unpriced[j].productId
+	})
+	for _, key := range unpriced {
+		fmt.Fprintf(b, "free_items_unpriced_total{tenant=%s,product_id=%s} %d\n",
+			labelValue(key.tenant), labelValue(key.productId), m.unpricedItems[key])
+	}
+}
+
+func labelValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,153 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/internal/infrastructure/metrics"
+	"order-placement-system/internal/infrastructure/pricelist"
+	"order-placement-system/internal/usecases/implementation"
+	"order-placement-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessMetrics_WriteOpenMetrics(t *testing.T) {
+	m := metrics.NewBusinessMetrics(nil)
+
+	batch := func(tenantId string, totals ...float64) *entity.ProcessBatch {
+		b := entity.NewProcessBatch(nil, &entity.ProcessOptions{TenantId: tenantId})
+		for _, total := range totals {
+			b.Rows = append(b.Rows, &entity.ProcessRow{
+				Products: []*entity.Product{{TotalPrice: value_object.MustNewPrice(total)}},
+			})
+		}
+		b.ComplementaryLines = []*entity.CleanedOrder{
+			{ProductId: "WIPING-CLOTH", Qty: len(totals)},
+			{ProductId: "CLEAR-CLEANNER", Qty: 1},
+		}
+		return b
+	}
+
+	m.RecordBusinessBatch(batch("acme", 50, 30.5))
+	m.RecordBusinessBatch(batch("acme", 20))
+	dropped := batch("beta", 10, 99)
+	dropped.Rows[1].Err = errors.NewRowError(2, errors.ErrBundleTooLarge)
+	dropped.RowErrors = []*errors.RowError{dropped.Rows[1].Err, errors.NewRowError(3, errors.ErrProcessingTimeout)}
+	m.RecordBusinessBatch(dropped)
+
+	var out strings.Builder
+	require.NoError(t, m.WriteOpenMetrics(&out))
+
+	assert.Equal(t, `# TYPE orders_cleaned counter
+# HELP orders_cleaned Input rows cleaned successfully.
+orders_cleaned_total{tenant="acme"} 3
+orders_cleaned_total{tenant="beta"} 1
+# TYPE free_items_issued counter
+# HELP free_items_issued Complementary units issued, by product.
+free_items_issued_total{tenant="acme",product_id="CLEAR-CLEANNER"} 2
+free_items_issued_total{tenant="acme",product_id="WIPING-CLOTH"} 3
+free_items_issued_total{tenant="beta",product_id="CLEAR-CLEANNER"} 1
+free_items_issued_total{tenant="beta",product_id="WIPING-CLOTH"} 2
+# TYPE revenue_processed counter
+# HELP revenue_processed Total price of the cleaned main lines.
+revenue_processed_total{tenant="acme",currency="THB"} 100.5
+revenue_processed_total{tenant="beta",currency="THB"} 10
+# TYPE orders_failed counter
+# HELP orders_failed Input rows dropped from lenient batches, by error category.
+orders_failed_total{tenant="beta",category="business_rule_violation"} 1
+orders_failed_total{tenant="beta",category="system_error"} 1
+# EOF
+`, out.String())
+}
+
+func TestBusinessMetrics_FreeItemValue(t *testing.T) {
+	prices, err := pricelist.NewStaticPriceList(map[string]map[string]float64{
+		"*":    {"WIPING-CLOTH": 5, "CLEAR-CLEANNER": 12.5},
+		"acme": {"WIPING-CLOTH": 4},
+	})
+	require.NoError(t, err)
+	m := metrics.NewBusinessMetrics(prices)
+
+	batch := func(tenantId, currency string, lines ...*entity.CleanedOrder) *entity.ProcessBatch {
+		b := entity.NewProcessBatch(nil, &entity.ProcessOptions{
+			TenantId:    tenantId,
+			PricePolicy: &value_object.PricePolicy{Currency: currency, MinorUnits: 2, Epsilon: 0.005},
+		})
+		b.ComplementaryLines = lines
+		return b
+	}
+
+	m.RecordBusinessBatch(batch("acme", "THB",
+		&entity.CleanedOrder{ProductId: "WIPING-CLOTH", Qty: 3},
+		&entity.CleanedOrder{ProductId: "CLEAR-CLEANNER", Qty: 2},
+		&entity.CleanedOrder{ProductId: "CARE-KIT-CLEAR", Qty: 1},
+	))
+	m.RecordBusinessBatch(batch("beta", "THB", &entity.CleanedOrder{ProductId: "WIPING-CLOTH", Qty: 1}))
+	m.RecordBusinessBatch(batch("beta", "USD", &entity.CleanedOrder{ProductId: "WIPING-CLOTH", Qty: 2}))
+
+	var out strings.Builder
+	require.NoError(t, m.WriteOpenMetrics(&out))
+
+	assert.Contains(t, out.String(), `# TYPE free_item_value_issued counter
+# HELP free_item_value_issued Price list value of the complementary units issued.
+free_item_value_issued_total{tenant="acme",currency="THB"} 37
+free_item_value_issued_total{tenant="beta",currency="THB"} 5
+free_item_value_issued_total{tenant="beta",currency="USD"} 10
+# TYPE free_items_unpriced counter
+# HELP free_items_unpriced Complementary units issued without a price list price, by product.
+free_items_unpriced_total{tenant="acme",product_id="CARE-KIT-CLEAR"} 1
+`)
+}
+
+func TestBusinessMetrics_FreeItemValueWithSkuAffix(t *testing.T) {
+	prices, err := pricelist.NewStaticPriceList(map[string]map[string]float64{
+		"*": {"WIPING-CLOTH": 5},
+	})
+	require.NoError(t, err)
+	m := metrics.NewBusinessMetrics(prices)
+
+	b := entity.NewProcessBatch(nil, &entity.ProcessOptions{
+		TenantId:   "acme",
+		SkuAffixes: entity.SkuAffixes{"acme": {Prefix: "TH-"}},
+	})
+	b.ComplementaryLines = []*entity.CleanedOrder{{No: 2, ProductId: "WIPING-CLOTH", Qty: 3}}
+	b.CleanedOrders = b.ComplementaryLines
+	require.NoError(t, implementation.NewAffixStage().Run(b))
+	require.Equal(t, "TH-WIPING-CLOTH", b.ComplementaryLines[0].ProductId)
+
+	m.RecordBusinessBatch(b)
+
+	var out strings.Builder
+	require.NoError(t, m.WriteOpenMetrics(&out))
+
+	assert.Contains(t, out.String(), `free_items_issued_total{tenant="acme",product_id="WIPING-CLOTH"} 3`)
+	assert.Contains(t, out.String(), `free_item_value_issued_total{tenant="acme",currency="THB"} 15`)
+	assert.NotContains(t, out.String(), `free_items_unpriced_total`)
+}
+
+func TestBusinessMetrics_NoPriceList(t *testing.T) {
+	m := metrics.NewBusinessMetrics(nil)
+	b := entity.NewProcessBatch(nil, &entity.ProcessOptions{TenantId: "acme"})
+	b.ComplementaryLines = []*entity.CleanedOrder{{ProductId: "WIPING-CLOTH", Qty: 1}}
+	m.RecordBusinessBatch(b)
+
+	var out strings.Builder
+	require.NoError(t, m.WriteOpenMetrics(&out))
+
+	assert.NotContains(t, out.String(), "free_item_value_issued")
+	assert.NotContains(t, out.String(), "free_items_unpriced")
+}
+
+func TestBusinessMetrics_EscapesLabels(t *testing.T) {
+	m := metrics.NewBusinessMetrics(nil)
+	m.RecordBusinessBatch(entity.NewProcessBatch(nil, &entity.ProcessOptions{TenantId: `a"b\c`}))
+
+	var out strings.Builder
+	require.NoError(t, m.WriteOpenMetrics(&out))
+
+	assert.Contains(t, out.String(), `orders_cleaned_total{tenant="a\"b\\c"} 0`)
+}
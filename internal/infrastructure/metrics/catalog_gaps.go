@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	"order-placement-system/internal/domain/entity"
+)
+
+const (
+	// distinct gaps kept, later new gaps are not counted
+	maxCatalogGaps = 1000
+	// rows kept per gap to show where it came from
+	maxCatalogGapExamples = 3
+)
+
+type CatalogGapExample struct {
+	No                int    `json:"no"`
+	PlatformProductId string `json:"platformProductId"`
+}
+
+type CatalogGapCount struct {
+	Kind     entity.CatalogGapKind `json:"kind"`
+	Value    string                `json:"value"`
+	Count    int                   `json:"count"`
+	Examples []*CatalogGapExample  `json:"examples"`
+}
+
+type catalogGapKey struct {
+	kind  entity.CatalogGapKind
+	value string
+}
+
+// CatalogGapMetrics counts the unknown film types and textures product codes
+// failed on, with the first rows each one was seen in. Resolved gaps are
+// dropped and counted afresh if they come back
+type CatalogGapMetrics struct {
+	mu   sync.Mutex
+	gaps map[catalogGapKey]*CatalogGapCount
+}
+
+func NewCatalogGapMetrics() *CatalogGapMetrics {
+	return &CatalogGapMetrics{gaps: make(map[catalogGapKey]*CatalogGapCount)}
+}
+
+func (m *CatalogGapMetrics) RecordCatalogGaps(gaps []*entity.CatalogGap) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, gap := range gaps {
+		key := catalogGapKey{kind: gap.Kind, value: gap.Value}
+		count, seen := m.gaps[key]
+		if !seen {
+			if len(m.gaps) >= maxCatalogGaps {
+				continue
+			}
+			count = &CatalogGapCount{Kind: gap.Kind, Value: gap.Value}
+			m.gaps[key] = count
+		}
+
+		count.Count++
+		if len(count.Examples) < maxCatalogGapExamples {
+			count.Examples = append(count.Examples, &CatalogGapExample{No: gap.No, PlatformProductId: gap.PlatformProductId})
+		}
+	}
+}
+
+// false when the gap was not recorded
+func (m *CatalogGapMetrics) Resolve(kind entity.CatalogGapKind, value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := catalogGapKey{kind: kind, value: value}
+	if _, ok := m.gaps[key]; !ok {
+		return false
+	}
+	delete(m.gaps, key)
+	return true
+}
+
+// gaps most seen first
+func (m *CatalogGapMetrics) Snapshot() []*CatalogGapCount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]*CatalogGapCount, 0, len(m.gaps))
+	for _, count := range m.gaps {
+		copied := *count
+		copied.Examples = append([]*CatalogGapExample(nil), count.Examples...)
+		snapshot = append(snapshot, &copied)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Count != snapshot[j].Count {
+			return snapshot[i].Count > snapshot[j].Count
+		}
+		if snapshot[i].Kind != snapshot[j].Kind {
+			return snapshot[i].Kind < snapshot[j].Kind
+		}
+		return snapshot[i].Value < snapshot[j].Value
+	})
+
+	return snapshot
+}
+
+// Report is Snapshot for the endpoints that serve it
+func (m *CatalogGapMetrics) Report() any {
+	return m.Snapshot()
+}
@@ -0,0 +1,55 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogGapMetrics_Snapshot(t *testing.T) {
+	m := metrics.NewCatalogGapMetrics()
+	glossy := func(no int) *entity.CatalogGap {
+		return &entity.CatalogGap{Kind: entity.CatalogGapTexture, Value: "GLOSSY", No: no, PlatformProductId: "FG0A-GLOSSY-OPPOA3"}
+	}
+
+	m.RecordCatalogGaps([]*entity.CatalogGap{glossy(1), glossy(2)})
+	m.RecordCatalogGaps([]*entity.CatalogGap{
+		glossy(1),
+		glossy(5),
+		{Kind: entity.CatalogGapFilmType, Value: "XX0A", No: 3, PlatformProductId: "XX0A-CLEAR-OPPOA3"},
+	})
+
+	assert.Equal(t, []*metrics.CatalogGapCount{
+		{
+			Kind:  entity.CatalogGapTexture,
+			Value: "GLOSSY",
+			Count: 4,
+			Examples: []*metrics.CatalogGapExample{
+				{No: 1, PlatformProductId: "FG0A-GLOSSY-OPPOA3"},
+				{No: 2, PlatformProductId: "FG0A-GLOSSY-OPPOA3"},
+				{No: 1, PlatformProductId: "FG0A-GLOSSY-OPPOA3"},
+			},
+		},
+		{
+			Kind:     entity.CatalogGapFilmType,
+			Value:    "XX0A",
+			Count:    1,
+			Examples: []*metrics.CatalogGapExample{{No: 3, PlatformProductId: "XX0A-CLEAR-OPPOA3"}},
+		},
+	}, m.Snapshot())
+}
+
+func TestCatalogGapMetrics_Resolve(t *testing.T) {
+	m := metrics.NewCatalogGapMetrics()
+	m.RecordCatalogGaps([]*entity.CatalogGap{{Kind: entity.CatalogGapTexture, Value: "GLOSSY"}})
+
+	assert.False(t, m.Resolve(entity.CatalogGapFilmType, "GLOSSY"))
+	assert.True(t, m.Resolve(entity.CatalogGapTexture, "GLOSSY"))
+	assert.False(t, m.Resolve(entity.CatalogGapTexture, "GLOSSY"))
+
+	assert.NotNil(t, m.Snapshot())
+	assert.Empty(t, m.Snapshot())
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// distinct API keys kept, later new keys are not counted
+const maxClients = 1000
+
+// ClientRequest is one order request as the client sent it. Client is the
+// X-Client-Id header, or the user agent when it is missing
+type ClientRequest struct {
+	Tenant     string
+	Client     string
+	SDKVersion string
+	UserAgent  string
+	SourceIP   string
+	// fingerprint of the API key the request was authenticated with, never
+	// the key itself. Empty when it was not authenticated
+	ApiKeyId string
+	Status   int
+	// codes of the batch warnings the response carried
+	Warnings []string
+}
+
+// ClientCount is one API key of a tenant. Client, SDKVersion, UserAgent and
+// SourceIP are sent by the client and only the last seen
+type ClientCount struct {
+	Tenant     string         `json:"tenant,omitempty"`
+	Client     string         `json:"client"`
+	SDKVersion string         `json:"sdkVersion,omitempty"`
+	UserAgent  string         `json:"userAgent,omitempty"`
+	SourceIP   string         `json:"sourceIp,omitempty"`
+	ApiKeyId   string         `json:"apiKeyId,omitempty"`
+	Batches    int            `json:"batches"`
+	Rejected   int            `json:"rejected"`
+	Failed     int            `json:"failed"`
+	Warnings   map[string]int `json:"warnings,omitempty"`
+	LastSeenAt time.Time      `json:"lastSeenAt"`
+}
+
+type clientKey struct {
+	tenant   string
+	apiKeyId string
+}
+
+// ClientMetrics counts the order requests of each API key, rejected ones (4xx)
+// and failed ones (5xx) apart, with the warnings their batches got. Requests
+// are told apart by the key they were authenticated with, headers a client
+// sets itself cannot split or merge the counts. An outdated integration shows
+// as a key with many rejections or warnings
+type ClientMetrics struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	clients map[clientKey]*ClientCount
+}
+
+// now is the clock, time.Now outside tests
+func NewClientMetrics(now func() time.Time) *ClientMetrics {
+	return &ClientMetrics{now: now, clients: make(map[clientKey]*ClientCount)}
+}
+
+func (m *ClientMetrics) Record(request *ClientRequest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := clientKey{tenant: request.Tenant, apiKeyId: request.ApiKeyId}
+	count, seen := m.clients[key]
+	if !seen {
+		if len(m.clients) >= maxClients {
+			return
+		}
+		count = &ClientCount{Tenant: request.Tenant, ApiKeyId: request.ApiKeyId}
+		m.clients[key] = count
+	}
+
+	count.Client = request.Client
+	count.SDKVersion = request.SDKVersion
+	count.UserAgent = request.UserAgent
+	count.SourceIP = request.SourceIP
+	count.LastSeenAt = m.now()
+	count.Batches++
+	switch {
+	case request.Status >= 500:
+		count.Failed++
+	case request.Status >= 400:
+		count.Rejected++
+	}
+
+	for _, code := range request.Warnings {
+		if count.Warnings == nil {
+			count.Warnings = make(map[string]int)
+		}
+		count.Warnings[code]++
+	}
+}
+
+// clients of tenantId with most batches first, every tenant's when tenantId
+// is empty
+func (m *ClientMetrics) Snapshot(tenantId string) []*ClientCount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]*ClientCount, 0, len(m.clients))
+	for key, count := range m.clients {
+		if tenantId != "" && key.tenant != tenantId {
+			continue
+		}
+		copied := *count
+		if count.Warnings != nil {
+			copied.Warnings = make(map[string]int, len(count.Warnings))
+			for code, n := range count.Warnings {
+				copied.Warnings[code] = n
+			}
+		}
+		snapshot = append(snapshot, &copied)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Batches != snapshot[j].Batches {
+			return snapshot[i].Batches > snapshot[j].Batches
+		}
+		if snapshot[i].Tenant != snapshot[j].Tenant {
+			return snapshot[i].Tenant < snapshot[j].Tenant
+		}
+		return snapshot[i].ApiKeyId < snapshot[j].ApiKeyId
+	})
+
+	return snapshot
+}
+
+// TenantReport is Snapshot for the endpoints that serve it
+func (m *ClientMetrics) TenantReport(tenantId string) any {
+	return m.Snapshot(tenantId)
+}
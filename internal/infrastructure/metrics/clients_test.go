@@ -0,0 +1,65 @@
+package metrics_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientMetrics_Snapshot(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	m := metrics.NewClientMetrics(func() time.Time { return now })
+
+	m.Record(&metrics.ClientRequest{ApiKeyId: "k1", Client: "acme", SDKVersion: "1.2.0", UserAgent: "acme/1.2", SourceIP: "10.0.0.1", Status: http.StatusOK})
+	m.Record(&metrics.ClientRequest{ApiKeyId: "k2", Client: "acme", SDKVersion: "1.1.0", UserAgent: "acme/1.1", SourceIP: "10.0.0.2", Status: http.StatusBadRequest})
+	m.Record(&metrics.ClientRequest{ApiKeyId: "k2", Client: "acme", SDKVersion: "1.1.0", UserAgent: "acme/1.1", SourceIP: "10.0.0.3", Status: http.StatusOK, Warnings: []string{"PREFIXED_ROWS"}})
+	m.Record(&metrics.ClientRequest{ApiKeyId: "k2", Client: "spoofed", SDKVersion: "9.9.9", UserAgent: "acme/1.1", SourceIP: "10.0.0.3", Status: http.StatusInternalServerError})
+
+	assert.Equal(t, []*metrics.ClientCount{
+		{
+			Client:     "spoofed",
+			SDKVersion: "9.9.9",
+			UserAgent:  "acme/1.1",
+			SourceIP:   "10.0.0.3",
+			ApiKeyId:   "k2",
+			Batches:    3,
+			Rejected:   1,
+			Failed:     1,
+			Warnings:   map[string]int{"PREFIXED_ROWS": 1},
+			LastSeenAt: now,
+		},
+		{Client: "acme", SDKVersion: "1.2.0", UserAgent: "acme/1.2", SourceIP: "10.0.0.1", ApiKeyId: "k1", Batches: 1, LastSeenAt: now},
+	}, m.Snapshot(""), "client headers do not split a key's counts")
+}
+
+func TestClientMetrics_SnapshotIsACopy(t *testing.T) {
+	m := metrics.NewClientMetrics(time.Now)
+	m.Record(&metrics.ClientRequest{Client: "acme", Status: http.StatusOK, Warnings: []string{"BUNDLE_RATIO"}})
+
+	snapshot := m.Snapshot("")
+	snapshot[0].Warnings["BUNDLE_RATIO"] = 10
+	snapshot[0].Batches = 10
+
+	assert.Equal(t, 1, m.Snapshot("")[0].Warnings["BUNDLE_RATIO"])
+	assert.Equal(t, 1, m.Snapshot("")[0].Batches)
+}
+
+func TestClientMetrics_SnapshotByTenant(t *testing.T) {
+	m := metrics.NewClientMetrics(time.Now)
+	m.Record(&metrics.ClientRequest{Tenant: "acme", Client: "shopify", ApiKeyId: "k1", Status: http.StatusOK})
+	m.Record(&metrics.ClientRequest{Tenant: "acme", Client: "lazada", ApiKeyId: "k1", Status: http.StatusOK})
+	m.Record(&metrics.ClientRequest{Tenant: "beta", Client: "shopify", ApiKeyId: "k1", Status: http.StatusOK})
+
+	acme := m.Snapshot("acme")
+	assert.Len(t, acme, 1)
+	assert.Equal(t, "acme", acme[0].Tenant)
+	assert.Equal(t, 2, acme[0].Batches, "tenants do not share counts")
+	assert.Equal(t, "lazada", acme[0].Client, "the last client seen")
+
+	assert.Len(t, m.Snapshot(""), 2)
+	assert.Empty(t, m.Snapshot("gamma"))
+}
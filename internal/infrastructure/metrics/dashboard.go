@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/errors"
+)
+
+const (
+	// rows per second and responses are counted over the last minute
+	dashboardWindowSeconds = 60
+	maxRecentDroppedRows   = 50
+)
+
+type DroppedRow struct {
+	TenantId   string          `json:"tenantId,omitempty"`
+	No         int             `json:"no"`
+	Error      string          `json:"error"`
+	Category   errors.Category `json:"category"`
+	OccurredAt time.Time       `json:"occurredAt"`
+}
+
+// ResponsesByStatus counts the order endpoint responses in the window and
+// ErrorRate is the share of them with a 4xx or 5xx status. Dropped rows are
+// newest first
+type DashboardSnapshot struct {
+	InFlightBatches   int            `json:"inFlightBatches"`
+	RowsPerSecond     float64        `json:"rowsPerSecond"`
+	ResponsesByStatus map[string]int `json:"responsesByStatus"`
+	ErrorRate         float64        `json:"errorRate"`
+	QueueDepths       map[string]int `json:"queueDepths"`
+	ConfigLoadedAt    time.Time      `json:"configLoadedAt"`
+	RecentDroppedRows []*DroppedRow  `json:"recentDroppedRows"`
+}
+
+type dashboardSecond struct {
+	unix      int64
+	rows      int
+	responses map[int]int
+}
+
+// DashboardMetrics keeps what an operator looks at during an incident. It is
+// fed by the order routes middleware and by the domain events
+type DashboardMetrics struct {
+	mu             sync.Mutex
+	now            func() time.Time
+	configLoadedAt time.Time
+	inFlight       int
+	seconds        [dashboardWindowSeconds]dashboardSecond
+	droppedRows    []*DroppedRow
+	queues         map[string]func() int
+}
+
+// now is the clock, time.Now outside tests
+func NewDashboardMetrics(configLoadedAt time.Time, now func() time.Time) *DashboardMetrics {
+	return &DashboardMetrics{
+		now:            now,
+		configLoadedAt: configLoadedAt,
+		queues:         make(map[string]func() int),
+	}
+}
+
+// depth is called on every snapshot
+func (m *DashboardMetrics) WatchQueue(name string, depth func() int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queues[name] = depth
+}
+
+func (m *DashboardMetrics) BatchStarted() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight++
+}
+
+func (m *DashboardMetrics) BatchFinished(status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	m.second().responses[status]++
+}
+
+// RecordEvent is an events.Subscriber for OrderBatchProcessed and RowDropped
+func (m *DashboardMetrics) RecordEvent(event *entity.DomainEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch payload := event.Payload.(type) {
+	case *entity.OrderBatchProcessedPayload:
+		m.second().rows += payload.Rows - payload.DroppedRows
+	case *entity.RowDroppedPayload:
+		m.droppedRows = append(m.droppedRows, &DroppedRow{
+			TenantId:   event.TenantId,
+			No:         payload.No,
+			Error:      payload.Error,
+			Category:   payload.Category,
+			OccurredAt: event.OccurredAt,
+		})
+		if len(m.droppedRows) > maxRecentDroppedRows {
+			m.droppedRows = m.droppedRows[len(m.droppedRows)-maxRecentDroppedRows:]
+		}
+	}
+}
+
+func (m *DashboardMetrics) Snapshot() *DashboardSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &DashboardSnapshot{
+		InFlightBatches:   m.inFlight,
+		ResponsesByStatus: make(map[string]int),
+		QueueDepths:       make(map[string]int, len(m.queues)),
+		ConfigLoadedAt:    m.configLoadedAt,
+		RecentDroppedRows: make([]*DroppedRow, 0, len(m.droppedRows)),
+	}
+
+	rows, responses, failed := 0, 0, 0
+	oldest := m.now().Unix() - dashboardWindowSeconds
+	for _, second := range m.seconds {
+		if second.unix <= oldest {
+			continue
+		}
+		rows += second.rows
+		for status, count := range second.responses {
+			snapshot.ResponsesByStatus[strconv.Itoa(status)] += count
+			responses += count
+			if status >= 400 {
+				failed += count
+			}
+		}
+	}
+
+	snapshot.RowsPerSecond = float64(rows) / dashboardWindowSeconds
+	if responses > 0 {
+		snapshot.ErrorRate = float64(failed) / float64(responses)
+	}
+
+	for name, depth := range m.queues {
+		snapshot.QueueDepths[name] = depth()
+	}
+
+	for i := len(m.droppedRows) - 1; i >= 0; i-- {
+		row := *m.droppedRows[i]
+		snapshot.RecentDroppedRows = append(snapshot.RecentDroppedRows, &row)
+	}
+
+	return snapshot
+}
+
+// the bucket of the current second, reset when it last held an older second
+func (m *DashboardMetrics) second() *dashboardSecond {
+	unix := m.now().Unix()
+	second := &m.seconds[unix%dashboardWindowSeconds]
+	if second.unix != unix {
+		*second = dashboardSecond{unix: unix, responses: make(map[int]int)}
+	}
+	return second
+}
+
+// Report is Snapshot for the endpoints that serve it
+func (m *DashboardMetrics) Report() any {
+	return m.Snapshot()
+}
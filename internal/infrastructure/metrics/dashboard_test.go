@@ -0,0 +1,65 @@
+package metrics_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardMetrics_Snapshot(t *testing.T) {
+	loadedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := loadedAt.Add(time.Hour)
+	m := metrics.NewDashboardMetrics(loadedAt, func() time.Time { return now })
+
+	depth := 3
+	m.WatchQueue("eventWebhook", func() int { return depth })
+
+	m.BatchStarted()
+	m.BatchStarted()
+	m.BatchFinished(http.StatusOK)
+	m.BatchStarted()
+	m.BatchFinished(http.StatusUnprocessableEntity)
+
+	m.RecordEvent(&entity.DomainEvent{
+		Name:    entity.EventOrderBatchProcessed,
+		Payload: &entity.OrderBatchProcessedPayload{Rows: 130, DroppedRows: 10, Lines: 200},
+	})
+	for no := 1; no <= 60; no++ {
+		m.RecordEvent(&entity.DomainEvent{
+			Name:       entity.EventRowDropped,
+			TenantId:   "acme",
+			OccurredAt: now,
+			Payload:    &entity.RowDroppedPayload{No: no, Error: "invalid input"},
+		})
+	}
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, 1, snapshot.InFlightBatches)
+	assert.InDelta(t, 2.0, snapshot.RowsPerSecond, 0.001, "120 cleaned rows over a minute")
+	assert.Equal(t, map[string]int{"200": 1, "422": 1}, snapshot.ResponsesByStatus)
+	assert.InDelta(t, 0.5, snapshot.ErrorRate, 0.001)
+	assert.Equal(t, map[string]int{"eventWebhook": 3}, snapshot.QueueDepths)
+	assert.Equal(t, loadedAt, snapshot.ConfigLoadedAt)
+
+	require.Len(t, snapshot.RecentDroppedRows, 50)
+	assert.Equal(t, 60, snapshot.RecentDroppedRows[0].No, "newest first")
+	assert.Equal(t, 11, snapshot.RecentDroppedRows[49].No)
+	assert.Equal(t, "acme", snapshot.RecentDroppedRows[0].TenantId)
+
+	// the window moves on, dropped rows stay
+	now = now.Add(time.Minute)
+	snapshot = m.Snapshot()
+	assert.Zero(t, snapshot.RowsPerSecond)
+	assert.Empty(t, snapshot.ResponsesByStatus)
+	assert.Zero(t, snapshot.ErrorRate)
+	assert.Len(t, snapshot.RecentDroppedRows, 50)
+
+	m.BatchFinished(http.StatusOK)
+	assert.Equal(t, map[string]int{"200": 1}, m.Snapshot().ResponsesByStatus, "the reused bucket starts empty")
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// distinct tokens kept, later new tokens are not counted
+const maxGarbageTokens = 1000
+
+type GarbageTokenCount struct {
+	Token string `json:"token"`
+	Count int    `json:"count"`
+}
+
+type GarbageTokenSnapshot struct {
+	Learning  bool                 `json:"learning"`
+	Tokens    []*GarbageTokenCount `json:"tokens"`
+	Proposals []string             `json:"proposals,omitempty"`
+}
+
+// GarbageTokenMetrics counts leading tokens the parser did not know how to
+// strip. In learning mode tokens seen at least proposeAfter times are proposed
+// as new prefix rules, a human still has to add them to the parser
+type GarbageTokenMetrics struct {
+	mu           sync.Mutex
+	learning     bool
+	proposeAfter int
+	counts       map[string]int
+}
+
+func NewGarbageTokenMetrics(learning bool, proposeAfter int) *GarbageTokenMetrics {
+	return &GarbageTokenMetrics{
+		learning:     learning,
+		proposeAfter: proposeAfter,
+		counts:       make(map[string]int),
+	}
+}
+
+func (m *GarbageTokenMetrics) RecordGarbageTokens(tokens []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, token := range tokens {
+		if _, seen := m.counts[token]; !seen && len(m.counts) >= maxGarbageTokens {
+			continue
+		}
+		m.counts[token]++
+	}
+}
+
+// tokens most seen first
+func (m *GarbageTokenMetrics) Snapshot() *GarbageTokenSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &GarbageTokenSnapshot{
+		Learning: m.learning,
+		Tokens:   make([]*GarbageTokenCount, 0, len(m.counts)),
+	}
+	for token, count := range m.counts {
+		snapshot.Tokens = append(snapshot.Tokens, &GarbageTokenCount{Token: token, Count: count})
+	}
+	sort.Slice(snapshot.Tokens, func(i, j int) bool {
+		if snapshot.Tokens[i].Count != snapshot.Tokens[j].Count {
+			return snapshot.Tokens[i].Count > snapshot.Tokens[j].Count
+		}
+		return snapshot.Tokens[i].Token < snapshot.Tokens[j].Token
+	})
+
+	if m.learning {
+		for _, token := range snapshot.Tokens {
+			if token.Count >= m.proposeAfter {
+				snapshot.Proposals = append(snapshot.Proposals, token.Token)
+			}
+		}
+	}
+
+	return snapshot
+}
+
+// Report is Snapshot for the endpoints that serve it
+func (m *GarbageTokenMetrics) Report() any {
+	return m.Snapshot()
+}
@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGarbageTokenMetrics_Snapshot(t *testing.T) {
+	tests := []struct {
+		name              string
+		learning          bool
+		expectedProposals []string
+	}{
+		{"Learning proposes frequent tokens", true, []string{"##", "%21"}},
+		{"Without learning nothing is proposed", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := metrics.NewGarbageTokenMetrics(tt.learning, 2)
+
+			m.RecordGarbageTokens([]string{"##", "%21", "~"})
+			m.RecordGarbageTokens([]string{"##", "%21"})
+			m.RecordGarbageTokens([]string{"##"})
+
+			snapshot := m.Snapshot()
+			assert.Equal(t, tt.learning, snapshot.Learning)
+			assert.Equal(t, []*metrics.GarbageTokenCount{
+				{Token: "##", Count: 3},
+				{Token: "%21", Count: 2},
+				{Token: "~", Count: 1},
+			}, snapshot.Tokens)
+			assert.Equal(t, tt.expectedProposals, snapshot.Proposals)
+		})
+	}
+}
+
+func TestGarbageTokenMetrics_EmptySnapshot(t *testing.T) {
+	snapshot := metrics.NewGarbageTokenMetrics(true, 1).Snapshot()
+
+	assert.NotNil(t, snapshot.Tokens)
+	assert.Empty(t, snapshot.Tokens)
+	assert.Nil(t, snapshot.Proposals)
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+)
+
+type PriceSplitBatch struct {
+	ProcessedAt    time.Time            `json:"processedAt"`
+	Rows           int                  `json:"rows"`
+	WithRemainder  int                  `json:"withRemainder"`
+	WorstOffenders []*entity.PriceSplit `json:"worstOffenders"`
+}
+
+type PriceSplitSnapshot struct {
+	Rows           int                `json:"rows"`
+	WithRemainder  int                `json:"withRemainder"`
+	RemainderRate  float64            `json:"remainderRate"`
+	TotalRemainder float64            `json:"totalRemainder"`
+	MaxRemainder   float64            `json:"maxRemainder"`
+	RecentBatches  []*PriceSplitBatch `json:"recentBatches"`
+}
+
+// PriceSplitMetrics keeps running totals of price-split remainders plus the
+// worst offending rows of the most recent batches
+type PriceSplitMetrics struct {
+	mu                sync.Mutex
+	rows              int
+	withRemainder     int
+	totalRemainder    float64
+	maxRemainder      float64
+	recentBatches     []*PriceSplitBatch
+	maxRecentBatches  int
+	offendersPerBatch int
+}
+
+func NewPriceSplitMetrics(maxRecentBatches, offendersPerBatch int) *PriceSplitMetrics {
+	return &PriceSplitMetrics{
+		maxRecentBatches:  max(maxRecentBatches, 0),
+		offendersPerBatch: max(offendersPerBatch, 0),
+	}
+}
+
+func (m *PriceSplitMetrics) RecordBatch(splits []*entity.PriceSplit) {
+	batch := &PriceSplitBatch{
+		ProcessedAt:    time.Now().UTC(),
+		Rows:           len(splits),
+		WorstOffenders: make([]*entity.PriceSplit, 0),
+	}
+
+	for _, split := range splits {
+		if split.HasRemainder() {
+			batch.WithRemainder++
+			batch.WorstOffenders = append(batch.WorstOffenders, split)
+		}
+	}
+
+	sort.SliceStable(batch.WorstOffenders, func(i, j int) bool {
+		return batch.WorstOffenders[i].Magnitude() > batch.WorstOffenders[j].Magnitude()
+	})
+	if len(batch.WorstOffenders) > m.offendersPerBatch {
+		batch.WorstOffenders = batch.WorstOffenders[:m.offendersPerBatch]
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rows += len(splits)
+	for _, split := range splits {
+		if !split.HasRemainder() {
+			continue
+		}
+		m.withRemainder++
+		m.totalRemainder += split.Magnitude()
+		if split.Magnitude() > m.maxRemainder {
+			m.maxRemainder = split.Magnitude()
+		}
+	}
+
+	m.recentBatches = append(m.recentBatches, batch)
+	if len(m.recentBatches) > m.maxRecentBatches {
+		m.recentBatches = m.recentBatches[len(m.recentBatches)-m.maxRecentBatches:]
+	}
+}
+
+// newest batch first
+func (m *PriceSplitMetrics) Snapshot() *PriceSplitSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &PriceSplitSnapshot{
+		Rows:           m.rows,
+		WithRemainder:  m.withRemainder,
+		TotalRemainder: roundCents(m.totalRemainder),
+		MaxRemainder:   m.maxRemainder,
+		RecentBatches:  make([]*PriceSplitBatch, 0, len(m.recentBatches)),
+	}
+
+	if m.rows > 0 {
+		snapshot.RemainderRate = float64(m.withRemainder) / float64(m.rows)
+	}
+
+	for i := len(m.recentBatches) - 1; i >= 0; i-- {
+		snapshot.RecentBatches = append(snapshot.RecentBatches, m.recentBatches[i])
+	}
+
+	return snapshot
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Report is Snapshot for the endpoints that serve it
+func (m *PriceSplitMetrics) Report() any {
+	return m.Snapshot()
+}
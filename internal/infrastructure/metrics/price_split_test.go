@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceSplitMetrics_RecordBatch(t *testing.T) {
+	m := metrics.NewPriceSplitMetrics(2, 2)
+
+	m.RecordBatch([]*entity.PriceSplit{
+		{No: 1, Remainder: 0},
+		{No: 2, Remainder: 0.01},
+		{No: 3, Remainder: -0.02},
+		{No: 4, Remainder: 0.01},
+	})
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, 4, snapshot.Rows)
+	assert.Equal(t, 3, snapshot.WithRemainder)
+	assert.InDelta(t, 0.75, snapshot.RemainderRate, 1e-9)
+	assert.InDelta(t, 0.04, snapshot.TotalRemainder, 1e-9)
+	assert.InDelta(t, 0.02, snapshot.MaxRemainder, 1e-9)
+
+	require.Len(t, snapshot.RecentBatches, 1)
+	batch := snapshot.RecentBatches[0]
+	assert.Equal(t, 4, batch.Rows)
+	assert.Equal(t, 3, batch.WithRemainder)
+	require.Len(t, batch.WorstOffenders, 2)
+	assert.Equal(t, 3, batch.WorstOffenders[0].No)
+	assert.Equal(t, 2, batch.WorstOffenders[1].No)
+}
+
+func TestPriceSplitMetrics_KeepsRecentBatchesOnly(t *testing.T) {
+	m := metrics.NewPriceSplitMetrics(2, 5)
+
+	m.RecordBatch([]*entity.PriceSplit{{No: 1}})
+	m.RecordBatch([]*entity.PriceSplit{{No: 1}, {No: 2}})
+	m.RecordBatch([]*entity.PriceSplit{{No: 1}, {No: 2}, {No: 3}})
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, 6, snapshot.Rows)
+	require.Len(t, snapshot.RecentBatches, 2)
+	assert.Equal(t, 3, snapshot.RecentBatches[0].Rows, "newest batch first")
+	assert.Equal(t, 2, snapshot.RecentBatches[1].Rows)
+}
+
+func TestPriceSplitMetrics_EmptySnapshot(t *testing.T) {
+	snapshot := metrics.NewPriceSplitMetrics(5, 5).Snapshot()
+
+	assert.Zero(t, snapshot.Rows)
+	assert.Zero(t, snapshot.RemainderRate)
+	assert.NotNil(t, snapshot.RecentBatches)
+	assert.Empty(t, snapshot.RecentBatches)
+}
+
+func TestPriceSplitMetrics_NegativeLimitsKeepNothing(t *testing.T) {
+	m := metrics.NewPriceSplitMetrics(-1, -1)
+
+	require.NotPanics(t, func() {
+		m.RecordBatch([]*entity.PriceSplit{{No: 1, Remainder: 0.01}, {No: 2, Remainder: 0.02}})
+	})
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, 2, snapshot.Rows)
+	assert.Equal(t, 2, snapshot.WithRemainder)
+	assert.Empty(t, snapshot.RecentBatches)
+}
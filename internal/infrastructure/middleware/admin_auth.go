@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+const TenantIdHeader = "X-Tenant-Id"
+
+// AdminRole is what an admin API key may do
+type AdminRole string
+
+const (
+	// reads dashboards, metrics and settings
+	RoleViewer AdminRole = "viewer"
+	// also verifies configuration and redelivers webhooks
+	RoleOperator AdminRole = "operator"
+	// an operator limited to one tenant's X-Tenant-Id
+	RoleTenantAdmin AdminRole = "tenant-admin"
+	// also changes parsing and ordering rules
+	RoleRuleAdmin AdminRole = "rule-admin"
+)
+
+// AdminPermission is what an admin route needs
+type AdminPermission string
+
+const (
+	PermissionRead        AdminPermission = "read"
+	PermissionOperate     AdminPermission = "operate"
+	PermissionManageRules AdminPermission = "manage-rules"
+)
+
+var rolePermissions = map[AdminRole][]AdminPermission{
+	RoleViewer:      {PermissionRead},
+	RoleOperator:    {PermissionRead, PermissionOperate},
+	RoleTenantAdmin: {PermissionRead, PermissionOperate},
+	RoleRuleAdmin:   {PermissionRead, PermissionOperate, PermissionManageRules},
+}
+
+// admin routes that change something without changing rules, any other
+// admin route that is not a read needs PermissionManageRules
+var operateRoutes = map[string]bool{
+	"POST /api/v1/admin/config/verify":                    true,
+	"POST /api/v1/admin/parser/lint":                      true,
+	"POST /api/v1/admin/webhook/deliveries/:id/redeliver": true,
+}
+
+// admin routes a tenant-admin may use, they show nothing but its tenant's data
+// or run as its tenant. The others show every tenant's data and are refused
+var tenantScopedRoutes = map[string]bool{
+	"GET /api/v1/admin/clients":            true,
+	"GET /api/v1/admin/texture-priorities": true,
+	"POST /api/v1/admin/config/verify":     true,
+	"POST /api/v1/admin/parser/lint":       true,
+}
+
+// AdminKey is the role assigned to one API key, Tenant is required for
+// tenant-admin and ignored otherwise
+type AdminKey struct {
+	Role   AdminRole `json:"role"`
+	Tenant string    `json:"tenant,omitempty"`
+}
+
+// AdminKeys maps an API key to its role, e.g.
+// {"k3y": {"role": "tenant-admin", "tenant": "acme"}}
+type AdminKeys map[string]*AdminKey
+
+func (k AdminKeys) Validate() error {
+	for key, adminKey := range k {
+		if key == "" || adminKey == nil {
+			log.Errorf("admin key without a role")
+			return errors.ErrInvalidInput
+		}
+		if _, ok := rolePermissions[adminKey.Role]; !ok {
+			log.Errorf("unknown admin role", log.S("role", string(adminKey.Role)))
+			return errors.ErrInvalidInput
+		}
+		if adminKey.Role == RoleTenantAdmin && adminKey.Tenant == "" {
+			log.Errorf("tenant-admin key without a tenant")
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// AdminAuthorization checks the "Authorization: Bearer <key>" of every request
+// to /api/v1/admin and /metrics against the permission its route
+// needs. A tenant-admin's requests run as its tenant, another X-Tenant-Id is
+// refused, and so are routes showing other tenants' data. Register it with
+// engine.Use before the admin routes, other routes pass
+func AdminAuthorization(keys AdminKeys) gin.HandlerFunc {
+	// keyed by hash so the lookup time does not depend on the key's bytes
+	hashed := make(map[string]*AdminKey, len(keys))
+	for key, adminKey := range keys {
+		hashed[hashKey(key)] = adminKey
+	}
+
+	return func(c *gin.Context) {
+		permission, ok := adminPermission(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		adminKey := hashed[hashKey(strings.TrimSpace(token))]
+		if !found || adminKey == nil {
+			rejectAdmin(c, errors.ErrUnauthorized, "missing or unknown admin key", "")
+			return
+		}
+
+		if !adminKey.allows(permission) {
+			rejectAdmin(c, errors.ErrForbidden, "admin role lacks permission", adminKey.Role)
+			return
+		}
+
+		if adminKey.Role == RoleTenantAdmin {
+			if !tenantScopedRoutes[c.Request.Method+" "+c.FullPath()] {
+				rejectAdmin(c, errors.ErrForbidden, "route is not limited to a tenant", adminKey.Role)
+				return
+			}
+			tenantId := c.GetHeader(TenantIdHeader)
+			if tenantId != "" && tenantId != adminKey.Tenant {
+				rejectAdmin(c, errors.ErrForbidden, "tenant-admin of another tenant", adminKey.Role)
+				return
+			}
+			c.Request.Header.Set(TenantIdHeader, adminKey.Tenant)
+			c.Set(model.AdminTenantContextKey, adminKey.Tenant)
+		}
+		// admins are trusted with any tenant's prices, tenant-admins only get
+		// their own
+		c.Set(model.TenantVerifiedContextKey, true)
+
+		c.Next()
+	}
+}
+
+func (k *AdminKey) allows(permission AdminPermission) bool {
+	for _, allowed := range rolePermissions[k.Role] {
+		if allowed == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// false for routes outside the admin API and the metrics, unmatched paths
+// included
+func adminPermission(method, route string) (AdminPermission, bool) {
+	if !strings.HasPrefix(route, "/api/v1/admin/") && !strings.HasPrefix(route, "/metrics/") {
+		return "", false
+	}
+
+	switch {
+	case method == "GET" || method == "HEAD":
+		return PermissionRead, true
+	case operateRoutes[method+" "+route]:
+		return PermissionOperate, true
+	default:
+		return PermissionManageRules, true
+	}
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// the reason is logged only, clients get a plain 401 or 403
+func rejectAdmin(c *gin.Context, err error, reason string, role AdminRole) {
+	log.Warnf("rejected admin request",
+		log.S("reason", reason),
+		log.S("role", string(role)),
+		log.S("method", c.Request.Method),
+		log.S("path", c.Request.URL.Path))
+	errors.MapJsonError(c, err)
+	c.Abort()
+}
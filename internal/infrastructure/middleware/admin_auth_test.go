@@ -0,0 +1,111 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order-placement-system/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthorization(t *testing.T) {
+	keys := middleware.AdminKeys{
+		"viewer-key":   {Role: middleware.RoleViewer},
+		"operator-key": {Role: middleware.RoleOperator},
+		"rules-key":    {Role: middleware.RoleRuleAdmin},
+		"acme-key":     {Role: middleware.RoleTenantAdmin, Tenant: "acme"},
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		key          string
+		tenantId     string
+		expectStatus int
+		expectTenant string
+	}{
+		{"No key", http.MethodGet, "/api/v1/admin/dashboard", "", "", http.StatusUnauthorized, ""},
+		{"Unknown key", http.MethodGet, "/api/v1/admin/dashboard", "other-key", "", http.StatusUnauthorized, ""},
+		{"Viewer reads", http.MethodGet, "/api/v1/admin/dashboard", "viewer-key", "", http.StatusOK, ""},
+		{"Viewer reads garbage tokens", http.MethodGet, "/api/v1/admin/parser/garbage-tokens", "viewer-key", "", http.StatusOK, ""},
+		{"Former admin route is gone", http.MethodGet, "/admin/parser/garbage-tokens", "", "", http.StatusNotFound, ""},
+		{"Viewer cannot operate", http.MethodPost, "/api/v1/admin/config/verify", "viewer-key", "", http.StatusForbidden, ""},
+		{"Operator verifies config", http.MethodPost, "/api/v1/admin/config/verify", "operator-key", "", http.StatusOK, ""},
+		{"Operator redelivers webhook", http.MethodPost, "/api/v1/admin/webhook/deliveries/1/redeliver", "operator-key", "", http.StatusOK, ""},
+		{"Operator cannot change rules", http.MethodPut, "/api/v1/admin/texture-priorities", "operator-key", "", http.StatusForbidden, ""},
+		{"Rule admin changes rules", http.MethodPut, "/api/v1/admin/texture-priorities", "rules-key", "", http.StatusOK, ""},
+		{"Tenant admin runs as its tenant", http.MethodPost, "/api/v1/admin/config/verify", "acme-key", "", http.StatusOK, "acme"},
+		{"Tenant admin of its tenant", http.MethodGet, "/api/v1/admin/clients", "acme-key", "acme", http.StatusOK, "acme"},
+		{"Tenant admin of another tenant", http.MethodGet, "/api/v1/admin/clients", "acme-key", "globex", http.StatusForbidden, ""},
+		{"Tenant admin cannot see every tenant", http.MethodGet, "/api/v1/admin/dashboard", "acme-key", "acme", http.StatusForbidden, ""},
+		{"Metrics need a key", http.MethodGet, "/metrics/business", "", "", http.StatusUnauthorized, ""},
+		{"Viewer reads metrics", http.MethodGet, "/metrics/business", "viewer-key", "", http.StatusOK, ""},
+		{"Tenant admin cannot read metrics", http.MethodGet, "/metrics/business", "acme-key", "acme", http.StatusForbidden, ""},
+		{"Tenant admin cannot change rules", http.MethodPut, "/api/v1/admin/texture-priorities", "acme-key", "acme", http.StatusForbidden, ""},
+		{"Other routes need no key", http.MethodPost, "/api/v1/orders", "", "", http.StatusOK, ""},
+	}
+
+	engine := gin.New()
+	engine.Use(middleware.AdminAuthorization(keys))
+	respond := func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetHeader(middleware.TenantIdHeader))
+	}
+	engine.GET("/api/v1/admin/dashboard", respond)
+	engine.GET("/api/v1/admin/clients", respond)
+	engine.GET("/metrics/business", respond)
+	engine.GET("/api/v1/admin/parser/garbage-tokens", respond)
+	engine.POST("/api/v1/admin/config/verify", respond)
+	engine.POST("/api/v1/admin/webhook/deliveries/:id/redeliver", respond)
+	engine.PUT("/api/v1/admin/texture-priorities", respond)
+	engine.POST("/api/v1/orders", respond)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.key)
+			}
+			if tt.tenantId != "" {
+				req.Header.Set(middleware.TenantIdHeader, tt.tenantId)
+			}
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectStatus == http.StatusOK {
+				assert.Equal(t, tt.expectTenant, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminKeys_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      middleware.AdminKeys
+		expectErr bool
+	}{
+		{"Empty", nil, false},
+		{"Valid roles", middleware.AdminKeys{"a": {Role: middleware.RoleViewer}, "b": {Role: middleware.RoleTenantAdmin, Tenant: "acme"}}, false},
+		{"Unknown role", middleware.AdminKeys{"a": {Role: "root"}}, true},
+		{"Tenant admin without tenant", middleware.AdminKeys{"a": {Role: middleware.RoleTenantAdmin}}, true},
+		{"Key without role", middleware.AdminKeys{"a": nil}, true},
+		{"Empty key", middleware.AdminKeys{"": {Role: middleware.RoleViewer}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.keys.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
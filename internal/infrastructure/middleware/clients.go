@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// the partner integration sending the request, e.g. "acme-shopify"
+	ClientIdHeader = "X-Client-Id"
+	// version of the partner's integration or of the SDK it is built on
+	SDKVersionHeader = "X-SDK-Version"
+	// the partner's API key, "Authorization: Bearer <key>" is read too
+	ApiKeyHeader = "X-Api-Key"
+
+	maxClientHeaderLength = 128
+)
+
+// TrackClients counts the order requests of each tenant's API keys with
+// their response status and batch warnings, and puts the client into the
+// batch meta. Register it before OrderAuthentication, the tenant and key are
+// read once the request was authenticated. Without authentication every
+// request of a tenant is counted under an empty key
+func TrackClients(clients *metrics.ClientMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := &metrics.ClientRequest{
+			Client:     clientHeader(c, ClientIdHeader),
+			SDKVersion: clientHeader(c, SDKVersionHeader),
+			UserAgent:  clientHeader(c, "User-Agent"),
+			SourceIP:   c.ClientIP(),
+		}
+		if request.Client == "" {
+			request.Client = request.UserAgent
+		}
+		c.Set(model.BatchClientContextKey, &model.BatchClient{
+			Client:     request.Client,
+			SDKVersion: request.SDKVersion,
+			UserAgent:  request.UserAgent,
+			SourceIP:   request.SourceIP,
+		})
+
+		defer func() {
+			request.Tenant = clientHeader(c, TenantIdHeader)
+			request.ApiKeyId = c.GetString(model.ApiKeyIdContextKey)
+
+			// a panic is answered with a 500 by the recovery further out
+			if r := recover(); r != nil {
+				request.Status = http.StatusInternalServerError
+				clients.Record(request)
+				panic(r)
+			}
+
+			request.Status = c.Writer.Status()
+			if warnings, ok := c.Get(model.BatchWarningsContextKey); ok {
+				for _, warning := range warnings.([]*model.BatchWarning) {
+					request.Warnings = append(request.Warnings, warning.Code)
+				}
+			}
+			clients.Record(request)
+		}()
+
+		c.Next()
+	}
+}
+
+// headers are cut so one client cannot blow up the metrics
+func clientHeader(c *gin.Context, name string) string {
+	value := strings.TrimSpace(c.GetHeader(name))
+	if len(value) > maxClientHeaderLength {
+		value = value[:maxClientHeaderLength]
+	}
+	return value
+}
+
+// the first 12 hex digits of the key's SHA-256, enough to tell keys apart in
+// the metrics without storing one. Empty without a key
+func apiKeyId(c *gin.Context) string {
+	key := apiKey(c)
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// X-Api-Key, or the bearer token without it
+func apiKey(c *gin.Context) string {
+	key := strings.TrimSpace(c.GetHeader(ApiKeyHeader))
+	if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && key == "" {
+		key = strings.TrimSpace(bearer)
+	}
+	return key
+}
@@ -0,0 +1,106 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/infrastructure/metrics"
+	"order-placement-system/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackClients(t *testing.T) {
+	clients := metrics.NewClientMetrics(time.Now)
+
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	engine.Use(middleware.TrackClients(clients))
+	engine.POST("/ok", func(c *gin.Context) {
+		c.Set(model.BatchWarningsContextKey, []*model.BatchWarning{{Code: "PREFIXED_ROWS"}})
+		c.Status(http.StatusOK)
+	})
+	engine.POST("/invalid", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+	engine.POST("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	send := func(path, clientId, sdkVersion, userAgent string) {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		if clientId != "" {
+			r.Header.Set(middleware.ClientIdHeader, clientId)
+		}
+		r.Header.Set(middleware.SDKVersionHeader, sdkVersion)
+		r.Header.Set("User-Agent", userAgent)
+		engine.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	send("/ok", "acme", "2.0", "acme-sdk/2.0")
+	send("/invalid", "acme", "2.0", "acme-sdk/2.0")
+	send("/panic", "acme", "2.0", "acme-sdk/2.0")
+	send("/ok", strings.Repeat("x", 500), "", "")
+	send("/ok", "", "", "curl/8.0")
+
+	snapshot := clients.Snapshot("")
+	require.Len(t, snapshot, 1, "requests without an authenticated key are counted together")
+
+	count := snapshot[0]
+	assert.Empty(t, count.ApiKeyId)
+	assert.Equal(t, "curl/8.0", count.Client, "the user agent stands in for a missing client id")
+	assert.NotEmpty(t, count.SourceIP)
+	assert.Equal(t, 5, count.Batches)
+	assert.Equal(t, 1, count.Rejected)
+	assert.Equal(t, 1, count.Failed)
+	assert.Equal(t, map[string]int{"PREFIXED_ROWS": 3}, count.Warnings)
+}
+
+func TestTrackClients_AuthenticatedKey(t *testing.T) {
+	clients := metrics.NewClientMetrics(time.Now)
+
+	var recorded *model.BatchClient
+	engine := gin.New()
+	engine.Use(middleware.TrackClients(clients), middleware.OrderAuthentication(middleware.OrderKeys{"s3cret": "acme", "other": "beta"}))
+	engine.POST("/orders", func(c *gin.Context) {
+		recorded = model.BatchClientFrom(c)
+		c.Status(http.StatusOK)
+	})
+
+	send := func(clientId string, headers map[string]string) {
+		r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		r.Header.Set(middleware.ClientIdHeader, clientId)
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+		engine.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	send("shopify", map[string]string{middleware.ApiKeyHeader: "s3cret"})
+	require.NotNil(t, recorded)
+	assert.Equal(t, "shopify", recorded.Client)
+	assert.Len(t, recorded.ApiKeyId, 12, "the batch meta names the authenticated key")
+
+	send("lazada", map[string]string{"Authorization": "Bearer s3cret"})
+	send("shopify", map[string]string{middleware.ApiKeyHeader: "other"})
+	send("shopify", map[string]string{middleware.ApiKeyHeader: "unknown", middleware.TenantIdHeader: "acme"})
+
+	acme := clients.Snapshot("acme")
+	require.Len(t, acme, 2)
+	assert.Len(t, acme[0].ApiKeyId, 12)
+	assert.NotContains(t, acme[0].ApiKeyId, "s3cret")
+	assert.Equal(t, 2, acme[0].Batches, "a bearer token is the same key, whatever client id it sends")
+	assert.Equal(t, "lazada", acme[0].Client)
+	assert.Empty(t, acme[1].ApiKeyId, "a rejected key is not attributed")
+	assert.Equal(t, 1, acme[1].Rejected)
+
+	beta := clients.Snapshot("beta")
+	require.Len(t, beta, 1)
+	assert.NotEqual(t, acme[0].ApiKeyId, beta[0].ApiKeyId)
+	assert.Equal(t, beta[0].ApiKeyId, recorded.ApiKeyId)
+}
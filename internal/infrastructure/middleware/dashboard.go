@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"order-placement-system/internal/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrackBatches counts the requests in flight and their response status on
+// the operator dashboard
+func TrackBatches(dashboard *metrics.DashboardMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dashboard.BatchStarted()
+		defer func() {
+			// a panic is answered with a 500 by the recovery further out
+			if r := recover(); r != nil {
+				dashboard.BatchFinished(http.StatusInternalServerError)
+				panic(r)
+			}
+			dashboard.BatchFinished(c.Writer.Status())
+		}()
+
+		c.Next()
+	}
+}
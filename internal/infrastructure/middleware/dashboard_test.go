@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/infrastructure/metrics"
+	"order-placement-system/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackBatches(t *testing.T) {
+	dashboard := metrics.NewDashboardMetrics(time.Now(), time.Now)
+
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	engine.Use(middleware.TrackBatches(dashboard))
+	engine.POST("/ok", func(c *gin.Context) {
+		assert.Equal(t, 1, dashboard.Snapshot().InFlightBatches)
+		c.Status(http.StatusOK)
+	})
+	engine.POST("/invalid", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+	engine.POST("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	for _, path := range []string{"/ok", "/invalid", "/panic"} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	snapshot := dashboard.Snapshot()
+	assert.Zero(t, snapshot.InFlightBatches)
+	assert.Equal(t, map[string]int{"200": 1, "400": 1, "500": 1}, snapshot.ResponsesByStatus)
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+	WarningHeader     = "Warning"
+
+	// dates in the configuration, e.g. 2027-01-31
+	deprecationDateLayout = "2006-01-02"
+)
+
+// Deprecation flags a route, or one field of it, as going away. Route is
+// "<METHOD> <path>" as registered, e.g. "POST /api/v1/orders/process/single".
+// Dates are optional, Since defaults to now
+type Deprecation struct {
+	Route string `json:"route"`
+	// request or response field that changes, empty deprecates the route
+	Field       string `json:"field,omitempty"`
+	Since       string `json:"since,omitempty"`
+	Sunset      string `json:"sunset,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// human readable, e.g. "CLEANNER product ids will be spelled CLEANER"
+	Message string `json:"message,omitempty"`
+}
+
+type Deprecations []*Deprecation
+
+func (d Deprecations) Validate() error {
+	for _, deprecation := range d {
+		if deprecation == nil {
+			log.Errorf("empty deprecation")
+			return errors.ErrInvalidInput
+		}
+		method, path, ok := strings.Cut(deprecation.Route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			log.Errorf("deprecated route must be \"<METHOD> <path>\"", log.S("route", deprecation.Route))
+			return errors.ErrInvalidInput
+		}
+
+		since, err := parseDeprecationDate(deprecation.Since)
+		if err != nil {
+			log.Errorf("invalid deprecation date", log.S("route", deprecation.Route), log.S("since", deprecation.Since))
+			return errors.ErrInvalidInput
+		}
+		sunset, err := parseDeprecationDate(deprecation.Sunset)
+		if err != nil {
+			log.Errorf("invalid sunset date", log.S("route", deprecation.Route), log.S("sunset", deprecation.Sunset))
+			return errors.ErrInvalidInput
+		}
+		if !since.IsZero() && !sunset.IsZero() && !sunset.After(since) {
+			log.Errorf("sunset must come after the deprecation", log.S("route", deprecation.Route))
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// DeprecationNotices announces the configured deprecations in band. A
+// deprecated route answers with Deprecation, Sunset, a successor Link and a
+// Warning, a deprecated field only with a Warning. Register it with
+// engine.Use before the routes it covers
+func DeprecationNotices(deprecations Deprecations) gin.HandlerFunc {
+	byRoute := make(map[string][]*Deprecation)
+	for _, deprecation := range deprecations {
+		byRoute[deprecation.Route] = append(byRoute[deprecation.Route], deprecation)
+	}
+
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		matched := byRoute[route]
+		if len(matched) == 0 {
+			c.Next()
+			return
+		}
+
+		for _, deprecation := range matched {
+			if deprecation.Field == "" {
+				setRouteDeprecation(c, deprecation)
+			}
+			c.Writer.Header().Add(WarningHeader, deprecation.warning())
+		}
+		c.Header("Access-Control-Expose-Headers", strings.Join([]string{DeprecationHeader, SunsetHeader, "Link", WarningHeader}, ", "))
+
+		log.Infof("deprecated route called", log.S("route", route), log.S("user_agent", c.Request.UserAgent()))
+		c.Next()
+	}
+}
+
+// Deprecation is "@<unix time>" (RFC 9745), "true" when no date is known
+// yet. Sunset is an HTTP date (RFC 8594)
+func setRouteDeprecation(c *gin.Context, deprecation *Deprecation) {
+	since, _ := parseDeprecationDate(deprecation.Since)
+	if since.IsZero() {
+		c.Header(DeprecationHeader, "true")
+	} else {
+		c.Header(DeprecationHeader, fmt.Sprintf("@%d", since.Unix()))
+	}
+
+	if sunset, _ := parseDeprecationDate(deprecation.Sunset); !sunset.IsZero() {
+		c.Header(SunsetHeader, sunset.Format(http.TimeFormat))
+	}
+	if deprecation.Replacement != "" {
+		c.Writer.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", deprecation.Replacement))
+	}
+}
+
+// 299 is the persistent miscellaneous warning
+func (d *Deprecation) warning() string {
+	subject := d.Route
+	if d.Field != "" {
+		subject = "field " + d.Field + " of " + d.Route
+	}
+
+	text := subject + " is deprecated"
+	if d.Sunset != "" {
+		text += " and will be removed on " + d.Sunset
+	}
+	if d.Replacement != "" {
+		text += ", use " + d.Replacement
+	}
+	if d.Message != "" {
+		text += ": " + d.Message
+	}
+
+	return fmt.Sprintf("299 - %q", text)
+}
+
+// zero for an empty date
+func parseDeprecationDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(deprecationDateLayout, date)
+}
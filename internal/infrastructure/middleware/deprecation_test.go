@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order-placement-system/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationNotices(t *testing.T) {
+	deprecations := middleware.Deprecations{
+		{
+			Route:       "POST /api/v1/orders/process/single",
+			Since:       "2026-01-01",
+			Sunset:      "2027-01-31",
+			Replacement: "/api/v1/orders/process",
+		},
+		{
+			Route:   "POST /api/v1/orders/process",
+			Field:   "productId",
+			Sunset:  "2027-01-31",
+			Message: "CLEANNER product ids will be spelled CLEANER",
+		},
+	}
+
+	engine := gin.New()
+	engine.Use(middleware.DeprecationNotices(deprecations))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	engine.POST("/api/v1/orders/process/single", ok)
+	engine.POST("/api/v1/orders/process", ok)
+	engine.GET("/health", ok)
+
+	tests := []struct {
+		name              string
+		method            string
+		path              string
+		expectDeprecation string
+		expectSunset      string
+		expectLink        string
+		expectWarning     string
+	}{
+		{
+			name:              "Deprecated route",
+			method:            http.MethodPost,
+			path:              "/api/v1/orders/process/single",
+			expectDeprecation: "@1767225600",
+			expectSunset:      "Sun, 31 Jan 2027 00:00:00 GMT",
+			expectLink:        `</api/v1/orders/process>; rel="successor-version"`,
+			expectWarning:     `299 - "POST /api/v1/orders/process/single is deprecated and will be removed on 2027-01-31, use /api/v1/orders/process"`,
+		},
+		{
+			name:          "Deprecated field",
+			method:        http.MethodPost,
+			path:          "/api/v1/orders/process",
+			expectWarning: `299 - "field productId of POST /api/v1/orders/process is deprecated and will be removed on 2027-01-31: CLEANNER product ids will be spelled CLEANER"`,
+		},
+		{
+			name:   "Other route",
+			method: http.MethodGet,
+			path:   "/health",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectDeprecation, w.Header().Get(middleware.DeprecationHeader))
+			assert.Equal(t, tt.expectSunset, w.Header().Get(middleware.SunsetHeader))
+			assert.Equal(t, tt.expectLink, w.Header().Get("Link"))
+			assert.Equal(t, tt.expectWarning, w.Header().Get(middleware.WarningHeader))
+		})
+	}
+}
+
+func TestDeprecations_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		deprecations middleware.Deprecations
+		expectErr    bool
+	}{
+		{"Empty", nil, false},
+		{"Route without dates", middleware.Deprecations{{Route: "GET /api/v1/orders"}}, false},
+		{"Route without method", middleware.Deprecations{{Route: "/api/v1/orders"}}, true},
+		{"Path not absolute", middleware.Deprecations{{Route: "GET api/v1/orders"}}, true},
+		{"Invalid date", middleware.Deprecations{{Route: "GET /health", Sunset: "31/01/2027"}}, true},
+		{"Sunset before deprecation", middleware.Deprecations{{Route: "GET /health", Since: "2027-01-01", Sunset: "2026-01-01"}}, true},
+		{"Nil entry", middleware.Deprecations{nil}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.deprecations.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
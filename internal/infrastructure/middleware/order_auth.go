@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"strings"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderKeys maps a partner's API key to the tenant it orders for, e.g.
+// {"k3y": "acme"}
+type OrderKeys map[string]string
+
+// the X-Tenant-Id the client sent, kept on the gin context under this key
+// before OrderAuthentication sets the header to the key's tenant
+const sentTenantIdContextKey = "sentTenantId"
+
+func (k OrderKeys) Validate() error {
+	for key, tenant := range k {
+		if key == "" || strings.TrimSpace(tenant) == "" {
+			log.Errorf("order key without a tenant")
+			return errors.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// OrderAuthentication checks the API key of every request, sent in X-Api-Key
+// or "Authorization: Bearer <key>", and runs the request as the key's tenant.
+// An X-Tenant-Id naming another tenant is refused, so a client cannot pick
+// another tenant's contract prices
+func OrderAuthentication(keys OrderKeys) gin.HandlerFunc {
+	// keyed by hash so the lookup time does not depend on the key's bytes
+	hashed := make(map[string]string, len(keys))
+	for key, tenant := range keys {
+		hashed[hashKey(key)] = strings.TrimSpace(tenant)
+	}
+
+	return func(c *gin.Context) {
+		key := apiKey(c)
+		tenant, ok := hashed[hashKey(key)]
+		if key == "" || !ok {
+			rejectOrder(c, errors.ErrUnauthorized, "missing or unknown order key", "")
+			return
+		}
+
+		if tenantId := strings.TrimSpace(c.GetHeader(TenantIdHeader)); tenantId != "" && tenantId != tenant {
+			rejectOrder(c, errors.ErrForbidden, "order key of another tenant", tenant)
+			return
+		}
+
+		c.Set(sentTenantIdContextKey, c.GetHeader(TenantIdHeader))
+		c.Request.Header.Set(TenantIdHeader, tenant)
+		c.Set(model.TenantVerifiedContextKey, true)
+		c.Set(model.ApiKeyIdContextKey, apiKeyId(c))
+
+		c.Next()
+	}
+}
+
+// the reason is logged only, clients get a plain 401 or 403
+func rejectOrder(c *gin.Context, err error, reason string, tenant string) {
+	log.Warnf("rejected order request",
+		log.S("reason", reason),
+		log.S("tenant_id", tenant),
+		log.S("method", c.Request.Method),
+		log.S("path", c.Request.URL.Path))
+	errors.MapJsonError(c, err)
+	c.Abort()
+}
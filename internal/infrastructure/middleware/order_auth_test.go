@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order-placement-system/internal/adapter/handler/model"
+	"order-placement-system/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderAuthentication(t *testing.T) {
+	keys := middleware.OrderKeys{"acme-key": "acme"}
+
+	tests := []struct {
+		name         string
+		key          string
+		bearer       bool
+		tenantId     string
+		expectStatus int
+	}{
+		{"No key", "", false, "", http.StatusUnauthorized},
+		{"Unknown key", "other-key", false, "acme", http.StatusUnauthorized},
+		{"Key runs as its tenant", "acme-key", false, "", http.StatusOK},
+		{"Bearer key", "acme-key", true, "", http.StatusOK},
+		{"Key of its tenant", "acme-key", false, "acme", http.StatusOK},
+		{"Key of another tenant", "acme-key", false, "globex", http.StatusForbidden},
+	}
+
+	engine := gin.New()
+	engine.POST("/api/v1/orders/process", middleware.OrderAuthentication(keys), func(c *gin.Context) {
+		assert.True(t, c.GetBool(model.TenantVerifiedContextKey))
+		c.String(http.StatusOK, c.GetHeader(middleware.TenantIdHeader))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/process", nil)
+			if tt.key != "" && tt.bearer {
+				req.Header.Set("Authorization", "Bearer "+tt.key)
+			} else if tt.key != "" {
+				req.Header.Set(middleware.ApiKeyHeader, tt.key)
+			}
+			if tt.tenantId != "" {
+				req.Header.Set(middleware.TenantIdHeader, tt.tenantId)
+			}
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectStatus == http.StatusOK {
+				assert.Equal(t, "acme", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrderKeys_Validate(t *testing.T) {
+	assert.NoError(t, middleware.OrderKeys{"k": "acme"}.Validate())
+	assert.Error(t, middleware.OrderKeys{"k": " "}.Validate())
+	assert.Error(t, middleware.OrderKeys{"": "acme"}.Validate())
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"order-placement-system/pkg/cache"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/utils/canonicaljson"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureNonceHeader     = "X-Signature-Nonce"
+
+	// bodies are read whole to be verified, larger ones are refused
+	maxSignedBodyBytes = 10 << 20
+)
+
+// SignedRequest is what a signature covers
+type SignedRequest struct {
+	Timestamp string
+	Nonce     string
+	Method    string
+	// path and query as sent, e.g. /api/v1/orders?startNo=101
+	Target string
+	// the X-Tenant-Id header as the client sent it, empty when it is not
+	// sent, even after OrderAuthentication set it to the key's tenant
+	TenantId string
+	Body     []byte
+}
+
+// SignedRequests rejects a request unless X-Signature holds the HMAC-SHA256
+// of its timestamp, nonce, method, path and query, tenant and body, the
+// timestamp is within clockSkew of now and the nonce was not used before.
+// Nonces are remembered for twice the skew, nonceCacheSize must cover the
+// requests of that window. When it does not, requests are refused with a 429
+// rather than forgetting a nonce that could then be replayed
+func SignedRequests(secret []byte, clockSkew time.Duration, nonceCacheSize int) gin.HandlerFunc {
+	return SignedRequestsWithCanonicalBody(secret, clockSkew, nonceCacheSize, false)
+}
+
+// with canonicalBody the signature covers the canonical JSON of the body
+// (see canonicaljson.Transform) instead of its raw bytes, so a client or
+// proxy re-serializing the payload does not break it
+func SignedRequestsWithCanonicalBody(secret []byte, clockSkew time.Duration, nonceCacheSize int, canonicalBody bool) gin.HandlerFunc {
+	nonces := cache.NewTTLCache(2*clockSkew, nonceCacheSize)
+
+	return func(c *gin.Context) {
+		timestamp := c.GetHeader(SignatureTimestampHeader)
+		nonce := c.GetHeader(SignatureNonceHeader)
+		signature := c.GetHeader(SignatureHeader)
+
+		if timestamp == "" || nonce == "" || signature == "" {
+			rejectRequest(c, "missing signature headers")
+			return
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			rejectRequest(c, "invalid signature timestamp")
+			return
+		}
+
+		if skew := time.Since(time.Unix(unix, 0)); skew > clockSkew || skew < -clockSkew {
+			rejectRequest(c, "signature timestamp outside clock skew")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				rejectRequestWith(c, errors.ErrPayloadTooLarge, "refused signed request", "body too large")
+				return
+			}
+			rejectRequestWith(c, errors.ErrInvalidInput, "failed to read signed request", err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		signed := body
+		if canonicalBody {
+			if signed, err = canonicaljson.Transform(body); err != nil {
+				rejectRequest(c, "body is not valid JSON")
+				return
+			}
+		}
+
+		request := SignedRequest{
+			Timestamp: timestamp,
+			Nonce:     nonce,
+			Method:    c.Request.Method,
+			Target:    c.Request.URL.RequestURI(),
+			TenantId:  sentTenantId(c),
+			Body:      signed,
+		}
+		provided, err := hex.DecodeString(signature)
+		if err != nil || !hmac.Equal(provided, sign(secret, request)) {
+			rejectRequest(c, "signature mismatch")
+			return
+		}
+
+		// only a correctly signed nonce is remembered, so forged requests
+		// cannot fill the cache
+		stored, full := nonces.AddIfRoom(nonce, struct{}{})
+		if full {
+			rejectRequestWith(c, errors.ErrTooManyRequests, "refused signed request", "nonce cache full")
+			return
+		}
+		if !stored {
+			rejectRequestWith(c, errors.ErrUnauthorized, "rejected replayed request", "nonce replayed")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func sentTenantId(c *gin.Context) string {
+	if sent, ok := c.Get(sentTenantIdContextKey); ok {
+		return sent.(string)
+	}
+	return c.GetHeader(TenantIdHeader)
+}
+
+// Sign returns the hex signature a client sends in X-Signature
+func Sign(secret []byte, request SignedRequest) string {
+	return hex.EncodeToString(sign(secret, request))
+}
+
+// SignCanonical is Sign over the canonical JSON of body
+func SignCanonical(secret []byte, request SignedRequest) (string, error) {
+	canonical, err := canonicaljson.Transform(request.Body)
+	if err != nil {
+		return "", err
+	}
+	request.Body = canonical
+	return Sign(secret, request), nil
+}
+
+// every field but the body ends in a newline
+func sign(secret []byte, request SignedRequest) []byte {
+	mac := hmac.New(sha256.New, secret)
+	for _, field := range []string{request.Timestamp, request.Nonce, request.Method, request.Target, request.TenantId} {
+		mac.Write([]byte(field))
+		mac.Write([]byte("\n"))
+	}
+	mac.Write(request.Body)
+	return mac.Sum(nil)
+}
+
+// the reason is logged only, clients get the same 401 for every signature
+// that does not hold
+func rejectRequest(c *gin.Context, reason string) {
+	rejectRequestWith(c, errors.ErrUnauthorized, "rejected request without a valid signature", reason)
+}
+
+func rejectRequestWith(c *gin.Context, err error, message, reason string) {
+	log.Warnf(message,
+		log.S("reason", reason),
+		log.S("path", c.Request.URL.Path))
+	errors.MapJsonError(c, err)
+	c.Abort()
+}
@@ -0,0 +1,319 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"order-placement-system/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// a request to POST /test?tenant=acme as tenant acme
+func testRequest(timestamp, nonce, body string) middleware.SignedRequest {
+	return middleware.SignedRequest{
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Method:    http.MethodPost,
+		Target:    "/test?tenant=acme",
+		TenantId:  "acme",
+		Body:      []byte(body),
+	}
+}
+
+func TestSignedRequests(t *testing.T) {
+	secret := []byte("secret")
+	body := `{"no":1}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signChanged := func(nonce string, change func(request *middleware.SignedRequest)) string {
+		request := testRequest(now, nonce, body)
+		change(&request)
+		return middleware.Sign(secret, request)
+	}
+
+	tests := []struct {
+		name         string
+		timestamp    string
+		nonce        string
+		signature    string
+		expectStatus int
+	}{
+		{
+			name:         "Valid signature",
+			timestamp:    now,
+			nonce:        "nonce-1",
+			signature:    middleware.Sign(secret, testRequest(now, "nonce-1", body)),
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Replayed nonce",
+			timestamp:    now,
+			nonce:        "nonce-1",
+			signature:    middleware.Sign(secret, testRequest(now, "nonce-1", body)),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Missing headers",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Wrong secret",
+			timestamp:    now,
+			nonce:        "nonce-2",
+			signature:    middleware.Sign([]byte("other"), testRequest(now, "nonce-2", body)),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Signature of another body",
+			timestamp:    now,
+			nonce:        "nonce-3",
+			signature:    middleware.Sign(secret, testRequest(now, "nonce-3", `{"no":2}`)),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Signature for another tenant",
+			timestamp: now,
+			nonce:     "nonce-7",
+			signature: signChanged("nonce-7", func(request *middleware.SignedRequest) {
+				request.TenantId = "globex"
+			}),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Signature for another query",
+			timestamp: now,
+			nonce:     "nonce-8",
+			signature: signChanged("nonce-8", func(request *middleware.SignedRequest) {
+				request.Target = "/test?tenant=globex"
+			}),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Signature for another method and path",
+			timestamp: now,
+			nonce:     "nonce-9",
+			signature: signChanged("nonce-9", func(request *middleware.SignedRequest) {
+				request.Method = http.MethodPut
+				request.Target = "/other?tenant=acme"
+			}),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Signature not hex",
+			timestamp:    now,
+			nonce:        "nonce-4",
+			signature:    "not-hex",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Timestamp outside clock skew",
+			timestamp:    strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10),
+			nonce:        "nonce-5",
+			signature:    middleware.Sign(secret, testRequest(strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10), "nonce-5", body)),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Timestamp not a number",
+			timestamp:    "yesterday",
+			nonce:        "nonce-6",
+			signature:    middleware.Sign(secret, testRequest("yesterday", "nonce-6", body)),
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	engine := gin.New()
+	engine.Use(middleware.SignedRequests(secret, time.Minute, 100))
+	engine.POST("/test", func(c *gin.Context) {
+		received, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(received))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/test?tenant=acme", strings.NewReader(body))
+			req.Header.Set(middleware.TenantIdHeader, "acme")
+			req.Header.Set(middleware.SignatureTimestampHeader, tt.timestamp)
+			req.Header.Set(middleware.SignatureNonceHeader, tt.nonce)
+			req.Header.Set(middleware.SignatureHeader, tt.signature)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectStatus == http.StatusOK {
+				assert.Equal(t, body, w.Body.String(), "the handler still reads the body")
+			}
+		})
+	}
+}
+
+func TestSignedRequests_Limits(t *testing.T) {
+	secret := []byte("secret")
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	engine := gin.New()
+	engine.Use(middleware.SignedRequests(secret, time.Minute, 1))
+	engine.POST("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(nonce, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/test?tenant=acme", strings.NewReader(body))
+		req.Header.Set(middleware.TenantIdHeader, "acme")
+		req.Header.Set(middleware.SignatureTimestampHeader, now)
+		req.Header.Set(middleware.SignatureNonceHeader, nonce)
+		req.Header.Set(middleware.SignatureHeader, middleware.Sign(secret, testRequest(now, nonce, body)))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("nonce-1", `{"no":1}`))
+	assert.Equal(t, http.StatusTooManyRequests, send("nonce-2", `{"no":1}`), "a full nonce cache refuses instead of forgetting nonces")
+	assert.Equal(t, http.StatusUnauthorized, send("nonce-1", `{"no":1}`), "the remembered nonce is still refused")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("nonce-3", strings.Repeat(" ", 10<<20+1)))
+}
+
+func TestSignedRequestsWithCanonicalBody(t *testing.T) {
+	secret := []byte("secret")
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signed, err := middleware.SignCanonical(secret, testRequest(now, "nonce-1", `{"no":1,"qty":2}`))
+	assert.NoError(t, err)
+	signedOther, err := middleware.SignCanonical(secret, testRequest(now, "nonce-4", `{"no":1,"qty":2}`))
+	assert.NoError(t, err)
+	signedFifth, err := middleware.SignCanonical(secret, testRequest(now, "nonce-5", `{"no":1,"qty":2}`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		nonce        string
+		body         string
+		signature    string
+		expectStatus int
+	}{
+		{
+			name:         "Same payload with other key order and spacing",
+			nonce:        "nonce-1",
+			body:         `{ "qty": 2, "no": 1 }`,
+			signature:    signed,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Raw body signature is rejected",
+			nonce:        "nonce-2",
+			body:         `{ "qty": 2, "no": 1 }`,
+			signature:    middleware.Sign(secret, testRequest(now, "nonce-2", `{ "qty": 2, "no": 1 }`)),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Body not JSON",
+			nonce:        "nonce-3",
+			body:         `no=1`,
+			signature:    middleware.Sign(secret, testRequest(now, "nonce-3", `no=1`)),
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "Number written another way is the same payload",
+			nonce:        "nonce-4",
+			body:         `{ "qty": 2.0, "no": 1e0 }`,
+			signature:    signedOther,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Other number is another payload",
+			nonce:        "nonce-5",
+			body:         `{ "qty": 2.5, "no": 1 }`,
+			signature:    signedFifth,
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	engine := gin.New()
+	engine.Use(middleware.SignedRequestsWithCanonicalBody(secret, time.Minute, 100, true))
+	engine.POST("/test", func(c *gin.Context) {
+		received, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(received))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/test?tenant=acme", strings.NewReader(tt.body))
+			req.Header.Set(middleware.TenantIdHeader, "acme")
+			req.Header.Set(middleware.SignatureTimestampHeader, now)
+			req.Header.Set(middleware.SignatureNonceHeader, tt.nonce)
+			req.Header.Set(middleware.SignatureHeader, tt.signature)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectStatus == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String(), "the handler reads the raw body")
+			}
+		})
+	}
+}
+
+// the order of cmd/main.go, authentication sets X-Tenant-Id before the
+// signature is checked
+func TestSignedRequests_AfterOrderAuthentication(t *testing.T) {
+	secret := []byte("secret")
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	body := `{"no":1}`
+
+	engine := gin.New()
+	engine.Use(
+		middleware.OrderAuthentication(middleware.OrderKeys{"acme-key": "acme"}),
+		middleware.SignedRequests(secret, time.Minute, 100),
+	)
+	engine.POST("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetHeader(middleware.TenantIdHeader))
+	})
+
+	tests := []struct {
+		name         string
+		sentTenantId string
+		signedFor    string
+		expectStatus int
+	}{
+		{"Signed without the header", "", "", http.StatusOK},
+		{"Signed with the header", "acme", "acme", http.StatusOK},
+		{"Signed for the key's tenant without sending it", "", "acme", http.StatusUnauthorized},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce := "nonce-" + strconv.Itoa(i)
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+			req.Header.Set(middleware.ApiKeyHeader, "acme-key")
+			if tt.sentTenantId != "" {
+				req.Header.Set(middleware.TenantIdHeader, tt.sentTenantId)
+			}
+			req.Header.Set(middleware.SignatureTimestampHeader, now)
+			req.Header.Set(middleware.SignatureNonceHeader, nonce)
+			req.Header.Set(middleware.SignatureHeader, middleware.Sign(secret, middleware.SignedRequest{
+				Timestamp: now,
+				Nonce:     nonce,
+				Method:    http.MethodPost,
+				Target:    "/test",
+				TenantId:  tt.signedFor,
+				Body:      []byte(body),
+			}))
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectStatus == http.StatusOK {
+				assert.Equal(t, "acme", w.Body.String(), "the request still runs as the key's tenant")
+			}
+		})
+	}
+}
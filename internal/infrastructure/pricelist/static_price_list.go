@@ -0,0 +1,71 @@
+package pricelist
+
+import (
+	"encoding/json"
+	"os"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/log"
+)
+
+// prices under this tenant apply to every tenant without its own price
+const DefaultTenant = entity.DefaultTenantId
+
+// StaticPriceList is a read-only price list loaded once at startup,
+// keyed by tenant and then by canonical product id
+type StaticPriceList struct {
+	prices map[string]map[string]*value_object.Price
+}
+
+func NewStaticPriceList(prices map[string]map[string]float64) (*StaticPriceList, error) {
+	list := &StaticPriceList{
+		prices: make(map[string]map[string]*value_object.Price, len(prices)),
+	}
+
+	for tenantId, products := range prices {
+		list.prices[tenantId] = make(map[string]*value_object.Price, len(products))
+		for productId, amount := range products {
+			price, err := value_object.NewPrice(amount)
+			if err != nil {
+				log.Errorf("invalid contract price",
+					log.S("tenant_id", tenantId),
+					log.S("product_id", productId),
+					log.E(err))
+				return nil, err
+			}
+			list.prices[tenantId][productId] = price
+		}
+	}
+
+	return list, nil
+}
+
+// reads {"<tenantId>": {"<productId>": <unitPrice>}} from a JSON file
+func LoadStaticPriceList(path string) (*StaticPriceList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Errorf("failed to read price list", log.S("path", path), log.E(err))
+		return nil, err
+	}
+
+	var prices map[string]map[string]float64
+	if err := json.Unmarshal(data, &prices); err != nil {
+		log.Errorf("failed to decode price list", log.S("path", path), log.E(err))
+		return nil, err
+	}
+
+	return NewStaticPriceList(prices)
+}
+
+func (l *StaticPriceList) UnitPrice(tenantId, productId string) (*value_object.Price, bool) {
+	if price, ok := l.prices[tenantId][productId]; ok {
+		return price.Clone(), true
+	}
+
+	if price, ok := l.prices[DefaultTenant][productId]; ok {
+		return price.Clone(), true
+	}
+
+	return nil, false
+}
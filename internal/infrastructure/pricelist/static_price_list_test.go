@@ -0,0 +1,88 @@
+package pricelist_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"order-placement-system/internal/infrastructure/pricelist"
+	"order-placement-system/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func TestStaticPriceList_UnitPrice(t *testing.T) {
+	list, err := pricelist.NewStaticPriceList(map[string]map[string]float64{
+		pricelist.DefaultTenant: {
+			"FG0A-CLEAR-IPHONE16PROMAX": 50,
+			"FG0A-MATTE-IPHONE16PROMAX": 60,
+		},
+		"acme": {
+			"FG0A-CLEAR-IPHONE16PROMAX": 40,
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		tenantId  string
+		productId string
+		expected  float64
+		found     bool
+	}{
+		{"Tenant contract price", "acme", "FG0A-CLEAR-IPHONE16PROMAX", 40, true},
+		{"Falls back to default tenant", "acme", "FG0A-MATTE-IPHONE16PROMAX", 60, true},
+		{"Unknown tenant uses default", "globex", "FG0A-CLEAR-IPHONE16PROMAX", 50, true},
+		{"No tenant uses default", "", "FG0A-CLEAR-IPHONE16PROMAX", 50, true},
+		{"Unknown product", "acme", "FG0A-PRIVACY-IPHONE16PROMAX", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := list.UnitPrice(tt.tenantId, tt.productId)
+			assert.Equal(t, tt.found, ok)
+			if tt.found {
+				assert.Equal(t, tt.expected, price.Amount())
+			}
+		})
+	}
+}
+
+func TestNewStaticPriceList_NegativePrice(t *testing.T) {
+	_, err := pricelist.NewStaticPriceList(map[string]map[string]float64{
+		"acme": {"FG0A-CLEAR-IPHONE16PROMAX": -1},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoadStaticPriceList(t *testing.T) {
+	t.Run("Valid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "prices.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"acme":{"FG0A-CLEAR-IPHONE16PROMAX":40}}`), 0o600))
+
+		list, err := pricelist.LoadStaticPriceList(path)
+		require.NoError(t, err)
+
+		price, ok := list.UnitPrice("acme", "FG0A-CLEAR-IPHONE16PROMAX")
+		require.True(t, ok)
+		assert.Equal(t, 40.0, price.Amount())
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := pricelist.LoadStaticPriceList(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Malformed file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "prices.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[1,2,3]`), 0o600))
+
+		_, err := pricelist.LoadStaticPriceList(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"order-placement-system/internal/adapter/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDocs(engine *gin.Engine, reports handler.ReportHandlerInterface) {
+	group := engine.Group("/docs")
+	{
+		group.GET("/complementary-ordering", reports.ComplementaryOrdering)
+		group.GET("/price-policy", reports.PricePolicy)
+	}
+}
+
+// the knowledge base is read by anyone, admins keep it up to date under
+// /api/v1/admin/errors
+func ErrorArticlesV1Routes(engine *gin.Engine, reports handler.ReportHandlerInterface) {
+	errors := engine.Group("/api/v1/errors")
+	{
+		errors.GET("", reports.SearchErrorArticles)
+		errors.GET("/:code", reports.GetErrorArticle)
+	}
+}
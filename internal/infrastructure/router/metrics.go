@@ -0,0 +1,16 @@
+package router
+
+import (
+	"order-placement-system/internal/adapter/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMetrics(engine *gin.Engine, reports handler.ReportHandlerInterface) {
+	group := engine.Group("/metrics")
+	{
+		group.GET("/price-splits", reports.PriceSplits)
+		group.GET("/batch-warnings", reports.BatchWarnings)
+		group.GET("/business", reports.BusinessMetrics)
+	}
+}
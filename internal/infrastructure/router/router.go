@@ -10,11 +10,58 @@ func SetupHealthCheck(engine *gin.Engine) {
 	engine.GET("/health", healthCheck)
 }
 
-func OrderPlacementV1Routes(engine *gin.Engine, order handler.OrderHandlerInterface) {
-	v1 := engine.Group("/api/v1")
+// middlewares run on the order routes only, e.g. request signing
+func OrderPlacementV1Routes(engine *gin.Engine, order handler.OrderHandlerInterface, middlewares ...gin.HandlerFunc) {
+	v1 := engine.Group("/api/v1", middlewares...)
 
 	orders := v1.Group("/orders")
 	{
 		orders.POST("/process", order.ProcessOrders)
+		orders.POST("/process/single", order.ProcessSingleOrder)
+		orders.POST("/renumber", order.RenumberOrders)
+	}
+}
+
+func AdminV1Routes(engine *gin.Engine, admin handler.AdminHandlerInterface) {
+	v1 := engine.Group("/api/v1/admin")
+
+	config := v1.Group("/config")
+	{
+		config.POST("/verify", admin.VerifyConfig)
+	}
+
+	v1.GET("/dashboard", admin.Dashboard)
+	v1.GET("/clients", admin.Clients)
+	v1.GET("/batch-usage", admin.BatchUsage)
+
+	texturePriorities := v1.Group("/texture-priorities")
+	{
+		texturePriorities.GET("", admin.TexturePriorities)
+		texturePriorities.PUT("", admin.SetTexturePriorities)
+	}
+
+	parser := v1.Group("/parser")
+	{
+		parser.GET("/garbage-tokens", admin.GarbageTokens)
+		parser.POST("/lint", admin.LintParserRules)
+	}
+
+	catalogGaps := v1.Group("/catalog-gaps")
+	{
+		catalogGaps.GET("", admin.CatalogGaps)
+		catalogGaps.POST("/texture-aliases", admin.AddCatalogTextureAlias)
+		catalogGaps.DELETE("/:kind/:value", admin.ResolveCatalogGap)
+	}
+
+	deliveries := v1.Group("/webhook/deliveries")
+	{
+		deliveries.GET("", admin.WebhookDeliveries)
+		deliveries.POST("/:id/redeliver", admin.RedeliverWebhook)
+	}
+
+	errors := v1.Group("/errors")
+	{
+		errors.PUT("/:code", admin.PutErrorArticle)
+		errors.DELETE("/:code", admin.DeleteErrorArticle)
 	}
 }
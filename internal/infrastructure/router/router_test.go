@@ -1,11 +1,23 @@
 package router_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
-
+	"time"
+
+	"order-placement-system/internal/adapter/handler"
+	"order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/internal/infrastructure/events"
+	"order-placement-system/internal/infrastructure/knowledgebase"
+	"order-placement-system/internal/infrastructure/metrics"
+	"order-placement-system/internal/infrastructure/middleware"
 	"order-placement-system/internal/infrastructure/router"
+	"order-placement-system/internal/infrastructure/runtimerules"
 	mockHandler "order-placement-system/internal/mock/handler"
 
 	"github.com/gin-gonic/gin"
@@ -17,6 +29,14 @@ func init() {
 	gin.SetMode(gin.TestMode)
 }
 
+func adminHandler(deps handler.AdminHandlerDeps) handler.AdminHandlerInterface {
+	return handler.NewAdminHandler(nil, presenter.NewOrderPresenter(), presenter.NewReportPresenter(), deps)
+}
+
+func reportHandler(deps handler.ReportHandlerDeps) handler.ReportHandlerInterface {
+	return handler.NewReportHandler(presenter.NewReportPresenter(), deps)
+}
+
 func TestSetupHealthCheck(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -53,6 +73,312 @@ func TestSetupHealthCheck(t *testing.T) {
 	}
 }
 
+func TestSetupMetrics(t *testing.T) {
+	engine := gin.New()
+	priceSplits := metrics.NewPriceSplitMetrics(5, 5)
+	priceSplits.RecordBatch([]*entity.PriceSplit{{No: 1, Remainder: 0.01}})
+
+	batchWarnings := metrics.NewBatchWarningMetrics()
+	batchWarnings.RecordWarnings(10, []*entity.BatchWarning{entity.NewBatchWarning(entity.WarningBundleRatio, 0.9, 0.5)})
+
+	business := metrics.NewBusinessMetrics(nil)
+	business.RecordBusinessBatch(entity.NewProcessBatch(nil, &entity.ProcessOptions{TenantId: "acme"}))
+
+	router.SetupMetrics(engine, reportHandler(handler.ReportHandlerDeps{PriceSplits: priceSplits, BatchWarnings: batchWarnings, Business: business}))
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics/price-splits", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"withRemainder":1`)
+	assert.Contains(t, w.Body.String(), `"worstOffenders"`)
+
+	req, err = http.NewRequest(http.MethodGet, "/metrics/batch-warnings", nil)
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"flaggedBatches":1`)
+	assert.Contains(t, w.Body.String(), `"BUNDLE_RATIO":1`)
+
+	req, err = http.NewRequest(http.MethodGet, "/metrics/business", nil)
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, presenter.OpenMetricsContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `orders_cleaned_total{tenant="acme"} 0`)
+	assert.True(t, strings.HasSuffix(w.Body.String(), "# EOF\n"))
+}
+
+func TestParserGarbageTokensV1Routes(t *testing.T) {
+	engine := gin.New()
+	garbageTokens := metrics.NewGarbageTokenMetrics(true, 1)
+	garbageTokens.RecordGarbageTokens([]string{"##"})
+
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{GarbageTokens: garbageTokens}))
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/admin/parser/garbage-tokens", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"learning":true,"tokens":[{"token":"##","count":1}],"proposals":["##"]}`, w.Body.String())
+}
+
+func TestCatalogGapsV1Routes(t *testing.T) {
+	engine := gin.New()
+	gaps := metrics.NewCatalogGapMetrics()
+	gaps.RecordCatalogGaps([]*entity.CatalogGap{
+		{Kind: entity.CatalogGapTexture, Value: "SATIN", No: 1, PlatformProductId: "FG0A-SATIN-OPPOA3"},
+		{Kind: entity.CatalogGapFilmType, Value: "XX0A", No: 2, PlatformProductId: "XX0A-CLEAR-OPPOA3"},
+	})
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{CatalogGaps: gaps, RuntimeRules: runtimerules.NewRuntimeRules(nil)}))
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{"List gaps", http.MethodGet, "/api/v1/admin/catalog-gaps", "", http.StatusOK, `"value":"SATIN"`},
+		{"Alias of an unknown texture", http.MethodPost, "/api/v1/admin/catalog-gaps/texture-aliases", `{"alias":"satin","texture":"SHINY"}`, http.StatusBadRequest, ""},
+		{"Alias without texture", http.MethodPost, "/api/v1/admin/catalog-gaps/texture-aliases", `{"alias":"satin"}`, http.StatusBadRequest, ""},
+		{"Alias resolves the gap", http.MethodPost, "/api/v1/admin/catalog-gaps/texture-aliases", `{"alias":"satin","texture":"MATTE"}`, http.StatusCreated, `"SATIN":"MATTE"`},
+		{"Dismiss unknown kind", http.MethodDelete, "/api/v1/admin/catalog-gaps/model/XX0A", "", http.StatusBadRequest, ""},
+		{"Dismiss gap", http.MethodDelete, "/api/v1/admin/catalog-gaps/film-type/XX0A", "", http.StatusNoContent, ""},
+		{"Dismiss gap twice", http.MethodDelete, "/api/v1/admin/catalog-gaps/film-type/XX0A", "", http.StatusNotFound, ""},
+		{"Nothing left", http.MethodGet, "/api/v1/admin/catalog-gaps", "", http.StatusOK, `{"gaps":[]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectBody)
+		})
+	}
+}
+
+func TestBatchUsageV1Routes(t *testing.T) {
+	usage := metrics.NewBatchUsageMetrics()
+	for i := 1; i <= 3; i++ {
+		usage.RecordBatchUsage(entity.NewBatchUsage("acme", i, time.Duration(i)*time.Millisecond, 0, 0, time.Now()))
+	}
+
+	engine := gin.New()
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{BatchUsage: usage}))
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedRows   []int
+	}{
+		{name: "Default top", query: "", expectedStatus: http.StatusOK, expectedRows: []int{3, 2, 1}},
+		{name: "Top heaviest", query: "?top=2", expectedStatus: http.StatusOK, expectedRows: []int{3, 2}},
+		{name: "Zero top", query: "?top=0", expectedStatus: http.StatusBadRequest},
+		{name: "Not a number", query: "?top=all", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/batch-usage"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var snapshot metrics.BatchUsageSnapshot
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+			assert.Equal(t, 3, snapshot.Batches)
+			rows := make([]int, 0, len(snapshot.Heaviest))
+			for _, batch := range snapshot.Heaviest {
+				rows = append(rows, batch.Rows)
+			}
+			assert.Equal(t, tt.expectedRows, rows)
+		})
+	}
+}
+
+func TestClientsV1Routes(t *testing.T) {
+	clients := metrics.NewClientMetrics(time.Now)
+	clients.Record(&metrics.ClientRequest{Tenant: "acme", Client: "shopify", Status: http.StatusOK})
+	clients.Record(&metrics.ClientRequest{Tenant: "beta", Client: "lazada", Status: http.StatusOK})
+
+	engine := gin.New()
+	engine.Use(middleware.AdminAuthorization(middleware.AdminKeys{
+		"viewer-key": {Role: middleware.RoleViewer},
+		"acme-key":   {Role: middleware.RoleTenantAdmin, Tenant: "acme"},
+	}))
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{Clients: clients}))
+
+	tests := []struct {
+		name    string
+		key     string
+		clients []string
+	}{
+		{"Viewer sees every tenant", "viewer-key", []string{"lazada", "shopify"}},
+		{"Tenant-admin sees its tenant only", "acme-key", []string{"shopify"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/admin/clients", nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body struct {
+				Clients []*metrics.ClientCount `json:"clients"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			var names []string
+			for _, count := range body.Clients {
+				names = append(names, count.Client)
+			}
+			assert.ElementsMatch(t, tt.clients, names)
+		})
+	}
+}
+
+func TestDashboardV1Routes(t *testing.T) {
+	engine := gin.New()
+	loadedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{Dashboard: metrics.NewDashboardMetrics(loadedAt, time.Now)}))
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/admin/dashboard", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"inFlightBatches": 0,
+		"rowsPerSecond": 0,
+		"responsesByStatus": {},
+		"errorRate": 0,
+		"queueDepths": {},
+		"configLoadedAt": "2025-01-01T00:00:00Z",
+		"recentDroppedRows": []
+	}`, w.Body.String())
+}
+
+func TestWebhookV1Routes(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	webhook := events.NewWebhook(receiver.URL, time.Second, 10)
+	defer webhook.Close()
+	webhook.Deliver(&entity.DomainEvent{Id: "evt-1", Name: entity.EventOrderBatchProcessed})
+	assert.Eventually(t, func() bool { return len(webhook.Deliveries(true)) == 1 }, time.Second, 10*time.Millisecond)
+
+	engine := gin.New()
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{Webhook: webhook}))
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectStatus int
+		expectBody   string
+	}{
+		{"List failed deliveries", http.MethodGet, "/api/v1/admin/webhook/deliveries?status=failed", http.StatusOK, `"id":"1"`},
+		{"Unknown status filter", http.MethodGet, "/api/v1/admin/webhook/deliveries?status=sent", http.StatusBadRequest, ""},
+		{"Redeliver", http.MethodPost, "/api/v1/admin/webhook/deliveries/1/redeliver", http.StatusAccepted, ""},
+		{"Redeliver unknown delivery", http.MethodPost, "/api/v1/admin/webhook/deliveries/42/redeliver", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectBody)
+		})
+	}
+}
+
+func TestTexturePrioritiesV1Routes(t *testing.T) {
+	engine := gin.New()
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{RuntimeRules: runtimerules.NewRuntimeRules(nil)}))
+
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{"Current priorities", http.MethodGet, "", http.StatusOK, `{"CLEAR":1,"MATTE":2,"PRIVACY":3}`},
+		{"Duplicate priority", http.MethodPut, `{"CLEAR":1,"MATTE":2,"PRIVACY":2}`, http.StatusBadRequest, ""},
+		{"Not JSON", http.MethodPut, `CLEAR=1`, http.StatusBadRequest, ""},
+		{"Reorder", http.MethodPut, `{"CLEAR":1,"MATTE":3,"PRIVACY":2}`, http.StatusOK, `{"CLEAR":1,"MATTE":3,"PRIVACY":2}`},
+		{"Reorder applies at once", http.MethodGet, "", http.StatusOK, `{"CLEAR":1,"MATTE":3,"PRIVACY":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, "/api/v1/admin/texture-priorities", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectBody)
+		})
+	}
+}
+
+func TestParserLintV1Routes(t *testing.T) {
+	engine := gin.New()
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{
+		Rules: &entity.RuleSet{PlatformPrefixes: []string{"%20--", "--"}, AccessoryPattern: `^ACC-`},
+	}))
+
+	tests := []struct {
+		name         string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{"Running rule set", "", http.StatusOK, `{"passed":true,"findings":[]}`},
+		{"Shadowed prefix", `{"platformPrefixes":["--","--%20x"]}`, http.StatusOK, `"rule":"platformPrefixes[1]"`},
+		{"Broken pattern fails", `{"accessoryPattern":"^ACC-("}`, http.StatusOK, `"passed":false`},
+		{"Not JSON", `prefixes=--`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/parser/lint", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectBody)
+		})
+	}
+}
+
 func TestOrderPlacementV1Routes(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -73,6 +399,18 @@ func TestOrderPlacementV1Routes(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:           "POST /api/v1/orders/process/single should call ProcessSingleOrder",
+			method:         http.MethodPost,
+			path:           "/api/v1/orders/process/single",
+			expectedStatus: http.StatusOK,
+			setupMock: func(m *mockHandler.OrderHandlerInterface) {
+				m.On("ProcessSingleOrder", mock.AnythingOfType("*gin.Context")).Return().Run(func(args mock.Arguments) {
+					c := args.Get(0).(*gin.Context)
+					c.JSON(http.StatusOK, gin.H{"message": "order processed"})
+				})
+			},
+		},
 		{
 			name:           "GET /api/v1/orders/process should return 404 (method not allowed)",
 			method:         http.MethodGet,
@@ -456,8 +794,9 @@ func TestRouteRegistration(t *testing.T) {
 		routes := engine.Routes()
 
 		expectedRoutes := map[string]string{
-			"GET /health":                 "health check endpoint",
-			"POST /api/v1/orders/process": "process orders endpoint",
+			"GET /health":                        "health check endpoint",
+			"POST /api/v1/orders/process":        "process orders endpoint",
+			"POST /api/v1/orders/process/single": "process single order endpoint",
 		}
 
 		for expectedRoute, description := range expectedRoutes {
@@ -475,3 +814,97 @@ func TestRouteRegistration(t *testing.T) {
 		assert.GreaterOrEqual(t, len(routes), len(expectedRoutes), "Should register at least %d routes", len(expectedRoutes))
 	})
 }
+
+func TestSetupDocs(t *testing.T) {
+	engine := gin.New()
+	router.SetupDocs(engine, reportHandler(handler.ReportHandlerDeps{PricePolicy: value_object.DefaultPricePolicy()}))
+
+	tests := []struct {
+		path         string
+		expectedBody string
+	}{
+		{
+			path: "/docs/complementary-ordering",
+			expectedBody: `{
+				"groupedBy": "parentNo",
+				"order": ["WIPING-CLOTH", "CLEAR-CLEANNER", "MATTE-CLEANNER", "PRIVACY-CLEANNER", "CARE-KIT-CLEAR", "CARE-KIT-MATTE", "CARE-KIT-PRIVACY"],
+				"unlisted": "after the listed items, in the order their rule produced them"
+			}`,
+		},
+		{
+			path:         "/docs/price-policy",
+			expectedBody: `{"currency": "THB", "minorUnits": 2, "epsilon": 0.005}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestErrorArticlesV1Routes(t *testing.T) {
+	engine := gin.New()
+	articles := knowledgebase.NewErrorArticles(time.Now, entity.DefaultErrorArticles())
+	router.ErrorArticlesV1Routes(engine, reportHandler(handler.ReportHandlerDeps{ErrorArticles: articles}))
+	router.AdminV1Routes(engine, adminHandler(handler.AdminHandlerDeps{ErrorArticles: articles}))
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{"List articles", http.MethodGet, "/api/v1/errors", "", http.StatusOK, `"code":"system_error"`},
+		{"Search articles", http.MethodGet, "/api/v1/errors?q=timeout", "", http.StatusOK, `"code":"system_error"`},
+		{"Search without match", http.MethodGet, "/api/v1/errors?q=nothing-like-this", "", http.StatusOK, `{"errors":[]}`},
+		{"Get article", http.MethodGet, "/api/v1/errors/CATALOG_MISMATCH", "", http.StatusOK, `"code":"catalog_mismatch"`},
+		{"Get unknown article", http.MethodGet, "/api/v1/errors/UNKNOWN", "", http.StatusNotFound, ""},
+		{"Create article", http.MethodPut, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", `{"title":"Bundle over the limit"}`, http.StatusCreated, `"code":"BUNDLE_TOO_LARGE"`},
+		{"Replace article", http.MethodPut, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", `{"code":"BUNDLE_TOO_LARGE","title":"Bundle too large"}`, http.StatusOK, `"title":"Bundle too large"`},
+		{"Code differs from path", http.MethodPut, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", `{"code":"OTHER","title":"Other"}`, http.StatusBadRequest, ""},
+		{"Article without title", http.MethodPut, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", `{}`, http.StatusBadRequest, ""},
+		{"Delete article", http.MethodDelete, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", "", http.StatusNoContent, ""},
+		{"Delete article twice", http.MethodDelete, "/api/v1/admin/errors/BUNDLE_TOO_LARGE", "", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectBody)
+		})
+	}
+}
+
+func TestAdminV1Routes(t *testing.T) {
+	engine := gin.New()
+	mockAdminHandler := mockHandler.NewAdminHandlerInterface(t)
+
+	mockAdminHandler.On("VerifyConfig", mock.AnythingOfType("*gin.Context")).Return().Run(func(args mock.Arguments) {
+		c := args.Get(0).(*gin.Context)
+		c.JSON(http.StatusOK, gin.H{"passed": true})
+	})
+
+	router.AdminV1Routes(engine, mockAdminHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/admin/config/verify", nil)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
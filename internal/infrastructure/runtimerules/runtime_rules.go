@@ -0,0 +1,111 @@
+package runtimerules
+
+import (
+	"sync"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// texture aliases kept, a new alias past it is refused
+const maxTextureAliases = 200
+
+// RuntimeRules holds the rules changed through the admin API. They are kept
+// in memory, changes are lost on restart
+type RuntimeRules struct {
+	mu                sync.RWMutex
+	texturePriorities value_object.TexturePriorities
+	textureAliases    value_object.TextureAliases
+	generation        uint64
+}
+
+// texturePriorities are the ones it starts with, nil is the defaults
+func NewRuntimeRules(texturePriorities value_object.TexturePriorities) *RuntimeRules {
+	if texturePriorities == nil {
+		texturePriorities = value_object.DefaultTexturePriorities()
+	}
+	return &RuntimeRules{
+		texturePriorities: texturePriorities.Clone(),
+		textureAliases:    value_object.TextureAliases{},
+	}
+}
+
+// moves on with every change, 0 until the first one
+func (r *RuntimeRules) Generation() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.generation
+}
+
+// a copy of the priorities in effect
+func (r *RuntimeRules) TexturePriorities() value_object.TexturePriorities {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.texturePriorities.Clone()
+}
+
+// replaces every priority at once, invalid priorities leave the current ones
+func (r *RuntimeRules) SetTexturePriorities(priorities value_object.TexturePriorities) error {
+	if err := priorities.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.texturePriorities = priorities.Clone()
+	r.generation++
+	log.Infof("texture priorities updated", log.AtoS("priorities", r.texturePriorities))
+	return nil
+}
+
+// a copy of the aliases added so far
+func (r *RuntimeRules) TextureAliases() value_object.TextureAliases {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make(value_object.TextureAliases, len(r.textureAliases))
+	for alias, texture := range r.textureAliases {
+		aliases[alias] = texture
+	}
+	return aliases
+}
+
+// AddTextureAlias makes alias read as texture for every product code parsed
+// after it returns, see value_object.NewTextureAlias. An alias already added
+// is pointed at texture again
+func (r *RuntimeRules) AddTextureAlias(alias string, texture value_object.Texture) error {
+	alias, err := value_object.NewTextureAlias(alias, texture)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.textureAliases[alias]; !exists && len(r.textureAliases) >= maxTextureAliases {
+		log.Errorf("runtime texture aliases are full", log.S("alias", alias))
+		return errors.ErrUnprocessableEntity
+	}
+	r.textureAliases[alias] = texture
+	r.generation++
+
+	log.Infof("texture alias added", log.S("alias", alias), log.S("texture", texture.String()))
+	return nil
+}
+
+// the parser asks for every texture its configured aliases do not know
+func (r *RuntimeRules) ResolveTextureAlias(s string) (value_object.Texture, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.textureAliases.Resolve(s)
+}
+
+func (r *RuntimeRules) Overrides() *entity.ProcessOptions {
+	return &entity.ProcessOptions{TexturePriorities: r.TexturePriorities()}
+}
@@ -0,0 +1,88 @@
+package runtimerules_test
+
+import (
+	"fmt"
+	"testing"
+
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/internal/infrastructure/runtimerules"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func TestRuntimeRules_TexturePriorities(t *testing.T) {
+	rules := runtimerules.NewRuntimeRules(nil)
+	assert.Equal(t, value_object.DefaultTexturePriorities(), rules.TexturePriorities())
+
+	require.NoError(t, rules.SetTexturePriorities(value_object.TexturePriorities{"CLEAR": 3, "MATTE": 1, "PRIVACY": 2}))
+	assert.Equal(t, 1, rules.TexturePriorities()[value_object.TextureMatte])
+	assert.Equal(t, 3, rules.Overrides().TexturePriorities[value_object.TextureClear])
+
+	// callers get copies
+	rules.TexturePriorities()[value_object.TextureClear] = 99
+	assert.Equal(t, 3, rules.TexturePriorities()[value_object.TextureClear])
+
+	assert.Error(t, rules.SetTexturePriorities(value_object.TexturePriorities{"CLEAR": 1}))
+	assert.Equal(t, 1, rules.TexturePriorities()[value_object.TextureMatte], "invalid priorities keep the current ones")
+}
+
+func TestRuntimeRules_StartsWithConfiguredPriorities(t *testing.T) {
+	configured := value_object.TexturePriorities{"CLEAR": 2, "MATTE": 1, "PRIVACY": 3}
+	rules := runtimerules.NewRuntimeRules(configured)
+
+	configured[value_object.TextureClear] = 99
+	assert.Equal(t, 2, rules.TexturePriorities()[value_object.TextureClear])
+}
+
+func TestRuntimeRules_TextureAliases(t *testing.T) {
+	rules := runtimerules.NewRuntimeRules(nil)
+
+	require.NoError(t, rules.AddTextureAlias(" silk ", value_object.TextureMatte))
+	texture, ok := rules.ResolveTextureAlias("Silk")
+	assert.True(t, ok)
+	assert.Equal(t, value_object.TextureMatte, texture)
+	assert.Equal(t, value_object.TextureAliases{"SILK": value_object.TextureMatte}, rules.TextureAliases())
+
+	assert.Error(t, rules.AddTextureAlias("clear", value_object.TextureMatte), "a texture name is not an alias")
+	assert.Error(t, rules.AddTextureAlias("SATIN", value_object.Texture("SHINY")))
+	_, ok = rules.ResolveTextureAlias("SATIN")
+	assert.False(t, ok)
+
+	// callers get copies
+	rules.TextureAliases()["SATIN"] = value_object.TextureClear
+	_, ok = rules.ResolveTextureAlias("SATIN")
+	assert.False(t, ok)
+}
+
+func TestRuntimeRules_TextureAliasesAreBounded(t *testing.T) {
+	rules := runtimerules.NewRuntimeRules(nil)
+
+	var err error
+	for i := 0; err == nil; i++ {
+		err = rules.AddTextureAlias(fmt.Sprintf("ALIAS-%d", i), value_object.TextureClear)
+	}
+	assert.ErrorIs(t, err, errors.ErrUnprocessableEntity)
+
+	full := len(rules.TextureAliases())
+	require.NoError(t, rules.AddTextureAlias("ALIAS-0", value_object.TextureMatte), "an alias already added can be repointed")
+	assert.Len(t, rules.TextureAliases(), full)
+}
+
+func TestRuntimeRules_Generation(t *testing.T) {
+	rules := runtimerules.NewRuntimeRules(nil)
+	assert.Equal(t, uint64(0), rules.Generation())
+
+	require.NoError(t, rules.SetTexturePriorities(value_object.TexturePriorities{"CLEAR": 3, "MATTE": 1, "PRIVACY": 2}))
+	require.NoError(t, rules.AddTextureAlias("SILK", value_object.TextureMatte))
+	assert.Equal(t, uint64(2), rules.Generation())
+
+	assert.Error(t, rules.AddTextureAlias("SATIN", value_object.Texture("SHINY")))
+	assert.Equal(t, uint64(2), rules.Generation(), "rejected changes keep the generation")
+}
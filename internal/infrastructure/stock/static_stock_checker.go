@@ -0,0 +1,22 @@
+package stock
+
+// StaticStockChecker treats the products it was given as out of stock and
+// everything else as in stock. It is read once at startup, a stand-in until
+// a live inventory feed is wired in
+type StaticStockChecker struct {
+	outOfStock map[string]bool
+}
+
+func NewStaticStockChecker(outOfStock []string) *StaticStockChecker {
+	checker := &StaticStockChecker{
+		outOfStock: make(map[string]bool, len(outOfStock)),
+	}
+	for _, productId := range outOfStock {
+		checker.outOfStock[productId] = true
+	}
+	return checker
+}
+
+func (c *StaticStockChecker) InStock(productId string) bool {
+	return !c.outOfStock[productId]
+}
@@ -0,0 +1,17 @@
+package stock_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/infrastructure/stock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticStockChecker_InStock(t *testing.T) {
+	checker := stock.NewStaticStockChecker([]string{"PRIVACY-CLEANNER"})
+
+	assert.False(t, checker.InStock("PRIVACY-CLEANNER"))
+	assert.True(t, checker.InStock("CLEAR-CLEANNER"))
+	assert.True(t, stock.NewStaticStockChecker(nil).InStock("PRIVACY-CLEANNER"))
+}
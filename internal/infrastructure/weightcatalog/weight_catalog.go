@@ -0,0 +1,30 @@
+package weightcatalog
+
+import (
+	"encoding/json"
+	"os"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/pkg/log"
+)
+
+// reads {"<productId or modelId>": <grams per unit>} from a JSON file
+func LoadWeightCatalog(path string) (entity.WeightCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Errorf("failed to read weight catalog", log.S("path", path), log.E(err))
+		return nil, err
+	}
+
+	var catalog entity.WeightCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		log.Errorf("failed to decode weight catalog", log.S("path", path), log.E(err))
+		return nil, err
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,48 @@
+package weightcatalog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/infrastructure/weightcatalog"
+	"order-placement-system/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func TestLoadWeightCatalog(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Valid file", func(t *testing.T) {
+		catalog, err := weightcatalog.LoadWeightCatalog(write("valid.json", `{"IPHONE16PROMAX": 12, "WIPING-CLOTH": 5}`))
+		require.NoError(t, err)
+		assert.Equal(t, entity.WeightCatalog{"IPHONE16PROMAX": 12, "WIPING-CLOTH": 5}, catalog)
+	})
+
+	t.Run("Invalid weight", func(t *testing.T) {
+		_, err := weightcatalog.LoadWeightCatalog(write("zero.json", `{"WIPING-CLOTH": 0}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := weightcatalog.LoadWeightCatalog(write("broken.json", `{"WIPING-CLOTH": "5g"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := weightcatalog.LoadWeightCatalog(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+	})
+}
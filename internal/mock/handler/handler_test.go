@@ -0,0 +1,14 @@
+package handler_test
+
+import (
+	adapterHandler "order-placement-system/internal/adapter/handler"
+	"order-placement-system/internal/mock/handler"
+)
+
+// fails the build when an interface changed and the mocks were not generated
+// again
+var (
+	_ adapterHandler.OrderHandlerInterface  = (*handler.OrderHandlerInterface)(nil)
+	_ adapterHandler.AdminHandlerInterface  = (*handler.AdminHandlerInterface)(nil)
+	_ adapterHandler.ReportHandlerInterface = (*handler.ReportHandlerInterface)(nil)
+)
@@ -0,0 +1,13 @@
+package presenter_test
+
+import (
+	adapterPresenter "order-placement-system/internal/adapter/presenter"
+	"order-placement-system/internal/mock/presenter"
+)
+
+// fails the build when an interface changed and the mocks were not generated
+// again
+var (
+	_ adapterPresenter.OrderPresenter  = (*presenter.OrderPresenter)(nil)
+	_ adapterPresenter.ReportPresenter = (*presenter.ReportPresenter)(nil)
+)
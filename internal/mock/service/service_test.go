@@ -0,0 +1,14 @@
+package service_test
+
+import (
+	domainService "order-placement-system/internal/domain/service"
+	"order-placement-system/internal/mock/service"
+)
+
+// fails the build when an interface changed and the mocks were not generated
+// again
+var (
+	_ domainService.ProductParser        = (*service.ProductParser)(nil)
+	_ domainService.PriceCalculator      = (*service.PriceCalculator)(nil)
+	_ domainService.TextureAliasResolver = (*service.TextureAliasResolver)(nil)
+)
@@ -43,6 +43,66 @@ func (_m *OrderProcessorUseCase) ProcessOrders(inputOrders []*entity.InputOrder)
 	return r0, r1
 }
 
+// ProcessOrdersWithOptions provides a mock function with given fields: inputOrders, options
+func (_m *OrderProcessorUseCase) ProcessOrdersWithOptions(inputOrders []*entity.InputOrder, options *entity.ProcessOptions) ([]*entity.CleanedOrder, error) {
+	ret := _m.Called(inputOrders, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessOrdersWithOptions")
+	}
+
+	var r0 []*entity.CleanedOrder
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]*entity.InputOrder, *entity.ProcessOptions) ([]*entity.CleanedOrder, error)); ok {
+		return rf(inputOrders, options)
+	}
+	if rf, ok := ret.Get(0).(func([]*entity.InputOrder, *entity.ProcessOptions) []*entity.CleanedOrder); ok {
+		r0 = rf(inputOrders, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CleanedOrder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]*entity.InputOrder, *entity.ProcessOptions) error); ok {
+		r1 = rf(inputOrders, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenumberOrders provides a mock function with given fields: cleanedOrders, options
+func (_m *OrderProcessorUseCase) RenumberOrders(cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) ([]*entity.CleanedOrder, error) {
+	ret := _m.Called(cleanedOrders, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenumberOrders")
+	}
+
+	var r0 []*entity.CleanedOrder
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]*entity.CleanedOrder, *entity.ProcessOptions) ([]*entity.CleanedOrder, error)); ok {
+		return rf(cleanedOrders, options)
+	}
+	if rf, ok := ret.Get(0).(func([]*entity.CleanedOrder, *entity.ProcessOptions) []*entity.CleanedOrder); ok {
+		r0 = rf(cleanedOrders, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CleanedOrder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]*entity.CleanedOrder, *entity.ProcessOptions) error); ok {
+		r1 = rf(cleanedOrders, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewOrderProcessorUseCase creates a new instance of OrderProcessorUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOrderProcessorUseCase(t interface {
@@ -0,0 +1,34 @@
+package usecases_test
+
+import (
+	"order-placement-system/internal/mock/usecases"
+	usecase "order-placement-system/internal/usecases/interfaces"
+)
+
+// fails the build when an interface changed and the mocks were not generated
+// again
+var (
+	_ usecase.OrderProcessorUseCase   = (*usecases.OrderProcessorUseCase)(nil)
+	_ usecase.ProcessStage            = (*usecases.ProcessStage)(nil)
+	_ usecase.ComplementaryCalculator = (*usecases.ComplementaryCalculator)(nil)
+	_ usecase.PriceSplitRecorder      = (*usecases.PriceSplitRecorder)(nil)
+	_ usecase.BusinessRecorder        = (*usecases.BusinessRecorder)(nil)
+	_ usecase.EventPublisher          = (*usecases.EventPublisher)(nil)
+	_ usecase.StockChecker            = (*usecases.StockChecker)(nil)
+	_ usecase.PriceList               = (*usecases.PriceList)(nil)
+	_ usecase.BatchUsageRecorder      = (*usecases.BatchUsageRecorder)(nil)
+	_ usecase.BatchInspector          = (*usecases.BatchInspector)(nil)
+	_ usecase.BatchWarningRecorder    = (*usecases.BatchWarningRecorder)(nil)
+	_ usecase.ConfigVerifier          = (*usecases.ConfigVerifier)(nil)
+	_ usecase.GarbageTokenRecorder    = (*usecases.GarbageTokenRecorder)(nil)
+	_ usecase.CatalogGapRecorder      = (*usecases.CatalogGapRecorder)(nil)
+	_ usecase.ReportSource            = (*usecases.ReportSource)(nil)
+	_ usecase.TenantReportSource      = (*usecases.TenantReportSource)(nil)
+	_ usecase.HeaviestReportSource    = (*usecases.HeaviestReportSource)(nil)
+	_ usecase.CatalogGapReport        = (*usecases.CatalogGapReport)(nil)
+	_ usecase.DeliveryLog             = (*usecases.DeliveryLog)(nil)
+	_ usecase.OpenMetricsSource       = (*usecases.OpenMetricsSource)(nil)
+	_ usecase.ErrorArticleStore       = (*usecases.ErrorArticleStore)(nil)
+	_ usecase.RuntimeRules            = (*usecases.RuntimeRules)(nil)
+	_ usecase.ConfigGeneration        = (*usecases.ConfigGeneration)(nil)
+)
@@ -0,0 +1,405 @@
+package implementation
+
+import (
+	"math"
+	"strings"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/service"
+	"order-placement-system/internal/domain/value_object"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// every product code starts with its film type, FG0A, FG05...
+const productCodeStart = "FG"
+
+// any model does, it only completes the code findCatalogGaps probes with
+const catalogProbeModel = "PROBE"
+
+type batchInspector struct {
+	productParser service.ProductParser
+	priceList     usecase.PriceList
+	thresholds    entity.BatchWarningThresholds
+	recorder      usecase.BatchWarningRecorder
+	garbageTokens usecase.GarbageTokenRecorder
+	catalogGaps   usecase.CatalogGapRecorder
+	defaults      *entity.ProcessOptions
+}
+
+type BatchInspectorOptions struct {
+	// prices the cleaned lines, the unit price checks are skipped without it
+	PriceList usecase.PriceList
+	// zero thresholds disable their check
+	Thresholds entity.BatchWarningThresholds
+	// receives the warnings of every batch
+	Recorder usecase.BatchWarningRecorder
+	// receives the unknown leading tokens of every batch, however small
+	GarbageTokens usecase.GarbageTokenRecorder
+	// receives the unknown film types and textures of every batch
+	CatalogGaps usecase.CatalogGapRecorder
+	// the options passed to Inspect are laid over them, usually the ones the
+	// order processor runs with, so a film texture matrix or strictness set
+	// for the service applies without every request repeating it
+	Defaults *entity.ProcessOptions
+}
+
+func NewBatchInspector(
+	parser service.ProductParser,
+	options BatchInspectorOptions,
+) usecase.BatchInspector {
+	return &batchInspector{
+		productParser: parser,
+		priceList:     options.PriceList,
+		thresholds:    options.Thresholds,
+		recorder:      options.Recorder,
+		garbageTokens: options.GarbageTokens,
+		catalogGaps:   options.CatalogGaps,
+		defaults:      options.Defaults,
+	}
+}
+
+func (i *batchInspector) Inspect(
+	inputOrders []*entity.InputOrder,
+	cleanedOrders []*entity.CleanedOrder,
+	options *entity.ProcessOptions,
+) []*entity.BatchWarning {
+	if i.defaults != nil {
+		options = i.defaults.WithOverrides(options)
+	}
+
+	if i.garbageTokens != nil {
+		if tokens := i.leadingGarbage(inputOrders); len(tokens) > 0 {
+			i.garbageTokens.RecordGarbageTokens(tokens)
+		}
+	}
+	if i.catalogGaps != nil {
+		if gaps := i.findCatalogGaps(inputOrders); len(gaps) > 0 {
+			i.catalogGaps.RecordCatalogGaps(gaps)
+		}
+	}
+
+	substitution := i.inspectSubstitutions(cleanedOrders)
+	outlier := i.inspectUnitPriceOutliers(cleanedOrders, options)
+	capped := i.inspectCaps(cleanedOrders)
+	incompatible := i.inspectTextures(cleanedOrders, options)
+	rewritten := i.inspectRewrites(cleanedOrders)
+
+	warnings := make([]*entity.BatchWarning, 0)
+	if len(inputOrders) == 0 || len(inputOrders) < i.thresholds.MinRows {
+		// substitutions, outliers, caps, incompatible textures and rewrites
+		// are reported whatever the size of the batch
+		if substitution != nil {
+			warnings = append(warnings, substitution)
+		}
+		if outlier != nil {
+			warnings = append(warnings, outlier)
+		}
+		if capped != nil {
+			warnings = append(warnings, capped)
+		}
+		if incompatible != nil {
+			warnings = append(warnings, incompatible)
+		}
+		return append(warnings, rewritten...)
+	}
+
+	if warning := i.inspectUnitPrices(cleanedOrders, options); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	if warning := i.inspectPrefixes(inputOrders); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	if warning := i.inspectBundles(inputOrders); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	if substitution != nil {
+		warnings = append(warnings, substitution)
+	}
+	if outlier != nil {
+		warnings = append(warnings, outlier)
+	}
+	if capped != nil {
+		warnings = append(warnings, capped)
+	}
+	if incompatible != nil {
+		warnings = append(warnings, incompatible)
+	}
+	warnings = append(warnings, rewritten...)
+
+	for _, warning := range warnings {
+		log.Warnf("suspicious batch",
+			log.S("code", string(warning.Code)),
+			log.AtoS("value", warning.Value),
+			log.AtoS("threshold", warning.Threshold))
+	}
+
+	if i.recorder != nil {
+		i.recorder.RecordWarnings(len(inputOrders), warnings)
+	}
+
+	return warnings
+}
+
+// mean of unit price / catalog price over main lines the catalog knows,
+// the value is how far that mean is from 1
+func (i *batchInspector) inspectUnitPrices(cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) *entity.BatchWarning {
+	if i.priceList == nil || i.thresholds.UnitPriceDeviation <= 0 {
+		return nil
+	}
+
+	tenantId := options.PriceListTenantId()
+
+	var ratioSum float64
+	matched := 0
+	for _, order := range cleanedOrders {
+		if !order.IsMainProduct() || order.PriceEnriched || order.UnitPrice == nil {
+			continue
+		}
+
+		catalogPrice, ok := i.priceList.UnitPrice(tenantId, order.CatalogId())
+		if !ok || !catalogPrice.IsPositive() {
+			continue
+		}
+
+		ratioSum += order.UnitPrice.Amount() / catalogPrice.Amount()
+		matched++
+	}
+
+	if matched == 0 {
+		return nil
+	}
+
+	deviation := math.Abs(ratioSum/float64(matched) - 1)
+	if deviation <= i.thresholds.UnitPriceDeviation {
+		return nil
+	}
+
+	return entity.NewBatchWarning(entity.WarningUnitPriceDeviation, deviation, i.thresholds.UnitPriceDeviation)
+}
+
+// main lines whose unit price over the price list price is outside the
+// tolerance band. The value is the ratio furthest out and the threshold the
+// bound it crossed
+func (i *batchInspector) inspectUnitPriceOutliers(cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) *entity.BatchWarning {
+	minRatio, maxRatio := i.thresholds.UnitPriceMinRatio, i.thresholds.UnitPriceMaxRatio
+	if i.priceList == nil || (minRatio <= 0 && maxRatio <= 0) {
+		return nil
+	}
+
+	tenantId := options.PriceListTenantId()
+
+	var warning *entity.BatchWarning
+	worst := 0.0
+	for _, order := range cleanedOrders {
+		if !order.IsMainProduct() || order.PriceEnriched || order.UnitPrice == nil {
+			continue
+		}
+
+		catalogPrice, ok := i.priceList.UnitPrice(tenantId, order.CatalogId())
+		if !ok || !catalogPrice.IsPositive() {
+			continue
+		}
+
+		ratio := order.UnitPrice.Amount() / catalogPrice.Amount()
+		threshold := 0.0
+		switch {
+		case maxRatio > 0 && ratio > maxRatio:
+			threshold = maxRatio
+		case minRatio > 0 && ratio < minRatio:
+			threshold = minRatio
+		default:
+			continue
+		}
+
+		log.Warnf("unit price outside tolerance band",
+			log.AtoS("order_no", order.No),
+			log.S("product_id", order.ProductId),
+			log.AtoS("unit_price", order.UnitPrice.Amount()),
+			log.AtoS("catalog_price", catalogPrice.Amount()))
+
+		if warning == nil {
+			warning = entity.NewBatchWarning(entity.WarningUnitPriceOutlier, ratio, threshold)
+		}
+		warning.Lines = append(warning.Lines, order.No)
+		// how far out, whichever side of the band
+		if distance := math.Abs(math.Log(ratio)); distance > worst {
+			worst = distance
+			warning.Value, warning.Threshold = ratio, threshold
+		}
+	}
+
+	return warning
+}
+
+func (i *batchInspector) inspectPrefixes(inputOrders []*entity.InputOrder) *entity.BatchWarning {
+	if i.thresholds.PrefixedRowRate <= 0 {
+		return nil
+	}
+
+	prefixed := 0
+	for _, order := range inputOrders {
+		if i.productParser.CleanPrefix(order.PlatformProductId) != order.PlatformProductId {
+			prefixed++
+		}
+	}
+
+	rate := float64(prefixed) / float64(len(inputOrders))
+	if rate <= i.thresholds.PrefixedRowRate {
+		return nil
+	}
+
+	return entity.NewBatchWarning(entity.WarningPrefixedRows, rate, i.thresholds.PrefixedRowRate)
+}
+
+// whatever is left in front of the product code once the known prefixes are
+// stripped, rows without a product code are skipped
+func (i *batchInspector) leadingGarbage(inputOrders []*entity.InputOrder) []string {
+	var tokens []string
+	for _, order := range inputOrders {
+		cleaned := i.productParser.CleanPrefix(order.PlatformProductId)
+		if start := strings.Index(cleaned, productCodeStart); start > 0 {
+			tokens = append(tokens, cleaned[:start])
+		}
+	}
+	return tokens
+}
+
+// product codes the parser rejects as a catalog mismatch, codes with garbage
+// in front of the film type are left to leadingGarbage
+func (i *batchInspector) findCatalogGaps(inputOrders []*entity.InputOrder) []*entity.CatalogGap {
+	var gaps []*entity.CatalogGap
+	for _, order := range inputOrders {
+		if order == nil {
+			continue
+		}
+
+		cleaned := i.productParser.CleanPrefix(order.PlatformProductId)
+		for _, part := range i.productParser.SplitBundle(cleaned) {
+			code, _, _ := i.productParser.ExtractQuantity(part)
+			_, _, err := i.productParser.ParseProductCode(code)
+			if err == nil || errors.CategoryOf(err) != errors.CategoryCatalogMismatch {
+				continue
+			}
+
+			filmType, rest, _ := strings.Cut(code, "-")
+			texture, _, _ := strings.Cut(rest, "-")
+			if strings.Index(filmType, productCodeStart) > 0 {
+				continue
+			}
+
+			gap := &entity.CatalogGap{
+				Kind:              entity.CatalogGapFilmType,
+				Value:             filmType,
+				No:                order.No,
+				PlatformProductId: order.PlatformProductId,
+			}
+			// the parser checks the film type first, a known one with a
+			// known texture tells the texture was the problem
+			if _, _, err := i.productParser.ParseProductCode(filmType + "-" + string(value_object.TextureClear) + "-" + catalogProbeModel); err == nil {
+				gap.Kind, gap.Value = entity.CatalogGapTexture, strings.ToUpper(texture)
+			}
+			gaps = append(gaps, gap)
+		}
+	}
+	return gaps
+}
+
+func (i *batchInspector) inspectBundles(inputOrders []*entity.InputOrder) *entity.BatchWarning {
+	if i.thresholds.BundleRatio <= 0 {
+		return nil
+	}
+
+	bundles := 0
+	for _, order := range inputOrders {
+		cleaned := i.productParser.CleanPrefix(order.PlatformProductId)
+		if len(i.productParser.SplitBundle(cleaned)) > 1 {
+			bundles++
+		}
+	}
+
+	ratio := float64(bundles) / float64(len(inputOrders))
+	if ratio <= i.thresholds.BundleRatio {
+		return nil
+	}
+
+	return entity.NewBatchWarning(entity.WarningBundleRatio, ratio, i.thresholds.BundleRatio)
+}
+
+// the value is how many units were shipped as a substitute
+func (i *batchInspector) inspectSubstitutions(cleanedOrders []*entity.CleanedOrder) *entity.BatchWarning {
+	units := 0
+	for _, order := range cleanedOrders {
+		if order.SubstitutedFor != "" {
+			units += order.Qty
+		}
+	}
+
+	if units == 0 {
+		return nil
+	}
+
+	return entity.NewBatchWarning(entity.WarningCleanerSubstituted, float64(units), 0)
+}
+
+// the value is how many main lines are in a texture their film type is not
+// made in. With the reject strictness those rows failed instead
+func (i *batchInspector) inspectTextures(cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) *entity.BatchWarning {
+	if !options.ChecksFilmTexture(entity.FilmTextureWarn) {
+		return nil
+	}
+
+	var warning *entity.BatchWarning
+	for _, order := range cleanedOrders {
+		if !order.IsMainProduct() || order.IsTextureCompatible(options.FilmTextureMatrixInEffect()) {
+			continue
+		}
+		if warning == nil {
+			warning = entity.NewBatchWarning(entity.WarningIncompatibleTexture, 0, 0)
+		}
+		warning.Value++
+		warning.Lines = append(warning.Lines, order.No)
+	}
+	return warning
+}
+
+// one warning for each way main lines were rewritten, the value is how many
+// lines were
+func (i *batchInspector) inspectRewrites(cleanedOrders []*entity.CleanedOrder) []*entity.BatchWarning {
+	var warnings []*entity.BatchWarning
+	for _, code := range entity.RewriteWarnings() {
+		var warning *entity.BatchWarning
+		for _, order := range cleanedOrders {
+			if !order.IsRewritten(code) {
+				continue
+			}
+			if warning == nil {
+				warning = entity.NewBatchWarning(code, 0, 0)
+			}
+			warning.Value++
+			warning.Lines = append(warning.Lines, order.No)
+		}
+		if warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// the value is how many free units the caps withheld, the lines are the main
+// lines of the rows they were withheld from
+func (i *batchInspector) inspectCaps(cleanedOrders []*entity.CleanedOrder) *entity.BatchWarning {
+	var warning *entity.BatchWarning
+	for _, order := range cleanedOrders {
+		if order.CappedQty <= 0 {
+			continue
+		}
+		if warning == nil {
+			warning = entity.NewBatchWarning(entity.WarningComplementaryCapped, 0, 0)
+		}
+		warning.Value += float64(order.CappedQty)
+		warning.Lines = append(warning.Lines, order.No)
+	}
+	return warning
+}
@@ -0,0 +1,413 @@
+package implementation_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	mockUsecases "order-placement-system/internal/mock/usecases"
+	"order-placement-system/internal/usecases/implementation"
+	"order-placement-system/pkg/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func inputRows(platformProductIds ...string) []*entity.InputOrder {
+	orders := make([]*entity.InputOrder, len(platformProductIds))
+	for i, id := range platformProductIds {
+		orders[i] = &entity.InputOrder{
+			No:                i + 1,
+			PlatformProductId: id,
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		}
+	}
+	return orders
+}
+
+func TestBatchInspector_Inspect(t *testing.T) {
+	thresholds := entity.BatchWarningThresholds{
+		MinRows:            2,
+		UnitPriceDeviation: 0.5,
+		PrefixedRowRate:    0.5,
+		BundleRatio:        0.5,
+	}
+	priceList := priceListStub{
+		"": {"FG0A-CLEAR-IPHONE16PROMAX": 50},
+	}
+
+	cleaned := func(unitPrice float64) []*entity.CleanedOrder {
+		return []*entity.CleanedOrder{
+			{
+				No:         1,
+				ProductId:  "FG0A-CLEAR-IPHONE16PROMAX",
+				MaterialId: "FG0A-CLEAR",
+				ModelId:    "IPHONE16PROMAX",
+				Qty:        1,
+				UnitPrice:  value_object.MustNewPrice(unitPrice),
+				TotalPrice: value_object.MustNewPrice(unitPrice),
+			},
+			{
+				No:         2,
+				ProductId:  "WIPING-CLOTH",
+				Qty:        1,
+				UnitPrice:  value_object.ZeroPrice(),
+				TotalPrice: value_object.ZeroPrice(),
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		input    []*entity.InputOrder
+		cleaned  []*entity.CleanedOrder
+		expected []entity.BatchWarningCode
+	}{
+		{
+			name:     "Clean batch",
+			input:    inputRows("FG0A-CLEAR-IPHONE16PROMAX", "FG0A-MATTE-IPHONE16PROMAX", "FG0A-CLEAR-OPPOA3"),
+			cleaned:  cleaned(50),
+			expected: nil,
+		},
+		{
+			name:     "Unit price far from catalog",
+			input:    inputRows("FG0A-CLEAR-IPHONE16PROMAX", "FG0A-MATTE-IPHONE16PROMAX"),
+			cleaned:  cleaned(5000),
+			expected: []entity.BatchWarningCode{entity.WarningUnitPriceDeviation},
+		},
+		{
+			name:     "Mostly prefixed rows",
+			input:    inputRows("--FG0A-CLEAR-IPHONE16PROMAX", "x2-3&FG0A-MATTE-IPHONE16PROMAX", "FG0A-CLEAR-OPPOA3"),
+			cleaned:  cleaned(50),
+			expected: []entity.BatchWarningCode{entity.WarningPrefixedRows},
+		},
+		{
+			name:     "Mostly bundles",
+			input:    inputRows("FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-OPPOA3", "FG0A-MATTE-IPHONE16PROMAX/FG0A-CLEAR-OPPOA3"),
+			cleaned:  cleaned(50),
+			expected: []entity.BatchWarningCode{entity.WarningBundleRatio},
+		},
+		{
+			name:     "Batch below minimum rows is not inspected",
+			input:    inputRows("--FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-OPPOA3"),
+			cleaned:  cleaned(5000),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+				PriceList:  priceList,
+				Thresholds: thresholds,
+			})
+
+			warnings := inspector.Inspect(tt.input, tt.cleaned, nil)
+
+			codes := make([]entity.BatchWarningCode, 0, len(warnings))
+			for _, warning := range warnings {
+				codes = append(codes, warning.Code)
+			}
+			assert.ElementsMatch(t, tt.expected, codes)
+		})
+	}
+}
+
+func TestBatchInspector_UnitPriceOutliers(t *testing.T) {
+	priceList := priceListStub{
+		"": {"FG0A-CLEAR-IPHONE16PROMAX": 80, "FG0A-MATTE-OPPOA3": 100},
+	}
+	line := func(no int, productId string, unitPrice float64, enriched bool) *entity.CleanedOrder {
+		return &entity.CleanedOrder{
+			No:            no,
+			ProductId:     productId,
+			MaterialId:    "FG0A-CLEAR",
+			ModelId:       "IPHONE16PROMAX",
+			Qty:           1,
+			UnitPrice:     value_object.MustNewPrice(unitPrice),
+			TotalPrice:    value_object.MustNewPrice(unitPrice),
+			PriceEnriched: enriched,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		minRatio  float64
+		maxRatio  float64
+		cleaned   []*entity.CleanedOrder
+		expectNil bool
+		expected  *entity.BatchWarning
+	}{
+		{
+			name:      "Within band",
+			minRatio:  0.5,
+			maxRatio:  2,
+			cleaned:   []*entity.CleanedOrder{line(1, "FG0A-CLEAR-IPHONE16PROMAX", 100, false)},
+			expectNil: true,
+		},
+		{
+			name:     "Total typed with extra zeros",
+			minRatio: 0.5,
+			maxRatio: 2,
+			cleaned: []*entity.CleanedOrder{
+				line(1, "FG0A-CLEAR-IPHONE16PROMAX", 8000, false),
+				line(2, "FG0A-MATTE-OPPOA3", 100, false),
+				line(3, "FG0A-MATTE-OPPOA3", 10, false),
+			},
+			expected: &entity.BatchWarning{Code: entity.WarningUnitPriceOutlier, Value: 100, Threshold: 2, Lines: []int{1, 3}},
+		},
+		{
+			name:     "Only the lower bound set",
+			minRatio: 0.5,
+			cleaned: []*entity.CleanedOrder{
+				line(1, "FG0A-CLEAR-IPHONE16PROMAX", 8000, false),
+				line(2, "FG0A-MATTE-OPPOA3", 10, false),
+			},
+			expected: &entity.BatchWarning{Code: entity.WarningUnitPriceOutlier, Value: 0.1, Threshold: 0.5, Lines: []int{2}},
+		},
+		{
+			name:      "Enriched and unlisted lines are not checked",
+			maxRatio:  2,
+			cleaned:   []*entity.CleanedOrder{line(1, "FG0A-CLEAR-IPHONE16PROMAX", 8000, true), line(2, "FG0A-PRIVACY-OPPOA3", 8000, false)},
+			expectNil: true,
+		},
+		{
+			name:      "Band disabled",
+			cleaned:   []*entity.CleanedOrder{line(1, "FG0A-CLEAR-IPHONE16PROMAX", 8000, false)},
+			expectNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+				PriceList:  priceList,
+				Thresholds: entity.BatchWarningThresholds{MinRows: 10, UnitPriceMinRatio: tt.minRatio, UnitPriceMaxRatio: tt.maxRatio},
+			})
+
+			warnings := inspector.Inspect(inputRows("FG0A-CLEAR-IPHONE16PROMAX"), tt.cleaned, nil)
+
+			if tt.expectNil {
+				assert.Empty(t, warnings)
+				return
+			}
+			require.Len(t, warnings, 1, "raised below the minimum batch size")
+			assert.Equal(t, tt.expected.Code, warnings[0].Code)
+			assert.InDelta(t, tt.expected.Value, warnings[0].Value, 1e-9)
+			assert.Equal(t, tt.expected.Threshold, warnings[0].Threshold)
+			assert.Equal(t, tt.expected.Lines, warnings[0].Lines)
+		})
+	}
+}
+
+func TestBatchInspector_SkuAffix(t *testing.T) {
+	priceList := priceListStub{"acme": {"FG0A-CLEAR-IPHONE16PROMAX": 10}}
+	batch := newStageBatch(&entity.ProcessOptions{
+		TenantId:   "acme",
+		SkuAffixes: entity.SkuAffixes{"acme": {Prefix: "TH-"}},
+	}, "FG0A-CLEAR-IPHONE16PROMAX")
+	runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageComplement, entity.StageNumber, entity.StageAffix)
+	require.Equal(t, "TH-FG0A-CLEAR-IPHONE16PROMAX", batch.CleanedOrders[0].ProductId)
+
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		PriceList:  priceList,
+		Thresholds: entity.BatchWarningThresholds{UnitPriceDeviation: 0.5, UnitPriceMaxRatio: 2},
+	})
+
+	warnings := inspector.Inspect(batch.InputOrders, batch.CleanedOrders, batch.Options)
+
+	var codes []entity.BatchWarningCode
+	for _, warning := range warnings {
+		codes = append(codes, warning.Code)
+	}
+	assert.ElementsMatch(t, []entity.BatchWarningCode{entity.WarningUnitPriceDeviation, entity.WarningUnitPriceOutlier}, codes,
+		"affixed lines are priced by their catalog id")
+}
+
+func TestBatchInspector_DisabledChecks(t *testing.T) {
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{})
+
+	warnings := inspector.Inspect(
+		inputRows("--FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-OPPOA3", "--FG0A-MATTE-IPHONE16PROMAX/FG0A-CLEAR-OPPOA3"),
+		nil,
+		nil,
+	)
+
+	assert.Empty(t, warnings)
+}
+
+func TestBatchInspector_CleanerSubstituted(t *testing.T) {
+	// the substitution is reported even below MinRows
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds: entity.BatchWarningThresholds{MinRows: 10},
+	})
+
+	warnings := inspector.Inspect(
+		inputRows("FG0A-PRIVACY-IPHONE16PROMAX", "FG0A-PRIVACY-OPPOA3"),
+		[]*entity.CleanedOrder{
+			{No: 3, ProductId: "WIPING-CLOTH", Qty: 2},
+			{No: 4, ProductId: "UNIVERSAL-CLEANNER", Qty: 2, SubstitutedFor: "PRIVACY-CLEANNER"},
+		},
+		nil,
+	)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, entity.NewBatchWarning(entity.WarningCleanerSubstituted, 2, 0), warnings[0])
+}
+
+func TestBatchInspector_ComplementaryCapped(t *testing.T) {
+	// caps are reported even below MinRows
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds: entity.BatchWarningThresholds{MinRows: 10},
+	})
+
+	warnings := inspector.Inspect(
+		inputRows("FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3"),
+		[]*entity.CleanedOrder{
+			{No: 1, ProductId: "FG0A-CLEAR-OPPOA3", Qty: 1, CappedQty: 1},
+			{No: 2, ProductId: "FG0A-MATTE-OPPOA3", Qty: 1, CappedQty: 2},
+			{No: 3, ProductId: "WIPING-CLOTH", Qty: 2},
+		},
+		nil,
+	)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, &entity.BatchWarning{Code: entity.WarningComplementaryCapped, Value: 3, Lines: []int{1, 2}}, warnings[0])
+}
+
+func TestBatchInspector_IncompatibleTexture(t *testing.T) {
+	matrix := value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear}}
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds: entity.BatchWarningThresholds{MinRows: 10},
+	})
+	lines := []*entity.CleanedOrder{
+		{No: 1, ProductId: "FG05-CLEAR-OPPOA3", MaterialId: "FG05-CLEAR", ModelId: "OPPOA3", Qty: 1},
+		{No: 2, ProductId: "FG05-PRIVACY-OPPOA3", MaterialId: "FG05-PRIVACY", ModelId: "OPPOA3", Qty: 1},
+		{No: 3, ProductId: "PRIVACY-CLEANNER", Qty: 1},
+	}
+
+	warnings := inspector.Inspect(inputRows("FG05-CLEAR-OPPOA3", "FG05-PRIVACY-OPPOA3"), lines,
+		&entity.ProcessOptions{FilmTextureStrictness: entity.FilmTextureWarn, FilmTextureMatrix: matrix})
+	require.Len(t, warnings, 1)
+	assert.Equal(t, &entity.BatchWarning{Code: entity.WarningIncompatibleTexture, Value: 1, Lines: []int{2}}, warnings[0])
+
+	warnings = inspector.Inspect(inputRows("FG05-CLEAR-OPPOA3", "FG05-PRIVACY-OPPOA3"), lines,
+		&entity.ProcessOptions{FilmTextureStrictness: entity.FilmTextureOff, FilmTextureMatrix: matrix})
+	assert.Empty(t, warnings)
+
+	warnings = inspector.Inspect(inputRows("FG05-CLEAR-OPPOA3", "FG05-PRIVACY-OPPOA3"), lines,
+		&entity.ProcessOptions{FilmTextureStrictness: entity.FilmTextureWarn})
+	assert.Empty(t, warnings, "without a matrix every texture is made")
+
+	t.Run("Defaults", func(t *testing.T) {
+		inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+			Thresholds: entity.BatchWarningThresholds{MinRows: 10},
+			Defaults:   &entity.ProcessOptions{FilmTextureStrictness: entity.FilmTextureWarn, FilmTextureMatrix: matrix},
+		})
+
+		warnings := inspector.Inspect(inputRows("FG05-CLEAR-OPPOA3", "FG05-PRIVACY-OPPOA3"), lines, nil)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, entity.WarningIncompatibleTexture, warnings[0].Code)
+
+		warnings = inspector.Inspect(inputRows("FG05-CLEAR-OPPOA3", "FG05-PRIVACY-OPPOA3"), lines,
+			&entity.ProcessOptions{FilmTextureStrictness: entity.FilmTextureOff})
+		assert.Empty(t, warnings, "the request options win")
+	})
+}
+
+func TestBatchInspector_Rewrites(t *testing.T) {
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds: entity.BatchWarningThresholds{MinRows: 10},
+	})
+	lines := []*entity.CleanedOrder{
+		{No: 1, ProductId: "FG0A-CLEAR-IPHONE16-B", MaterialId: "FG0A-CLEAR", ModelId: "IPHONE16-B", Qty: 1,
+			Rewrites: []entity.BatchWarningCode{entity.WarningModelSuffixNormalized}},
+		{No: 2, ProductId: "FG0A-CLEAR-OPPOA3", MaterialId: "FG0A-CLEAR", ModelId: "OPPOA3", Qty: 1},
+		{No: 3, ProductId: "FG0A-MATTE-IPHONE16-B", MaterialId: "FG0A-MATTE", ModelId: "IPHONE16-B", Qty: 1,
+			Rewrites: []entity.BatchWarningCode{entity.WarningModelSuffixNormalized}},
+	}
+
+	warnings := inspector.Inspect(inputRows("FG0A-CLEAR-IPHONE16-BLK", "FG0A-CLEAR-OPPOA3", "FG0A-MATTE-IPHONE16-BLACK"), lines, nil)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, &entity.BatchWarning{Code: entity.WarningModelSuffixNormalized, Value: 2, Lines: []int{1, 3}}, warnings[0])
+
+	assert.Empty(t, inspector.Inspect(inputRows("FG0A-CLEAR-OPPOA3"), lines[1:2], nil))
+}
+
+func TestBatchInspector_RecordsWarnings(t *testing.T) {
+	recorder := mockUsecases.NewBatchWarningRecorder(t)
+	var rows []int
+	var warnings [][]*entity.BatchWarning
+	recorder.On("RecordWarnings", mock.AnythingOfType("int"), mock.Anything).Run(func(args mock.Arguments) {
+		rows = append(rows, args.Int(0))
+		warnings = append(warnings, args.Get(1).([]*entity.BatchWarning))
+	}).Return().Twice()
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds: entity.BatchWarningThresholds{BundleRatio: 0.5},
+		Recorder:   recorder,
+	})
+
+	inspector.Inspect(inputRows("FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-OPPOA3", "FG0A-CLEAR-OPPOA3"), nil, nil)
+	inspector.Inspect(inputRows("FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-OPPOA3"), nil, nil)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, []int{2, 1}, rows)
+	assert.Empty(t, warnings[0])
+	require.Len(t, warnings[1], 1)
+	assert.Equal(t, entity.WarningBundleRatio, warnings[1][0].Code)
+	assert.Equal(t, 1.0, warnings[1][0].Value)
+}
+
+func TestBatchInspector_RecordsGarbageTokens(t *testing.T) {
+	recorder := mockUsecases.NewGarbageTokenRecorder(t)
+	var tokens [][]string
+	recorder.On("RecordGarbageTokens", mock.AnythingOfType("[]string")).Run(func(args mock.Arguments) {
+		tokens = append(tokens, args.Get(0).([]string))
+	}).Return().Once()
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds:    entity.BatchWarningThresholds{MinRows: 10},
+		GarbageTokens: recorder,
+	})
+
+	inspector.Inspect(inputRows(
+		"--FG0A-CLEAR-IPHONE16PROMAX",
+		"##FG0A-CLEAR-OPPOA3",
+		"--%21FG0A-MATTE-OPPOA3",
+		"FG0A-PRIVACY-IPHONE16PROMAX",
+		"WIPING-CLOTH",
+	), nil, nil)
+	inspector.Inspect(inputRows("FG0A-CLEAR-OPPOA3"), nil, nil)
+
+	require.Len(t, tokens, 1, "batches without garbage record nothing")
+	assert.Equal(t, []string{"##", "%21"}, tokens[0])
+}
+
+func TestBatchInspector_RecordsCatalogGaps(t *testing.T) {
+	recorder := mockUsecases.NewCatalogGapRecorder(t)
+	var gaps [][]*entity.CatalogGap
+	recorder.On("RecordCatalogGaps", mock.AnythingOfType("[]*entity.CatalogGap")).Run(func(args mock.Arguments) {
+		gaps = append(gaps, args.Get(0).([]*entity.CatalogGap))
+	}).Return().Once()
+	inspector := implementation.NewBatchInspector(parser.NewProductParser(), implementation.BatchInspectorOptions{
+		Thresholds:  entity.BatchWarningThresholds{MinRows: 10},
+		CatalogGaps: recorder,
+	})
+
+	inspector.Inspect(inputRows(
+		"FG0A-GLOSSY-IPHONE16PROMAX",
+		"--FG0A-CLEAR-OPPOA3/XX0A-matte-OPPOA3*2",
+		"FG0A-mat-OPPOA3",
+		"##FG0A-CLEAR-OPPOA3",
+		"FG0A-CLEAR-",
+	), nil, nil)
+	inspector.Inspect(inputRows("FG0A-CLEAR-OPPOA3"), nil, nil)
+
+	require.Len(t, gaps, 1, "batches without gaps record nothing")
+	assert.Equal(t, []*entity.CatalogGap{
+		{Kind: entity.CatalogGapTexture, Value: "GLOSSY", No: 1, PlatformProductId: "FG0A-GLOSSY-IPHONE16PROMAX"},
+		{Kind: entity.CatalogGapFilmType, Value: "XX0A", No: 2, PlatformProductId: "--FG0A-CLEAR-OPPOA3/XX0A-matte-OPPOA3*2"},
+	}, gaps[0])
+}
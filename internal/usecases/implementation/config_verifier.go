@@ -0,0 +1,106 @@
+package implementation
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"order-placement-system/internal/domain/entity"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/log"
+)
+
+// the seven cases of the original specification
+//
+//go:embed canonical_cases.json
+var canonicalCasesJson []byte
+
+type configVerifier struct {
+	orderProcessor usecase.OrderProcessorUseCase
+	canonicalCases []*entity.VerificationCase
+}
+
+func NewConfigVerifier(orderProcessor usecase.OrderProcessorUseCase) (usecase.ConfigVerifier, error) {
+	var canonicalCases []*entity.VerificationCase
+	if err := json.Unmarshal(canonicalCasesJson, &canonicalCases); err != nil {
+		log.Errorf("failed to load canonical cases", log.E(err))
+		return nil, err
+	}
+
+	return &configVerifier{
+		orderProcessor: orderProcessor,
+		canonicalCases: canonicalCases,
+	}, nil
+}
+
+// canonical cases run with the processor defaults except for the
+// complementary unit they were written for, samples run with options
+func (v *configVerifier) Verify(samples []*entity.VerificationCase, options *entity.ProcessOptions) *entity.VerificationReport {
+	report := &entity.VerificationReport{Passed: true}
+
+	for _, verificationCase := range v.canonicalCases {
+		report.Cases = append(report.Cases, v.run(verificationCase, &entity.ProcessOptions{
+			ComplementaryUnit: entity.ComplementaryPerBatch,
+		}))
+	}
+	for _, sample := range samples {
+		report.Cases = append(report.Cases, v.run(sample, options))
+	}
+
+	for _, result := range report.Cases {
+		if !result.Passed {
+			report.Passed = false
+			log.Warnf("config verification case failed",
+				log.S("case", result.Name),
+				log.AtoS("diffs", result.Diffs))
+		}
+	}
+
+	return report
+}
+
+func (v *configVerifier) run(verificationCase *entity.VerificationCase, options *entity.ProcessOptions) *entity.VerificationResult {
+	result := &entity.VerificationResult{Name: verificationCase.Name}
+
+	actual, err := v.orderProcessor.ProcessOrdersWithOptions(verificationCase.Input, options)
+	if err != nil {
+		result.Diffs = []string{fmt.Sprintf("processing failed: %s", err.Error())}
+		return result
+	}
+
+	result.Diffs = diffLines(verificationCase.Expected, actual)
+	result.Passed = len(result.Diffs) == 0
+	return result
+}
+
+// compares what identifies a line and its amounts, metadata such as parentNo
+// is not part of the contract
+func diffLines(expected, actual []*entity.CleanedOrder) []string {
+	var diffs []string
+	if len(expected) != len(actual) {
+		diffs = append(diffs, fmt.Sprintf("expected %d lines, got %d", len(expected), len(actual)))
+	}
+
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		want, got := expected[i], actual[i]
+		fields := []struct {
+			name      string
+			want, got string
+		}{
+			{"no", fmt.Sprint(want.No), fmt.Sprint(got.No)},
+			{"productId", want.ProductId, got.ProductId},
+			{"materialId", want.MaterialId, got.MaterialId},
+			{"modelId", want.ModelId, got.ModelId},
+			{"qty", fmt.Sprint(want.Qty), fmt.Sprint(got.Qty)},
+			{"unitPrice", want.UnitPrice.String(), got.UnitPrice.String()},
+			{"totalPrice", want.TotalPrice.String(), got.TotalPrice.String()},
+		}
+		for _, field := range fields {
+			if field.want != field.got {
+				diffs = append(diffs, fmt.Sprintf("line %d %s: expected %s, got %s", i+1, field.name, field.want, field.got))
+			}
+		}
+	}
+
+	return diffs
+}
@@ -0,0 +1,81 @@
+package implementation_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/internal/usecases/implementation"
+	"order-placement-system/pkg/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigVerifier_Verify(t *testing.T) {
+	pipeline := implementation.NewDefaultPipeline(parser.NewProductParser(), implementation.NewComplementaryCalculator(), nil)
+
+	t.Run("Canonical cases pass with the default config", func(t *testing.T) {
+		verifier, err := implementation.NewConfigVerifier(implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder},
+		}))
+		require.NoError(t, err)
+
+		report := verifier.Verify(nil, nil)
+
+		assert.True(t, report.Passed)
+		require.Len(t, report.Cases, 7)
+		for _, result := range report.Cases {
+			assert.True(t, result.Passed, result.Name)
+		}
+	})
+
+	t.Run("A broken config is reported with diffs", func(t *testing.T) {
+		verifier, err := implementation.NewConfigVerifier(implementation.NewOrderProcessor(nil, nil, implementation.OrderProcessorOptions{
+			Pipeline: pipeline.Replace(&noComplementStage{}),
+		}))
+		require.NoError(t, err)
+
+		report := verifier.Verify(nil, nil)
+
+		assert.False(t, report.Passed)
+		assert.False(t, report.Cases[0].Passed)
+		assert.Contains(t, report.Cases[0].Diffs, "expected 3 lines, got 1")
+	})
+
+	t.Run("Samples run with the given options", func(t *testing.T) {
+		verifier, err := implementation.NewConfigVerifier(implementation.NewOrderProcessor(nil, nil, implementation.OrderProcessorOptions{
+			Pipeline:        pipeline,
+			TenantPipelines: map[string]*implementation.Pipeline{"acme": pipeline.Replace(&noComplementStage{})},
+		}))
+		require.NoError(t, err)
+
+		sample := &entity.VerificationCase{
+			Name:  "acme sells without cloths",
+			Input: inputRows("FG0A-CLEAR-OPPOA3"),
+			Expected: []*entity.CleanedOrder{
+				{
+					No:         1,
+					ProductId:  "FG0A-CLEAR-OPPOA3",
+					MaterialId: "FG0A-CLEAR",
+					ModelId:    "OPPOA3",
+					Qty:        1,
+					UnitPrice:  value_object.MustNewPrice(50),
+					TotalPrice: value_object.MustNewPrice(40),
+				},
+			},
+		}
+
+		report := verifier.Verify([]*entity.VerificationCase{sample}, &entity.ProcessOptions{TenantId: "acme"})
+
+		require.Len(t, report.Cases, 8)
+		assert.False(t, report.Passed)
+		assert.Equal(t, &entity.VerificationResult{
+			Name:  "acme sells without cloths",
+			Diffs: []string{"line 1 totalPrice: expected 40.00, got 50.00"},
+		}, report.Cases[7])
+		for _, result := range report.Cases[:7] {
+			assert.True(t, result.Passed, "canonical cases run without the tenant")
+		}
+	})
+}
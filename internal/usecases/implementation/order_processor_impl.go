@@ -1,7 +1,9 @@
 package implementation
 
 import (
+	"sort"
 	"strconv"
+	"time"
 
 	"order-placement-system/internal/domain/entity"
 	"order-placement-system/internal/domain/service"
@@ -11,130 +13,159 @@ import (
 )
 
 type orderProcessorUseCase struct {
-	productParser           service.ProductParser
-	complementaryCalculator usecase.ComplementaryCalculator
+	pipeline           *Pipeline
+	tenantPipelines    map[string]*Pipeline
+	options            *entity.ProcessOptions
+	runtimeRules       usecase.RuntimeRules
+	priceSplitRecorder usecase.PriceSplitRecorder
+	businessRecorder   usecase.BusinessRecorder
+	eventPublisher     usecase.EventPublisher
 }
 
+// OrderProcessorOptions configure the order processor, every field is
+// optional
+type OrderProcessorOptions struct {
+	// the processor defaults, entity.DefaultProcessOptions when nil
+	ProcessOptions *entity.ProcessOptions
+	// rules changed while the service runs, laid over ProcessOptions for
+	// every batch
+	RuntimeRules usecase.RuntimeRules
+	// prices rows sent without a total, used by the default pipeline
+	PriceList usecase.PriceList
+	// replaces the default pipeline built from the parser, the complementary
+	// calculator and PriceList
+	Pipeline *Pipeline
+	// replace the pipeline for the tenant in ProcessOptions.TenantId once it is
+	// verified, usually built with pipeline.Replace
+	TenantPipelines map[string]*Pipeline
+	// receives how each row's total was split
+	PriceSplitRecorder usecase.PriceSplitRecorder
+	// receives every completed batch
+	BusinessRecorder usecase.BusinessRecorder
+	// every completed batch publishes its domain events to it (see
+	// entity.BatchEvents)
+	EventPublisher usecase.EventPublisher
+}
+
+// parser and complementaryCalculator build the default pipeline, they may be
+// nil when options.Pipeline is set
 func NewOrderProcessor(
 	parser service.ProductParser,
 	complementaryCalculator usecase.ComplementaryCalculator,
+	options OrderProcessorOptions,
 ) usecase.OrderProcessorUseCase {
+	pipeline := options.Pipeline
+	if pipeline == nil {
+		pipeline = NewDefaultPipeline(parser, complementaryCalculator, options.PriceList)
+	}
+
+	processOptions := options.ProcessOptions
+	if processOptions == nil {
+		processOptions = entity.DefaultProcessOptions()
+	}
+
 	return &orderProcessorUseCase{
-		productParser:           parser,
-		complementaryCalculator: complementaryCalculator,
+		pipeline:           pipeline,
+		tenantPipelines:    options.TenantPipelines,
+		options:            processOptions,
+		runtimeRules:       options.RuntimeRules,
+		priceSplitRecorder: options.PriceSplitRecorder,
+		businessRecorder:   options.BusinessRecorder,
+		eventPublisher:     options.EventPublisher,
 	}
 }
 
 func (uc *orderProcessorUseCase) ProcessOrders(inputOrders []*entity.InputOrder) ([]*entity.CleanedOrder, error) {
+	return uc.ProcessOrdersWithOptions(inputOrders, nil)
+}
+
+// non-zero fields of options override the processor defaults for this call only.
+// A batch that dropped rows, any failing row when lenient and rows out of
+// time when options.DropTimedOutRows is set, returns the remaining lines together with an
+// *errors.PartialError listing the dropped rows
+func (uc *orderProcessorUseCase) ProcessOrdersWithOptions(inputOrders []*entity.InputOrder, options *entity.ProcessOptions) ([]*entity.CleanedOrder, error) {
 	if len(inputOrders) == 0 {
 		return []*entity.CleanedOrder{}, nil
 	}
 
-	if err := uc.validateInputOrders(inputOrders); err != nil {
-		log.Errorf("invalid input orders", log.E(err))
+	options = uc.optionsWith(options)
+	batch := entity.NewProcessBatch(inputOrders, options)
+
+	if err := uc.pipelineFor(options.PipelineTenantId()).Run(batch); err != nil {
 		return nil, err
 	}
 
-	var allMainProducts []*entity.Product
-	var allCleanedOrders []*entity.CleanedOrder
-	currentOrderNo := 1
-
-	// Process each input order
-	for _, inputOrder := range inputOrders {
-		parsedProducts, err := uc.productParser.Parse(
-			inputOrder.PlatformProductId,
-			inputOrder.Qty,
-			inputOrder.TotalPrice,
-		)
-		if err != nil {
-			log.Errorf("failed to parse product id", log.S("product_id", inputOrder.PlatformProductId), log.E(err))
-			return nil, err
-		}
-
-		for _, parsedProduct := range parsedProducts {
-			product, err := uc.createProductFromParsed(parsedProduct)
-			if err != nil {
-				log.Errorf("failed to create product from parsed data", log.S("product_id", parsedProduct.CleanProductId), log.E(err))
-				return nil, err
-			}
-
-			allMainProducts = append(allMainProducts, product)
-
-			cleanedOrder := product.ToCleanedOrder(currentOrderNo)
-			allCleanedOrders = append(allCleanedOrders, cleanedOrder)
-
-			currentOrderNo++
-		}
+	if uc.priceSplitRecorder != nil {
+		uc.priceSplitRecorder.RecordBatch(batch.PriceSplits)
 	}
 
-	complementaryOrders, err := uc.complementaryCalculator.CalculateWithStartingOrderNo(allMainProducts, currentOrderNo)
-	if err != nil {
-		log.Errorf("failed to calculate complementary items", log.E(err))
-		return nil, err
+	if uc.businessRecorder != nil {
+		uc.businessRecorder.RecordBusinessBatch(batch)
 	}
 
-	allCleanedOrders = append(allCleanedOrders, complementaryOrders...)
-
-	if err := uc.validateCleanedOrders(allCleanedOrders); err != nil {
-		log.Errorf("invalid cleaned orders", log.E(err))
-		return nil, err
+	if uc.eventPublisher != nil {
+		uc.eventPublisher.Publish(entity.BatchEvents(batch, time.Now())...)
 	}
 
-	return allCleanedOrders, nil
-}
-
-func (uc *orderProcessorUseCase) createProductFromParsed(parsedProduct *entity.ParsedProduct) (*entity.Product, error) {
-	materialId, modelId, err := uc.productParser.ParseProductCode(parsedProduct.CleanProductId)
-	if err != nil {
-		log.Errorf("failed to parse product code", log.S("product_code", parsedProduct.CleanProductId), log.E(err))
-		return nil, err
+	if len(batch.RowErrors) > 0 {
+		return batch.CleanedOrders, errors.NewPartialError(batch.RowErrors)
 	}
 
-	product := &entity.Product{
-		ProductId:  parsedProduct.CleanProductId,
-		MaterialId: materialId,
-		ModelId:    modelId,
-		Quantity:   parsedProduct.Quantity,
-		UnitPrice:  parsedProduct.UnitPrice,
-		TotalPrice: parsedProduct.TotalPrice,
-	}
+	return batch.CleanedOrders, nil
+}
 
-	if err := product.IsValid(); err != nil {
-		log.Errorf("invalid product", log.S("product_id", product.ProductId), log.E(err))
-		return nil, err
+// RenumberOrders numbers an already cleaned batch again under options, e.g.
+// another StartNo, NumberNamespace or ComplementaryPlacement, without parsing
+// it again. Main lines keep their relative order, the lines are copies and
+// only their position, no and namespacedNo change
+func (uc *orderProcessorUseCase) RenumberOrders(cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) ([]*entity.CleanedOrder, error) {
+	if len(cleanedOrders) == 0 {
+		return []*entity.CleanedOrder{}, nil
 	}
 
-	return product, nil
-}
+	options = uc.optionsWith(options)
 
-func (uc *orderProcessorUseCase) validateInputOrders(inputOrders []*entity.InputOrder) error {
-	for i, order := range inputOrders {
-		if order == nil {
-			log.Errorf("input order at index is nil", log.S("index", strconv.Itoa(i)))
-			return errors.ErrInvalidInput
+	var mainLines, complementaryLines []*entity.CleanedOrder
+	for i, cleanedOrder := range cleanedOrders {
+		if cleanedOrder == nil {
+			log.Errorf("cleaned order at index is nil", log.S("index", strconv.Itoa(i)))
+			return nil, errors.ErrInvalidInput
 		}
 
-		if err := order.IsValid(); err != nil {
-			log.Errorf("input order is invalid", log.S("order_no", strconv.Itoa(order.No)), log.E(err))
-			return err
+		line := *cleanedOrder
+		if line.IsMainProduct() {
+			mainLines = append(mainLines, &line)
+		} else {
+			complementaryLines = append(complementaryLines, &line)
+		}
+	}
+	sort.SliceStable(mainLines, func(i, j int) bool {
+		return mainLines[i].No < mainLines[j].No
+	})
+
+	lines := entity.NumberLines(mainLines, complementaryLines, options)
+	for _, line := range lines {
+		if err := line.IsValid(); err != nil {
+			log.Errorf("renumbered order is invalid", log.S("product_id", line.ProductId), log.E(err))
+			return nil, err
 		}
 	}
 
-	return nil
+	return lines, nil
 }
 
-func (uc *orderProcessorUseCase) validateCleanedOrders(cleanedOrders []*entity.CleanedOrder) error {
-	for i, order := range cleanedOrders {
-		if order == nil {
-			log.Errorf("cleaned order at index is nil", log.S("index", strconv.Itoa(i)))
-			return errors.ErrInvalidInput
-		}
-
-		if err := order.IsValid(); err != nil {
-			log.Errorf("cleaned order is invalid", log.S("order_no", strconv.Itoa(order.No)), log.E(err))
-			return err
-		}
+// the processor defaults, then the runtime rules, then overrides
+func (uc *orderProcessorUseCase) optionsWith(overrides *entity.ProcessOptions) *entity.ProcessOptions {
+	options := uc.options
+	if uc.runtimeRules != nil {
+		options = options.WithOverrides(uc.runtimeRules.Overrides())
 	}
+	return options.WithOverrides(overrides)
+}
 
-	return nil
+func (uc *orderProcessorUseCase) pipelineFor(tenantId string) *Pipeline {
+	if pipeline, ok := uc.tenantPipelines[tenantId]; ok {
+		return pipeline
+	}
+	return uc.pipeline
 }
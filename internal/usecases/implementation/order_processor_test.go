@@ -1,15 +1,23 @@
 package implementation_test
 
 import (
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/service"
 	"order-placement-system/internal/domain/value_object"
+	mockUsecases "order-placement-system/internal/mock/usecases"
 	"order-placement-system/internal/usecases/implementation"
+	"order-placement-system/pkg/errors"
 	"order-placement-system/pkg/log"
 	"order-placement-system/pkg/utils/parser"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,10 +27,7 @@ func init() {
 
 func TestOrderProcessor_ProcessOrders_SevenCases(t *testing.T) {
 
-	processor := implementation.NewOrderProcessor(
-		parser.NewProductParser(),
-		implementation.NewComplementaryCalculator(),
-	)
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
 
 	testCases := []struct {
 		name     string
@@ -416,10 +421,7 @@ func TestOrderProcessor_ProcessOrders_SevenCases(t *testing.T) {
 
 func TestOrderProcessor_EdgeCases(t *testing.T) {
 
-	processor := implementation.NewOrderProcessor(
-		parser.NewProductParser(),
-		implementation.NewComplementaryCalculator(),
-	)
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
 
 	t.Run("Empty input", func(t *testing.T) {
 		result, err := processor.ProcessOrders([]*entity.InputOrder{})
@@ -479,3 +481,1369 @@ func TestOrderProcessor_EdgeCases(t *testing.T) {
 	// 	assert.Error(t, err)
 	// })
 }
+
+type slowProductParser struct {
+	service.ProductParser
+	delay time.Duration
+}
+
+func (p *slowProductParser) ParseWithQuantities(platformProductId string, originalQty int, totalPrice *value_object.Price, quantities entity.QuantitySemantics) ([]*entity.ParsedProduct, error) {
+	if strings.Contains(platformProductId, "SLOW") {
+		time.Sleep(p.delay)
+	}
+	return p.ProductParser.ParseWithQuantities(platformProductId, originalQty, totalPrice, quantities)
+}
+
+func TestOrderProcessor_ProcessingBudget(t *testing.T) {
+	slowParser := &slowProductParser{
+		ProductParser: parser.NewProductParser(),
+		delay:         200 * time.Millisecond,
+	}
+
+	fastRow := &entity.InputOrder{
+		No:                1,
+		PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+		Qty:               1,
+		UnitPrice:         value_object.MustNewPrice(50),
+		TotalPrice:        value_object.MustNewPrice(50),
+	}
+	slowRow := &entity.InputOrder{
+		No:                2,
+		PlatformProductId: "FG0A-MATTE-SLOW",
+		Qty:               1,
+		UnitPrice:         value_object.MustNewPrice(50),
+		TotalPrice:        value_object.MustNewPrice(50),
+	}
+
+	t.Run("Row exceeding row timeout fails a strict batch", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(slowParser, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{RowTimeout: 20 * time.Millisecond, Mode: entity.ProcessModeStrict},
+		})
+
+		result, err := processor.ProcessOrders([]*entity.InputOrder{fastRow, slowRow})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrProcessingTimeout)
+
+		var partial *errors.PartialError
+		assert.False(t, errors.As(err, &partial), "a strict batch does not drop rows on its own")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Strict batch drops a row out of time when asked to", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(slowParser, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{RowTimeout: 20 * time.Millisecond, Mode: entity.ProcessModeStrict},
+		})
+
+		result, err := processor.ProcessOrdersWithOptions([]*entity.InputOrder{fastRow, slowRow}, &entity.ProcessOptions{DropTimedOutRows: true})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrProcessingTimeout)
+
+		var partial *errors.PartialError
+		require.ErrorAs(t, err, &partial)
+		require.Len(t, partial.Rows, 1)
+		assert.Equal(t, 2, partial.Rows[0].No)
+		assert.Equal(t, errors.RowCodeTimeout, partial.Rows[0].Code())
+
+		require.NotEmpty(t, result, "the rows within budget are still returned")
+		assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX", result[0].ProductId)
+	})
+
+	t.Run("Batch deadline bounds the row budget", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(slowParser, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{RowTimeout: time.Second, BatchDeadline: 20 * time.Millisecond},
+		})
+
+		start := time.Now()
+		_, err := processor.ProcessOrders([]*entity.InputOrder{fastRow, slowRow})
+		assert.ErrorIs(t, err, errors.ErrProcessingTimeout)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("Rows within budget are processed normally", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(slowParser, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{RowTimeout: time.Second, BatchDeadline: 5 * time.Second},
+		})
+
+		result, err := processor.ProcessOrders([]*entity.InputOrder{fastRow, slowRow})
+		require.NoError(t, err)
+		assert.Len(t, result, 5)
+	})
+
+	t.Run("Nil options disable the budget", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+		result, err := processor.ProcessOrders([]*entity.InputOrder{fastRow})
+		require.NoError(t, err)
+		assert.Len(t, result, 3)
+	})
+}
+
+// stalls every row until release is closed, recording the most calls in flight
+type stalledProductParser struct {
+	service.ProductParser
+	release  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (p *stalledProductParser) ParseWithQuantities(platformProductId string, originalQty int, totalPrice *value_object.Price, quantities entity.QuantitySemantics) ([]*entity.ParsedProduct, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	<-p.release
+	return p.ProductParser.ParseWithQuantities(platformProductId, originalQty, totalPrice, quantities)
+}
+
+func stalledRows(n int) []*entity.InputOrder {
+	rows := make([]*entity.InputOrder, 0, n)
+	for i := 1; i <= n; i++ {
+		rows = append(rows, &entity.InputOrder{
+			No:                i,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		})
+	}
+	return rows
+}
+
+func TestOrderProcessor_StalledRowsAreBounded(t *testing.T) {
+	stalled := &stalledProductParser{ProductParser: parser.NewProductParser(), release: make(chan struct{})}
+	processor := implementation.NewOrderProcessor(stalled, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{RowTimeout: 2 * time.Millisecond, Mode: entity.ProcessModeLenient},
+	})
+
+	result, err := processor.ProcessOrders(stalledRows(2 * implementation.MaxOutstandingRows))
+	assert.ErrorIs(t, err, errors.ErrProcessingTimeout)
+	assert.Empty(t, result)
+	assert.LessOrEqual(t, int(stalled.peak.Load()), implementation.MaxOutstandingRows)
+
+	close(stalled.release)
+	assert.Eventually(t, func() bool { return stalled.inFlight.Load() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestOrderProcessor_RowsWaitForAFreeSlot(t *testing.T) {
+	stalled := &stalledProductParser{ProductParser: parser.NewProductParser(), release: make(chan struct{})}
+	filler := implementation.NewOrderProcessor(stalled, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{RowTimeout: 2 * time.Millisecond, Mode: entity.ProcessModeLenient},
+	})
+
+	// every row times out holding its slot until release is closed
+	_, err := filler.ProcessOrders(stalledRows(implementation.MaxOutstandingRows))
+	require.ErrorIs(t, err, errors.ErrProcessingTimeout)
+	require.Equal(t, int32(implementation.MaxOutstandingRows), stalled.inFlight.Load())
+
+	t.Run("Row without a slot within its budget times out", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{RowTimeout: 20 * time.Millisecond, Mode: entity.ProcessModeStrict},
+		})
+
+		result, err := processor.ProcessOrders(stalledRows(2))
+		assert.ErrorIs(t, err, errors.ErrProcessingTimeout)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Row gets a slot freed within its budget", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{RowTimeout: 5 * time.Second, Mode: entity.ProcessModeStrict},
+		})
+
+		time.AfterFunc(20*time.Millisecond, func() { close(stalled.release) })
+		result, err := processor.ProcessOrders(stalledRows(2))
+		require.NoError(t, err)
+		assert.NotEmpty(t, result)
+	})
+
+	require.Eventually(t, func() bool { return stalled.inFlight.Load() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestOrderProcessor_Accessories(t *testing.T) {
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{AccessoryPattern: regexp.MustCompile(`^ACC-`)},
+	})
+
+	t.Run("Accessory in bundle passes through without complementary items", func(t *testing.T) {
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "--FG0A-CLEAR-OPPOA3/ACC-CABLE-USBC-1M",
+				Qty:               2,
+				UnitPrice:         value_object.MustNewPrice(50),
+				TotalPrice:        value_object.MustNewPrice(200),
+			},
+		}
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+
+		assert.Equal(t, "FG0A-CLEAR-OPPOA3", result[0].ProductId)
+		assert.False(t, result[0].IsAccessory)
+
+		assert.Equal(t, 2, result[1].No)
+		assert.Equal(t, "ACC-CABLE-USBC-1M", result[1].ProductId)
+		assert.True(t, result[1].IsAccessory)
+		assert.Empty(t, result[1].MaterialId)
+		assert.Empty(t, result[1].ModelId)
+		assert.Equal(t, 2, result[1].Qty)
+		assert.InDelta(t, 100.0, result[1].TotalPrice.Amount(), 0.001)
+
+		assert.Equal(t, "WIPING-CLOTH", result[2].ProductId)
+		assert.Equal(t, 2, result[2].Qty)
+		assert.Equal(t, "CLEAR-CLEANNER", result[3].ProductId)
+		assert.Equal(t, 2, result[3].Qty)
+	})
+
+	t.Run("Accessory only order has no complementary items", func(t *testing.T) {
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "ACC-CABLE-USBC-1M",
+				Qty:               1,
+				UnitPrice:         value_object.MustNewPrice(30),
+				TotalPrice:        value_object.MustNewPrice(30),
+			},
+		}
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.True(t, result[0].IsAccessory)
+	})
+
+	t.Run("Accessory without pattern still fails", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "ACC-CABLE-USBC-1M",
+				Qty:               1,
+				UnitPrice:         value_object.MustNewPrice(30),
+				TotalPrice:        value_object.MustNewPrice(30),
+			},
+		}
+
+		_, err := processor.ProcessOrders(input)
+		assert.Error(t, err)
+	})
+
+	t.Run("Accessories matching the cloth pattern get wiping cloths", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{
+				AccessoryPattern:      regexp.MustCompile(`^ACC-`),
+				ClothAccessoryPattern: regexp.MustCompile(`^ACC-LENS-`),
+			},
+		})
+
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-OPPOA3/ACC-LENS-OPPOA3/ACC-CABLE-USBC-1M",
+				Qty:               2,
+				UnitPrice:         value_object.MustNewPrice(50),
+				TotalPrice:        value_object.MustNewPrice(300),
+			},
+		}
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		require.Len(t, result, 5)
+
+		assert.Equal(t, "WIPING-CLOTH", result[3].ProductId)
+		assert.Equal(t, 4, result[3].Qty, "the film and the lens accessory, not the cable")
+		assert.Equal(t, "CLEAR-CLEANNER", result[4].ProductId)
+		assert.Equal(t, 2, result[4].Qty)
+	})
+}
+
+func TestOrderProcessor_RecordsPriceSplits(t *testing.T) {
+	recorder := mockUsecases.NewPriceSplitRecorder(t)
+	var batches [][]*entity.PriceSplit
+	recorder.On("RecordBatch", mock.AnythingOfType("[]*entity.PriceSplit")).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).([]*entity.PriceSplit))
+	}).Return().Once()
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions:     entity.DefaultProcessOptions(),
+		PriceSplitRecorder: recorder,
+	})
+
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3/FG0A-PRIVACY-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(100),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+	}
+
+	_, err := processor.ProcessOrders(input)
+	require.NoError(t, err)
+
+	require.Len(t, batches, 1)
+	splits := batches[0]
+	require.Len(t, splits, 2)
+
+	assert.Equal(t, 3, splits[0].Lines)
+	assert.InDelta(t, 0.01, splits[0].Remainder, 1e-9)
+	assert.False(t, splits[1].HasRemainder())
+}
+
+func TestOrderProcessor_RecordsBusinessBatches(t *testing.T) {
+	recorder := mockUsecases.NewBusinessRecorder(t)
+	var batches []*entity.ProcessBatch
+	recorder.On("RecordBusinessBatch", mock.AnythingOfType("*entity.ProcessBatch")).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).(*entity.ProcessBatch))
+	}).Return().Once()
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions:   entity.DefaultProcessOptions(),
+		BusinessRecorder: recorder,
+	})
+
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+	}
+
+	_, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+	require.NoError(t, err)
+
+	require.Len(t, batches, 1)
+	batch := batches[0]
+	assert.Equal(t, "acme", batch.Options.TenantId)
+	assert.Len(t, batch.ActiveRows(), 1)
+	assert.Len(t, batch.ComplementaryLines, 2)
+
+	_, err = processor.ProcessOrders([]*entity.InputOrder{{No: 1, PlatformProductId: "INVALID-ID", Qty: 1, UnitPrice: value_object.MustNewPrice(1), TotalPrice: value_object.MustNewPrice(1)}})
+	assert.Error(t, err)
+	assert.Len(t, batches, 1, "failed batches are not recorded")
+}
+
+func TestOrderProcessor_PublishesEvents(t *testing.T) {
+	publisher := mockUsecases.NewEventPublisher(t)
+	var events []*entity.DomainEvent
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, arg := range args {
+			events = append(events, arg.(*entity.DomainEvent))
+		}
+	}).Return().Once()
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: entity.DefaultProcessOptions(),
+		EventPublisher: publisher,
+	})
+
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+		{No: 2, PlatformProductId: "INVALID-ID", Qty: 1, UnitPrice: value_object.MustNewPrice(1), TotalPrice: value_object.MustNewPrice(1)},
+	}
+
+	_, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme", Mode: entity.ProcessModeLenient})
+	require.Error(t, err)
+
+	require.Len(t, events, 3)
+	names := []entity.EventName{events[0].Name, events[1].Name, events[2].Name}
+	assert.Equal(t, []entity.EventName{
+		entity.EventRowDropped,
+		entity.EventComplementaryItemsGenerated,
+		entity.EventOrderBatchProcessed,
+	}, names)
+
+	for _, event := range events {
+		assert.Equal(t, "acme", event.TenantId)
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+
+	assert.Equal(t, 2, events[0].Payload.(*entity.RowDroppedPayload).No)
+	assert.Len(t, events[1].Payload.(*entity.ComplementaryItemsGeneratedPayload).Lines, 2)
+	assert.Equal(t, &entity.OrderBatchProcessedPayload{Rows: 2, DroppedRows: 1, Lines: 3},
+		events[2].Payload)
+
+	events = nil
+	_, err = processor.ProcessOrders([]*entity.InputOrder{input[1]})
+	assert.Error(t, err)
+	assert.Empty(t, events, "failed batches publish nothing")
+}
+
+func TestOrderProcessor_ComplementaryUnit(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-MATTE-IPHONE16PROMAX",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		},
+	}
+
+	t.Run("Per batch is the default and sums across orders", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		require.Len(t, result, 5)
+
+		assert.Equal(t, 1, result[0].ParentNo)
+		assert.Equal(t, 2, result[1].ParentNo)
+
+		assert.Equal(t, "WIPING-CLOTH", result[2].ProductId)
+		assert.Equal(t, 3, result[2].Qty)
+		assert.Zero(t, result[2].ParentNo)
+	})
+
+	t.Run("Per order keeps each order's items apart", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{
+			ComplementaryUnit: entity.ComplementaryPerOrder,
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 6)
+
+		expected := []struct {
+			productId string
+			qty       int
+			parentNo  int
+		}{
+			{"FG0A-CLEAR-IPHONE16PROMAX", 2, 1},
+			{"FG0A-MATTE-IPHONE16PROMAX", 1, 2},
+			{"WIPING-CLOTH", 2, 1},
+			{"CLEAR-CLEANNER", 2, 1},
+			{"WIPING-CLOTH", 1, 2},
+			{"MATTE-CLEANNER", 1, 2},
+		}
+		for i, want := range expected {
+			assert.Equal(t, i+1, result[i].No)
+			assert.Equal(t, want.productId, result[i].ProductId)
+			assert.Equal(t, want.qty, result[i].Qty)
+			assert.Equal(t, want.parentNo, result[i].ParentNo)
+		}
+	})
+
+	t.Run("Processor default can be per order", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder},
+		})
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		assert.Len(t, result, 6)
+
+		result, err = processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{
+			ComplementaryUnit: entity.ComplementaryPerBatch,
+		})
+		require.NoError(t, err)
+		assert.Len(t, result, 5)
+	})
+}
+
+type priceListStub map[string]map[string]float64
+
+func (s priceListStub) UnitPrice(tenantId, productId string) (*value_object.Price, bool) {
+	amount, ok := s[tenantId][productId]
+	if !ok {
+		return nil, false
+	}
+	return value_object.MustNewPrice(amount), true
+}
+
+func TestOrderProcessor_PriceEnrichment(t *testing.T) {
+	priceList := priceListStub{
+		"acme": {
+			"FG0A-CLEAR-IPHONE16PROMAX": 40,
+			"FG0A-MATTE-OPPOA3":         35,
+		},
+	}
+	recorder := mockUsecases.NewPriceSplitRecorder(t)
+	var batches [][]*entity.PriceSplit
+	recorder.On("RecordBatch", mock.AnythingOfType("[]*entity.PriceSplit")).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).([]*entity.PriceSplit))
+	}).Return()
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions:     entity.DefaultProcessOptions(),
+		PriceSplitRecorder: recorder,
+		PriceList:          priceList,
+	})
+
+	t.Run("Zero priced row is filled from the tenant price list", func(t *testing.T) {
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-OPPOA3*2",
+				Qty:               1,
+				UnitPrice:         value_object.ZeroPrice(),
+				TotalPrice:        value_object.ZeroPrice(),
+			},
+		}
+
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(result), 2)
+
+		assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX", result[0].ProductId)
+		assert.True(t, result[0].PriceEnriched)
+		assert.Equal(t, 40.0, result[0].UnitPrice.Amount())
+		assert.Equal(t, 40.0, result[0].TotalPrice.Amount())
+
+		assert.Equal(t, "FG0A-MATTE-OPPOA3", result[1].ProductId)
+		assert.True(t, result[1].PriceEnriched)
+		assert.Equal(t, 2, result[1].Qty)
+		assert.Equal(t, 35.0, result[1].UnitPrice.Amount())
+		assert.Equal(t, 70.0, result[1].TotalPrice.Amount())
+
+		// no input total to split
+		require.NotEmpty(t, batches)
+		assert.Empty(t, batches[len(batches)-1])
+	})
+
+	t.Run("Priced row is left as sent", func(t *testing.T) {
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               1,
+				UnitPrice:         value_object.MustNewPrice(50),
+				TotalPrice:        value_object.MustNewPrice(50),
+			},
+		}
+
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+		require.NoError(t, err)
+
+		assert.False(t, result[0].PriceEnriched)
+		assert.Equal(t, 50.0, result[0].UnitPrice.Amount())
+	})
+
+	t.Run("Unverified tenant is not priced from its contract prices", func(t *testing.T) {
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               1,
+				UnitPrice:         value_object.ZeroPrice(),
+				TotalPrice:        value_object.ZeroPrice(),
+			},
+		}
+
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true})
+		require.NoError(t, err)
+
+		assert.False(t, result[0].PriceEnriched)
+		assert.True(t, result[0].TotalPrice.IsZero())
+	})
+
+	t.Run("Product without contract price keeps zero price", func(t *testing.T) {
+		input := []*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+				Qty:               1,
+				UnitPrice:         value_object.ZeroPrice(),
+				TotalPrice:        value_object.ZeroPrice(),
+			},
+		}
+
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "globex"})
+		require.NoError(t, err)
+
+		assert.False(t, result[0].PriceEnriched)
+		assert.True(t, result[0].TotalPrice.IsZero())
+	})
+}
+
+func TestOrderProcessor_ModelSuffixAliases(t *testing.T) {
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{
+			ModelSuffixAliases: entity.ModelSuffixAliases{
+				entity.DefaultTenantId: {"-BLACK": "-B", "-BLK": "-B"},
+				"acme":                 {"-BLK": "-BK"},
+			},
+		},
+	})
+
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX-BLACK/FG0A-CLEAR-IPHONE16PROMAX-BLK",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+	}
+
+	t.Run("Spellings are normalized to one suffix", func(t *testing.T) {
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+
+		for _, line := range result[:2] {
+			assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX-B", line.ProductId)
+			assert.Equal(t, "FG0A-CLEAR", line.MaterialId)
+			assert.Equal(t, "IPHONE16PROMAX-B", line.ModelId)
+		}
+	})
+
+	t.Run("Tenant aliases take precedence", func(t *testing.T) {
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+
+		assert.Equal(t, "IPHONE16PROMAX-B", result[0].ModelId)
+		assert.Equal(t, "IPHONE16PROMAX-BK", result[1].ModelId)
+	})
+}
+
+func TestOrderProcessor_TexturePriorities(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX/FG0A-MATTE-IPHONE16PROMAX/FG0A-PRIVACY-IPHONE16PROMAX",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(150),
+		},
+	}
+	cleaners := func(lines []*entity.CleanedOrder) []string {
+		var productIds []string
+		for _, line := range lines {
+			if strings.HasSuffix(line.ProductId, entity.CleanerSuffix) {
+				productIds = append(productIds, line.ProductId)
+			}
+		}
+		return productIds
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CLEAR-CLEANNER", "MATTE-CLEANNER", "PRIVACY-CLEANNER"}, cleaners(result))
+	})
+
+	t.Run("Processor options", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{TexturePriorities: value_object.TexturePriorities{"CLEAR": 3, "MATTE": 2, "PRIVACY": 1}},
+		})
+
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PRIVACY-CLEANNER", "MATTE-CLEANNER", "CLEAR-CLEANNER"}, cleaners(result))
+	})
+
+	t.Run("Runtime rules apply to the next batch", func(t *testing.T) {
+		rules := mockUsecases.NewRuntimeRules(t)
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{TexturePriorities: value_object.TexturePriorities{"CLEAR": 3, "MATTE": 2, "PRIVACY": 1}},
+			RuntimeRules:   rules,
+		})
+
+		rules.On("Overrides").Return(&entity.ProcessOptions{}).Once()
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PRIVACY-CLEANNER", "MATTE-CLEANNER", "CLEAR-CLEANNER"}, cleaners(result), "nil runtime priorities keep the processor's")
+
+		rules.On("Overrides").Return(&entity.ProcessOptions{
+			TexturePriorities: value_object.TexturePriorities{"CLEAR": 2, "MATTE": 1, "PRIVACY": 3},
+		}).Once()
+		result, err = processor.ProcessOrders(input)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"MATTE-CLEANNER", "CLEAR-CLEANNER", "PRIVACY-CLEANNER"}, cleaners(result))
+	})
+}
+
+func TestOrderProcessor_VariantTokens(t *testing.T) {
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{
+			VariantTokens:      entity.VariantTokens{entity.DefaultTenantId: {"RED", "BLACK"}},
+			ModelSuffixAliases: entity.ModelSuffixAliases{entity.DefaultTenantId: {"-BLK": "-B"}},
+		},
+	})
+
+	result, err := processor.ProcessOrders([]*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-RED-IPHONE16PROMAX/FG0A-CLEAR-black-IPHONE16PROMAX-BLK/FG0A-MATTE-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(150),
+			TotalPrice:        value_object.MustNewPrice(150),
+		},
+	})
+	require.NoError(t, err)
+
+	mainLines := result[:3]
+	assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX", mainLines[0].ProductId)
+	assert.Equal(t, "IPHONE16PROMAX", mainLines[0].ModelId)
+	assert.Equal(t, "RED", mainLines[0].Variant)
+	assert.Equal(t, []entity.BatchWarningCode{entity.WarningVariantExtracted}, mainLines[0].Rewrites)
+
+	// the token comes off before the suffix is normalized
+	assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX-B", mainLines[1].ProductId)
+	assert.Equal(t, "IPHONE16PROMAX-B", mainLines[1].ModelId)
+	assert.Equal(t, "BLACK", mainLines[1].Variant)
+	assert.Equal(t, []entity.BatchWarningCode{entity.WarningVariantExtracted, entity.WarningModelSuffixNormalized}, mainLines[1].Rewrites)
+
+	assert.Equal(t, "FG0A-MATTE-OPPOA3", mainLines[2].ProductId)
+	assert.Empty(t, mainLines[2].Variant)
+	assert.Empty(t, mainLines[2].Rewrites)
+
+	for _, line := range result[3:] {
+		assert.Empty(t, line.Variant)
+	}
+}
+
+func TestOrderProcessor_BundleLimits(t *testing.T) {
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{MaxBundleComponents: 3, MaxBundleUnits: 10},
+	})
+
+	tests := []struct {
+		name              string
+		platformProductId string
+		qty               int
+		expectError       bool
+	}{
+		{"Within limits", "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3*2", 1, false},
+		{"Too many components", "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3/FG0A-PRIVACY-OPPOA3/FG0A-CLEAR-IPHONE16PROMAX", 1, true},
+		{"Too many units from a multiplier", "FG0A-CLEAR-OPPOA3*20/FG0A-MATTE-OPPOA3", 1, true},
+		{"Row quantity is not counted", "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3", 6, false},
+		{"Large row that is no bundle", "FG0A-CLEAR-OPPOA3", 1500, false},
+		{"Multiplier on a row that is no bundle", "FG0A-CLEAR-OPPOA3*20", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := []*entity.InputOrder{
+				{
+					No:                4,
+					PlatformProductId: tt.platformProductId,
+					Qty:               tt.qty,
+					UnitPrice:         value_object.MustNewPrice(10),
+					TotalPrice:        value_object.MustNewPrice(100),
+				},
+			}
+
+			result, err := processor.ProcessOrders(input)
+			if !tt.expectError {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result)
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrBundleTooLarge)
+
+			var rowErr *errors.RowError
+			require.ErrorAs(t, err, &rowErr)
+			assert.Equal(t, 4, rowErr.No)
+		})
+	}
+}
+
+type panickingProductParser struct {
+	service.ProductParser
+}
+
+func (p *panickingProductParser) ParseWithQuantities(platformProductId string, originalQty int, totalPrice *value_object.Price, quantities entity.QuantitySemantics) ([]*entity.ParsedProduct, error) {
+	if strings.Contains(platformProductId, "PANIC") {
+		panic("malformed row")
+	}
+	return p.ProductParser.ParseWithQuantities(platformProductId, originalQty, totalPrice, quantities)
+}
+
+func TestOrderProcessor_LenientMode(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-MATTE-PANIC",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		},
+		{
+			No:                3,
+			PlatformProductId: "FG0A-MATTE-OPPOA3*20/FG0A-CLEAR-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		rowTimeout time.Duration
+	}{
+		{"Without row budget", 0},
+		{"With row budget", time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := implementation.NewOrderProcessor(&panickingProductParser{ProductParser: parser.NewProductParser()}, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+				ProcessOptions: &entity.ProcessOptions{
+					RowTimeout:     tt.rowTimeout,
+					MaxBundleUnits: 10,
+					Mode:           entity.ProcessModeLenient,
+				},
+			})
+
+			result, err := processor.ProcessOrders(input)
+			require.Error(t, err)
+
+			var partial *errors.PartialError
+			require.ErrorAs(t, err, &partial)
+			require.Len(t, partial.Rows, 2)
+			assert.Equal(t, 2, partial.Rows[0].No)
+			assert.ErrorIs(t, partial.Rows[0], errors.ErrRowPanicked)
+			assert.Equal(t, 3, partial.Rows[1].No)
+			assert.ErrorIs(t, partial.Rows[1], errors.ErrBundleTooLarge)
+
+			require.Len(t, result, 3)
+			assert.Equal(t, "FG0A-CLEAR-IPHONE16PROMAX", result[0].ProductId)
+			assert.Equal(t, "WIPING-CLOTH", result[1].ProductId)
+			assert.Equal(t, 2, result[1].Qty)
+			assert.Equal(t, "CLEAR-CLEANNER", result[2].ProductId)
+			for i, line := range result {
+				assert.Equal(t, i+1, line.No)
+			}
+		})
+	}
+
+	t.Run("Strict mode still fails the batch", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: &entity.ProcessOptions{MaxBundleUnits: 10, Mode: entity.ProcessModeLenient},
+		})
+
+		_, err := processor.ProcessOrdersWithOptions(input[2:], &entity.ProcessOptions{Mode: entity.ProcessModeStrict})
+		assert.ErrorIs(t, err, errors.ErrBundleTooLarge)
+
+		var partial *errors.PartialError
+		assert.False(t, errors.As(err, &partial))
+	})
+
+	t.Run("Strict mode re-raises a panicking row", func(t *testing.T) {
+		processor := implementation.NewOrderProcessor(&panickingProductParser{ProductParser: parser.NewProductParser()}, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+		assert.Panics(t, func() {
+			_, _ = processor.ProcessOrders(input[1:2])
+		})
+	})
+}
+
+func TestOrderProcessor_ExternalRef(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(80),
+			TotalPrice:        value_object.MustNewPrice(80),
+			ExternalRef:       "line-a",
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-PRIVACY-IPHONE16PROMAX",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+			ExternalRef:       "line-b",
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+	t.Run("Main lines and bundle components carry their row's ref", func(t *testing.T) {
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+
+		assert.Equal(t, "line-a", result[0].ExternalRef)
+		assert.Equal(t, "line-a", result[1].ExternalRef)
+		assert.Equal(t, "line-b", result[2].ExternalRef)
+		for _, line := range result[3:] {
+			assert.Empty(t, line.ExternalRef, "batch wide complementary lines belong to no single row")
+		}
+	})
+
+	t.Run("Per order complementary lines carry their parent's ref", func(t *testing.T) {
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder})
+		require.NoError(t, err)
+
+		for _, line := range result {
+			want := map[int]string{1: "line-a", 2: "line-b"}[line.ParentNo]
+			assert.Equal(t, want, line.ExternalRef, "line %d", line.No)
+		}
+	})
+}
+
+func TestOrderProcessor_Kits(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(80),
+			TotalPrice:        value_object.MustNewPrice(80),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{KitTenants: entity.KitTenants{"acme"}},
+	})
+
+	productIds := func(lines []*entity.CleanedOrder) []string {
+		ids := make([]string, len(lines))
+		for i, line := range lines {
+			ids[i] = line.ProductId
+		}
+		return ids
+	}
+
+	t.Run("Listed tenant gets kits", func(t *testing.T) {
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "CARE-KIT-CLEAR", "CARE-KIT-MATTE",
+		}, productIds(result))
+		for i, line := range result {
+			assert.Equal(t, i+1, line.No)
+		}
+	})
+
+	t.Run("Other tenants get separate items", func(t *testing.T) {
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "other"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "MATTE-CLEANNER",
+		}, productIds(result))
+	})
+}
+
+func TestOrderProcessor_Numbering(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(80),
+			TotalPrice:        value_object.MustNewPrice(80),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+	tests := []struct {
+		name             string
+		options          *entity.ProcessOptions
+		expectedNos      []int
+		expectedNamedNos []string
+	}{
+		{
+			name:             "Default starts at 1 without a namespace",
+			options:          nil,
+			expectedNos:      []int{1, 2, 3, 4, 5},
+			expectedNamedNos: []string{"", "", "", "", ""},
+		},
+		{
+			name:             "Start number offsets every line",
+			options:          &entity.ProcessOptions{StartNo: 101},
+			expectedNos:      []int{101, 102, 103, 104, 105},
+			expectedNamedNos: []string{"", "", "", "", ""},
+		},
+		{
+			name:             "Namespace prefixes the number",
+			options:          &entity.ProcessOptions{StartNo: 6, NumberNamespace: "B123"},
+			expectedNos:      []int{6, 7, 8, 9, 10},
+			expectedNamedNos: []string{"B123-6", "B123-7", "B123-8", "B123-9", "B123-10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := processor.ProcessOrdersWithOptions(input, tt.options)
+			require.NoError(t, err)
+
+			nos := make([]int, len(result))
+			namedNos := make([]string, len(result))
+			for i, line := range result {
+				nos[i] = line.No
+				namedNos[i] = line.NamespacedNo
+			}
+			assert.Equal(t, tt.expectedNos, nos)
+			assert.Equal(t, tt.expectedNamedNos, namedNos)
+			assert.Equal(t, 1, result[0].ParentNo, "parentNo keeps the input row number")
+		})
+	}
+}
+
+func TestOrderProcessor_CustomsClassification(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{
+			KitTenants: entity.KitTenants{"acme"},
+			CustomsClassification: entity.CustomsClassification{
+				"WIPING-CLOTH":   {HSCode: "6307.10", UnitValue: 5},
+				"CARE-KIT-CLEAR": {HSCode: "3405.90", UnitValue: 12},
+			},
+		},
+	})
+
+	customsOf := func(lines []*entity.CleanedOrder) map[string]*entity.CustomsInfo {
+		customs := make(map[string]*entity.CustomsInfo)
+		for _, line := range lines {
+			customs[line.ProductId] = line.Customs
+		}
+		return customs
+	}
+
+	t.Run("Complementary lines are classified", func(t *testing.T) {
+		result, err := processor.ProcessOrders(input)
+		require.NoError(t, err)
+
+		customs := customsOf(result)
+		assert.Nil(t, customs["FG0A-CLEAR-IPHONE16PROMAX"])
+		assert.Equal(t, &entity.CustomsInfo{HSCode: "6307.10", UnitValue: 5}, customs["WIPING-CLOTH"])
+		assert.Nil(t, customs["CLEAR-CLEANNER"], "unclassified products get no customs info")
+	})
+
+	t.Run("Kits are classified by their own product id", func(t *testing.T) {
+		result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+		require.NoError(t, err)
+
+		assert.Equal(t, &entity.CustomsInfo{HSCode: "3405.90", UnitValue: 12}, customsOf(result)["CARE-KIT-CLEAR"])
+	})
+}
+
+func TestOrderProcessor_DiscountAndSurcharge(t *testing.T) {
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+	t.Run("Bundle components share the row's discount", func(t *testing.T) {
+		result, err := processor.ProcessOrders([]*entity.InputOrder{{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3*2/FG0A-MATTE-OPPOA3*2",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(160),
+			TotalPrice:        value_object.MustNewPrice(160),
+			Discount:          value_object.MustNewPrice(20),
+			Surcharge:         value_object.MustNewPrice(4),
+		}})
+		require.NoError(t, err)
+
+		for _, line := range result[:2] {
+			assert.Equal(t, 72.0, line.TotalPrice.Amount())
+			assert.Equal(t, 36.0, line.UnitPrice.Amount())
+			assert.Equal(t, 80.0, line.GrossTotalPrice.Amount())
+			assert.Equal(t, 40.0, line.GrossUnitPrice.Amount())
+		}
+		for _, line := range result[2:] {
+			assert.Nil(t, line.GrossTotalPrice, "complementary lines are not adjusted")
+		}
+	})
+
+	t.Run("Rows without adjustment carry no gross prices", func(t *testing.T) {
+		result, err := processor.ProcessOrders([]*entity.InputOrder{{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		}})
+		require.NoError(t, err)
+		assert.Nil(t, result[0].GrossTotalPrice)
+	})
+
+	t.Run("A discount above the row total drops it in lenient mode", func(t *testing.T) {
+		result, err := processor.ProcessOrdersWithOptions([]*entity.InputOrder{
+			{
+				No:                1,
+				PlatformProductId: "FG0A-CLEAR-OPPOA3",
+				Qty:               1,
+				UnitPrice:         value_object.MustNewPrice(50),
+				TotalPrice:        value_object.MustNewPrice(50),
+				Discount:          value_object.MustNewPrice(60),
+			},
+			{
+				No:                2,
+				PlatformProductId: "FG0A-MATTE-OPPOA3",
+				Qty:               1,
+				UnitPrice:         value_object.MustNewPrice(50),
+				TotalPrice:        value_object.MustNewPrice(50),
+			},
+		}, &entity.ProcessOptions{Mode: entity.ProcessModeLenient})
+
+		var partial *errors.PartialError
+		require.True(t, errors.As(err, &partial))
+		require.Len(t, partial.Rows, 1)
+		assert.Equal(t, 1, partial.Rows[0].No)
+		assert.Equal(t, "FG0A-MATTE-OPPOA3", result[0].ProductId)
+	})
+}
+
+func TestOrderProcessor_RowErrorCategories(t *testing.T) {
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+	row := func(no int, productId string) *entity.InputOrder {
+		return &entity.InputOrder{
+			No:                no,
+			PlatformProductId: productId,
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		}
+	}
+
+	_, err := processor.ProcessOrdersWithOptions([]*entity.InputOrder{
+		row(1, "FG0A-GLOSSY-OPPOA3"),
+		row(2, "FG0A-CLEAR-"),
+		row(3, "FG0A-CLEAR-OPPOA3*3/FG0A-MATTE-OPPOA3*3"),
+		row(4, "FG0A-CLEAR-OPPOA3"),
+	}, &entity.ProcessOptions{Mode: entity.ProcessModeLenient, MaxBundleUnits: 4})
+
+	var partial *errors.PartialError
+	require.True(t, errors.As(err, &partial))
+	require.Len(t, partial.Rows, 3)
+	assert.Equal(t, errors.CategoryCatalogMismatch, partial.Rows[0].Category())
+	assert.Equal(t, errors.CategoryFormatError, partial.Rows[1].Category())
+	assert.Equal(t, errors.CategoryBusinessRule, partial.Rows[2].Category())
+}
+
+func TestOrderProcessor_ComplementaryPlacement(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-MATTE-OPPOA3",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+
+	tests := []struct {
+		name            string
+		options         *entity.ProcessOptions
+		expectedProduct []string
+		expectedParent  []int
+	}{
+		{
+			name:            "Per order lines at the end by default",
+			options:         &entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder},
+			expectedProduct: []string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "WIPING-CLOTH", "MATTE-CLEANNER"},
+			expectedParent:  []int{1, 2, 1, 1, 2, 2},
+		},
+		{
+			name: "Per order lines follow their row when interleaved",
+			options: &entity.ProcessOptions{
+				ComplementaryUnit:      entity.ComplementaryPerOrder,
+				ComplementaryPlacement: entity.ComplementaryInterleaved,
+			},
+			expectedProduct: []string{"FG0A-CLEAR-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "FG0A-MATTE-OPPOA3", "WIPING-CLOTH", "MATTE-CLEANNER"},
+			expectedParent:  []int{1, 1, 1, 2, 2, 2},
+		},
+		{
+			name:            "Per batch lines stay at the end when interleaved",
+			options:         &entity.ProcessOptions{ComplementaryPlacement: entity.ComplementaryInterleaved},
+			expectedProduct: []string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "MATTE-CLEANNER"},
+			expectedParent:  []int{1, 2, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := processor.ProcessOrdersWithOptions(input, tt.options)
+			require.NoError(t, err)
+
+			products := make([]string, len(result))
+			parents := make([]int, len(result))
+			for i, line := range result {
+				products[i] = line.ProductId
+				parents[i] = line.ParentNo
+				assert.Equal(t, i+1, line.No, "lines are numbered in output order")
+			}
+			assert.Equal(t, tt.expectedProduct, products)
+			assert.Equal(t, tt.expectedParent, parents)
+		})
+	}
+}
+
+func TestOrderProcessor_ShippingWeight(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3*2",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(100),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+	result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{
+		WeightCatalog: entity.WeightCatalog{"OPPOA3": 10, "WIPING-CLOTH": 5},
+		SkuAffixes:    entity.SkuAffixes{"acme": {Prefix: "ACME-"}},
+		TenantId:      "acme",
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	assert.Equal(t, "ACME-FG0A-CLEAR-OPPOA3", result[0].ProductId)
+	assert.Equal(t, 20, result[0].ShippingWeight, "weighed by canonical ids before the affix")
+	assert.Equal(t, 10, result[1].ShippingWeight)
+	assert.Zero(t, result[2].ShippingWeight, "cleaner is not in the catalog")
+	assert.Equal(t, &entity.ShippingWeight{Total: 30, UnweighedLines: 1}, entity.TotalShippingWeight(result))
+}
+
+func TestOrderProcessor_RenumberOrders(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(50),
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-MATTE-OPPOA3",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(100),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{})
+	sequential, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder})
+	require.NoError(t, err)
+
+	renumberOptions := &entity.ProcessOptions{
+		ComplementaryPlacement: entity.ComplementaryInterleaved,
+		StartNo:                101,
+		NumberNamespace:        "WEEK42",
+	}
+	expected, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{
+		ComplementaryUnit:      entity.ComplementaryPerOrder,
+		ComplementaryPlacement: renumberOptions.ComplementaryPlacement,
+		StartNo:                renumberOptions.StartNo,
+		NumberNamespace:        renumberOptions.NumberNamespace,
+	})
+	require.NoError(t, err)
+
+	t.Run("Matches processing under the new numbering", func(t *testing.T) {
+		renumbered, err := processor.RenumberOrders(sequential, renumberOptions)
+		require.NoError(t, err)
+		assert.Equal(t, expected, renumbered)
+		assert.Equal(t, 1, sequential[0].No, "the input lines are not changed")
+		assert.Empty(t, sequential[0].NamespacedNo)
+	})
+
+	t.Run("Renumbering again changes nothing", func(t *testing.T) {
+		renumbered, err := processor.RenumberOrders(expected, renumberOptions)
+		require.NoError(t, err)
+		assert.Equal(t, expected, renumbered)
+	})
+
+	t.Run("Back to sequential numbering", func(t *testing.T) {
+		renumbered, err := processor.RenumberOrders(expected, nil)
+		require.NoError(t, err)
+		assert.Equal(t, sequential, renumbered)
+	})
+
+	t.Run("Invalid lines are rejected", func(t *testing.T) {
+		_, err := processor.RenumberOrders([]*entity.CleanedOrder{{ProductId: "WIPING-CLOTH", Qty: 0}}, nil)
+		assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+		_, err = processor.RenumberOrders([]*entity.CleanedOrder{nil}, nil)
+		assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	})
+}
+
+func TestOrderProcessor_QuantitySemantics(t *testing.T) {
+	input := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "FG0A-CLEAR-OPPOA3*3/FG0A-MATTE-OPPOA3",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(80),
+			TotalPrice:        value_object.MustNewPrice(160),
+		},
+	}
+
+	processor := implementation.NewOrderProcessor(parser.NewProductParser(), implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+		ProcessOptions: &entity.ProcessOptions{AdditiveQuantityTenants: entity.QuantityTenants{"legacy"}},
+	})
+
+	tests := []struct {
+		name     string
+		tenantId string
+		expected []int
+	}{
+		{"Row quantity multiplies the components", "acme", []int{6, 2}},
+		{"Legacy tenant adds them", "legacy", []int{4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: tt.tenantId})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, []int{result[0].Qty, result[1].Qty})
+			assert.Equal(t, 160.0, result[0].TotalPrice.Amount()+result[1].TotalPrice.Amount())
+		})
+	}
+}
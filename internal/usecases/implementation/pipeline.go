@@ -0,0 +1,70 @@
+package implementation
+
+import (
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/service"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/log"
+)
+
+// Pipeline runs its stages in order and stops at the first error
+type Pipeline struct {
+	stages []usecase.ProcessStage
+}
+
+func NewPipeline(stages ...usecase.ProcessStage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Normalize → Parse → Allocate → Validate → Complement → Number → Weigh → Affix,
+// priceList is optional
+func NewDefaultPipeline(
+	parser service.ProductParser,
+	complementaryCalculator usecase.ComplementaryCalculator,
+	priceList usecase.PriceList,
+) *Pipeline {
+	return NewPipeline(
+		NewNormalizeStage(),
+		NewParseStage(parser),
+		NewAllocateStage(priceList),
+		NewValidateStage(),
+		NewComplementStage(complementaryCalculator, nil),
+		NewNumberStage(),
+		NewWeighStage(),
+		NewAffixStage(),
+	)
+}
+
+// returns a copy with the stage of the same name swapped for stage,
+// the pipeline is returned unchanged when it has no such stage
+func (p *Pipeline) Replace(stage usecase.ProcessStage) *Pipeline {
+	stages := make([]usecase.ProcessStage, len(p.stages))
+	copy(stages, p.stages)
+
+	for i, existing := range stages {
+		if existing.Name() == stage.Name() {
+			stages[i] = stage
+		}
+	}
+
+	return &Pipeline{stages: stages}
+}
+
+func (p *Pipeline) StageNames() []entity.StageName {
+	names := make([]entity.StageName, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+func (p *Pipeline) Run(batch *entity.ProcessBatch) error {
+	for _, stage := range p.stages {
+		if err := stage.Run(batch); err != nil {
+			log.Errorf("processing stage failed", log.S("stage", string(stage.Name())), log.E(err))
+			return err
+		}
+	}
+
+	return nil
+}
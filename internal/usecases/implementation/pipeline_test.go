@@ -0,0 +1,81 @@
+package implementation_test
+
+import (
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/usecases/implementation"
+	"order-placement-system/pkg/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drops every complementary line
+type noComplementStage struct{}
+
+func (s *noComplementStage) Name() entity.StageName {
+	return entity.StageComplement
+}
+
+func (s *noComplementStage) Run(batch *entity.ProcessBatch) error {
+	batch.ComplementaryLines = nil
+	return nil
+}
+
+func TestPipeline_Replace(t *testing.T) {
+	pipeline := implementation.NewDefaultPipeline(parser.NewProductParser(), implementation.NewComplementaryCalculator(), nil)
+
+	expected := []entity.StageName{
+		entity.StageNormalize,
+		entity.StageParse,
+		entity.StageAllocate,
+		entity.StageValidate,
+		entity.StageComplement,
+		entity.StageNumber,
+		entity.StageWeigh,
+		entity.StageAffix,
+	}
+	assert.Equal(t, expected, pipeline.StageNames())
+
+	replaced := pipeline.Replace(&noComplementStage{})
+	assert.Equal(t, expected, replaced.StageNames())
+
+	batch := entity.NewProcessBatch(inputRows("FG0A-CLEAR-OPPOA3"), entity.DefaultProcessOptions())
+	require.NoError(t, replaced.Run(batch))
+	assert.Len(t, batch.CleanedOrders, 1)
+
+	// the original pipeline is left as it was
+	batch = entity.NewProcessBatch(inputRows("FG0A-CLEAR-OPPOA3"), entity.DefaultProcessOptions())
+	require.NoError(t, pipeline.Run(batch))
+	assert.Len(t, batch.CleanedOrders, 3)
+}
+
+func TestOrderProcessor_TenantPipelines(t *testing.T) {
+	pipeline := implementation.NewDefaultPipeline(parser.NewProductParser(), implementation.NewComplementaryCalculator(), nil)
+
+	processor := implementation.NewOrderProcessor(nil, nil, implementation.OrderProcessorOptions{
+		Pipeline: pipeline,
+		TenantPipelines: map[string]*implementation.Pipeline{
+			"acme": pipeline.Replace(&noComplementStage{}),
+		},
+	})
+
+	input := inputRows("FG0A-CLEAR-OPPOA3")
+
+	result, err := processor.ProcessOrders(input)
+	require.NoError(t, err)
+	assert.Len(t, result, 3)
+
+	result, err = processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme"})
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	result, err = processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "globex"})
+	require.NoError(t, err)
+	assert.Len(t, result, 3)
+
+	result, err = processor.ProcessOrdersWithOptions(input, &entity.ProcessOptions{TenantId: "acme", UnverifiedTenant: true})
+	require.NoError(t, err)
+	assert.Len(t, result, 3, "an unverified tenant runs the default pipeline")
+}
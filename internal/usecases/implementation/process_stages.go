@@ -0,0 +1,673 @@
+package implementation
+
+import (
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/service"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+type normalizeStage struct{}
+
+type parseStage struct {
+	productParser service.ProductParser
+}
+
+type allocateStage struct {
+	priceList usecase.PriceList
+}
+
+type validateStage struct{}
+
+type complementStage struct {
+	complementaryCalculator usecase.ComplementaryCalculator
+	stockChecker            usecase.StockChecker
+}
+
+type numberStage struct{}
+
+type weighStage struct{}
+
+type affixStage struct{}
+
+// MaxOutstandingRows bounds the rows parsing under a budget at once across
+// the process. The parser cannot be cancelled, so a row that overruns its
+// budget keeps its slot until it returns. A row that finds every slot taken
+// waits for one within its budget
+const MaxOutstandingRows = 64
+
+var rowSlots = make(chan struct{}, MaxOutstandingRows)
+
+type rowResult struct {
+	products []*entity.Product
+	err      error
+	panicked interface{}
+	stack    []byte
+}
+
+// rejects nil or invalid input orders and opens a row for each one
+func NewNormalizeStage() usecase.ProcessStage {
+	return &normalizeStage{}
+}
+
+// splits each row into products under the row timeout and batch deadline
+func NewParseStage(parser service.ProductParser) usecase.ProcessStage {
+	return &parseStage{productParser: parser}
+}
+
+// fills rows sent without a total from priceList (optional), records how
+// each remaining row total was split across its products and then spreads
+// the row's discount and surcharge over them
+func NewAllocateStage(priceList usecase.PriceList) usecase.ProcessStage {
+	return &allocateStage{priceList: priceList}
+}
+
+func NewValidateStage() usecase.ProcessStage {
+	return &validateStage{}
+}
+
+// per batch or per order depending on ProcessOptions.ComplementaryUnit, rows
+// of channels ProcessOptions.ChannelPolicies excludes get none. stockChecker
+// is optional, when set out of stock cleaners are replaced as
+// ProcessOptions.CleanerSubstitutions says
+func NewComplementStage(complementaryCalculator usecase.ComplementaryCalculator, stockChecker usecase.StockChecker) usecase.ProcessStage {
+	return &complementStage{
+		complementaryCalculator: complementaryCalculator,
+		stockChecker:            stockChecker,
+	}
+}
+
+// numbers main lines in input order followed by complementary lines in
+// entity.ComplementaryOrdering, or with ComplementaryInterleaved each row's
+// complementary lines right after its main lines. Lines over
+// ProcessOptions.MaxLineQty are split first
+func NewNumberStage() usecase.ProcessStage {
+	return &numberStage{}
+}
+
+// sets the shipping weight of every line ProcessOptions.WeightCatalog knows,
+// before the affix stage changes the product ids
+func NewWeighStage() usecase.ProcessStage {
+	return &weighStage{}
+}
+
+// puts the tenant's SKU prefix and suffix around every output product id,
+// kit components included. Lines keep the plain id as their catalog id
+func NewAffixStage() usecase.ProcessStage {
+	return &affixStage{}
+}
+
+func (s *normalizeStage) Name() entity.StageName {
+	return entity.StageNormalize
+}
+
+func (s *normalizeStage) Run(batch *entity.ProcessBatch) error {
+	for i, order := range batch.InputOrders {
+		if order == nil {
+			log.Errorf("input order at index is nil", log.S("index", strconv.Itoa(i)))
+			return errors.ErrInvalidInput
+		}
+
+		if err := order.IsValid(); err != nil {
+			log.Errorf("input order is invalid", log.S("order_no", strconv.Itoa(order.No)), log.E(err))
+			return err
+		}
+	}
+
+	batch.Rows = make([]*entity.ProcessRow, len(batch.InputOrders))
+	for i, order := range batch.InputOrders {
+		batch.Rows[i] = &entity.ProcessRow{Input: order}
+	}
+
+	return nil
+}
+
+func (s *parseStage) Name() entity.StageName {
+	return entity.StageParse
+}
+
+func (s *parseStage) Run(batch *entity.ProcessBatch) error {
+	for _, row := range batch.ActiveRows() {
+		products, err := s.parseRowWithinBudget(row.Input, batch.Deadline, batch.Options)
+		if err != nil {
+			if err := batch.FailRow(row, err); err != nil {
+				return err
+			}
+			continue
+		}
+		row.Products = products
+	}
+
+	return nil
+}
+
+// runs parseRow under the row timeout and whatever is left of the batch deadline,
+// a row that overruns is failed with ErrProcessingTimeout. A row waits for a
+// parse slot within the same budget, its own budget starts once it has one
+func (s *parseStage) parseRowWithinBudget(inputOrder *entity.InputOrder, deadline time.Time, options *entity.ProcessOptions) ([]*entity.Product, error) {
+	budget, expired := rowBudget(options.RowTimeout, deadline)
+	if expired {
+		log.Errorf("batch deadline exceeded before row", log.S("order_no", strconv.Itoa(inputOrder.No)))
+		return nil, errors.NewRowError(inputOrder.No, errors.ErrProcessingTimeout)
+	}
+
+	if budget <= 0 {
+		return s.rowOutcome(inputOrder, s.parseRowRecovering(inputOrder, options), options)
+	}
+
+	// stalled rows cannot pile up goroutines without bound, a row waits for
+	// one of them to return for as long as its budget allows
+	wait := time.NewTimer(budget)
+	select {
+	case rowSlots <- struct{}{}:
+		wait.Stop()
+	case <-wait.C:
+		log.Errorf("no parse slot freed within budget",
+			log.S("order_no", strconv.Itoa(inputOrder.No)),
+			log.S("budget", budget.String()))
+		return nil, errors.NewRowError(inputOrder.No, errors.ErrProcessingTimeout)
+	}
+
+	// the wait used up part of the batch deadline
+	if budget, expired = rowBudget(options.RowTimeout, deadline); expired {
+		<-rowSlots
+		log.Errorf("batch deadline exceeded waiting for a parse slot", log.S("order_no", strconv.Itoa(inputOrder.No)))
+		return nil, errors.NewRowError(inputOrder.No, errors.ErrProcessingTimeout)
+	}
+
+	timer := time.NewTimer(budget)
+	defer timer.Stop()
+
+	done := make(chan rowResult, 1)
+	go func() {
+		defer func() { <-rowSlots }()
+		done <- s.parseRowRecovering(inputOrder, options)
+	}()
+
+	select {
+	case result := <-done:
+		return s.rowOutcome(inputOrder, result, options)
+	case <-timer.C:
+		log.Errorf("row exceeded processing budget",
+			log.S("order_no", strconv.Itoa(inputOrder.No)),
+			log.S("budget", budget.String()))
+		return nil, errors.NewRowError(inputOrder.No, errors.ErrProcessingTimeout)
+	}
+}
+
+// the row timeout cut to what is left of the deadline, zero when neither is
+// set. expired when the deadline has passed
+func rowBudget(rowTimeout time.Duration, deadline time.Time) (budget time.Duration, expired bool) {
+	if deadline.IsZero() {
+		return rowTimeout, false
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0, true
+	}
+	if rowTimeout <= 0 || remaining < rowTimeout {
+		return remaining, false
+	}
+	return rowTimeout, false
+}
+
+func (s *parseStage) parseRowRecovering(inputOrder *entity.InputOrder, options *entity.ProcessOptions) (result rowResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = rowResult{panicked: r, stack: debug.Stack()}
+		}
+	}()
+
+	products, err := s.parseRow(inputOrder, options)
+	return rowResult{products: products, err: err}
+}
+
+// a lenient batch turns a panicking row into a row error, otherwise the panic
+// is re-raised on the caller goroutine so the usual recovery still applies
+func (s *parseStage) rowOutcome(inputOrder *entity.InputOrder, result rowResult, options *entity.ProcessOptions) ([]*entity.Product, error) {
+	if result.panicked == nil {
+		return result.products, result.err
+	}
+
+	if !options.IsLenient() {
+		panic(result.panicked)
+	}
+
+	log.Errorf("row processing panicked",
+		log.S("order_no", strconv.Itoa(inputOrder.No)),
+		log.AtoS("panic", result.panicked),
+		log.S("stack", string(result.stack)))
+	return nil, errors.NewRowError(inputOrder.No, errors.ErrRowPanicked)
+}
+
+func (s *parseStage) parseRow(inputOrder *entity.InputOrder, options *entity.ProcessOptions) ([]*entity.Product, error) {
+	parsedProducts, err := s.productParser.ParseWithQuantities(
+		inputOrder.PlatformProductId,
+		inputOrder.Qty,
+		inputOrder.TotalPrice,
+		options.QuantitySemantics(),
+	)
+	if err != nil {
+		log.Errorf("failed to parse product id", log.S("product_id", inputOrder.PlatformProductId), log.E(err))
+		return nil, err
+	}
+
+	// a plain row is no bundle, however large its quantity
+	units := 0
+	for _, parsedProduct := range parsedProducts {
+		units += parsedProduct.BundleUnits()
+	}
+	if len(parsedProducts) > 1 && options.ExceedsBundleLimits(len(parsedProducts), units) {
+		log.Errorf("bundle exceeds limits",
+			log.S("order_no", strconv.Itoa(inputOrder.No)),
+			log.S("components", strconv.Itoa(len(parsedProducts))),
+			log.S("units", strconv.Itoa(units)))
+		return nil, errors.NewRowError(inputOrder.No, errors.ErrBundleTooLarge)
+	}
+
+	products := make([]*entity.Product, 0, len(parsedProducts))
+	for _, parsedProduct := range parsedProducts {
+		product, err := s.createProductFromParsed(parsedProduct, options)
+		if err != nil {
+			log.Errorf("failed to create product from parsed data", log.S("product_id", parsedProduct.CleanProductId), log.E(err))
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+func (s *parseStage) createProductFromParsed(parsedProduct *entity.ParsedProduct, options *entity.ProcessOptions) (*entity.Product, error) {
+	if options.IsAccessory(parsedProduct.CleanProductId) {
+		product := entity.NewAccessoryProduct(
+			parsedProduct.CleanProductId,
+			parsedProduct.Quantity,
+			parsedProduct.UnitPrice,
+			parsedProduct.TotalPrice,
+		)
+		product.ComplementsCloth = options.AccessoryComplementsCloth(parsedProduct.CleanProductId)
+		product.Rewrites = append([]entity.BatchWarningCode(nil), parsedProduct.Rewrites...)
+		return product, nil
+	}
+
+	materialId, modelId, err := s.productParser.ParseProductCode(parsedProduct.CleanProductId)
+	if err != nil {
+		log.Errorf("failed to parse product code", log.S("product_code", parsedProduct.CleanProductId), log.E(err))
+		return nil, err
+	}
+
+	productId := parsedProduct.CleanProductId
+	rewrites := append([]entity.BatchWarningCode(nil), parsedProduct.Rewrites...)
+	model, variant, hasVariant := options.ExtractVariant(modelId)
+	if hasVariant {
+		log.Warnf("extracted variant token",
+			log.S("product_id", productId),
+			log.S("variant", variant))
+		modelId = model
+		productId = materialId + "-" + modelId
+		rewrites = append(rewrites, entity.WarningVariantExtracted)
+	}
+	if normalized, ok := options.NormalizeModelSuffix(modelId); ok {
+		log.Warnf("normalized model suffix",
+			log.S("product_id", productId),
+			log.S("model_id", modelId),
+			log.S("normalized_model_id", normalized))
+		modelId = normalized
+		productId = materialId + "-" + modelId
+		rewrites = append(rewrites, entity.WarningModelSuffixNormalized)
+	}
+
+	return &entity.Product{
+		ProductId:  productId,
+		MaterialId: materialId,
+		ModelId:    modelId,
+		Variant:    variant,
+		Rewrites:   rewrites,
+		Quantity:   parsedProduct.Quantity,
+		UnitPrice:  parsedProduct.UnitPrice,
+		TotalPrice: parsedProduct.TotalPrice,
+	}, nil
+}
+
+func (s *allocateStage) Name() entity.StageName {
+	return entity.StageAllocate
+}
+
+func (s *allocateStage) Run(batch *entity.ProcessBatch) error {
+	for _, row := range batch.ActiveRows() {
+		enriched, err := s.enrichPrices(row, batch.Options)
+		if err != nil {
+			if err := batch.FailRow(row, err); err != nil {
+				return err
+			}
+			continue
+		}
+		row.PriceEnriched = enriched
+
+		// an enriched row has no input total to split
+		if !enriched {
+			batch.PriceSplits = append(batch.PriceSplits, entity.NewPriceSplit(row.Input, row.Products, batch.Options.EffectivePricePolicy()))
+		}
+
+		if !row.Input.HasAdjustment() {
+			continue
+		}
+		if err := entity.AllocateAdjustment(row.Products, row.Input.Adjustment(), batch.Options.EffectivePricePolicy()); err != nil {
+			log.Errorf("failed to allocate discount and surcharge", log.S("order_no", strconv.Itoa(row.Input.No)), log.E(err))
+			if err := batch.FailRow(row, errors.NewRowError(row.Input.No, err)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// prices the products of a row sent without a total from the tenant price list,
+// products the list has no price for keep their zero price
+func (s *allocateStage) enrichPrices(row *entity.ProcessRow, options *entity.ProcessOptions) (bool, error) {
+	if s.priceList == nil || !row.Input.NeedsPriceEnrichment() {
+		return false, nil
+	}
+
+	enriched := false
+	for _, product := range row.Products {
+		unitPrice, ok := s.priceList.UnitPrice(options.PriceListTenantId(), product.ProductId)
+		if !ok {
+			log.Warnf("no contract price for product",
+				log.S("tenant_id", options.PriceListTenantId()),
+				log.S("product_id", product.ProductId))
+			continue
+		}
+
+		if err := product.EnrichPrice(unitPrice); err != nil {
+			log.Errorf("failed to enrich product price", log.S("product_id", product.ProductId), log.E(err))
+			return false, errors.NewRowError(row.Input.No, err)
+		}
+		enriched = true
+	}
+
+	return enriched, nil
+}
+
+func (s *validateStage) Name() entity.StageName {
+	return entity.StageValidate
+}
+
+func (s *validateStage) Run(batch *entity.ProcessBatch) error {
+	for _, row := range batch.ActiveRows() {
+		for _, product := range row.Products {
+			if err := product.IsValid(); err != nil {
+				log.Errorf("invalid product",
+					log.S("order_no", strconv.Itoa(row.Input.No)),
+					log.S("product_id", product.ProductId),
+					log.E(err))
+				if err := batch.FailRow(row, err); err != nil {
+					return err
+				}
+				break
+			}
+			if batch.Options.ChecksFilmTexture(entity.FilmTextureReject) && !product.IsTextureCompatible(batch.Options.FilmTextureMatrixInEffect()) {
+				log.Errorf("texture not made for film type",
+					log.S("order_no", strconv.Itoa(row.Input.No)),
+					log.S("material_id", product.MaterialId))
+				if err := batch.FailRow(row, errors.NewRowError(row.Input.No, errors.ErrIncompatibleTexture)); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *complementStage) Name() entity.StageName {
+	return entity.StageComplement
+}
+
+// lines come out numbered from 1, the number stage renumbers them
+func (s *complementStage) Run(batch *entity.ProcessBatch) error {
+	if err := s.calculate(batch); err != nil {
+		return err
+	}
+	// before kits, which would hide the cleaners and cloths in them
+	if err := s.applyCaps(batch); err != nil {
+		return err
+	}
+
+	if batch.Options.EmitsKits() {
+		batch.ComplementaryLines = entity.BundleIntoKits(batch.ComplementaryLines)
+	}
+
+	if s.stockChecker != nil {
+		batch.Options.CleanerSubstitutions.Substitute(batch.ComplementaryLines, s.stockChecker.InStock)
+	}
+	batch.Options.CustomsClassification.Classify(batch.ComplementaryLines)
+	batch.Options.PromoCampaigns.Attribute(batch.ComplementaryLines)
+
+	// after substitution, which can give several lines the same product
+	if batch.Options.MergesComplementaryDuplicates() {
+		lines, err := entity.MergeDuplicateLines(batch.ComplementaryLines, batch.Options.EffectivePricePolicy())
+		if err != nil {
+			log.Errorf("failed to merge complementary lines", log.E(err))
+			return err
+		}
+		batch.ComplementaryLines = lines
+	}
+
+	return nil
+}
+
+func (s *complementStage) calculate(batch *entity.ProcessBatch) error {
+	if !batch.Options.IsComplementaryPerOrder() {
+		var products []*entity.Product
+		for _, row := range batch.ComplementedRows() {
+			products = append(products, row.Products...)
+		}
+
+		lines, err := s.complementaryCalculator.CalculateWithStartingOrderNo(products, 1)
+		if err != nil {
+			log.Errorf("failed to calculate complementary items", log.E(err))
+			return err
+		}
+		batch.ComplementaryLines = lines
+		return nil
+	}
+
+	// each row on its own, linked back to it through ParentNo
+	batch.ComplementaryLines = nil
+	for _, row := range batch.ComplementedRows() {
+		lines, err := s.complementaryCalculator.CalculateWithStartingOrderNo(row.Products, 1)
+		if err != nil {
+			log.Errorf("failed to calculate complementary items",
+				log.S("order_no", strconv.Itoa(row.Input.No)),
+				log.E(err))
+			return err
+		}
+
+		for _, line := range lines {
+			line.ParentNo = row.Input.No
+			line.ExternalRef = row.Input.ExternalRef
+		}
+		batch.ComplementaryLines = append(batch.ComplementaryLines, lines...)
+	}
+
+	return nil
+}
+
+// trims the complementary lines to the tenant's value caps. Units taken from
+// a row's lines are charged to that row, those taken from lines counted over
+// the batch to its first complemented row
+func (s *complementStage) applyCaps(batch *entity.ProcessBatch) error {
+	caps := batch.Options.ValueCaps()
+	rows := batch.ComplementedRows()
+	if len(caps) == 0 || len(rows) == 0 {
+		return nil
+	}
+
+	rowsByNo := make(map[int]*entity.ProcessRow, len(rows))
+	batchValue := 0.0
+	for _, row := range rows {
+		rowsByNo[row.Input.No] = row
+		batchValue += row.Value()
+	}
+
+	for _, valueCap := range caps {
+		var withheld map[int]int
+		var err error
+		if valueCap.Scope == entity.ComplementaryCapPerRow && batch.Options.IsComplementaryPerOrder() {
+			batch.ComplementaryLines, withheld, err = s.trimPerRow(valueCap, batch.ComplementaryLines, rowsByNo)
+		} else {
+			allowance := valueCap.Allowance(batchValue)
+			if valueCap.Scope == entity.ComplementaryCapPerRow {
+				allowance = 0
+				for _, row := range rows {
+					allowance += valueCap.Allowance(row.Value())
+				}
+			}
+			batch.ComplementaryLines, withheld, err = valueCap.Trim(batch.ComplementaryLines, allowance)
+		}
+		if err != nil {
+			return err
+		}
+
+		for parentNo, units := range withheld {
+			row, ok := rowsByNo[parentNo]
+			if !ok {
+				row = rows[0]
+			}
+			row.CappedQty += units
+			log.Warnf("complementary items capped",
+				log.S("order_no", strconv.Itoa(row.Input.No)),
+				log.S("item", string(valueCap.Item)),
+				log.S("units", strconv.Itoa(units)))
+		}
+	}
+
+	return nil
+}
+
+// each row's lines against the row's own allowance, lines of one row are
+// next to each other when counted per order
+func (s *complementStage) trimPerRow(valueCap *entity.ComplementaryCap, lines []*entity.CleanedOrder, rowsByNo map[int]*entity.ProcessRow) ([]*entity.CleanedOrder, map[int]int, error) {
+	trimmed := make([]*entity.CleanedOrder, 0, len(lines))
+	withheld := make(map[int]int)
+	for start := 0; start < len(lines); {
+		end := start
+		for end < len(lines) && lines[end].ParentNo == lines[start].ParentNo {
+			end++
+		}
+
+		allowance := 0
+		if row, ok := rowsByNo[lines[start].ParentNo]; ok {
+			allowance = valueCap.Allowance(row.Value())
+		}
+		kept, taken, err := valueCap.Trim(lines[start:end], allowance)
+		if err != nil {
+			return nil, nil, err
+		}
+		trimmed = append(trimmed, kept...)
+		for parentNo, units := range taken {
+			withheld[parentNo] += units
+		}
+		start = end
+	}
+	return trimmed, withheld, nil
+}
+
+func (s *numberStage) Name() entity.StageName {
+	return entity.StageNumber
+}
+
+func (s *numberStage) Run(batch *entity.ProcessBatch) error {
+	for i, line := range batch.ComplementaryLines {
+		if line == nil {
+			log.Errorf("complementary line at index is nil", log.S("index", strconv.Itoa(i)))
+			return errors.ErrInvalidInput
+		}
+	}
+
+	var mainLines []*entity.CleanedOrder
+	for _, row := range batch.ActiveRows() {
+		for _, product := range row.Products {
+			line := product.ToCleanedOrder(0)
+			line.ParentNo = row.Input.No
+			line.ExternalRef = row.Input.ExternalRef
+			mainLines = append(mainLines, line)
+		}
+	}
+
+	policy := batch.Options.EffectivePricePolicy()
+	mainLines, err := entity.SplitLines(mainLines, batch.Options.LineQtyLimit(), policy)
+	if err != nil {
+		return err
+	}
+	complementaryLines, err := entity.SplitLines(batch.ComplementaryLines, batch.Options.LineQtyLimit(), policy)
+	if err != nil {
+		return err
+	}
+
+	capped := make(map[int]int)
+	for _, row := range batch.ActiveRows() {
+		if row.CappedQty > 0 {
+			capped[row.Input.No] = row.CappedQty
+		}
+	}
+	for _, line := range mainLines {
+		if units, ok := capped[line.ParentNo]; ok {
+			line.CappedQty = units
+			delete(capped, line.ParentNo)
+		}
+	}
+
+	batch.CleanedOrders = entity.NumberLines(mainLines, complementaryLines, batch.Options)
+
+	for _, order := range batch.CleanedOrders {
+		if err := order.IsValid(); err != nil {
+			log.Errorf("cleaned order is invalid", log.S("order_no", strconv.Itoa(order.No)), log.E(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *weighStage) Name() entity.StageName {
+	return entity.StageWeigh
+}
+
+func (s *weighStage) Run(batch *entity.ProcessBatch) error {
+	batch.Options.WeightCatalog.Weigh(batch.CleanedOrders)
+	return nil
+}
+
+func (s *affixStage) Name() entity.StageName {
+	return entity.StageAffix
+}
+
+func (s *affixStage) Run(batch *entity.ProcessBatch) error {
+	affix := batch.Options.SkuAffix()
+	if affix == nil {
+		return nil
+	}
+
+	// the lines are shared with batch.ComplementaryLines, the catalog id keeps
+	// price list lookups after this stage working
+	for _, line := range batch.CleanedOrders {
+		line.CatalogProductId = line.CatalogId()
+		line.ProductId = affix.Apply(line.CatalogProductId)
+		for _, component := range line.Components {
+			component.ProductId = affix.Apply(component.ProductId)
+		}
+	}
+
+	return nil
+}
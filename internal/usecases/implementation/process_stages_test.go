@@ -0,0 +1,500 @@
+package implementation_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/internal/usecases/implementation"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStageBatch(options *entity.ProcessOptions, platformProductIds ...string) *entity.ProcessBatch {
+	if options == nil {
+		options = entity.DefaultProcessOptions()
+	}
+	return entity.NewProcessBatch(inputRows(platformProductIds...), options)
+}
+
+func runStages(t *testing.T, batch *entity.ProcessBatch, names ...entity.StageName) {
+	stages := map[entity.StageName]interface {
+		Run(batch *entity.ProcessBatch) error
+	}{
+		entity.StageNormalize:  implementation.NewNormalizeStage(),
+		entity.StageParse:      implementation.NewParseStage(parser.NewProductParser()),
+		entity.StageAllocate:   implementation.NewAllocateStage(nil),
+		entity.StageValidate:   implementation.NewValidateStage(),
+		entity.StageComplement: implementation.NewComplementStage(implementation.NewComplementaryCalculator(), nil),
+		entity.StageNumber:     implementation.NewNumberStage(),
+		entity.StageAffix:      implementation.NewAffixStage(),
+	}
+
+	for _, name := range names {
+		require.NoError(t, stages[name].Run(batch), string(name))
+	}
+}
+
+func TestNormalizeStage(t *testing.T) {
+	stage := implementation.NewNormalizeStage()
+	assert.Equal(t, entity.StageNormalize, stage.Name())
+
+	t.Run("Opens a row per input order", func(t *testing.T) {
+		batch := newStageBatch(nil, "FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3")
+
+		require.NoError(t, stage.Run(batch))
+		require.Len(t, batch.Rows, 2)
+		assert.Same(t, batch.InputOrders[1], batch.Rows[1].Input)
+	})
+
+	t.Run("Rejects nil input order", func(t *testing.T) {
+		batch := entity.NewProcessBatch([]*entity.InputOrder{nil}, entity.DefaultProcessOptions())
+
+		assert.ErrorIs(t, stage.Run(batch), errors.ErrInvalidInput)
+		assert.Empty(t, batch.Rows)
+	})
+}
+
+func TestParseStage(t *testing.T) {
+	stage := implementation.NewParseStage(parser.NewProductParser())
+	assert.Equal(t, entity.StageParse, stage.Name())
+
+	batch := newStageBatch(nil, "--FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3*2")
+	runStages(t, batch, entity.StageNormalize)
+
+	require.NoError(t, stage.Run(batch))
+	require.Len(t, batch.Rows[0].Products, 2)
+	assert.Equal(t, "FG0A-CLEAR-OPPOA3", batch.Rows[0].Products[0].ProductId)
+	assert.Equal(t, "FG0A-MATTE", batch.Rows[0].Products[1].MaterialId)
+	assert.Equal(t, 2, batch.Rows[0].Products[1].Quantity)
+}
+
+func TestAllocateStage(t *testing.T) {
+	t.Run("Records a price split per priced row", func(t *testing.T) {
+		stage := implementation.NewAllocateStage(nil)
+		assert.Equal(t, entity.StageAllocate, stage.Name())
+
+		batch := newStageBatch(nil, "FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3")
+		runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+		require.NoError(t, stage.Run(batch))
+		assert.Len(t, batch.PriceSplits, 2)
+	})
+
+	t.Run("Enriched row has no price split", func(t *testing.T) {
+		stage := implementation.NewAllocateStage(priceListStub{"": {"FG0A-CLEAR-OPPOA3": 40}})
+
+		batch := newStageBatch(nil, "FG0A-CLEAR-OPPOA3")
+		batch.InputOrders[0].UnitPrice = value_object.ZeroPrice()
+		batch.InputOrders[0].TotalPrice = value_object.ZeroPrice()
+		runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+		require.NoError(t, stage.Run(batch))
+		assert.True(t, batch.Rows[0].PriceEnriched)
+		assert.Equal(t, 40.0, batch.Rows[0].Products[0].UnitPrice.Amount())
+		assert.Empty(t, batch.PriceSplits)
+	})
+}
+
+func TestValidateStage(t *testing.T) {
+	stage := implementation.NewValidateStage()
+	assert.Equal(t, entity.StageValidate, stage.Name())
+
+	batch := newStageBatch(nil, "FG0A-CLEAR-OPPOA3")
+	runStages(t, batch, entity.StageNormalize, entity.StageParse)
+	require.NoError(t, stage.Run(batch))
+
+	batch.Rows[0].Products[0].ModelId = ""
+	assert.ErrorIs(t, stage.Run(batch), errors.ErrInvalidInput)
+}
+
+func TestValidateStage_FilmTexture(t *testing.T) {
+	matrix := value_object.FilmTextureMatrix{"FG05": {value_object.TextureClear}}
+
+	tests := []struct {
+		strictness entity.FilmTextureStrictness
+		mode       entity.ProcessMode
+		expectErr  bool
+		dropped    bool
+	}{
+		{entity.FilmTextureOff, entity.ProcessModeStrict, false, false},
+		{entity.FilmTextureWarn, entity.ProcessModeStrict, false, false},
+		{entity.FilmTextureReject, entity.ProcessModeStrict, true, false},
+		{entity.FilmTextureReject, entity.ProcessModeLenient, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strictness)+" "+string(tt.mode), func(t *testing.T) {
+			batch := newStageBatch(&entity.ProcessOptions{FilmTextureStrictness: tt.strictness, FilmTextureMatrix: matrix, Mode: tt.mode},
+				"FG05-CLEAR-OPPOA3", "FG05-PRIVACY-OPPOA3")
+			runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+			err := implementation.NewValidateStage().Run(batch)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, errors.ErrIncompatibleTexture)
+				return
+			}
+			require.NoError(t, err)
+			assert.Nil(t, batch.Rows[0].Err)
+			if tt.dropped {
+				require.Len(t, batch.RowErrors, 1)
+				assert.Equal(t, 2, batch.RowErrors[0].No)
+				assert.Equal(t, errors.CategoryBusinessRule, batch.RowErrors[0].Category())
+			} else {
+				assert.Empty(t, batch.RowErrors)
+			}
+		})
+	}
+}
+
+func TestComplementStage(t *testing.T) {
+	stage := implementation.NewComplementStage(implementation.NewComplementaryCalculator(), nil)
+	assert.Equal(t, entity.StageComplement, stage.Name())
+
+	t.Run("Per batch", func(t *testing.T) {
+		batch := newStageBatch(nil, "FG0A-CLEAR-OPPOA3", "FG0A-CLEAR-IPHONE16PROMAX")
+		runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+		require.NoError(t, stage.Run(batch))
+		require.Len(t, batch.ComplementaryLines, 2)
+		assert.Equal(t, "WIPING-CLOTH", batch.ComplementaryLines[0].ProductId)
+		assert.Equal(t, 2, batch.ComplementaryLines[0].Qty)
+		assert.Zero(t, batch.ComplementaryLines[0].ParentNo)
+	})
+
+	t.Run("Per order", func(t *testing.T) {
+		batch := newStageBatch(&entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder},
+			"FG0A-CLEAR-OPPOA3", "FG0A-CLEAR-IPHONE16PROMAX")
+		runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+		require.NoError(t, stage.Run(batch))
+		require.Len(t, batch.ComplementaryLines, 4)
+		assert.Equal(t, 1, batch.ComplementaryLines[0].ParentNo)
+		assert.Equal(t, 2, batch.ComplementaryLines[3].ParentNo)
+	})
+}
+
+type stockCheckerStub map[string]bool
+
+func (s stockCheckerStub) InStock(productId string) bool {
+	return !s[productId]
+}
+
+func TestComplementStage_ChannelPolicies(t *testing.T) {
+	for _, unit := range []entity.ComplementaryUnit{entity.ComplementaryPerBatch, entity.ComplementaryPerOrder} {
+		t.Run(string(unit), func(t *testing.T) {
+			options := entity.DefaultProcessOptions()
+			options.ComplementaryUnit = unit
+			options.ChannelPolicies = entity.ChannelPolicies{"wholesale": entity.ComplementaryNone}
+
+			batch := newStageBatch(options, "FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3")
+			batch.InputOrders[0].Channel = "retail"
+			batch.InputOrders[1].Channel = "Wholesale"
+			runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageComplement)
+
+			// the retail row and the row without a channel, not the wholesale one
+			cloths := 0
+			var cleaners []string
+			for _, line := range batch.ComplementaryLines {
+				assert.NotEqual(t, 2, line.ParentNo)
+				if line.ProductId == entity.WipingClothProductId {
+					cloths += line.Qty
+				} else {
+					cleaners = append(cleaners, line.ProductId)
+				}
+			}
+			assert.Equal(t, 2, cloths)
+			assert.ElementsMatch(t, []string{"CLEAR-CLEANNER", "PRIVACY-CLEANNER"}, cleaners)
+		})
+	}
+}
+
+func TestComplementStage_CleanerSubstitutions(t *testing.T) {
+	stage := implementation.NewComplementStage(
+		implementation.NewComplementaryCalculator(),
+		stockCheckerStub{"PRIVACY-CLEANNER": true},
+	)
+
+	batch := newStageBatch(&entity.ProcessOptions{
+		CleanerSubstitutions: entity.CleanerSubstitutions{"PRIVACY-CLEANNER": "UNIVERSAL-CLEANNER"},
+		CustomsClassification: entity.CustomsClassification{
+			"UNIVERSAL-CLEANNER": {HSCode: "3405.90", UnitValue: 3},
+		},
+	}, "FG0A-PRIVACY-OPPOA3", "FG0A-CLEAR-OPPOA3")
+	runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+	require.NoError(t, stage.Run(batch))
+	require.Len(t, batch.ComplementaryLines, 3)
+
+	substituted := batch.ComplementaryLines[2]
+	assert.Equal(t, "UNIVERSAL-CLEANNER", substituted.ProductId)
+	assert.Equal(t, "PRIVACY-CLEANNER", substituted.SubstitutedFor)
+	assert.Equal(t, 1, substituted.Qty)
+	assert.Equal(t, "3405.90", substituted.Customs.HSCode, "the substitute is classified")
+	assert.Empty(t, batch.ComplementaryLines[1].SubstitutedFor, "clear cleaners are in stock")
+}
+
+func TestComplementStage_ComplementaryDuplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		duplicates entity.ComplementaryDuplicates
+		expectIds  []string
+		expectQtys []int
+	}{
+		{
+			"Merged by default",
+			"",
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "UNIVERSAL-CLEANNER"},
+			[]int{1, 1, 1, 3, 1, 2},
+		},
+		{
+			"Kept separate",
+			entity.ComplementaryDuplicatesSeparate,
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "UNIVERSAL-CLEANNER", "UNIVERSAL-CLEANNER"},
+			[]int{1, 1, 1, 3, 1, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := implementation.NewComplementStage(
+				implementation.NewComplementaryCalculator(),
+				stockCheckerStub{"MATTE-CLEANNER": true, "PRIVACY-CLEANNER": true},
+			)
+			batch := newStageBatch(&entity.ProcessOptions{
+				ComplementaryDuplicates: tt.duplicates,
+				CleanerSubstitutions: entity.CleanerSubstitutions{
+					"MATTE-CLEANNER":   "UNIVERSAL-CLEANNER",
+					"PRIVACY-CLEANNER": "UNIVERSAL-CLEANNER",
+				},
+			}, "FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3")
+			runStages(t, batch, entity.StageNormalize, entity.StageParse)
+
+			require.NoError(t, stage.Run(batch))
+			runStages(t, batch, entity.StageNumber)
+
+			var ids []string
+			var qtys []int
+			for i, line := range batch.CleanedOrders {
+				assert.Equal(t, i+1, line.No)
+				ids = append(ids, line.ProductId)
+				qtys = append(qtys, line.Qty)
+			}
+			assert.Equal(t, tt.expectIds, ids)
+			assert.Equal(t, tt.expectQtys, qtys)
+		})
+	}
+}
+
+func TestComplementStage_ComplementaryCaps(t *testing.T) {
+	cleanerCap := func(scope entity.ComplementaryCapScope) entity.ComplementaryCaps {
+		return entity.ComplementaryCaps{"*": {{Item: entity.ComplementaryCapCleaner, Units: 1, PerValue: 100, Scope: scope}}}
+	}
+
+	// three rows of 50 each
+	tests := []struct {
+		name         string
+		options      *entity.ProcessOptions
+		expectIds    []string
+		expectCapped []int
+	}{
+		{
+			"Batch value earns one cleaner",
+			&entity.ProcessOptions{ComplementaryCaps: cleanerCap(entity.ComplementaryCapPerBatch)},
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER"},
+			[]int{2, 0, 0, 0, 0},
+		},
+		{
+			"Rows below the value earn nothing",
+			&entity.ProcessOptions{ComplementaryCaps: cleanerCap(entity.ComplementaryCapPerRow)},
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH"},
+			[]int{3, 0, 0, 0},
+		},
+		{
+			"Counted per order, each row is charged its own",
+			&entity.ProcessOptions{ComplementaryUnit: entity.ComplementaryPerOrder, ComplementaryCaps: cleanerCap(entity.ComplementaryCapPerRow)},
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "WIPING-CLOTH", "WIPING-CLOTH"},
+			[]int{1, 1, 1, 0, 0, 0},
+		},
+		{
+			"Cap that does not bite",
+			&entity.ProcessOptions{ComplementaryCaps: entity.ComplementaryCaps{"*": {{Item: entity.ComplementaryCapCloth, Units: 1, PerValue: 50, Scope: entity.ComplementaryCapPerBatch}}}},
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "MATTE-CLEANNER", "PRIVACY-CLEANNER"},
+			[]int{0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			"Other tenants keep their items",
+			&entity.ProcessOptions{TenantId: "acme", ComplementaryCaps: entity.ComplementaryCaps{"globex": cleanerCap(entity.ComplementaryCapPerRow)["*"]}},
+			[]string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER", "MATTE-CLEANNER", "PRIVACY-CLEANNER"},
+			[]int{0, 0, 0, 0, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := newStageBatch(entity.DefaultProcessOptions().WithOverrides(tt.options), "FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3", "FG0A-PRIVACY-OPPOA3")
+			runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageAllocate, entity.StageComplement, entity.StageNumber)
+
+			var ids []string
+			var capped []int
+			for _, line := range batch.CleanedOrders {
+				ids = append(ids, line.ProductId)
+				capped = append(capped, line.CappedQty)
+			}
+			assert.Equal(t, tt.expectIds, ids)
+			assert.Equal(t, tt.expectCapped, capped)
+		})
+	}
+}
+
+func TestNumberStage(t *testing.T) {
+	stage := implementation.NewNumberStage()
+	assert.Equal(t, entity.StageNumber, stage.Name())
+
+	batch := newStageBatch(nil, "FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3", "FG0A-CLEAR-IPHONE16PROMAX")
+	runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageComplement)
+
+	require.NoError(t, stage.Run(batch))
+	require.Len(t, batch.CleanedOrders, 6)
+
+	for i, line := range batch.CleanedOrders {
+		assert.Equal(t, i+1, line.No)
+	}
+	assert.Equal(t, []int{1, 1, 2}, []int{
+		batch.CleanedOrders[0].ParentNo,
+		batch.CleanedOrders[1].ParentNo,
+		batch.CleanedOrders[2].ParentNo,
+	})
+	assert.Equal(t, "WIPING-CLOTH", batch.CleanedOrders[3].ProductId)
+}
+
+func TestNumberStage_MaxLineQty(t *testing.T) {
+	options := entity.DefaultProcessOptions()
+	options.MaxLineQty = 2
+	batch := newStageBatch(options, "FG0A-CLEAR-IPHONE16PROMAX")
+	batch.InputOrders[0].Qty = 5
+	batch.InputOrders[0].TotalPrice = value_object.MustNewPrice(250)
+	runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageComplement, entity.StageNumber)
+
+	var qtys []int
+	for i, line := range batch.CleanedOrders {
+		assert.Equal(t, i+1, line.No)
+		qtys = append(qtys, line.Qty)
+	}
+	// the main line, its wiping cloths and its cleaners
+	assert.Equal(t, []int{2, 2, 1, 2, 2, 1, 2, 2, 1}, qtys)
+	assert.Equal(t, 100.0, batch.CleanedOrders[0].TotalPrice.Amount())
+	assert.Equal(t, 50.0, batch.CleanedOrders[2].TotalPrice.Amount())
+}
+
+func TestAffixStage(t *testing.T) {
+	stage := implementation.NewAffixStage()
+	assert.Equal(t, entity.StageAffix, stage.Name())
+
+	affixes := entity.SkuAffixes{"acme": {Prefix: "TH-", Suffix: "-X"}}
+
+	t.Run("Tenant with an affix", func(t *testing.T) {
+		batch := newStageBatch(&entity.ProcessOptions{
+			TenantId:   "acme",
+			SkuAffixes: affixes,
+			KitTenants: entity.KitTenants{"acme"},
+		}, "FG0A-CLEAR-OPPOA3")
+		runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageComplement, entity.StageNumber)
+
+		require.NoError(t, stage.Run(batch))
+		require.Len(t, batch.CleanedOrders, 2)
+		assert.Equal(t, "TH-FG0A-CLEAR-OPPOA3-X", batch.CleanedOrders[0].ProductId)
+		assert.Equal(t, "FG0A-CLEAR", batch.CleanedOrders[0].MaterialId, "only the product id is affixed")
+		assert.Equal(t, "TH-CARE-KIT-CLEAR-X", batch.CleanedOrders[1].ProductId)
+		assert.Equal(t, "TH-WIPING-CLOTH-X", batch.CleanedOrders[1].Components[0].ProductId)
+		assert.Equal(t, "TH-CLEAR-CLEANNER-X", batch.CleanedOrders[1].Components[1].ProductId)
+
+		assert.Equal(t, "FG0A-CLEAR-OPPOA3", batch.CleanedOrders[0].CatalogId())
+		require.Len(t, batch.ComplementaryLines, 1)
+		assert.Equal(t, "CARE-KIT-CLEAR", batch.ComplementaryLines[0].CatalogId(), "shared lines keep the catalog id")
+	})
+
+	t.Run("Other tenants are left alone", func(t *testing.T) {
+		batch := newStageBatch(&entity.ProcessOptions{TenantId: "other", SkuAffixes: affixes}, "FG0A-CLEAR-OPPOA3")
+		runStages(t, batch, entity.StageNormalize, entity.StageParse, entity.StageComplement, entity.StageNumber)
+
+		require.NoError(t, stage.Run(batch))
+		assert.Equal(t, "FG0A-CLEAR-OPPOA3", batch.CleanedOrders[0].ProductId)
+		assert.Equal(t, "WIPING-CLOTH", batch.CleanedOrders[1].ProductId)
+		assert.Equal(t, "FG0A-CLEAR-OPPOA3", batch.CleanedOrders[0].CatalogId())
+	})
+}
+
+type shufflingCalculator struct {
+	usecase.ComplementaryCalculator
+	rng *rand.Rand
+}
+
+func (c *shufflingCalculator) CalculateWithStartingOrderNo(mainProducts []*entity.Product, startingOrderNo int) ([]*entity.CleanedOrder, error) {
+	lines, err := c.ComplementaryCalculator.CalculateWithStartingOrderNo(mainProducts, startingOrderNo)
+	c.rng.Shuffle(len(lines), func(i, j int) { lines[i], lines[j] = lines[j], lines[i] })
+	return lines, err
+}
+
+// property: whatever order the calculator returns them in, complementary
+// lines come out per parent, wiping cloth first, then cleaners by texture
+func TestNumberStage_ComplementaryOrdering_Property(t *testing.T) {
+	textures := []string{"CLEAR", "MATTE", "PRIVACY"}
+	ordering := entity.ComplementaryOrdering(nil)
+	rank := func(productId string) int {
+		for i, id := range ordering {
+			if id == productId {
+				return i
+			}
+		}
+		return len(ordering)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 100; run++ {
+		inputs := make([]*entity.InputOrder, 1+rng.Intn(5))
+		for i := range inputs {
+			var components []string
+			for j := 0; j <= rng.Intn(3); j++ {
+				components = append(components, "FG0A-"+textures[rng.Intn(len(textures))]+"-OPPOA3")
+			}
+			inputs[i] = &entity.InputOrder{
+				No:                i + 1,
+				PlatformProductId: strings.Join(components, "/"),
+				Qty:               1,
+				UnitPrice:         value_object.MustNewPrice(30),
+				TotalPrice:        value_object.MustNewPrice(30),
+			}
+		}
+
+		unit := entity.ComplementaryPerBatch
+		if rng.Intn(2) == 0 {
+			unit = entity.ComplementaryPerOrder
+		}
+
+		pipeline := implementation.NewDefaultPipeline(
+			parser.NewProductParser(),
+			&shufflingCalculator{ComplementaryCalculator: implementation.NewComplementaryCalculator(), rng: rng},
+			nil,
+		)
+		batch := entity.NewProcessBatch(inputs, &entity.ProcessOptions{ComplementaryUnit: unit})
+		require.NoError(t, pipeline.Run(batch))
+
+		lines := batch.ComplementaryLines
+		require.NotEmpty(t, lines)
+		for i := 1; i < len(lines); i++ {
+			prev, curr := lines[i-1], lines[i]
+			require.Equal(t, prev.No+1, curr.No)
+			require.LessOrEqual(t, prev.ParentNo, curr.ParentNo)
+			if prev.ParentNo == curr.ParentNo {
+				require.Less(t, rank(prev.ProductId), rank(curr.ProductId))
+			}
+		}
+	}
+}
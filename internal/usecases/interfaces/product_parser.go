@@ -1,13 +1,144 @@
 package interfaces
 
 import (
+	"io"
+
 	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
 )
 
 type OrderProcessorUseCase interface {
 	ProcessOrders(inputOrders []*entity.InputOrder) ([]*entity.CleanedOrder, error)
+	ProcessOrdersWithOptions(inputOrders []*entity.InputOrder, options *entity.ProcessOptions) ([]*entity.CleanedOrder, error)
+	RenumberOrders(cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) ([]*entity.CleanedOrder, error)
+}
+
+// ProcessStage is one step of the order processing pipeline, stages are
+// replaced by name
+type ProcessStage interface {
+	Name() entity.StageName
+	Run(batch *entity.ProcessBatch) error
 }
 
 type ComplementaryCalculator interface {
 	CalculateWithStartingOrderNo(mainProducts []*entity.Product, startingOrderNo int) ([]*entity.CleanedOrder, error)
 }
+
+type PriceSplitRecorder interface {
+	RecordBatch(splits []*entity.PriceSplit)
+}
+
+// BusinessRecorder receives every batch that completed, after numbering
+type BusinessRecorder interface {
+	RecordBusinessBatch(batch *entity.ProcessBatch)
+}
+
+// EventPublisher hands domain events to whoever subscribed to them
+type EventPublisher interface {
+	Publish(events ...*entity.DomainEvent)
+}
+
+// StockChecker tells whether a product can be shipped right now
+type StockChecker interface {
+	InStock(productId string) bool
+}
+
+// contract prices per tenant, ok is false when the tenant has no price for the product
+type PriceList interface {
+	UnitPrice(tenantId, productId string) (price *value_object.Price, ok bool)
+}
+
+// BatchUsageRecorder receives what each processed batch cost
+type BatchUsageRecorder interface {
+	RecordBatchUsage(usage *entity.BatchUsage)
+}
+
+type BatchInspector interface {
+	Inspect(inputOrders []*entity.InputOrder, cleanedOrders []*entity.CleanedOrder, options *entity.ProcessOptions) []*entity.BatchWarning
+}
+
+type BatchWarningRecorder interface {
+	RecordWarnings(rows int, warnings []*entity.BatchWarning)
+}
+
+// ConfigVerifier runs the canonical cases and any samples through the
+// processor as it is configured now
+type ConfigVerifier interface {
+	Verify(samples []*entity.VerificationCase, options *entity.ProcessOptions) *entity.VerificationReport
+}
+
+// GarbageTokenRecorder receives the leading tokens of a batch that no known
+// prefix rule stripped
+type GarbageTokenRecorder interface {
+	RecordGarbageTokens(tokens []string)
+}
+
+// CatalogGapRecorder receives the product codes of a batch that failed on an
+// unknown film type or texture
+type CatalogGapRecorder interface {
+	RecordCatalogGaps(gaps []*entity.CatalogGap)
+}
+
+// ReportSource hands out a snapshot of what it counted, the admin and metrics
+// endpoints serve it as it comes
+type ReportSource interface {
+	Report() any
+}
+
+// TenantReportSource is a ReportSource that can be limited to one tenant,
+// every tenant's when tenantId is empty
+type TenantReportSource interface {
+	TenantReport(tenantId string) any
+}
+
+// HeaviestReportSource reports the top heaviest entries first
+type HeaviestReportSource interface {
+	HeaviestReport(top int) any
+}
+
+// CatalogGapReport also drops a gap once the catalog has it, false when there
+// was no such gap
+type CatalogGapReport interface {
+	ReportSource
+	Resolve(kind entity.CatalogGapKind, value string) bool
+}
+
+// DeliveryLog lists the outgoing deliveries newest first and queues one again
+type DeliveryLog interface {
+	DeliveryReport(failedOnly bool) any
+	Redeliver(id string) error
+}
+
+// OpenMetricsSource writes its counters in the OpenMetrics text format
+type OpenMetricsSource interface {
+	WriteOpenMetrics(w io.Writer) error
+}
+
+// ErrorArticleStore keeps one article per error code
+type ErrorArticleStore interface {
+	Search(query string) []*entity.ErrorArticle
+	Get(code string) (article *entity.ErrorArticle, ok bool)
+	Put(article *entity.ErrorArticle) (stored *entity.ErrorArticle, created bool, err error)
+	Delete(code string) bool
+}
+
+// ConfigGeneration counts the changes made to the configuration while the
+// service runs, a result computed under another generation may be stale
+type ConfigGeneration interface {
+	Generation() uint64
+}
+
+// RuntimeRules are the rules admins change while the service runs. A batch
+// runs with the rules in effect when it starts, every change moves the
+// generation on
+type RuntimeRules interface {
+	ConfigGeneration
+	TexturePriorities() value_object.TexturePriorities
+	SetTexturePriorities(priorities value_object.TexturePriorities) error
+	// the aliases added at runtime, on top of the configured ones
+	TextureAliases() value_object.TextureAliases
+	AddTextureAlias(alias string, texture value_object.Texture) error
+	// the rules as options laid over the processor defaults, request options
+	// are laid over them in turn
+	Overrides() *entity.ProcessOptions
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// TTLCache is a small in-memory cache safe for concurrent use,
+// entries expire after ttl and the oldest one is evicted when full
+type TTLCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]entry
+}
+
+func NewTTLCache(ttl time.Duration, maxEntries int) *TTLCache {
+	if maxEntries <= 0 {
+		maxEntries = 1
+	}
+
+	return &TTLCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]entry),
+	}
+}
+
+func (c *TTLCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !time.Now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+func (c *TTLCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+
+	c.entries[key] = entry{
+		value:     value,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// AddIfRoom stores value only when key has no live entry, it reports whether
+// value was stored. Nothing is evicted, when the cache is full of live entries
+// value is not stored and full is true
+func (c *TTLCache) AddIfRoom(key string, value interface{}) (stored, full bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	e, exists := c.entries[key]
+	if exists && now.Before(e.expiresAt) {
+		return false, false
+	}
+
+	if !exists && len(c.entries) >= c.maxEntries {
+		c.dropExpired(now)
+		if len(c.entries) >= c.maxEntries {
+			return false, true
+		}
+	}
+
+	c.entries[key] = entry{
+		value:     value,
+		expiresAt: now.Add(c.ttl),
+	}
+	return true, false
+}
+
+func (c *TTLCache) dropExpired(now time.Time) {
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// drops expired entries, or the one closest to expiry if none has expired yet
+func (c *TTLCache) evict(now time.Time) {
+	oldestKey := ""
+	var oldest time.Time
+
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || e.expiresAt.Before(oldest) {
+			oldestKey = key
+			oldest = e.expiresAt
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,129 @@
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"order-placement-system/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache_GetSet(t *testing.T) {
+	c := cache.NewTTLCache(time.Minute, 10)
+
+	_, ok := c.Get("missing")
+	assert.False(t, ok)
+
+	c.Set("key", "value")
+	value, ok := c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+}
+
+func TestTTLCache_Expiry(t *testing.T) {
+	c := cache.NewTTLCache(20*time.Millisecond, 10)
+
+	c.Set("key", "value")
+	_, ok := c.Get("key")
+	assert.True(t, ok)
+
+	time.Sleep(30 * time.Millisecond)
+
+	_, ok = c.Get("key")
+	assert.False(t, ok)
+}
+
+func TestTTLCache_AddIfRoom(t *testing.T) {
+	c := cache.NewTTLCache(20*time.Millisecond, 2)
+
+	stored, full := c.AddIfRoom("a", 1)
+	assert.True(t, stored)
+	assert.False(t, full)
+
+	stored, full = c.AddIfRoom("a", 2)
+	assert.False(t, stored)
+	assert.False(t, full, "a live key is not stored again")
+
+	c.AddIfRoom("b", 1)
+	stored, full = c.AddIfRoom("c", 1)
+	assert.False(t, stored)
+	assert.True(t, full)
+	_, ok := c.Get("a")
+	assert.True(t, ok, "nothing is evicted")
+
+	time.Sleep(30 * time.Millisecond)
+
+	stored, full = c.AddIfRoom("c", 1)
+	assert.True(t, stored, "expired entries make room")
+	assert.False(t, full)
+}
+
+func TestTTLCache_Eviction(t *testing.T) {
+	t.Run("evicts oldest entry when full", func(t *testing.T) {
+		c := cache.NewTTLCache(time.Minute, 2)
+
+		c.Set("a", 1)
+		time.Sleep(time.Millisecond)
+		c.Set("b", 2)
+		time.Sleep(time.Millisecond)
+		c.Set("c", 3)
+
+		_, ok := c.Get("a")
+		assert.False(t, ok)
+		_, ok = c.Get("b")
+		assert.True(t, ok)
+		_, ok = c.Get("c")
+		assert.True(t, ok)
+	})
+
+	t.Run("prefers evicting expired entries", func(t *testing.T) {
+		c := cache.NewTTLCache(20*time.Millisecond, 2)
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		time.Sleep(30 * time.Millisecond)
+		c.Set("c", 3)
+
+		stored, full := c.AddIfRoom("d", 4)
+		assert.True(t, stored, "both expired entries were dropped")
+		assert.False(t, full)
+	})
+
+	t.Run("overwriting an existing key does not evict", func(t *testing.T) {
+		c := cache.NewTTLCache(time.Minute, 2)
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Set("a", 3)
+
+		value, _ := c.Get("a")
+		assert.Equal(t, 3, value)
+		_, ok := c.Get("b")
+		assert.True(t, ok)
+	})
+}
+
+func TestTTLCache_Concurrency(t *testing.T) {
+	c := cache.NewTTLCache(time.Minute, 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("%d-%d", i, j%10)
+				c.Set(key, j)
+				c.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	c.Set("last", 1)
+	_, ok := c.Get("last")
+	assert.True(t, ok)
+}
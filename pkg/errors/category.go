@@ -0,0 +1,72 @@
+package errors
+
+import "errors"
+
+// Category groups failures for metrics and dashboards, the values are stable
+// and safe to use as metric labels
+type Category string
+
+const (
+	// the input is well formed but names a film type, texture or model the
+	// catalog does not know
+	CategoryCatalogMismatch Category = "catalog_mismatch"
+	// the input cannot be read, e.g. a product code with missing parts
+	CategoryFormatError Category = "format_error"
+	// the input is understood but breaks a rule, e.g. a bundle over the limit
+	CategoryBusinessRule Category = "business_rule_violation"
+	// timeouts, panics and anything else that is not the client's fault
+	CategorySystemError Category = "system_error"
+)
+
+var AllCategories = []Category{
+	CategoryCatalogMismatch,
+	CategoryFormatError,
+	CategoryBusinessRule,
+	CategorySystemError,
+}
+
+// CategorizedError puts err in a category, Is and Error still see err
+type CategorizedError struct {
+	Category Category
+	Err      error
+}
+
+func Categorize(category Category, err error) error {
+	return &CategorizedError{Category: category, Err: err}
+}
+
+func (e *CategorizedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CategorizedError) Unwrap() error {
+	return e.Err
+}
+
+// the innermost explicit category wins, uncategorized errors fall back on the
+// sentinel they wrap
+func CategoryOf(err error) Category {
+	if category := categorizedIn(err); category != "" {
+		return category
+	}
+
+	switch {
+	case errors.Is(err, ErrBundleTooLarge), errors.Is(err, ErrIncompatibleTexture), errors.Is(err, ErrUnprocessableEntity):
+		return CategoryBusinessRule
+	case errors.Is(err, ErrInvalidInput), errors.Is(err, ErrBadRequest):
+		return CategoryFormatError
+	default:
+		return CategorySystemError
+	}
+}
+
+func categorizedIn(err error) Category {
+	var categorized *CategorizedError
+	if errors.As(err, &categorized) {
+		if inner := categorizedIn(categorized.Err); inner != "" {
+			return inner
+		}
+		return categorized.Category
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	errs "order-placement-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected errs.Category
+	}{
+		{"Invalid input is a format error", errs.ErrInvalidInput, errs.CategoryFormatError},
+		{"Bundle too large breaks a business rule", errs.ErrBundleTooLarge, errs.CategoryBusinessRule},
+		{"Incompatible texture breaks a business rule", errs.ErrIncompatibleTexture, errs.CategoryBusinessRule},
+		{"Timeout is a system error", errs.ErrProcessingTimeout, errs.CategorySystemError},
+		{"Panic is a system error", errs.ErrRowPanicked, errs.CategorySystemError},
+		{"Unknown error is a system error", errors.New("boom"), errs.CategorySystemError},
+		{"Explicit category wins", errs.Categorize(errs.CategoryCatalogMismatch, errs.ErrInvalidInput), errs.CategoryCatalogMismatch},
+		{"Category survives a row error", errs.NewRowError(3, errs.Categorize(errs.CategoryCatalogMismatch, errs.ErrInvalidInput)), errs.CategoryCatalogMismatch},
+		{"Innermost category wins", errs.Categorize(errs.CategorySystemError, fmt.Errorf("wrapped: %w", errs.Categorize(errs.CategoryBusinessRule, errs.ErrInvalidInput))), errs.CategoryBusinessRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, errs.CategoryOf(tt.err))
+		})
+	}
+}
+
+func TestCategorizedError_KeepsSentinel(t *testing.T) {
+	err := errs.Categorize(errs.CategoryCatalogMismatch, errs.ErrInvalidInput)
+
+	assert.ErrorIs(t, err, errs.ErrInvalidInput)
+	assert.Equal(t, "invalid input", err.Error())
+	assert.Equal(t, errs.CategoryCatalogMismatch, errs.NewRowError(1, err).Category())
+}
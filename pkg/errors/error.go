@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -18,26 +19,125 @@ var (
 	ErrBadRequest          = errors.New("bad request")
 	ErrUnprocessableEntity = errors.New("unprocessable entity")
 	ErrTooManyRequests     = errors.New("too many requests")
+	ErrProcessingTimeout   = errors.New("processing timeout")
+	ErrBundleTooLarge      = errors.New("bundle too large")
+	ErrIncompatibleTexture = errors.New("texture not made for film type")
+	ErrRowPanicked         = errors.New("row processing panicked")
+	ErrServiceUnavailable  = errors.New("service unavailable")
+	ErrPayloadTooLarge     = errors.New("payload too large")
 )
 
+// Is and As forward to the standard library so callers need one errors import
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+// RowError ties a failure to the input row (order No) that caused it
+type RowError struct {
+	No  int
+	Err error
+}
+
+func NewRowError(no int, err error) *RowError {
+	return &RowError{No: no, Err: err}
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.No, e.Err.Error())
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RowError) Category() Category {
+	return CategoryOf(e.Err)
+}
+
+// RowCodeTimeout marks a row that ran out of its processing budget
+const RowCodeTimeout = "TIMEOUT"
+
+// what clients can branch on besides the category, empty when the failure
+// has no code of its own
+func (e *RowError) Code() string {
+	if errors.Is(e.Err, ErrProcessingTimeout) {
+		return RowCodeTimeout
+	}
+	return ""
+}
+
+// the row may well succeed when sent again, e.g. it ran out of time while
+// the server was busy
+func (e *RowError) Transient() bool {
+	return errors.Is(e.Err, ErrProcessingTimeout)
+}
+
+// PartialError comes back with the results of a batch when some rows were
+// dropped, by a lenient batch or for running out of time when the batch
+// drops timed out rows
+type PartialError struct {
+	Rows []*RowError
+}
+
+func NewPartialError(rows []*RowError) *PartialError {
+	return &PartialError{Rows: rows}
+}
+
+func (e *PartialError) Error() string {
+	if len(e.Rows) == 1 {
+		return fmt.Sprintf("1 row failed: %s", e.Rows[0].Error())
+	}
+	return fmt.Sprintf("%d rows failed, first: %s", len(e.Rows), e.Rows[0].Error())
+}
+
+// some dropped row may succeed when the batch is sent again
+func (e *PartialError) Transient() bool {
+	for _, row := range e.Rows {
+		if row.Transient() {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Rows))
+	for i, row := range e.Rows {
+		errs[i] = row
+	}
+	return errs
+}
+
 func MapJsonError(c *gin.Context, err error) {
-	switch err {
-	case ErrNotFound:
+	switch {
+	case errors.Is(err, ErrNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-	case ErrInvalidInput:
+	case errors.Is(err, ErrInvalidInput):
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case ErrAlreadyExists:
+	case errors.Is(err, ErrAlreadyExists):
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	case ErrUnprocessableEntity:
+	case errors.Is(err, ErrUnprocessableEntity):
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
-	case ErrUnauthorized:
+	case errors.Is(err, ErrUnauthorized):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-	case ErrForbidden:
+	case errors.Is(err, ErrForbidden):
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-	case ErrConflict:
+	case errors.Is(err, ErrConflict):
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	case ErrTooManyRequests:
+	case errors.Is(err, ErrTooManyRequests):
 		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrPayloadTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrServiceUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrProcessingTimeout):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrBundleTooLarge), errors.Is(err, ErrIncompatibleTexture):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 
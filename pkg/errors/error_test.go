@@ -136,6 +136,36 @@ func TestMapJsonError(t *testing.T) {
 			expectedStatusCode: http.StatusTooManyRequests,
 			expectedMessage:    "too many requests",
 		},
+		{
+			name:               "ErrPayloadTooLarge should map to 413",
+			inputError:         errs.ErrPayloadTooLarge,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedMessage:    "payload too large",
+		},
+		{
+			name:               "ErrServiceUnavailable should map to 503",
+			inputError:         errs.ErrServiceUnavailable,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedMessage:    "service unavailable",
+		},
+		{
+			name:               "ErrProcessingTimeout should map to 422",
+			inputError:         errs.ErrProcessingTimeout,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedMessage:    "processing timeout",
+		},
+		{
+			name:               "ErrBundleTooLarge should map to 422",
+			inputError:         errs.NewRowError(2, errs.ErrBundleTooLarge),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedMessage:    "row 2: bundle too large",
+		},
+		{
+			name:               "RowError should map by its wrapped error",
+			inputError:         errs.NewRowError(3, errs.ErrInvalidInput),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "row 3: invalid input",
+		},
 		{
 			name:               "Unknown error should map to 500",
 			inputError:         errors.New("unknown error"),
@@ -270,3 +300,36 @@ func TestErrorMessages_AreUnique(t *testing.T) {
 		messages[message] = true
 	}
 }
+
+func TestPartialError(t *testing.T) {
+	t.Run("Single row", func(t *testing.T) {
+		err := errs.NewPartialError([]*errs.RowError{errs.NewRowError(2, errs.ErrRowPanicked)})
+
+		assert.Equal(t, "1 row failed: row 2: row processing panicked", err.Error())
+		assert.ErrorIs(t, err, errs.ErrRowPanicked)
+	})
+
+	t.Run("Several rows", func(t *testing.T) {
+		err := errs.NewPartialError([]*errs.RowError{
+			errs.NewRowError(2, errs.ErrRowPanicked),
+			errs.NewRowError(5, errs.ErrBundleTooLarge),
+		})
+
+		assert.Equal(t, "2 rows failed, first: row 2: row processing panicked", err.Error())
+		assert.ErrorIs(t, err, errs.ErrBundleTooLarge)
+
+		var rowErr *errs.RowError
+		require.True(t, errors.As(err, &rowErr))
+		assert.Equal(t, 2, rowErr.No)
+		assert.False(t, err.Transient())
+	})
+
+	t.Run("Timed out row is transient", func(t *testing.T) {
+		err := errs.NewPartialError([]*errs.RowError{
+			errs.NewRowError(2, errs.ErrBundleTooLarge),
+			errs.NewRowError(3, errs.ErrProcessingTimeout),
+		})
+
+		assert.True(t, err.Transient())
+	})
+}
@@ -28,6 +28,16 @@ func Default(envName string, defaultValue string) string {
 	return env
 }
 
+// DefaultIfEmpty is Default that also falls back when envName is set to
+// empty, as the blank keys of an env template are
+func DefaultIfEmpty(envName string, defaultValue string) string {
+	env, found := syscall.Getenv(envName)
+	if !found || env == "" {
+		return defaultValue
+	}
+	return env
+}
+
 func Require(envName string, description ...string) string {
 	env, found := syscall.Getenv(envName)
 	if !found {
@@ -144,6 +144,33 @@ func TestDefault(t *testing.T) {
 	}
 }
 
+func TestDefaultIfEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		envName  string
+		envValue string
+		setEnv   bool
+		expected string
+	}{
+		{"Environment variable exists", "TEST_DEFAULT_IF_EMPTY_EXISTS", "existing_value", true, "existing_value"},
+		{"Environment variable does not exist", "TEST_DEFAULT_IF_EMPTY_NOT_EXISTS", "", false, "default_value"},
+		{"Environment variable with empty value", "TEST_DEFAULT_IF_EMPTY_EMPTY", "", true, "default_value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				setEnv(t, tt.envName, tt.envValue)
+				defer unsetEnv(t, tt.envName)
+			} else {
+				unsetEnv(t, tt.envName)
+			}
+
+			assert.Equal(t, tt.expected, load_env.DefaultIfEmpty(tt.envName, "default_value"))
+		})
+	}
+}
+
 func TestRequire(t *testing.T) {
 	tests := []struct {
 		name        string
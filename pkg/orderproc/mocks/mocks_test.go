@@ -0,0 +1,10 @@
+package mocks_test
+
+import (
+	"order-placement-system/pkg/orderproc"
+	"order-placement-system/pkg/orderproc/mocks"
+)
+
+// fails the build when an interface changed and the mocks were not generated
+// again
+var _ orderproc.PriceList = (*mocks.PriceList)(nil)
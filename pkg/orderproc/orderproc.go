@@ -0,0 +1,220 @@
+// Package orderproc runs the order processor as a library, for services that
+// want the parsing and complementary rules without the HTTP server. Nothing
+// here reads the environment, registers routes or starts a server, although
+// gin stays a module dependency through pkg/errors.
+//
+// The identifiers exported by this package follow semantic versioning: they
+// are only removed or changed incompatibly in a new major version. Its types
+// are its own, copied to and from the internal ones, so changes inside the
+// module do not leak into them. New fields may be added.
+//
+// A Processor's configuration is its own, no state is shared between
+// Processors. Texture aliases come from WithParserProfile only.
+package orderproc
+
+import (
+	"regexp"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/internal/usecases/implementation"
+	usecase "order-placement-system/internal/usecases/interfaces"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/utils/parser"
+)
+
+// ParserProfile is how product codes are read
+type ParserProfile struct {
+	// extra spellings of textures, e.g. {"PRIV": "PRIVACY"}
+	TextureAliases map[string]string
+	// read "_" as "-" for exporters that separate the code with underscores
+	UnderscoreSeparators bool
+}
+
+// ComplementaryRules shape the free wiping cloths and cleaners added to a
+// batch. Zero fields keep the defaults: counted per batch, placed after the
+// main lines, duplicates merged, no kits and every channel complemented
+type ComplementaryRules struct {
+	// "batch" or "order"
+	Unit string
+	// "end" or "interleaved"
+	Placement string
+	// "merge" or "separate"
+	Duplicates string
+	// complementary policy by sales channel, "full" or "none"
+	Channels map[string]string
+	// tenants whose cloths and cleaners are packed into kits
+	KitTenants []string
+	// free items allowed per order value, by tenant
+	ValueCaps map[string][]ComplementaryCap
+	// product ids matching Accessories are returned whole and not
+	// complemented, unless they also match ClothAccessories
+	Accessories      *regexp.Regexp
+	ClothAccessories *regexp.Regexp
+}
+
+type config struct {
+	textureAliases       value_object.TextureAliases
+	underscoreSeparators bool
+	options              *entity.ProcessOptions
+	priceList            usecase.PriceList
+}
+
+// Option configures a Processor, see New
+type Option func(*config) error
+
+func WithParserProfile(profile ParserProfile) Option {
+	return func(c *config) error {
+		var aliases value_object.TextureAliases
+		if profile.TextureAliases != nil {
+			aliases = make(value_object.TextureAliases, len(profile.TextureAliases))
+			for alias, texture := range profile.TextureAliases {
+				aliases[alias] = value_object.Texture(texture)
+			}
+		}
+		if err := aliases.Validate(); err != nil {
+			return err
+		}
+		c.textureAliases = aliases
+		c.underscoreSeparators = profile.UnderscoreSeparators
+		return nil
+	}
+}
+
+func WithComplementaryRules(rules ComplementaryRules) Option {
+	return func(c *config) error {
+		unit := entity.ComplementaryUnit(rules.Unit)
+		if unit != "" && !unit.IsValid() {
+			log.Errorf("unknown complementary unit", log.S("unit", rules.Unit))
+			return errors.ErrInvalidInput
+		}
+		placement := entity.ComplementaryPlacement(rules.Placement)
+		if placement != "" && !placement.IsValid() {
+			log.Errorf("unknown complementary placement", log.S("placement", rules.Placement))
+			return errors.ErrInvalidInput
+		}
+		duplicates := entity.ComplementaryDuplicates(rules.Duplicates)
+		if duplicates != "" && !duplicates.IsValid() {
+			log.Errorf("unknown complementary duplicates", log.S("duplicates", rules.Duplicates))
+			return errors.ErrInvalidInput
+		}
+
+		var channels entity.ChannelPolicies
+		if rules.Channels != nil {
+			channels = make(entity.ChannelPolicies, len(rules.Channels))
+			for channel, policy := range rules.Channels {
+				channels[channel] = entity.ComplementaryPolicy(policy)
+			}
+		}
+		if err := channels.Validate(); err != nil {
+			return err
+		}
+		caps := toComplementaryCaps(rules.ValueCaps)
+		if err := caps.Validate(); err != nil {
+			return err
+		}
+
+		c.options = c.options.WithOverrides(&entity.ProcessOptions{
+			ComplementaryUnit:       unit,
+			ComplementaryPlacement:  placement,
+			ComplementaryDuplicates: duplicates,
+			ChannelPolicies:         channels,
+			KitTenants:              entity.KitTenants(rules.KitTenants),
+			ComplementaryCaps:       caps,
+			AccessoryPattern:        rules.Accessories,
+			ClothAccessoryPattern:   rules.ClothAccessories,
+		})
+		return nil
+	}
+}
+
+// WithCurrency rounds prices to the currency's minor unit, e.g. "JPY" to
+// whole yen. Amounts are float64 rounded on every split, not arbitrary
+// precision decimals. zero epsilon means half of the minor unit
+func WithCurrency(currency string, epsilon float64) Option {
+	return func(c *config) error {
+		policy, err := value_object.NewPricePolicy(currency, epsilon)
+		if err != nil {
+			return err
+		}
+		c.options = c.options.WithOverrides(&entity.ProcessOptions{PricePolicy: policy})
+		return nil
+	}
+}
+
+func WithPriceList(priceList PriceList) Option {
+	return func(c *config) error {
+		c.priceList = nil
+		if priceList != nil {
+			c.priceList = priceListAdapter{priceList}
+		}
+		return nil
+	}
+}
+
+// WithOptions applies the non-zero fields of options on top of the options
+// before it, for settings no other Option covers
+func WithOptions(options ProcessOptions) Option {
+	return func(c *config) error {
+		overrides, err := options.toEntity()
+		if err != nil {
+			return err
+		}
+		c.options = c.options.WithOverrides(overrides)
+		return nil
+	}
+}
+
+// Processor turns platform rows into cleaned order lines. It is safe for
+// concurrent use
+type Processor struct {
+	processor usecase.OrderProcessorUseCase
+}
+
+// New applies the options in order, a later option overrides an earlier one
+func New(opts ...Option) (*Processor, error) {
+	c := &config{options: entity.DefaultProcessOptions()}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	productParser := parser.NewProductParserWithOptions(parser.ProductParserOptions{
+		TextureAliases:       c.textureAliases,
+		UnderscoreSeparators: c.underscoreSeparators,
+	})
+	return &Processor{
+		processor: implementation.NewOrderProcessor(productParser, implementation.NewComplementaryCalculator(), implementation.OrderProcessorOptions{
+			ProcessOptions: c.options,
+			PriceList:      c.priceList,
+		}),
+	}, nil
+}
+
+// Process cleans one batch. A batch that dropped rows, failing ones when
+// lenient and rows out of time in every mode, returns the remaining lines
+// together with an *errors.PartialError
+func (p *Processor) Process(orders []*InputOrder) ([]*CleanedOrder, error) {
+	return p.ProcessWithOptions(orders, ProcessOptions{})
+}
+
+// non-zero fields of options override the processor's for this call only.
+// A negative amount fails the batch with errors.ErrInvalidInput
+func (p *Processor) ProcessWithOptions(orders []*InputOrder, options ProcessOptions) ([]*CleanedOrder, error) {
+	overrides, err := options.toEntity()
+	if err != nil {
+		return nil, err
+	}
+	inputOrders, err := toEntities(orders)
+	if err != nil {
+		return nil, errors.ErrInvalidInput
+	}
+
+	lines, err := p.processor.ProcessOrdersWithOptions(inputOrders, overrides)
+	if lines == nil {
+		return nil, err
+	}
+	return fromEntities(lines), err
+}
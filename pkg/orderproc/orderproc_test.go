@@ -0,0 +1,223 @@
+package orderproc_test
+
+import (
+	"regexp"
+	"testing"
+
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/orderproc"
+	"order-placement-system/pkg/orderproc/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func row(t *testing.T, no int, productId string, qty int, total float64) *orderproc.InputOrder {
+	return &orderproc.InputOrder{No: no, PlatformProductId: productId, Qty: qty, UnitPrice: total / float64(qty), TotalPrice: total}
+}
+
+func productIds(lines []*orderproc.CleanedOrder) []string {
+	ids := make([]string, len(lines))
+	for i, line := range lines {
+		ids[i] = line.ProductId
+	}
+	return ids
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []orderproc.Option
+		expectErr bool
+	}{
+		{"Defaults", nil, false},
+		{"Parser profile", []orderproc.Option{orderproc.WithParserProfile(orderproc.ParserProfile{TextureAliases: map[string]string{"PRIV": "PRIVACY"}})}, false},
+		{"Unknown texture alias", []orderproc.Option{orderproc.WithParserProfile(orderproc.ParserProfile{TextureAliases: map[string]string{"PRIV": "SHINY"}})}, true},
+		{"Unknown complementary unit", []orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{Unit: "week"})}, true},
+		{"Unknown placement", []orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{Placement: "start"})}, true},
+		{"Unknown duplicates", []orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{Duplicates: "sum"})}, true},
+		{"Unknown channel policy", []orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{Channels: map[string]string{"b2b": "half"}})}, true},
+		{"Unsupported currency", []orderproc.Option{orderproc.WithCurrency("XXX", 0)}, true},
+		{"Currency", []orderproc.Option{orderproc.WithCurrency("JPY", 0)}, false},
+		{"Unknown process mode", []orderproc.Option{orderproc.WithOptions(orderproc.ProcessOptions{Mode: "loose"})}, true},
+		{"Unknown film texture strictness", []orderproc.Option{orderproc.WithOptions(orderproc.ProcessOptions{FilmTextureStrictness: "loud"})}, true},
+		{"Film type without textures", []orderproc.Option{orderproc.WithOptions(orderproc.ProcessOptions{FilmTextureMatrix: map[string][]string{"FG05": {}}})}, true},
+		{"Duplicate texture priority", []orderproc.Option{orderproc.WithOptions(orderproc.ProcessOptions{TexturePriorities: map[string]int{"CLEAR": 1, "MATTE": 1, "PRIVACY": 2}})}, true},
+		{"Unknown cap item", []orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{
+			ValueCaps: map[string][]orderproc.ComplementaryCap{"*": {{Item: "sticker", Units: 1, PerValue: 100, Scope: "row"}}},
+		})}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor, err := orderproc.New(tt.opts...)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, processor)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, processor)
+			}
+		})
+	}
+}
+
+func TestProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []orderproc.Option
+		orders    func(t *testing.T) []*orderproc.InputOrder
+		expectIds []string
+	}{
+		{
+			"Defaults",
+			nil,
+			func(t *testing.T) []*orderproc.InputOrder {
+				return []*orderproc.InputOrder{row(t, 1, "FG0A-CLEAR-OPPOA3", 2, 80)}
+			},
+			[]string{"FG0A-CLEAR-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER"},
+		},
+		{
+			"Parser profile",
+			[]orderproc.Option{orderproc.WithParserProfile(orderproc.ParserProfile{
+				TextureAliases:       map[string]string{"PRIV": "PRIVACY"},
+				UnderscoreSeparators: true,
+			})},
+			func(t *testing.T) []*orderproc.InputOrder {
+				return []*orderproc.InputOrder{row(t, 1, "FG0A_PRIV_OPPOA3", 1, 50)}
+			},
+			[]string{"FG0A-PRIV-OPPOA3", "WIPING-CLOTH", "PRIVACY-CLEANNER"},
+		},
+		{
+			"Channel without complementary items",
+			[]orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{
+				Channels: map[string]string{"wholesale": "none"},
+			})},
+			func(t *testing.T) []*orderproc.InputOrder {
+				order := row(t, 1, "FG0A-CLEAR-OPPOA3", 2, 80)
+				order.Channel = "wholesale"
+				return []*orderproc.InputOrder{order}
+			},
+			[]string{"FG0A-CLEAR-OPPOA3"},
+		},
+		{
+			"Accessories",
+			[]orderproc.Option{orderproc.WithComplementaryRules(orderproc.ComplementaryRules{
+				Accessories: regexp.MustCompile(`^ACC-`),
+			})},
+			func(t *testing.T) []*orderproc.InputOrder {
+				return []*orderproc.InputOrder{row(t, 1, "ACC-STAND", 1, 30)}
+			},
+			[]string{"ACC-STAND"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor, err := orderproc.New(tt.opts...)
+			require.NoError(t, err)
+
+			lines, err := processor.Process(tt.orders(t))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectIds, productIds(lines))
+		})
+	}
+}
+
+func TestProcessor_ProcessWithOptions(t *testing.T) {
+	processor, err := orderproc.New(orderproc.WithOptions(orderproc.ProcessOptions{StartNo: 10}))
+	require.NoError(t, err)
+
+	lines, err := processor.ProcessWithOptions(
+		[]*orderproc.InputOrder{row(t, 1, "FG0A-CLEAR-OPPOA3", 1, 50)},
+		orderproc.ProcessOptions{NumberNamespace: "ORD"},
+	)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, lines)
+	assert.Equal(t, 10, lines[0].No)
+	assert.Equal(t, "ORD-10", lines[0].NamespacedNo)
+}
+
+func TestProcessor_TexturePriorities(t *testing.T) {
+	processor, err := orderproc.New(orderproc.WithOptions(orderproc.ProcessOptions{
+		TexturePriorities: map[string]int{"CLEAR": 3, "MATTE": 2, "PRIVACY": 1},
+	}))
+	require.NoError(t, err)
+
+	lines, err := processor.Process([]*orderproc.InputOrder{row(t, 1, "FG0A-CLEAR-OPPOA3/FG0A-PRIVACY-OPPOA3", 1, 100)})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FG0A-CLEAR-OPPOA3", "FG0A-PRIVACY-OPPOA3", "WIPING-CLOTH", "PRIVACY-CLEANNER", "CLEAR-CLEANNER"}, productIds(lines))
+}
+
+func TestProcessor_FilmTextureMatrix(t *testing.T) {
+	processor, err := orderproc.New(orderproc.WithOptions(orderproc.ProcessOptions{
+		Mode:                  "lenient",
+		FilmTextureStrictness: "reject",
+		FilmTextureMatrix:     map[string][]string{"FG05": {"CLEAR"}},
+	}))
+	require.NoError(t, err)
+
+	lines, err := processor.Process([]*orderproc.InputOrder{
+		row(t, 1, "FG05-CLEAR-OPPOA3", 1, 50),
+		row(t, 2, "FG05-PRIVACY-OPPOA3", 1, 50),
+	})
+
+	var partial *errors.PartialError
+	require.ErrorAs(t, err, &partial)
+	assert.Equal(t, []string{"FG05-CLEAR-OPPOA3", "WIPING-CLOTH", "CLEAR-CLEANNER"}, productIds(lines))
+
+	other, err := orderproc.New()
+	require.NoError(t, err)
+	lines, err = other.Process([]*orderproc.InputOrder{row(t, 1, "FG05-PRIVACY-OPPOA3", 1, 50)})
+	require.NoError(t, err, "another processor has its own matrix")
+	assert.NotEmpty(t, lines)
+}
+
+func TestProcessor_PriceList(t *testing.T) {
+	priceList := mocks.NewPriceList(t)
+	priceList.On("UnitPrice", mock.Anything, "FG0A-CLEAR-OPPOA3").Return(40.0, true)
+
+	processor, err := orderproc.New(orderproc.WithPriceList(priceList))
+	require.NoError(t, err)
+
+	lines, err := processor.Process([]*orderproc.InputOrder{{No: 1, PlatformProductId: "FG0A-CLEAR-OPPOA3", Qty: 2}})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, lines)
+	assert.Equal(t, 40.0, lines[0].UnitPrice)
+	assert.Equal(t, 80.0, lines[0].TotalPrice)
+	assert.True(t, lines[0].PriceEnriched)
+}
+
+func TestProcessor_Kits(t *testing.T) {
+	processor, err := orderproc.New(orderproc.WithComplementaryRules(orderproc.ComplementaryRules{KitTenants: []string{"acme"}}))
+	require.NoError(t, err)
+
+	lines, err := processor.ProcessWithOptions(
+		[]*orderproc.InputOrder{row(t, 1, "FG0A-CLEAR-OPPOA3", 1, 50)},
+		orderproc.ProcessOptions{TenantId: "acme"},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "CARE-KIT-CLEAR", lines[1].ProductId)
+	assert.Equal(t, []orderproc.KitComponent{{ProductId: "WIPING-CLOTH", Qty: 1}, {ProductId: "CLEAR-CLEANNER", Qty: 1}}, lines[1].Components)
+}
+
+func TestProcessor_NegativeAmount(t *testing.T) {
+	processor, err := orderproc.New()
+	require.NoError(t, err)
+
+	_, err = processor.Process([]*orderproc.InputOrder{row(t, 1, "FG0A-CLEAR-OPPOA3", 1, -50)})
+
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
@@ -0,0 +1,314 @@
+package orderproc
+
+import (
+	"time"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// InputOrder is one platform row. Amounts are in the processor's currency,
+// see WithCurrency
+type InputOrder struct {
+	No                int
+	PlatformProductId string
+	Qty               int
+	UnitPrice         float64
+	// zero prices the row from the price list, see WithPriceList
+	TotalPrice float64
+	// the client's own id for the row, copied to every line derived from it
+	ExternalRef string
+	// sales channel of the row, picks its complementary policy
+	Channel string
+	// platform voucher and fee of the row, spread across its lines
+	Discount  float64
+	Surcharge float64
+}
+
+// CleanedOrder is one output line
+type CleanedOrder struct {
+	No            int
+	ProductId     string
+	MaterialId    string
+	ModelId       string
+	Qty           int
+	UnitPrice     float64
+	TotalPrice    float64
+	IsAccessory   bool
+	ParentNo      int
+	PriceEnriched bool
+	ExternalRef   string
+	NamespacedNo  string
+	// set on kit lines only
+	Components []KitComponent
+	// set on classified complementary lines only
+	Customs *CustomsInfo
+	// the out of stock product this line replaces
+	SubstitutedFor string
+	// the rule and campaign behind a complementary line
+	Attribution *Attribution
+	// grams for the line's quantity, set when the weight catalog knows it
+	ShippingWeight int
+	// prices before the row's discount and surcharge, set on adjusted lines
+	// only
+	GrossUnitPrice  float64
+	GrossTotalPrice float64
+	// free units the complementary caps withheld on account of the row
+	CappedQty int
+	// color or variant token taken out of the product code, main lines only
+	Variant string
+}
+
+type KitComponent struct {
+	ProductId string
+	Qty       int
+}
+
+type CustomsInfo struct {
+	HSCode string
+	// declared value per unit
+	UnitValue float64
+}
+
+type Attribution struct {
+	RuleId       string
+	CampaignCode string
+}
+
+// ProcessOptions are settings of one call, zero fields keep the processor's.
+// Maps keyed by tenant take "*" for every tenant without its own entry
+type ProcessOptions struct {
+	// zero budgets mean the processor never times out
+	RowTimeout    time.Duration
+	BatchDeadline time.Duration
+
+	// "strict" fails the batch on the first bad row, "lenient" drops it and
+	// returns an *errors.PartialError
+	Mode string
+
+	// whose price list and settings apply
+	TenantId string
+
+	// per tenant model suffix spellings and the suffix each is normalized
+	// to, e.g. {"*": {"-BLACK": "-B"}}
+	ModelSuffixAliases map[string]map[string]string
+	// per tenant tokens taken off the front of model ids into the variant
+	VariantTokens map[string][]string
+
+	// per row limits on bundle components and on the units their
+	// multipliers add up to, and the most units a line may carry
+	MaxBundleComponents int
+	MaxBundleUnits      int
+	MaxLineQty          int
+
+	// number of the first line, and a namespace numbering every line also as
+	// "<namespace>-<no>"
+	StartNo         int
+	NumberNamespace string
+
+	// what a main line in a texture its film type is not made in does:
+	// "off", "warn" (kept) or "reject" (the row fails)
+	FilmTextureStrictness string
+	// the textures each film type is made in, e.g. {"FG05": ["CLEAR",
+	// "MATTE"]}. Film types not listed come in every texture
+	FilmTextureMatrix map[string][]string
+
+	// order of cleaner and kit lines, lower first, e.g. {"CLEAR": 1,
+	// "MATTE": 2, "PRIVACY": 3}. Every texture needs its own priority
+	TexturePriorities map[string]int
+}
+
+// ComplementaryCap allows at most Units free items of Item ("cleaner" or
+// "cloth") per PerValue of order value, measured per "row" or per "batch"
+type ComplementaryCap struct {
+	Item     string
+	Units    int
+	PerValue float64
+	Scope    string
+}
+
+// PriceList prices rows sent without a total, ok is false for unknown
+// products
+type PriceList interface {
+	UnitPrice(tenantId, productId string) (amount float64, ok bool)
+}
+
+type priceListAdapter struct {
+	priceList PriceList
+}
+
+func (a priceListAdapter) UnitPrice(tenantId, productId string) (*value_object.Price, bool) {
+	amount, ok := a.priceList.UnitPrice(tenantId, productId)
+	if !ok {
+		return nil, false
+	}
+	price, err := value_object.NewPrice(amount)
+	if err != nil {
+		return nil, false
+	}
+	return price, true
+}
+
+func (o *InputOrder) toEntity() (*entity.InputOrder, error) {
+	unitPrice, err := value_object.NewPrice(o.UnitPrice)
+	if err != nil {
+		return nil, err
+	}
+	totalPrice, err := value_object.NewPrice(o.TotalPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &entity.InputOrder{
+		No:                o.No,
+		PlatformProductId: o.PlatformProductId,
+		Qty:               o.Qty,
+		UnitPrice:         unitPrice,
+		TotalPrice:        totalPrice,
+		ExternalRef:       o.ExternalRef,
+		Channel:           o.Channel,
+	}
+	if o.Discount != 0 {
+		if order.Discount, err = value_object.NewPrice(o.Discount); err != nil {
+			return nil, err
+		}
+	}
+	if o.Surcharge != 0 {
+		if order.Surcharge, err = value_object.NewPrice(o.Surcharge); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func toEntities(orders []*InputOrder) ([]*entity.InputOrder, error) {
+	entities := make([]*entity.InputOrder, len(orders))
+	for i, order := range orders {
+		e, err := order.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = e
+	}
+	return entities, nil
+}
+
+func fromEntity(e *entity.CleanedOrder) *CleanedOrder {
+	line := &CleanedOrder{
+		No:              e.No,
+		ProductId:       e.ProductId,
+		MaterialId:      e.MaterialId,
+		ModelId:         e.ModelId,
+		Qty:             e.Qty,
+		UnitPrice:       e.UnitPrice.Amount(),
+		TotalPrice:      e.TotalPrice.Amount(),
+		IsAccessory:     e.IsAccessory,
+		ParentNo:        e.ParentNo,
+		PriceEnriched:   e.PriceEnriched,
+		ExternalRef:     e.ExternalRef,
+		NamespacedNo:    e.NamespacedNo,
+		SubstitutedFor:  e.SubstitutedFor,
+		ShippingWeight:  e.ShippingWeight,
+		GrossUnitPrice:  e.GrossUnitPrice.Amount(),
+		GrossTotalPrice: e.GrossTotalPrice.Amount(),
+		CappedQty:       e.CappedQty,
+		Variant:         e.Variant,
+	}
+	for _, component := range e.Components {
+		line.Components = append(line.Components, KitComponent{ProductId: component.ProductId, Qty: component.Qty})
+	}
+	if e.Customs != nil {
+		line.Customs = &CustomsInfo{HSCode: e.Customs.HSCode, UnitValue: e.Customs.UnitValue}
+	}
+	if e.Attribution != nil {
+		line.Attribution = &Attribution{RuleId: string(e.Attribution.RuleId), CampaignCode: e.Attribution.CampaignCode}
+	}
+	return line
+}
+
+func fromEntities(entities []*entity.CleanedOrder) []*CleanedOrder {
+	lines := make([]*CleanedOrder, len(entities))
+	for i, e := range entities {
+		lines[i] = fromEntity(e)
+	}
+	return lines
+}
+
+func (o ProcessOptions) toEntity() (*entity.ProcessOptions, error) {
+	mode := entity.ProcessMode(o.Mode)
+	if mode != "" && !mode.IsValid() {
+		log.Errorf("unknown process mode", log.S("mode", o.Mode))
+		return nil, errors.ErrInvalidInput
+	}
+
+	strictness := entity.FilmTextureStrictness(o.FilmTextureStrictness)
+	if strictness != "" && !strictness.IsValid() {
+		log.Errorf("unknown film texture strictness", log.S("strictness", o.FilmTextureStrictness))
+		return nil, errors.ErrInvalidInput
+	}
+
+	options := &entity.ProcessOptions{
+		RowTimeout:            o.RowTimeout,
+		BatchDeadline:         o.BatchDeadline,
+		Mode:                  mode,
+		TenantId:              o.TenantId,
+		MaxBundleComponents:   o.MaxBundleComponents,
+		MaxBundleUnits:        o.MaxBundleUnits,
+		MaxLineQty:            o.MaxLineQty,
+		StartNo:               o.StartNo,
+		NumberNamespace:       o.NumberNamespace,
+		FilmTextureStrictness: strictness,
+	}
+	if o.ModelSuffixAliases != nil {
+		options.ModelSuffixAliases = entity.ModelSuffixAliases(o.ModelSuffixAliases)
+	}
+	if o.VariantTokens != nil {
+		options.VariantTokens = entity.VariantTokens(o.VariantTokens)
+		if err := options.VariantTokens.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if o.FilmTextureMatrix != nil {
+		options.FilmTextureMatrix = make(value_object.FilmTextureMatrix, len(o.FilmTextureMatrix))
+		for filmType, textures := range o.FilmTextureMatrix {
+			converted := make([]value_object.Texture, len(textures))
+			for i, texture := range textures {
+				converted[i] = value_object.Texture(texture)
+			}
+			options.FilmTextureMatrix[filmType] = converted
+		}
+		if err := options.FilmTextureMatrix.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if o.TexturePriorities != nil {
+		options.TexturePriorities = make(value_object.TexturePriorities, len(o.TexturePriorities))
+		for texture, priority := range o.TexturePriorities {
+			options.TexturePriorities[value_object.Texture(texture)] = priority
+		}
+		if err := options.TexturePriorities.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+func toComplementaryCaps(caps map[string][]ComplementaryCap) entity.ComplementaryCaps {
+	if caps == nil {
+		return nil
+	}
+	converted := make(entity.ComplementaryCaps, len(caps))
+	for tenantId, tenantCaps := range caps {
+		for _, c := range tenantCaps {
+			converted[tenantId] = append(converted[tenantId], &entity.ComplementaryCap{
+				Item:     entity.ComplementaryCapItem(c.Item),
+				Units:    c.Units,
+				PerValue: c.PerValue,
+				Scope:    entity.ComplementaryCapScope(c.Scope),
+			})
+		}
+	}
+	return converted
+}
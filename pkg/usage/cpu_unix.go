@@ -0,0 +1,17 @@
+//go:build unix
+
+package usage
+
+import (
+	"syscall"
+	"time"
+)
+
+// user and system time of the whole process
+func processCPUTime() time.Duration {
+	var rusage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &rusage); err != nil {
+		return 0
+	}
+	return time.Duration(rusage.Utime.Nano() + rusage.Stime.Nano())
+}
@@ -0,0 +1,51 @@
+// Package usage measures what a piece of work cost the process. CPU time and
+// allocations are read for the whole process, so work running at the same
+// time is counted too
+package usage
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+const heapAllocsMetric = "/gc/heap/allocs:bytes"
+
+type Usage struct {
+	Wall time.Duration
+	// zero where the platform does not report it
+	CPU            time.Duration
+	AllocatedBytes uint64
+}
+
+type Meter struct {
+	start  time.Time
+	cpu    time.Duration
+	allocs uint64
+}
+
+func Start() *Meter {
+	return &Meter{start: time.Now(), cpu: processCPUTime(), allocs: heapAllocs()}
+}
+
+// Stop can be called more than once, each time measuring from Start
+func (m *Meter) Stop() *Usage {
+	usage := &Usage{Wall: time.Since(m.start)}
+	if cpu := processCPUTime(); cpu > m.cpu {
+		usage.CPU = cpu - m.cpu
+	}
+	if allocs := heapAllocs(); allocs > m.allocs {
+		usage.AllocatedBytes = allocs - m.allocs
+	}
+	return usage
+}
+
+// bytes allocated on the heap since the process started, cheap to read as it
+// does not stop the world
+func heapAllocs() uint64 {
+	sample := []metrics.Sample{{Name: heapAllocsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,27 @@
+package usage_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"order-placement-system/pkg/usage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sink []string
+
+func TestMeter_Stop(t *testing.T) {
+	meter := usage.Start()
+
+	deadline := time.Now().Add(20 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		sink = append(sink, strings.Repeat("x", 1024))
+	}
+	measured := meter.Stop()
+
+	assert.GreaterOrEqual(t, measured.Wall, 20*time.Millisecond)
+	assert.Greater(t, measured.AllocatedBytes, uint64(1024))
+	assert.Positive(t, measured.CPU)
+}
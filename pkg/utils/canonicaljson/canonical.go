@@ -0,0 +1,139 @@
+package canonicaljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes v as canonical JSON, see Transform
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Transform(data)
+}
+
+// Transform rewrites a JSON document so that documents meaning the same
+// thing come out byte for byte equal: object keys are sorted, insignificant
+// whitespace is dropped, strings are escaped the same way and numbers are
+// written one way (see normalizeNumber). Numbers are rewritten from their
+// digits, never through float64, so no two numbers collapse into one
+func Transform(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+
+	value, err := normalizeNumbers(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// maps are encoded with sorted keys
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+func normalizeNumbers(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			normalized, err := normalizeNumbers(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = normalized
+		}
+	case []interface{}:
+		for i, item := range v {
+			normalized, err := normalizeNumbers(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+	case json.Number:
+		return normalizeNumber(string(v))
+	}
+	return value, nil
+}
+
+// normalizeNumber writes a JSON number without trailing fractional zeros,
+// -0 as 0, and in exponent form (1.5e+22, 1e-7) only when the plain form
+// would need more than 21 integer digits or 6 or more zeros after
+// the point, so 50.0, 5e1 and 50 are all written 50
+func normalizeNumber(number string) (json.Number, error) {
+	mantissa, exponentText, _ := strings.Cut(strings.ToLower(number), "e")
+
+	negative := strings.HasPrefix(mantissa, "-")
+	mantissa = strings.TrimPrefix(mantissa, "-")
+
+	exponent := 0
+	if exponentText != "" {
+		var err error
+		if exponent, err = strconv.Atoi(exponentText); err != nil {
+			return "", fmt.Errorf("number exponent out of range: %s", number)
+		}
+	}
+
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(integer+fraction, "0")
+	exponent -= len(fraction)
+
+	trimmed := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(trimmed)
+	digits = trimmed
+
+	if digits == "" {
+		return "0", nil
+	}
+
+	// the value is 0.digits × 10^point
+	point := len(digits) + exponent
+
+	var out strings.Builder
+	if negative {
+		out.WriteByte('-')
+	}
+	switch {
+	case point > 21 || point <= -6:
+		out.WriteString(digits[:1])
+		if len(digits) > 1 {
+			out.WriteByte('.')
+			out.WriteString(digits[1:])
+		}
+		out.WriteByte('e')
+		if point-1 >= 0 {
+			out.WriteByte('+')
+		}
+		out.WriteString(strconv.Itoa(point - 1))
+	case point <= 0:
+		out.WriteString("0.")
+		out.WriteString(strings.Repeat("0", -point))
+		out.WriteString(digits)
+	case point >= len(digits):
+		out.WriteString(digits)
+		out.WriteString(strings.Repeat("0", point-len(digits)))
+	default:
+		out.WriteString(digits[:point])
+		out.WriteByte('.')
+		out.WriteString(digits[point:])
+	}
+	return json.Number(out.String()), nil
+}
@@ -0,0 +1,102 @@
+package canonicaljson_test
+
+import (
+	"testing"
+
+	"order-placement-system/pkg/utils/canonicaljson"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{"Keys are sorted at every level", `{"b":1,"a":{"d":2,"c":3}}`, `{"a":{"c":3,"d":2},"b":1}`, false},
+		{"Whitespace is dropped", " [ 1 ,\n\t2 ] ", `[1,2]`, false},
+		{"Trailing fractional zeros are dropped", `[1.0, 50.50, 123.4500, 0.10]`, `[1,50.5,123.45,0.1]`, false},
+		{"Exponents are written out", `[1e0, 5E1, 1.5e+2, 12e-1, 100e-2]`, `[1,50,150,1.2,1]`, false},
+		{"Large and small numbers keep an exponent", `[1e21, 1e20, 12345e20, 0.000001, 0.0000001, -15e-8]`, `[1e+21,100000000000000000000,1.2345e+24,0.000001,1e-7,-1.5e-7]`, false},
+		{"Negative zero is zero", `[-0, -0.0, -0e5, 0.000]`, `[0,0,0,0]`, false},
+		{"Sign is kept", `[-1.50, -2e1]`, `[-1.5,-20]`, false},
+		{"Integers beyond 2^53 are kept", `{"no": 9007199254740993}`, `{"no":9007199254740993}`, false},
+		{"Strings are escaped the same way", `["A", "<&>", "\/"]`, `["A","<&>","/"]`, false},
+		{"Array order is kept", `[3,1,2]`, `[3,1,2]`, false},
+		{"Invalid JSON", `{"a":`, "", true},
+		{"Trailing data", `{} {}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := canonicaljson.Transform([]byte(tt.input))
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestMarshal_ReorderedInputsMatch(t *testing.T) {
+	a, err := canonicaljson.Marshal(map[string]interface{}{"qty": 2, "no": 1, "unitPrice": 50.0})
+	require.NoError(t, err)
+
+	b, err := canonicaljson.Transform([]byte(`{"unitPrice": 50, "no": 1, "qty": 2}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(a), string(b))
+	assert.Equal(t, `{"no":1,"qty":2,"unitPrice":50}`, string(a))
+}
+
+func TestTransform_DifferentNumbersDiffer(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{"Integers beyond 2^53", `{"totalPrice": 9007199254740992}`, `{"totalPrice": 9007199254740993}`},
+		{"Decimals past float64 precision", `{"totalPrice": 0.10000000000000000001}`, `{"totalPrice": 0.1}`},
+		{"Zeros that are not trailing", `{"totalPrice": 50.05}`, `{"totalPrice": 50.5}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := canonicaljson.Transform([]byte(tt.a))
+			require.NoError(t, err)
+			b, err := canonicaljson.Transform([]byte(tt.b))
+			require.NoError(t, err)
+
+			assert.NotEqual(t, string(a), string(b))
+		})
+	}
+}
+
+func TestTransform_SameNumbersMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{"Trailing zeros", `{"totalPrice": 50.0}`, `{"totalPrice": 50}`},
+		{"Exponent", `{"totalPrice": 5e1}`, `{"totalPrice": 50.000}`},
+		{"Negative zero", `{"totalPrice": -0.0}`, `{"totalPrice": 0}`},
+		{"Digits past float64 precision", `{"totalPrice": 0.100000000000000000010}`, `{"totalPrice": 1.0000000000000000001e-1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := canonicaljson.Transform([]byte(tt.a))
+			require.NoError(t, err)
+			b, err := canonicaljson.Transform([]byte(tt.b))
+			require.NoError(t, err)
+
+			assert.Equal(t, string(a), string(b))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+)
+
+// AnonymizeConfig decides how a real batch is disguised before it is shared
+type AnonymizeConfig struct {
+	// keys the hashes, the same salt hashes the same ref the same way so rows
+	// of one seller stay linked across exports. Keep it secret
+	Salt string
+	// prices move by up to this share either way, 0.1 is ±10%
+	PriceJitter float64
+	// the same seed jitters the same way
+	Seed int64
+}
+
+func (c AnonymizeConfig) Validate() error {
+	if c.Salt == "" {
+		log.Errorf("anonymize salt cannot be empty")
+		return errors.ErrInvalidInput
+	}
+	if c.PriceJitter < 0 || c.PriceJitter >= 1 {
+		log.Errorf("price jitter must be at least 0 and below 1", log.AtoS("price_jitter", c.PriceJitter))
+		return errors.ErrInvalidInput
+	}
+	return nil
+}
+
+// Anonymize returns a copy of orders safe to hand to vendors or attach to a
+// bug report. External refs are replaced by a salted hash and every price of a
+// row is scaled by one random factor, so a total that did not match its unit
+// price still does not and one that matched still does. Row numbers, product
+// ids and quantities are kept as they are, they carry the structure a
+// reproduction needs
+func Anonymize(orders []*entity.InputOrder, config AnonymizeConfig) ([]*entity.InputOrder, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	random := rand.New(rand.NewSource(config.Seed))
+	policy := value_object.DefaultPricePolicy()
+
+	anonymized := make([]*entity.InputOrder, 0, len(orders))
+	for _, order := range orders {
+		if order == nil {
+			anonymized = append(anonymized, nil)
+			continue
+		}
+
+		factor := 1 + config.PriceJitter*(2*random.Float64()-1)
+		jitter := func(price *value_object.Price) *value_object.Price {
+			if price == nil {
+				return nil
+			}
+			return value_object.MustNewPrice(policy.Round(price.Amount() * factor))
+		}
+
+		unitPrice, totalPrice := jitter(order.UnitPrice), jitter(order.TotalPrice)
+		// rounding alone must not break a total that matched its unit price
+		if order.UnitPrice != nil && order.TotalPrice != nil &&
+			policy.Equal(value_object.MustNewPrice(order.UnitPrice.Amount()*float64(order.Qty)), order.TotalPrice) {
+			totalPrice = value_object.MustNewPrice(policy.Round(unitPrice.Amount() * float64(order.Qty)))
+		}
+
+		anonymized = append(anonymized, &entity.InputOrder{
+			No:                order.No,
+			PlatformProductId: order.PlatformProductId,
+			Qty:               order.Qty,
+			UnitPrice:         unitPrice,
+			TotalPrice:        totalPrice,
+			ExternalRef:       hashRef(config.Salt, order.ExternalRef),
+			Discount:          jitter(order.Discount),
+			Surcharge:         jitter(order.Surcharge),
+		})
+	}
+
+	return anonymized, nil
+}
+
+// empty stays empty, rows without a ref are not linked by the export
+func hashRef(salt, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(ref))
+	return "ref-" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/errors"
+	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/utils/parser"
+)
+
+var (
+	models = []string{
+		"OPPOA3",
+		"OPPOA3S",
+		"IPHONE16",
+		"IPHONE16PROMAX",
+		"SAMSUNGS24",
+		"SAMSUNGS24ULTRA",
+		"XIAOMI14",
+	}
+
+	// each one fails ParseProductCode for a different reason
+	invalidProductIds = []string{
+		"FG0A-GLOSSY-%s",
+		"XX0A-CLEAR-%s",
+		"FG0A-CLEAR-",
+		"%s",
+	}
+)
+
+const (
+	maxQty         = 5
+	maxBundleParts = 3
+	minUnitPrice   = 50
+	maxUnitPrice   = 500
+)
+
+// rates are the share of rows, between 0 and 1, that get a garbage prefix,
+// are a bundle or carry a product id the parser rejects
+type Config struct {
+	Rows            int
+	DirtyPrefixRate float64
+	BundleRate      float64
+	ErrorRate       float64
+	// the same seed always generates the same batch
+	Seed int64
+}
+
+func (c Config) Validate() error {
+	if c.Rows <= 0 {
+		log.Errorf("rows must be positive", log.AtoS("rows", c.Rows))
+		return errors.ErrInvalidInput
+	}
+
+	for name, rate := range map[string]float64{
+		"dirtyPrefixRate": c.DirtyPrefixRate,
+		"bundleRate":      c.BundleRate,
+		"errorRate":       c.ErrorRate,
+	} {
+		if rate < 0 || rate > 1 {
+			log.Errorf("rate must be between 0 and 1", log.S("rate", name), log.AtoS("value", rate))
+			return errors.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+// Generate builds a synthetic input batch from the film types, textures and
+// prefixes the parser knows, rows are numbered from 1
+func Generate(config Config) ([]*entity.InputOrder, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	random := rand.New(rand.NewSource(config.Seed))
+	filmTypes := parser.FilmTypes()
+	prefixes := parser.PlatformPrefixes()
+
+	product := func() string {
+		return fmt.Sprintf("%s-%s-%s",
+			filmTypes[random.Intn(len(filmTypes))],
+			value_object.AllTextures[random.Intn(len(value_object.AllTextures))],
+			models[random.Intn(len(models))])
+	}
+
+	orders := make([]*entity.InputOrder, 0, config.Rows)
+	for no := 1; no <= config.Rows; no++ {
+		var productId string
+		switch {
+		case random.Float64() < config.ErrorRate:
+			productId = invalidProductId(random)
+		case random.Float64() < config.BundleRate:
+			parts := make([]string, 2+random.Intn(maxBundleParts-1))
+			for i := range parts {
+				parts[i] = fmt.Sprintf("%s*%d", product(), 1+random.Intn(maxQty))
+			}
+			productId = strings.Join(parts, "/")
+		default:
+			productId = product()
+		}
+
+		if random.Float64() < config.DirtyPrefixRate {
+			productId = prefixes[random.Intn(len(prefixes))] + productId
+		}
+
+		qty := 1 + random.Intn(maxQty)
+		unitPrice := value_object.MustNewPrice(float64(minUnitPrice + random.Intn(maxUnitPrice-minUnitPrice+1)))
+		totalPrice, _ := unitPrice.MultiplyByInt(qty)
+
+		orders = append(orders, &entity.InputOrder{
+			No:                no,
+			PlatformProductId: productId,
+			Qty:               qty,
+			UnitPrice:         unitPrice,
+			TotalPrice:        totalPrice,
+		})
+	}
+
+	return orders, nil
+}
+
+func invalidProductId(random *rand.Rand) string {
+	format := invalidProductIds[random.Intn(len(invalidProductIds))]
+	if !strings.Contains(format, "%s") {
+		return format
+	}
+	return fmt.Sprintf(format, models[random.Intn(len(models))])
+}
@@ -0,0 +1,173 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"order-placement-system/internal/domain/entity"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/log"
+	"order-placement-system/pkg/utils/generator"
+	"order-placement-system/pkg/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	log.Init("dev")
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config generator.Config
+	}{
+		{"No rows", generator.Config{}},
+		{"Negative rate", generator.Config{Rows: 1, BundleRate: -0.1}},
+		{"Rate above one", generator.Config{Rows: 1, ErrorRate: 1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.Generate(tt.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGenerate_SameSeedSameBatch(t *testing.T) {
+	config := generator.Config{Rows: 50, DirtyPrefixRate: 0.5, BundleRate: 0.5, ErrorRate: 0.2, Seed: 7}
+
+	first, err := generator.Generate(config)
+	require.NoError(t, err)
+	second, err := generator.Generate(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func TestGenerate_Rates(t *testing.T) {
+	p := parser.NewProductParser()
+
+	parses := func(order *entity.InputOrder) bool {
+		products, err := p.Parse(order.PlatformProductId, order.Qty, order.TotalPrice)
+		if err != nil {
+			return false
+		}
+		for _, product := range products {
+			if _, _, err := p.ParseProductCode(product.CleanProductId); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	tests := []struct {
+		name   string
+		config generator.Config
+		check  func(t *testing.T, order *entity.InputOrder)
+	}{
+		{
+			name:   "Clean rows all parse",
+			config: generator.Config{Rows: 200, Seed: 1},
+			check: func(t *testing.T, order *entity.InputOrder) {
+				assert.True(t, parses(order), order.PlatformProductId)
+				assert.NotContains(t, order.PlatformProductId, "/")
+				assert.Equal(t, p.CleanPrefix(order.PlatformProductId), order.PlatformProductId)
+			},
+		},
+		{
+			name:   "Every row dirty and bundled still parses",
+			config: generator.Config{Rows: 200, DirtyPrefixRate: 1, BundleRate: 1, Seed: 2},
+			check: func(t *testing.T, order *entity.InputOrder) {
+				assert.True(t, parses(order), order.PlatformProductId)
+				assert.Contains(t, order.PlatformProductId, "/")
+				assert.NotEqual(t, p.CleanPrefix(order.PlatformProductId), order.PlatformProductId)
+			},
+		},
+		{
+			name:   "Every row an error",
+			config: generator.Config{Rows: 200, ErrorRate: 1, Seed: 3},
+			check: func(t *testing.T, order *entity.InputOrder) {
+				assert.False(t, parses(order), order.PlatformProductId)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, err := generator.Generate(tt.config)
+			require.NoError(t, err)
+			require.Len(t, orders, tt.config.Rows)
+
+			for i, order := range orders {
+				assert.Equal(t, i+1, order.No)
+				assert.NoError(t, order.IsValid())
+				assert.True(t, strings.TrimSpace(order.PlatformProductId) != "")
+				expected, _ := order.UnitPrice.MultiplyByInt(order.Qty)
+				assert.True(t, expected.Equals(order.TotalPrice))
+				tt.check(t, order)
+			}
+		})
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	orders := []*entity.InputOrder{
+		{
+			No:                1,
+			PlatformProductId: "x2-3&FG0A-CLEAR-IPHONE16PROMAX",
+			Qty:               2,
+			UnitPrice:         value_object.MustNewPrice(50),
+			TotalPrice:        value_object.MustNewPrice(120),
+			ExternalRef:       "SHOP42-ORDER-9001",
+			Discount:          value_object.MustNewPrice(10),
+		},
+		{
+			No:                2,
+			PlatformProductId: "FG0A-MATTE-OPPOA3",
+			Qty:               1,
+			UnitPrice:         value_object.MustNewPrice(80),
+			TotalPrice:        value_object.MustNewPrice(80),
+		},
+	}
+	config := generator.AnonymizeConfig{Salt: "secret", PriceJitter: 0.2, Seed: 7}
+
+	anonymized, err := generator.Anonymize(orders, config)
+	require.NoError(t, err)
+	require.Len(t, anonymized, 2)
+
+	first := anonymized[0]
+	assert.Equal(t, 1, first.No)
+	assert.Equal(t, orders[0].PlatformProductId, first.PlatformProductId)
+	assert.Equal(t, 2, first.Qty)
+	assert.NotContains(t, first.ExternalRef, "SHOP42")
+	assert.True(t, strings.HasPrefix(first.ExternalRef, "ref-"))
+	assert.Empty(t, anonymized[1].ExternalRef)
+	assert.Nil(t, first.Surcharge)
+	assert.InDelta(t, anonymized[1].UnitPrice.Amount(), anonymized[1].TotalPrice.Amount(), 0.001)
+
+	// one factor per row keeps the row's mismatched total mismatched
+	factor := first.UnitPrice.Amount() / 50
+	assert.InDelta(t, 1, factor, 0.2)
+	assert.InDelta(t, 120*factor, first.TotalPrice.Amount(), 0.01)
+	assert.InDelta(t, 10*factor, first.Discount.Amount(), 0.01)
+
+	again, err := generator.Anonymize(orders, config)
+	require.NoError(t, err)
+	assert.Equal(t, anonymized, again, "the same salt and seed anonymize the same way")
+
+	config.Salt = "other"
+	other, err := generator.Anonymize(orders, config)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ExternalRef, other[0].ExternalRef)
+}
+
+func TestAnonymize_Invalid(t *testing.T) {
+	_, err := generator.Anonymize(nil, generator.AnonymizeConfig{PriceJitter: 0.1})
+	assert.Error(t, err, "no salt")
+
+	_, err = generator.Anonymize(nil, generator.AnonymizeConfig{Salt: "secret", PriceJitter: 1})
+	assert.Error(t, err, "jitter of 100%")
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"sync"
+
+	"order-placement-system/internal/domain/service"
+)
+
+const DefaultProfile = "default"
+
+// ParserFactory hands out one shared parser per profile, built on first use.
+// Parsers are stateless so a single instance serves every goroutine
+type ParserFactory struct {
+	mu       sync.Mutex
+	builders map[string]func() service.ProductParser
+	parsers  map[string]service.ProductParser
+}
+
+func NewParserFactory() *ParserFactory {
+	factory := &ParserFactory{
+		builders: make(map[string]func() service.ProductParser),
+		parsers:  make(map[string]service.ProductParser),
+	}
+	factory.Register(DefaultProfile, NewProductParser)
+
+	return factory
+}
+
+// replaces the builder and drops any parser already built for profile
+func (f *ParserFactory) Register(profile string, build func() service.ProductParser) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.builders[profile] = build
+	delete(f.parsers, profile)
+}
+
+// unknown profiles get the default parser
+func (f *ParserFactory) Get(profile string) service.ProductParser {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.builders[profile]; !ok {
+		profile = DefaultProfile
+	}
+
+	if parser, ok := f.parsers[profile]; ok {
+		return parser
+	}
+
+	parser := f.builders[profile]()
+	f.parsers[profile] = parser
+
+	return parser
+}
@@ -0,0 +1,112 @@
+package parser_test
+
+import (
+	"sync"
+	"testing"
+
+	"order-placement-system/internal/domain/service"
+	"order-placement-system/internal/domain/value_object"
+	"order-placement-system/pkg/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserFactory_Get(t *testing.T) {
+	t.Run("Same profile shares one parser", func(t *testing.T) {
+		factory := parser.NewParserFactory()
+
+		assert.Same(t, factory.Get(parser.DefaultProfile), factory.Get(parser.DefaultProfile))
+	})
+
+	t.Run("Unknown profile gets the default parser", func(t *testing.T) {
+		factory := parser.NewParserFactory()
+
+		assert.Same(t, factory.Get(parser.DefaultProfile), factory.Get("unknown"))
+	})
+
+	t.Run("Registered profile gets its own parser", func(t *testing.T) {
+		factory := parser.NewParserFactory()
+		built := 0
+		factory.Register("wholesale", func() service.ProductParser {
+			built++
+			return parser.NewProductParser()
+		})
+
+		wholesale := factory.Get("wholesale")
+		assert.NotSame(t, factory.Get(parser.DefaultProfile), wholesale)
+		assert.Same(t, wholesale, factory.Get("wholesale"))
+		assert.Equal(t, 1, built)
+	})
+
+	t.Run("Concurrent callers get one instance", func(t *testing.T) {
+		factory := parser.NewParserFactory()
+
+		var wg sync.WaitGroup
+		parsers := make([]service.ProductParser, 32)
+		for i := range parsers {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				parsers[i] = factory.Get(parser.DefaultProfile)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, p := range parsers {
+			assert.Same(t, parsers[0], p)
+		}
+	})
+}
+
+// run with -race (make test-race) to catch shared state in the parser
+func TestProductParser_ConcurrentUse(t *testing.T) {
+	shared := parser.NewProductParser()
+
+	inputs := []string{
+		"FG0A-CLEAR-IPHONE16PROMAX",
+		"x2-3&FG0A-MATTE-OPPOA3*2",
+		"--FG0A-CLEAR-OPPOA3*2/FG0A-MATTE-OPPOA3",
+		"FG0A-MATTE/FG0A-PRIVACY-IPHONE16PROMAX*3",
+		"/FG05-MAT-OPPOA3//",
+	}
+
+	expected := make([][]string, len(inputs))
+	for i, input := range inputs {
+		products, err := shared.Parse(input, 2, value_object.MustNewPrice(120))
+		require.NoError(t, err)
+		for _, product := range products {
+			expected[i] = append(expected[i], product.CleanProductId)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 64*len(inputs))
+	for worker := 0; worker < 64; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, input := range inputs {
+				products, err := shared.Parse(input, 2, value_object.MustNewPrice(120))
+				if err != nil {
+					errs <- input + ": " + err.Error()
+					continue
+				}
+				for j, product := range products {
+					if product.CleanProductId != expected[i][j] {
+						errs <- input + ": got " + product.CleanProductId
+					}
+					if _, _, err := shared.ParseProductCode(product.CleanProductId); err != nil {
+						errs <- product.CleanProductId + ": " + err.Error()
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,17 +14,81 @@ import (
 	"order-placement-system/pkg/log"
 )
 
+// lookup tables shared by every parser, never written after init
+var (
+	quantitySuffixPattern = regexp.MustCompile(`\*(\d+)$`)
+
+	platformPrefixes = []string{
+		"%20--%20x",
+		"%20--",
+		"--%20x",
+		"x2-3&",
+		"%20x",
+		"%20-",
+		"--",
+	}
+
+	knownModelPatterns = map[string]string{
+		"FG0A-MATTE": "OPPOA3",
+		"FG0A-CLEAR": "OPPOA3",
+		"FG05-MATTE": "OPPOA3",
+	}
+
+	validFilmTypes = []string{"FG0A", "FG05", "FG1A", "FG1B"}
+	validTextures  = []string{"CLEAR", "MATTE", "PRIVACY"}
+)
+
+// PlatformPrefixes returns a copy of the garbage prefixes CleanPrefix strips
+func PlatformPrefixes() []string {
+	return append([]string(nil), platformPrefixes...)
+}
+
+// FilmTypes returns a copy of the film types known to the parser
+func FilmTypes() []string {
+	return append([]string(nil), validFilmTypes...)
+}
+
+// ProductParserImpl holds no mutable state, one instance is safe to share
+// between goroutines
 type ProductParserImpl struct {
 	priceCalculator service.PriceCalculator
+	textureAliases  value_object.TextureAliases
+	// asked after textureAliases, nil when there is none
+	runtimeAliases service.TextureAliasResolver
+	// read "_" as "-" for exporters that separate the code with underscores
+	underscoreSeparators bool
+}
+
+type ProductParserOptions struct {
+	// read only once the parser is built
+	TextureAliases value_object.TextureAliases
+	// asked for every texture TextureAliases do not know, so aliases added
+	// to it apply to the next product code parsed
+	RuntimeAliases service.TextureAliasResolver
+	// read "_" as "-" for exporters that separate the code with underscores
+	UnderscoreSeparators bool
 }
 
 func NewProductParser() service.ProductParser {
+	return NewProductParserWithOptions(ProductParserOptions{})
+}
+
+func NewProductParserWithOptions(options ProductParserOptions) service.ProductParser {
 	return &ProductParserImpl{
-		priceCalculator: NewPriceCalculator(),
+		priceCalculator:      NewPriceCalculator(),
+		textureAliases:       options.TextureAliases,
+		runtimeAliases:       options.RuntimeAliases,
+		underscoreSeparators: options.UnderscoreSeparators,
 	}
 }
 
+// components with a "*N" multiplier ship originalQty × N units
 func (p *ProductParserImpl) Parse(platformProductId string, originalQty int, totalPrice *value_object.Price) ([]*entity.ParsedProduct, error) {
+	return p.ParseWithQuantities(platformProductId, originalQty, totalPrice, entity.QuantityMultiply)
+}
+
+// quantities decides how originalQty and a component's "*N" combine
+func (p *ProductParserImpl) ParseWithQuantities(platformProductId string, originalQty int, totalPrice *value_object.Price, quantities entity.QuantitySemantics) ([]*entity.ParsedProduct, error) {
 	if platformProductId == "" {
 		log.Error("platform product id cannot be empty")
 		return nil, errors.ErrInvalidInput
@@ -34,19 +99,26 @@ func (p *ProductParserImpl) Parse(platformProductId string, originalQty int, tot
 		return nil, errors.ErrInvalidInput
 	}
 
-	cleanedId := p.CleanPrefix(platformProductId)
-	bundleProducts := p.SplitBundle(cleanedId)
+	cleanedId, underscored := p.normalizeSeparators(p.CleanPrefix(platformProductId))
+	bundleProducts, dropped := p.splitBundle(cleanedId)
 
 	var parsedProducts []*entity.ParsedProduct
 	totalQuantityUnits := 0
 	productQuantities := make([]int, len(bundleProducts))
+	multipliers := make([]int, len(bundleProducts))
 
 	for i, bundleProduct := range bundleProducts {
-		_, quantity, hasQuantity := p.ExtractQuantity(bundleProduct)
-		if !hasQuantity {
-			quantity = originalQty
+		_, multiplier, hasMultiplier := p.ExtractQuantity(bundleProduct)
+		quantity, ok := quantities.ComponentQty(originalQty, multiplier, hasMultiplier)
+		// a sum that wraps around could come out small and pass every limit
+		if !ok || quantity > math.MaxInt-totalQuantityUnits {
+			log.Errorf("bundle quantity overflows", log.S("productId", platformProductId))
+			return nil, errors.ErrInvalidInput
 		}
 		productQuantities[i] = quantity
+		if hasMultiplier {
+			multipliers[i] = multiplier
+		}
 		totalQuantityUnits += quantity
 	}
 
@@ -66,12 +138,25 @@ func (p *ProductParserImpl) Parse(platformProductId string, originalQty int, tot
 			return nil, err
 		}
 
+		var rewrites []entity.BatchWarningCode
+		if underscored {
+			rewrites = append(rewrites, entity.WarningUnderscoreSeparators)
+		}
+		if dropped {
+			rewrites = append(rewrites, entity.WarningEmptyBundleSegments)
+		}
+		if p.usesTextureAlias(cleanProduct) {
+			rewrites = append(rewrites, entity.WarningTextureAliased)
+		}
+
 		parsedProduct := &entity.ParsedProduct{
 			CleanProductId: cleanProduct,
 			Quantity:       quantity,
 			OriginalQty:    originalQty,
+			Multiplier:     multipliers[i],
 			UnitPrice:      pricePerUnit,
 			TotalPrice:     productTotalPrice,
+			Rewrites:       rewrites,
 		}
 
 		parsedProducts = append(parsedProducts, parsedProduct)
@@ -97,20 +182,10 @@ func (p *ProductParserImpl) CleanPrefix(productId string) string {
 
 	cleaned := productId
 
-	prefixes := []string{
-		"%20--%20x",
-		"%20--",
-		"--%20x",
-		"x2-3&",
-		"%20x",
-		"%20-",
-		"--",
-	}
-
 	for {
 		before := cleaned
 
-		for _, prefix := range prefixes {
+		for _, prefix := range platformPrefixes {
 			if strings.HasPrefix(cleaned, prefix) {
 				cleaned = cleaned[len(prefix):]
 				goto next
@@ -135,13 +210,25 @@ func (p *ProductParserImpl) CleanPrefix(productId string) string {
 	return cleaned
 }
 
+// normalized is false when productId is returned as is
+func (p *ProductParserImpl) normalizeSeparators(productId string) (string, bool) {
+	if !p.underscoreSeparators || !strings.Contains(productId, "_") {
+		return productId, false
+	}
+
+	normalized := strings.ReplaceAll(productId, "_", "-")
+	log.Warnf("underscore separators normalized",
+		log.S("original", productId),
+		log.S("normalized", normalized))
+	return normalized, true
+}
+
 func (p *ProductParserImpl) ExtractQuantity(productId string) (cleanId string, quantity int, hasQuantity bool) {
-	re := regexp.MustCompile(`\*(\d+)$`)
-	matches := re.FindStringSubmatch(productId)
+	matches := quantitySuffixPattern.FindStringSubmatch(productId)
 
 	if len(matches) == 2 {
 		if qty, err := strconv.Atoi(matches[1]); err == nil {
-			cleanId = re.ReplaceAllString(productId, "")
+			cleanId = quantitySuffixPattern.ReplaceAllString(productId, "")
 			quantity = qty
 			hasQuantity = true
 			return
@@ -154,27 +241,46 @@ func (p *ProductParserImpl) ExtractQuantity(productId string) (cleanId string, q
 	return
 }
 
+// empty segments from leading, trailing or doubled "/" are dropped with a warning
 func (p *ProductParserImpl) SplitBundle(productId string) []string {
+	parts, _ := p.splitBundle(productId)
+	return parts
+}
+
+// dropped tells whether empty segments were left out
+func (p *ProductParserImpl) splitBundle(productId string) ([]string, bool) {
 	parts := strings.Split(productId, "/")
 	cleanParts := make([]string, 0)
+	dropped := 0
 
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
 		part = strings.TrimPrefix(part, "%20x")
 
-		if part != "" {
-			fixedPart := p.fixIncompleteProductId(part)
-			cleanParts = append(cleanParts, fixedPart)
+		if part == "" {
+			dropped++
+			continue
 		}
+
+		fixedPart := p.fixIncompleteProductId(part)
+		cleanParts = append(cleanParts, fixedPart)
+	}
+
+	if dropped == 0 || len(parts) < 2 {
+		return cleanParts, false
 	}
 
-	return cleanParts
+	log.Warnf("dropped empty bundle segments",
+		log.S("product_id", productId),
+		log.S("dropped", strconv.Itoa(dropped)))
+	return cleanParts, true
 }
 
 func (p *ProductParserImpl) fixIncompleteProductId(productId string) string {
 	parts := strings.Split(productId, "-")
 
-	if len(parts) == 2 {
+	// only film codes can be completed, anything else is left as is
+	if len(parts) == 2 && p.isValidProductStart(parts[0]) {
 		filmType := parts[0]
 		texture := parts[1]
 
@@ -197,14 +303,8 @@ func (p *ProductParserImpl) fixIncompleteProductId(productId string) string {
 }
 
 func (p *ProductParserImpl) inferModelId(filmType, texture, originalId string) string {
-	knownPatterns := map[string]string{
-		"FG0A-MATTE": "OPPOA3",
-		"FG0A-CLEAR": "OPPOA3",
-		"FG05-MATTE": "OPPOA3",
-	}
-
 	key := fmt.Sprintf("%s-%s", filmType, texture)
-	if modelId, exists := knownPatterns[key]; exists {
+	if modelId, exists := knownModelPatterns[key]; exists {
 		return modelId
 	}
 
@@ -230,12 +330,12 @@ func (p *ProductParserImpl) ParseProductCode(productId string) (materialId, mode
 
 	if !p.isValidFilmType(filmType) {
 		log.Errorf("invalid film type", log.S("filmType", filmType))
-		return "", "", errors.ErrInvalidInput
+		return "", "", errors.Categorize(errors.CategoryCatalogMismatch, errors.ErrInvalidInput)
 	}
 
 	if !p.isValidTexture(texture) {
 		log.Errorf("invalid texture", log.S("texture", texture))
-		return "", "", errors.ErrInvalidInput
+		return "", "", errors.Categorize(errors.CategoryCatalogMismatch, errors.ErrInvalidInput)
 	}
 
 	if parts[2] == "" {
@@ -271,7 +371,6 @@ func (p *ProductParserImpl) isValidProductStart(s string) bool {
 }
 
 func (p *ProductParserImpl) isValidFilmType(filmType string) bool {
-	validFilmTypes := []string{"FG0A", "FG05", "FG1A", "FG1B"}
 	for _, valid := range validFilmTypes {
 		if filmType == valid {
 			return true
@@ -281,7 +380,6 @@ func (p *ProductParserImpl) isValidFilmType(filmType string) bool {
 }
 
 func (p *ProductParserImpl) isValidTexture(texture string) bool {
-	validTextures := []string{"CLEAR", "MATTE", "PRIVACY"}
 	for _, valid := range validTextures {
 		if texture == valid {
 			return true
@@ -290,6 +388,21 @@ func (p *ProductParserImpl) isValidTexture(texture string) bool {
 	return false
 }
 
+// the texture of a film code is known only through an alias, "MAT" is a
+// spelling of MATTE rather than an alias
+func (p *ProductParserImpl) usesTextureAlias(productId string) bool {
+	parts := strings.Split(productId, "-")
+	if len(parts) < 3 || !p.isValidProductStart(parts[0]) {
+		return false
+	}
+
+	texture := strings.ToUpper(parts[1])
+	if texture == "MAT" || p.isValidTexture(texture) {
+		return false
+	}
+	return p.isValidTexture(p.normalizeTexture(parts[1]))
+}
+
 func (p *ProductParserImpl) normalizeTexture(texture string) string {
 	switch strings.ToUpper(texture) {
 	case "MAT":
@@ -301,11 +414,20 @@ func (p *ProductParserImpl) normalizeTexture(texture string) string {
 	case "PRIVACY":
 		return "PRIVACY"
 	default:
+		if aliased, ok := p.textureAliases.Resolve(texture); ok {
+			return aliased.String()
+		}
+		if p.runtimeAliases != nil {
+			if aliased, ok := p.runtimeAliases.ResolveTextureAlias(texture); ok {
+				return aliased.String()
+			}
+		}
 		log.Debugf("unknown texture, normalizing to uppercase", log.S("texture", texture))
 		return strings.ToUpper(texture)
 	}
 }
 
+// PriceCalculatorImpl is stateless and safe for concurrent use
 type PriceCalculatorImpl struct{}
 
 func NewPriceCalculator() service.PriceCalculator {
@@ -0,0 +1,132 @@
+package parser_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"order-placement-system/pkg/utils/parser"
+)
+
+// no single product string may take longer than this to parse
+const parseBudget = 100 * time.Millisecond
+
+var fuzzSeeds = []string{
+	"FG0A-CLEAR-IPHONE16PROMAX",
+	"--FG0A-CLEAR-OPPOA3*2/FG0A-MATTE-OPPOA3*2",
+	"%20x%20--FG05-MATTE-OPPOA3",
+	"x2-3&FG0A-PRIVACY-IPHONE16PROMAX*3",
+	"FG0A-CLEAR/%20xFG0A-MATTE",
+	"//FG0A-MAT//",
+	"FG0A-CLEAR-OPPOA3*99999999999999999999",
+	"FG0A-CLEAR-OPPOA3*0",
+	"-------FG",
+	"*",
+	"",
+}
+
+func withinBudget(t *testing.T, input string, f func()) {
+	start := time.Now()
+	f()
+	if elapsed := time.Since(start); elapsed > parseBudget {
+		t.Fatalf("%q took %s, budget is %s", input, elapsed, parseBudget)
+	}
+}
+
+func FuzzCleanPrefix(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	p := parser.NewProductParser()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var cleaned string
+		withinBudget(t, input, func() { cleaned = p.CleanPrefix(input) })
+
+		if !strings.HasSuffix(input, cleaned) {
+			t.Fatalf("CleanPrefix(%q) = %q is not a suffix of the input", input, cleaned)
+		}
+		if again := p.CleanPrefix(cleaned); again != cleaned {
+			t.Fatalf("CleanPrefix is not idempotent on %q: %q then %q", input, cleaned, again)
+		}
+	})
+}
+
+func FuzzSplitBundle(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	p := parser.NewProductParser()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var parts []string
+		withinBudget(t, input, func() { parts = p.SplitBundle(input) })
+
+		for _, part := range parts {
+			if part == "" || strings.Contains(part, "/") {
+				t.Fatalf("SplitBundle(%q) returned part %q", input, part)
+			}
+		}
+	})
+}
+
+func FuzzExtractQuantity(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	p := parser.NewProductParser()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var cleanId string
+		var quantity int
+		var hasQuantity bool
+		withinBudget(t, input, func() { cleanId, quantity, hasQuantity = p.ExtractQuantity(input) })
+
+		if !hasQuantity {
+			if cleanId != input || quantity != 1 {
+				t.Fatalf("ExtractQuantity(%q) = %q, %d without a quantity", input, cleanId, quantity)
+			}
+			return
+		}
+
+		suffix := strings.TrimPrefix(input, cleanId+"*")
+		if suffix == input || quantity < 0 {
+			t.Fatalf("ExtractQuantity(%q) = %q, %d", input, cleanId, quantity)
+		}
+		if n, err := strconv.Atoi(suffix); err != nil || n != quantity {
+			t.Fatalf("ExtractQuantity(%q) read %d from %q", input, quantity, suffix)
+		}
+	})
+}
+
+func FuzzParseFromFloat64(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed, 1, 100.0)
+	}
+	f.Add("FG0A-CLEAR-OPPOA3*9223372036854775807/FG0A-MATTE-OPPOA3*9223372036854775807/FG0A-CLEAR-OPPOA3*4", 1, 100.0)
+	p := parser.NewProductParser()
+
+	f.Fuzz(func(t *testing.T, input string, qty int, totalPrice float64) {
+		withinBudget(t, input, func() {
+			products, err := p.ParseFromFloat64(input, qty, totalPrice)
+			if err != nil {
+				return
+			}
+
+			units := 0
+			for _, product := range products {
+				units += product.Quantity
+				if units <= 0 {
+					t.Fatalf("ParseFromFloat64(%q, %d) quantities add up to %d", input, qty, units)
+				}
+				if product.Quantity <= 0 {
+					t.Fatalf("ParseFromFloat64(%q, %d) returned quantity %d", input, qty, product.Quantity)
+				}
+				if product.UnitPrice == nil || product.TotalPrice == nil {
+					t.Fatalf("ParseFromFloat64(%q, %d) returned a product without prices", input, qty)
+				}
+			}
+		})
+	})
+}
@@ -1,9 +1,12 @@
 package parser_test
 
 import (
+	"order-placement-system/internal/domain/entity"
 	"order-placement-system/internal/domain/value_object"
+	mockService "order-placement-system/internal/mock/service"
 	"order-placement-system/pkg/log"
 	"order-placement-system/pkg/utils/parser"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -179,6 +182,14 @@ func TestProductParser_SplitBundle(t *testing.T) {
 			input:    "",
 			expected: []string{},
 		},
+		{
+			name:  "Non-film two-part code is not completed",
+			input: "FG0A-MATTE/ACC-CABLE",
+			expected: []string{
+				"FG0A-MATTE-OPPOA3",
+				"ACC-CABLE",
+			},
+		},
 		{
 			name:  "Bundle with spaces",
 			input: "FG0A-CLEAR-OPPOA3 / FG0A-MATTE-OPPOA3",
@@ -187,6 +198,28 @@ func TestProductParser_SplitBundle(t *testing.T) {
 				"FG0A-MATTE-OPPOA3",
 			},
 		},
+		{
+			name:  "Leading and trailing separators",
+			input: "/FG0A-CLEAR-OPPOA3/",
+			expected: []string{
+				"FG0A-CLEAR-OPPOA3",
+			},
+		},
+		{
+			name:  "Doubled trailing separator",
+			input: "FG0A-CLEAR-OPPOA3//",
+			expected: []string{
+				"FG0A-CLEAR-OPPOA3",
+			},
+		},
+		{
+			name:  "Blank segment between products",
+			input: "FG0A-CLEAR-OPPOA3/ /%20x/FG0A-MATTE-OPPOA3",
+			expected: []string{
+				"FG0A-CLEAR-OPPOA3",
+				"FG0A-MATTE-OPPOA3",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -197,6 +230,23 @@ func TestProductParser_SplitBundle(t *testing.T) {
 	}
 }
 
+func TestProductParser_Parse_EmptyBundleSegmentRewrites(t *testing.T) {
+	parser := parser.NewProductParser()
+
+	products, err := parser.Parse("/FG0A-CLEAR-OPPOA3//FG0A-MATTE-OPPOA3", 1, value_object.MustNewPrice(100))
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	for _, product := range products {
+		assert.Equal(t, []entity.BatchWarningCode{entity.WarningEmptyBundleSegments}, product.Rewrites)
+	}
+
+	products, err = parser.Parse("FG0A-CLEAR-OPPOA3/FG0A-MATTE-OPPOA3", 1, value_object.MustNewPrice(100))
+	require.NoError(t, err)
+	for _, product := range products {
+		assert.Empty(t, product.Rewrites)
+	}
+}
+
 func TestProductParser_ParseProductCode(t *testing.T) {
 
 	parser := parser.NewProductParser()
@@ -268,6 +318,160 @@ func TestProductParser_ParseProductCode(t *testing.T) {
 	}
 }
 
+func TestProductParser_ParseProductCode_TextureAliases(t *testing.T) {
+	parser := parser.NewProductParserWithOptions(parser.ProductParserOptions{
+		TextureAliases: value_object.TextureAliases{
+			"PRIV":         value_object.TexturePrivacy,
+			"PRIVACYGLASS": value_object.TexturePrivacy,
+		},
+	})
+
+	testCases := []struct {
+		name       string
+		input      string
+		materialId string
+		expectErr  bool
+	}{
+		{"Alias resolves", "FG0A-PRIV-IPHONE16PROMAX", "FG0A-PRIVACY", false},
+		{"Alias is case insensitive", "FG0A-PrivacyGlass-IPHONE16PROMAX", "FG0A-PRIVACY", false},
+		{"Built in MAT still works", "FG0A-MAT-IPHONE16PROMAX", "FG0A-MATTE", false},
+		{"Unknown texture is still rejected", "FG0A-GLOSS-IPHONE16PROMAX", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			materialId, modelId, err := parser.ParseProductCode(tc.input)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.materialId, materialId)
+			assert.Equal(t, "IPHONE16PROMAX", modelId)
+		})
+	}
+}
+
+func TestProductParser_Parse_TextureAliasRewrites(t *testing.T) {
+	parser := parser.NewProductParserWithOptions(parser.ProductParserOptions{
+		TextureAliases: value_object.TextureAliases{"PRIV": value_object.TexturePrivacy},
+	})
+
+	products, err := parser.Parse("FG0A-PRIV-IPHONE16PROMAX/FG0A-MAT-OPPOA3/FG0A-CLEAR-OPPOA3", 1, value_object.MustNewPrice(300))
+	require.NoError(t, err)
+	require.Len(t, products, 3)
+
+	assert.Equal(t, []entity.BatchWarningCode{entity.WarningTextureAliased}, products[0].Rewrites)
+	assert.Empty(t, products[1].Rewrites, "MAT is a spelling of MATTE, not an alias")
+	assert.Empty(t, products[2].Rewrites)
+}
+
+func TestProductParser_ParseProductCode_RuntimeTextureAliases(t *testing.T) {
+	runtimeAliases := mockService.NewTextureAliasResolver(t)
+	parser := parser.NewProductParserWithOptions(parser.ProductParserOptions{RuntimeAliases: runtimeAliases})
+
+	runtimeAliases.On("ResolveTextureAlias", "SILK").Return(value_object.Texture(""), false).Once()
+	_, _, err := parser.ParseProductCode("FG0A-SILK-IPHONE16PROMAX")
+	assert.Error(t, err)
+
+	runtimeAliases.On("ResolveTextureAlias", "silk").Return(value_object.TextureMatte, true).Once()
+
+	materialId, _, err := parser.ParseProductCode("FG0A-silk-IPHONE16PROMAX")
+	require.NoError(t, err, "aliases added at runtime apply to parsers built before")
+	assert.Equal(t, "FG0A-MATTE", materialId)
+}
+
+func TestProductParser_Parse_UnderscoreSeparators(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		underscoreSeparators bool
+		input                string
+		expected             []string
+		rewritten            bool
+	}{
+		{"Underscores read as dashes", true, "FG0A_CLEAR_IPHONE16PROMAX", []string{"FG0A-CLEAR-IPHONE16PROMAX"}, true},
+		{"Bundle with underscores", true, "FG0A_CLEAR_OPPOA3/FG0A_MATTE_OPPOA3*2", []string{"FG0A-CLEAR-OPPOA3", "FG0A-MATTE-OPPOA3"}, true},
+		{"Prefix is still cleaned", true, "--FG0A_PRIVACY_IPHONE16PROMAX", []string{"FG0A-PRIVACY-IPHONE16PROMAX"}, true},
+		{"Dashes untouched", true, "FG0A-CLEAR-IPHONE16PROMAX", []string{"FG0A-CLEAR-IPHONE16PROMAX"}, false},
+		{"Underscores kept when disabled", false, "FG0A_CLEAR_IPHONE16PROMAX", []string{"FG0A_CLEAR_IPHONE16PROMAX"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := parser.NewProductParserWithOptions(parser.ProductParserOptions{UnderscoreSeparators: tc.underscoreSeparators})
+
+			products, err := parser.Parse(tc.input, 1, value_object.MustNewPrice(100))
+			require.NoError(t, err)
+
+			ids := make([]string, len(products))
+			for i, product := range products {
+				ids[i] = product.CleanProductId
+				assert.Equal(t, tc.rewritten, slices.Contains(product.Rewrites, entity.WarningUnderscoreSeparators))
+			}
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestProductParser_Parse_QuantityOverflow(t *testing.T) {
+	parser := parser.NewProductParser()
+
+	// the three quantities wrap around to 2
+	products, err := parser.Parse(
+		"FG0A-CLEAR-OPPOA3*9223372036854775807/FG0A-MATTE-OPPOA3*9223372036854775807/FG0A-CLEAR-OPPOA3*4",
+		1,
+		value_object.MustNewPrice(100),
+	)
+
+	assert.Error(t, err)
+	assert.Nil(t, products)
+}
+
+func TestProductParser_ParseWithQuantities(t *testing.T) {
+	parser := parser.NewProductParser()
+
+	tests := []struct {
+		name       string
+		quantities entity.QuantitySemantics
+		qty        int
+		expected   []int
+	}{
+		{"Multiply single row", entity.QuantityMultiply, 1, []int{3, 1}},
+		{"Multiply row quantity into every component", entity.QuantityMultiply, 2, []int{6, 2}},
+		{"Additive single row", entity.QuantityAdditive, 1, []int{3, 1}},
+		{"Additive adds row quantity and multiplier", entity.QuantityAdditive, 2, []int{4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, err := parser.ParseWithQuantities("FG0A-CLEAR-OPPOA3*3/FG0A-MATTE-OPPOA3", tt.qty, value_object.MustNewPrice(160), tt.quantities)
+			require.NoError(t, err)
+
+			quantities := make([]int, len(products))
+			units := 0
+			for i, product := range products {
+				quantities[i] = product.Quantity
+				units += product.Quantity
+				assert.Equal(t, tt.qty, product.OriginalQty)
+			}
+			assert.Equal(t, tt.expected, quantities)
+			assert.InDelta(t, 160.0/float64(units), products[0].UnitPrice.Amount(), 0.0001, "the row total is spread over every unit")
+		})
+	}
+
+	t.Run("Parse multiplies", func(t *testing.T) {
+		products, err := parser.Parse("FG0A-CLEAR-OPPOA3*3", 2, value_object.MustNewPrice(60))
+		require.NoError(t, err)
+		assert.Equal(t, 6, products[0].Quantity)
+	})
+
+	t.Run("Multiplied quantity overflow", func(t *testing.T) {
+		_, err := parser.Parse("FG0A-CLEAR-OPPOA3*4611686018427387904", 2, value_object.MustNewPrice(60))
+		assert.Error(t, err)
+	})
+}
+
 func TestProductParser_ParseFromFloat64(t *testing.T) {
 
 	parser := parser.NewProductParser()
@@ -306,6 +510,25 @@ func TestProductParser_ParseFromFloat64(t *testing.T) {
 				totalPrice: 100.0,
 			},
 		},
+		{
+			name:              "Separator noise around a single product",
+			platformProductId: "/FG0A-CLEAR-IPHONE16PROMAX//",
+			originalQty:       2,
+			totalPrice:        100.0,
+			expectedCount:     1,
+			expectErr:         false,
+			expectedFirstProduct: struct {
+				cleanId    string
+				quantity   int
+				unitPrice  float64
+				totalPrice float64
+			}{
+				cleanId:    "FG0A-CLEAR-IPHONE16PROMAX",
+				quantity:   2,
+				unitPrice:  50.0,
+				totalPrice: 100.0,
+			},
+		},
 		{
 			name:              "Product with prefix",
 			platformProductId: "x2-3&FG0A-CLEAR-IPHONE16PROMAX",